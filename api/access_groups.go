@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/tum"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxMemberCSVSize = 5 << 20
+
+type accessGroupsRoutes struct {
+	dao.DaoWrapper
+}
+
+func configAccessGroupsRouter(r *gin.Engine, d dao.DaoWrapper) {
+	routes := accessGroupsRoutes{d}
+	g := r.Group("/api/accessGroups")
+	g.Use(tools.Admin)
+	{
+		g.GET("", routes.getAccessGroups)
+		g.POST("", routes.createAccessGroup)
+		g.PUT("/:id", routes.updateAccessGroup)
+		g.DELETE("/:id", routes.deleteAccessGroup)
+		g.GET("/:id/members", routes.getMembers)
+		g.POST("/:id/members", routes.uploadMembers)
+		g.POST("/:id/sync", routes.syncAccessGroup)
+	}
+}
+
+type accessGroupDto struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	LdapGroup   string `json:"ldapGroup"`
+	MemberCount int    `json:"memberCount"`
+	Courses     []uint `json:"courses"`
+	SyncedAt    string `json:"syncedAt"`
+	SyncAdded   int    `json:"syncAdded"`
+	SyncRemoved int    `json:"syncRemoved"`
+	SyncError   string `json:"syncError"`
+}
+
+func toAccessGroupDto(g model.AccessGroup) accessGroupDto {
+	dto := accessGroupDto{
+		ID:          g.ID,
+		Name:        g.Name,
+		LdapGroup:   g.LdapGroup,
+		MemberCount: g.MemberCount,
+		Courses:     []uint{},
+		SyncAdded:   g.SyncAdded,
+		SyncRemoved: g.SyncRemoved,
+		SyncError:   g.SyncError,
+	}
+	if g.SyncedAt.Valid {
+		dto.SyncedAt = g.SyncedAt.Time.Format("02.01.2006 15:04")
+	}
+	for _, course := range g.Courses {
+		dto.Courses = append(dto.Courses, course.ID)
+	}
+	return dto
+}
+
+// memberChanges reports how the members of an access group changed
+type memberChanges struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type accessGroupRequest struct {
+	Name      string `json:"name"`
+	LdapGroup string `json:"ldapGroup"`
+}
+
+func (req *accessGroupRequest) validate() error {
+	req.Name, req.LdapGroup = strings.TrimSpace(req.Name), strings.TrimSpace(req.LdapGroup)
+	if req.Name == "" || len(req.Name) > 128 {
+		return errors.New("name must have 1 to 128 characters")
+	}
+	return nil
+}
+
+func (r accessGroupsRoutes) getAccessGroups(c *gin.Context) {
+	groups, err := r.AccessGroupsDao.GetAccessGroups(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get access groups", Err: err})
+		return
+	}
+	res := make([]accessGroupDto, len(groups))
+	for i, group := range groups {
+		res[i] = toAccessGroupDto(group)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (r accessGroupsRoutes) createAccessGroup(c *gin.Context) {
+	var req accessGroupRequest
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid body", Err: err})
+		return
+	}
+	if err := req.validate(); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: err.Error(), Err: err})
+		return
+	}
+	group := model.AccessGroup{Name: req.Name, LdapGroup: req.LdapGroup}
+	if err := r.AccessGroupsDao.CreateAccessGroup(c, &group); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not create access group", Err: err})
+		return
+	}
+	r.audit(c, fmt.Sprintf("created access group %s (%d)", group.Name, group.ID))
+	c.JSON(http.StatusCreated, toAccessGroupDto(group))
+}
+
+func (r accessGroupsRoutes) updateAccessGroup(c *gin.Context) {
+	group, ok := r.accessGroup(c)
+	if !ok {
+		return
+	}
+	var req accessGroupRequest
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid body", Err: err})
+		return
+	}
+	if err := req.validate(); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: err.Error(), Err: err})
+		return
+	}
+	group.Name, group.LdapGroup = req.Name, req.LdapGroup
+	if err := r.AccessGroupsDao.UpdateAccessGroup(c, &group); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not update access group", Err: err})
+		return
+	}
+	c.JSON(http.StatusOK, toAccessGroupDto(group))
+}
+
+func (r accessGroupsRoutes) deleteAccessGroup(c *gin.Context) {
+	group, ok := r.accessGroup(c)
+	if !ok {
+		return
+	}
+	if err := r.AccessGroupsDao.DeleteAccessGroup(c, group.ID); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not delete access group", Err: err})
+		return
+	}
+	r.audit(c, fmt.Sprintf("deleted access group %s (%d)", group.Name, group.ID))
+	c.Status(http.StatusOK)
+}
+
+func (r accessGroupsRoutes) getMembers(c *gin.Context) {
+	group, ok := r.accessGroup(c)
+	if !ok {
+		return
+	}
+	members, err := r.AccessGroupsDao.GetMembers(c, group.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get members", Err: err})
+		return
+	}
+	if members == nil {
+		members = []string{}
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// uploadMembers replaces the members of a group with the LRZ IDs in the first column of the uploaded CSV file
+func (r accessGroupsRoutes) uploadMembers(c *gin.Context) {
+	group, ok := r.accessGroup(c)
+	if !ok {
+		return
+	}
+	if group.LdapGroup != "" {
+		_ = c.Error(tools.RequestError{Status: http.StatusConflict, CustomMessage: "the members of this group are synced from LDAP"})
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "missing form parameter 'file'", Err: err})
+		return
+	}
+	if file.Size > maxMemberCSVSize {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "file too large (limit is 5mb)"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not open file", Err: err})
+		return
+	}
+	defer f.Close()
+	lrzIDs, skipped, err := parseMemberCSV(f)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid csv file", Err: err})
+		return
+	}
+	added, removed, err := r.AccessGroupsDao.SetMembers(c, group.ID, lrzIDs)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not update members", Err: err})
+		return
+	}
+	r.audit(c, fmt.Sprintf("uploaded members of access group %s (%d): %d added, %d removed", group.Name, group.ID, len(added), len(removed)))
+	c.JSON(http.StatusOK, newMemberChanges(added, removed, skipped))
+}
+
+// syncAccessGroup syncs the members of the group with its LDAP group now
+func (r accessGroupsRoutes) syncAccessGroup(c *gin.Context) {
+	group, ok := r.accessGroup(c)
+	if !ok {
+		return
+	}
+	if group.LdapGroup == "" {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "the group has no LDAP group"})
+		return
+	}
+	added, removed, err := tum.SyncAccessGroup(context.Background(), r.DaoWrapper, group)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadGateway, CustomMessage: "can not sync group: " + err.Error(), Err: err})
+		return
+	}
+	c.JSON(http.StatusOK, newMemberChanges(added, removed, nil))
+}
+
+func newMemberChanges(added, removed, skipped []string) memberChanges {
+	res := memberChanges{Added: added, Removed: removed, Skipped: skipped}
+	if res.Added == nil {
+		res.Added = []string{}
+	}
+	if res.Removed == nil {
+		res.Removed = []string{}
+	}
+	return res
+}
+
+// parseMemberCSV returns the LRZ IDs in the first column of a CSV file separated by commas or semicolons and
+// the values that are no LRZ IDs, e.g. a header.
+func parseMemberCSV(file io.Reader) (lrzIDs []string, skipped []string, err error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	if firstLine, _, _ := bytes.Cut(content, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return lrzIDs, skipped, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		lrzID, ok := model.NormalizeLrzID(record[0])
+		if !ok {
+			skipped = append(skipped, record[0])
+			continue
+		}
+		if !seen[lrzID] {
+			seen[lrzID] = true
+			lrzIDs = append(lrzIDs, lrzID)
+		}
+	}
+}
+
+// accessGroup returns the access group with the id in the path
+func (r accessGroupsRoutes) accessGroup(c *gin.Context) (model.AccessGroup, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid id", Err: err})
+		return model.AccessGroup{}, false
+	}
+	group, err := r.AccessGroupsDao.GetAccessGroup(c, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = c.Error(tools.RequestError{Status: http.StatusNotFound, CustomMessage: "access group not found", Err: err})
+		return model.AccessGroup{}, false
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get access group", Err: err})
+		return model.AccessGroup{}, false
+	}
+	return group, true
+}
+
+func (r accessGroupsRoutes) audit(c *gin.Context, message string) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	if err := r.AuditDao.Create(&model.Audit{User: tumLiveContext.User, Message: message, Type: model.AuditInfo}); err != nil {
+		logger.Error("Create Audit:", "err", err)
+	}
+}
+
+// courseAccessGroupDto is an access group that can be attached to a course
+type courseAccessGroupDto struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	MemberCount int    `json:"memberCount"`
+	Attached    bool   `json:"attached"`
+}
+
+// getCourseAccessGroups returns all access groups and whether they are attached to the course
+func (r coursesRoutes) getCourseAccessGroups(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	groups, err := r.AccessGroupsDao.GetAccessGroups(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get access groups", Err: err})
+		return
+	}
+	res := make([]courseAccessGroupDto, len(groups))
+	for i, group := range groups {
+		res[i] = courseAccessGroupDto{ID: group.ID, Name: group.Name, MemberCount: group.MemberCount}
+		for _, course := range group.Courses {
+			if course.ID == tumLiveContext.Course.ID {
+				res[i].Attached = true
+			}
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// attachAccessGroup lets the members of the group watch the course if its visibility is "enrolled"
+func (r coursesRoutes) attachAccessGroup(c *gin.Context) {
+	r.changeCourseAccessGroup(c, true)
+}
+
+func (r coursesRoutes) detachAccessGroup(c *gin.Context) {
+	r.changeCourseAccessGroup(c, false)
+}
+
+func (r coursesRoutes) changeCourseAccessGroup(c *gin.Context, attach bool) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := tumLiveContext.Course
+	id, err := strconv.ParseUint(c.Param("groupID"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid groupID", Err: err})
+		return
+	}
+	group, err := r.AccessGroupsDao.GetAccessGroup(c, uint(id))
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusNotFound, CustomMessage: "access group not found", Err: err})
+		return
+	}
+	action := "attach"
+	if attach {
+		err = r.AccessGroupsDao.AddCourse(c, group.ID, course.ID)
+	} else {
+		action = "detach"
+		err = r.AccessGroupsDao.RemoveCourse(c, group.ID, course.ID)
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not " + action + " access group", Err: err})
+		return
+	}
+	r.auditCourseChange(tumLiveContext, fmt.Sprintf("%s:'%s' %s access group: %s (%d)", course.Name, course.Slug, action, group.Name, group.ID))
+	c.Status(http.StatusOK)
+}
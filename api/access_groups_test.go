@@ -0,0 +1,148 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+func TestAccessGroups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	group := model.AccessGroup{
+		Model:       gorm.Model{ID: 3},
+		Name:        "Master Informatics",
+		LdapGroup:   "cn=master-informatics,ou=groups,dc=tum,dc=de",
+		Courses:     []model.Course{testutils.CourseFPV},
+		SyncedAt:    sql.NullTime{Time: time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC), Valid: true},
+		SyncAdded:   2,
+		MemberCount: 120,
+	}
+
+	t.Run("GET/api/accessGroups", func(t *testing.T) {
+		gomino.TestCases{
+			"success": {
+				Router: func(r *gin.Engine) {
+					groupsMock := mock_dao.NewMockAccessGroupsDao(gomock.NewController(t))
+					groupsMock.EXPECT().GetAccessGroups(gomock.Any()).Return([]model.AccessGroup{group}, nil)
+					configAccessGroupsRouter(r, dao.DaoWrapper{AccessGroupsDao: groupsMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: []accessGroupDto{{
+					ID:          group.ID,
+					Name:        group.Name,
+					LdapGroup:   group.LdapGroup,
+					MemberCount: 120,
+					Courses:     []uint{testutils.CourseFPV.ID},
+					SyncedAt:    "01.10.2026 12:30",
+					SyncAdded:   2,
+				}},
+			},
+		}.Method(http.MethodGet).Url("/api/accessGroups").Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/accessGroups", func(t *testing.T) {
+		gomino.TestCases{
+			"empty name": {
+				Router: func(r *gin.Engine) {
+					configAccessGroupsRouter(r, dao.DaoWrapper{})
+				},
+				Body:         gin.H{"name": "  "},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					groupsMock := mock_dao.NewMockAccessGroupsDao(gomock.NewController(t))
+					groupsMock.EXPECT().CreateAccessGroup(gomock.Any(), &model.AccessGroup{Name: "Exchange Students"}).
+						DoAndReturn(func(_ interface{}, g *model.AccessGroup) error {
+							g.ID = 4
+							return nil
+						})
+					configAccessGroupsRouter(r, dao.DaoWrapper{AccessGroupsDao: groupsMock, AuditDao: testutils.GetAuditMock(t)})
+				},
+				Body:             gin.H{"name": " Exchange Students "},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode:     http.StatusCreated,
+				ExpectedResponse: accessGroupDto{ID: 4, Name: "Exchange Students", Courses: []uint{}},
+			},
+		}.Method(http.MethodPost).Url("/api/accessGroups").Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/accessGroups/:id/members", func(t *testing.T) {
+		gomino.TestCases{
+			"not found": {
+				Router: func(r *gin.Engine) {
+					groupsMock := mock_dao.NewMockAccessGroupsDao(gomock.NewController(t))
+					groupsMock.EXPECT().GetAccessGroup(gomock.Any(), uint(5)).Return(model.AccessGroup{}, gorm.ErrRecordNotFound)
+					configAccessGroupsRouter(r, dao.DaoWrapper{AccessGroupsDao: groupsMock})
+				},
+				Url:          "/api/accessGroups/5/members",
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusNotFound,
+			},
+			"synced from ldap": {
+				Router: func(r *gin.Engine) {
+					groupsMock := mock_dao.NewMockAccessGroupsDao(gomock.NewController(t))
+					groupsMock.EXPECT().GetAccessGroup(gomock.Any(), group.ID).Return(group, nil)
+					configAccessGroupsRouter(r, dao.DaoWrapper{AccessGroupsDao: groupsMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusConflict,
+			},
+		}.Method(http.MethodPost).Url(fmt.Sprintf("/api/accessGroups/%d/members", group.ID)).Run(t, testutils.Equal)
+	})
+
+	t.Run("PUT/api/course/:courseID/accessGroups/:groupID", func(t *testing.T) {
+		gomino.TestCases{
+			"success": {
+				Router: func(r *gin.Engine) {
+					groupsMock := mock_dao.NewMockAccessGroupsDao(gomock.NewController(t))
+					groupsMock.EXPECT().GetAccessGroup(gomock.Any(), group.ID).Return(group, nil)
+					groupsMock.EXPECT().AddCourse(gomock.Any(), group.ID, testutils.CourseFPV.ID).Return(nil)
+					configGinCourseRouter(r, dao.DaoWrapper{
+						CoursesDao:      testutils.GetCoursesMock(t),
+						AccessGroupsDao: groupsMock,
+						AuditDao:        testutils.GetAuditMock(t),
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.Method(http.MethodPut).Url(fmt.Sprintf("/api/course/%d/accessGroups/%d", testutils.CourseFPV.ID, group.ID)).Run(t, testutils.Equal)
+	})
+}
+
+func TestParseMemberCSV(t *testing.T) {
+	csv := "LRZ-Kennung;Name\nAB12CDE;Hansi\nfg34hij;\"Müller; Max\"\n\nab12cde;Hansi\n"
+	lrzIDs, skipped, err := parseMemberCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lrzIDs, []string{"ab12cde", "fg34hij"}) {
+		t.Errorf("unexpected lrz ids %v", lrzIDs)
+	}
+	if !reflect.DeepEqual(skipped, []string{"LRZ-Kennung"}) {
+		t.Errorf("unexpected skipped values %v", skipped)
+	}
+
+	lrzIDs, _, err = parseMemberCSV(strings.NewReader("ab12cde,Hansi\nfg34hij"))
+	if err != nil || !reflect.DeepEqual(lrzIDs, []string{"ab12cde", "fg34hij"}) {
+		t.Errorf("unexpected result %v, %v", lrzIDs, err)
+	}
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+)
+
+// attachmentPreviewWidth is the width of the images rendered from the first page of pdf attachments
+const attachmentPreviewWidth = 480
+
+// saveAttachment stores the file or url of the request according to the query parameter 'type' and returns its path
+// and filename
+func saveAttachment(c *gin.Context, course model.Course) (path string, filename string, ok bool) {
+	switch c.Query("type") {
+	case "file":
+		file, err := c.FormFile("file")
+		if err != nil {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusBadRequest,
+				CustomMessage: "missing form parameter 'file'",
+				Err:           err,
+			})
+			return "", "", false
+		}
+
+		if file.Size > MAX_FILE_SIZE {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusBadRequest,
+				CustomMessage: "file too large (limit is 50mb)",
+			})
+			return "", "", false
+		}
+
+		filename = file.Filename
+		fileUuid := uuid.NewV1()
+
+		filesFolder := fmt.Sprintf("%s/%s.%d/%s.%s/files",
+			tools.Cfg.Paths.Mass,
+			course.Name, course.Year,
+			course.Name, course.TeachingTerm)
+		path = fmt.Sprintf("%s/%s%s", filesFolder, fileUuid, filepath.Ext(file.Filename))
+
+		err = os.MkdirAll(filesFolder, os.ModePerm)
+		if err != nil {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "couldn't create folder: " + filesFolder,
+				Err:           err,
+			})
+			return "", "", false
+		}
+
+		if err = c.SaveUploadedFile(file, path); err != nil {
+			logger.Error("could not save file with path: "+path, "err", err)
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "could not save file with path: " + path,
+				Err:           err,
+			})
+			return "", "", false
+		}
+	case "url":
+		path = c.PostForm("file_url")
+		_, filename = filepath.Split(path)
+		if path == "" {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusBadRequest,
+				CustomMessage: "missing form parameter 'file_url'",
+			})
+			return "", "", false
+		}
+	default:
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "missing or invalid query parameter 'type'",
+		})
+		return "", "", false
+	}
+	return path, filename, true
+}
+
+func (r streamRoutes) newAttachment(c *gin.Context) {
+	foundContext, _ := c.Get("TUMLiveContext")
+	tumLiveContext := foundContext.(tools.TUMLiveContext)
+	stream := *tumLiveContext.Stream
+	course := *tumLiveContext.Course
+
+	path, filename, ok := saveAttachment(c, course)
+	if !ok {
+		return
+	}
+
+	file := model.File{StreamID: stream.ID, Path: path, Filename: filename, Type: model.FILETYPE_ATTACHMENT}
+	if err := r.FileDao.NewFile(&file); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not save file in database",
+			Err:           err,
+		})
+		return
+	}
+	go generateAttachmentPreview(r.FileDao, file)
+
+	c.JSON(http.StatusOK, file.ID)
+}
+
+// replaceAttachment uploads a new version of an attachment. The download link stays the same and the previous
+// version is kept.
+func (r streamRoutes) replaceAttachment(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	attachment, ok := r.attachment(c)
+	if !ok {
+		return
+	}
+	path, filename, ok := saveAttachment(c, *tumLiveContext.Course)
+	if !ok {
+		return
+	}
+	if err := r.FileDao.ReplaceAttachment(&attachment, path, filename); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not replace attachment",
+			Err:           err,
+		})
+		return
+	}
+	go generateAttachmentPreview(r.FileDao, attachment)
+	c.JSON(http.StatusOK, gin.H{"id": attachment.ID, "version": attachment.Version, "friendlyName": attachment.GetFriendlyFileName()})
+}
+
+// getAttachmentVersions returns the previous versions of an attachment
+func (r streamRoutes) getAttachmentVersions(c *gin.Context) {
+	attachment, ok := r.attachment(c)
+	if !ok {
+		return
+	}
+	versions, err := r.FileDao.GetAttachmentVersions(attachment.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get versions",
+			Err:           err,
+		})
+		return
+	}
+	res := make([]gin.H, len(versions))
+	for i, v := range versions {
+		res[i] = gin.H{"id": v.ID, "version": v.Version, "friendlyName": v.GetFriendlyFileName(), "replacedAt": v.CreatedAt}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// setAttachmentPlacement attaches an attachment to a section or a timestamp of the lecture, or to the whole lecture
+// if neither is set
+func (r streamRoutes) setAttachmentPlacement(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	stream := tumLiveContext.Stream
+	attachment, ok := r.attachment(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		VideoSectionID *uint `json:"videoSectionID"`
+		Timestamp      *uint `json:"timestamp"` // seconds since the start of the lecture
+	}
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "can not bind body", Err: err})
+		return
+	}
+	if req.VideoSectionID != nil {
+		if !slices.ContainsFunc(stream.VideoSections, func(s model.VideoSection) bool { return s.ID == *req.VideoSectionID }) {
+			_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "section is not part of this lecture"})
+			return
+		}
+		req.Timestamp = nil
+	}
+	if req.Timestamp != nil && stream.Duration.Valid && *req.Timestamp > uint(stream.Duration.Int32) {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "timestamp is after the end of the lecture"})
+		return
+	}
+	if err := r.FileDao.SetAttachmentPlacement(attachment, req.VideoSectionID, req.Timestamp); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not update attachment", Err: err})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (r streamRoutes) deleteAttachment(c *gin.Context) {
+	toDelete, ok := r.attachment(c)
+	if !ok {
+		return
+	}
+	if !toDelete.IsURL() {
+		err := os.Remove(toDelete.Path)
+		if err != nil {
+			logger.Error("can not delete file with path: "+toDelete.Path, "err", err)
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not delete file with path: " + toDelete.Path,
+				Err:           err,
+			})
+			return
+		}
+	}
+	versions, err := r.FileDao.GetAttachmentVersions(toDelete.ID)
+	if err != nil {
+		logger.Error("can not get versions of attachment", "err", err)
+	}
+	err = r.FileDao.DeleteAttachment(toDelete)
+	if err != nil {
+		logger.Error("can not delete file from database", "err", err)
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not delete file from database",
+			Err:           err,
+		})
+		return
+	}
+	toDelete.Path = "" // already removed
+	for _, file := range append(versions, toDelete) {
+		removeAttachmentFiles(file)
+	}
+}
+
+// attachment returns the attachment with the id in the path if it belongs to the stream
+func (r streamRoutes) attachment(c *gin.Context) (model.File, bool) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	file, err := r.FileDao.GetFileById(c.Param("fid"))
+	if err == nil && (file.StreamID != tumLiveContext.Stream.ID || file.Type != model.FILETYPE_ATTACHMENT) {
+		err = fmt.Errorf("file %d is no attachment of stream %d", file.ID, tumLiveContext.Stream.ID)
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not find file",
+			Err:           err,
+		})
+		return model.File{}, false
+	}
+	return file, true
+}
+
+// removeAttachmentFiles removes the uploaded file and the preview of an attachment from the disk
+func removeAttachmentFiles(file model.File) {
+	paths := []string{file.PreviewPath}
+	if file.Path != "" && !file.IsURL() {
+		paths = append(paths, file.Path)
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logger.Error("can not delete file with path: "+path, "err", err)
+		}
+	}
+}
+
+// generateAttachmentPreview renders the first page of pdf attachments to an image with pdftoppm (poppler-utils)
+func generateAttachmentPreview(fileDao dao.FileDao, file model.File) {
+	if !file.IsPDF() {
+		return
+	}
+	prefix := strings.TrimSuffix(file.Path, filepath.Ext(file.Path)) + "_preview"
+	out, err := exec.Command("pdftoppm", "-png", "-singlefile", "-f", "1", "-l", "1",
+		"-scale-to", strconv.Itoa(attachmentPreviewWidth), file.Path, prefix).CombinedOutput()
+	if err != nil {
+		logger.Warn("can not render preview of attachment", "file", file.ID, "err", err, "output", string(out))
+		return
+	}
+	if err = fileDao.SetPreviewPath(file, prefix+".png"); err != nil {
+		logger.Error("can not save preview of attachment", "file", file.ID, "err", err)
+	}
+}
+
+// attachmentsZipName returns the unique name of an attachment in the zip of all attachments of a course
+func attachmentsZipName(index int, stream model.Stream, file model.File, used map[string]int) string {
+	lecture := lectureName(stream)
+	name := file.Filename
+	if name == "" {
+		name = file.GetDownloadFileName()
+	}
+	path := fmt.Sprintf("%02d %s/%s", index, cleanFileName(lecture), cleanFileName(name))
+	used[path]++
+	if used[path] > 1 {
+		ext := filepath.Ext(path)
+		path = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(path, ext), used[path], ext)
+	}
+	return path
+}
+
+// lectureName returns the name of the lecture or its date if it has none
+func lectureName(stream model.Stream) string {
+	if stream.Name == "" {
+		return stream.Start.In(tools.Loc).Format("2006-01-02")
+	}
+	return stream.Name
+}
+
+// cleanFileName replaces the characters that aren't allowed in file names on common systems
+func cleanFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// downloadCourseAttachments sends a zip with the current version of the attachments of all lectures of the course.
+// Links are collected in links.txt.
+func (r downloadRoutes) downloadCourseAttachments(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := *tumLiveContext.Course
+	if !mayDownload(tumLiveContext, course) {
+		_ = c.Error(dlErr)
+		return
+	}
+
+	streams := slices.Clone(course.Streams)
+	slices.SortFunc(streams, func(a, b model.Stream) int { return a.Start.Compare(b.Start) })
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-attachments.zip", course.Slug))
+	w := zip.NewWriter(c.Writer)
+	used := make(map[string]int)
+	var links strings.Builder
+	index := 0
+	for _, stream := range streams {
+		attachments := stream.Attachments()
+		if stream.Private || len(attachments) == 0 {
+			continue
+		}
+		index++
+		for _, file := range attachments {
+			name := attachmentsZipName(index, stream, file, used)
+			if file.IsURL() {
+				links.WriteString(fmt.Sprintf("%s: %s\n", strings.TrimSuffix(name, filepath.Ext(name)), file.Path))
+				continue
+			}
+			if err := addFileToZip(w, name, file.Path); err != nil {
+				logger.Error("can not add attachment to zip", "file", file.ID, "err", err)
+			}
+		}
+	}
+	if links.Len() > 0 {
+		if f, err := w.Create("links.txt"); err == nil {
+			_, _ = io.WriteString(f, links.String())
+		}
+	}
+	if err := w.Close(); err != nil {
+		logger.Error("can not write attachments zip", "err", err)
+	}
+}
+
+func addFileToZip(w *zip.Writer, name string, path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/TUM-Dev/gocast/model"
+)
+
+func TestAttachmentsZipName(t *testing.T) {
+	used := make(map[string]int)
+	stream := model.Stream{Name: "Intro: Sets/Maps"}
+
+	if name := attachmentsZipName(1, stream, model.File{Filename: "slides.pdf"}, used); name != "01 Intro_ Sets_Maps/slides.pdf" {
+		t.Errorf("unexpected name %s", name)
+	}
+	if name := attachmentsZipName(1, stream, model.File{Filename: "slides.pdf"}, used); name != "01 Intro_ Sets_Maps/slides (2).pdf" {
+		t.Errorf("expected duplicate to be numbered, got %s", name)
+	}
+	if name := attachmentsZipName(12, stream, model.File{Filename: "slides.pdf"}, used); name != "12 Intro_ Sets_Maps/slides.pdf" {
+		t.Errorf("unexpected name %s", name)
+	}
+}
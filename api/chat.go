@@ -159,7 +159,7 @@ func (r chatRoutes) handleStartPoll(ctx tools.TUMLiveContext, msg []byte) {
 		logger.Warn("could not unmarshal start poll request", "err", err)
 		return
 	}
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionManagePolls) {
 		return
 	}
 
@@ -208,7 +208,7 @@ func (r chatRoutes) handleStartPoll(ctx tools.TUMLiveContext, msg []byte) {
 }
 
 func (r chatRoutes) handleCloseActivePoll(ctx tools.TUMLiveContext) {
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionManagePolls) {
 		return
 	}
 
@@ -245,7 +245,7 @@ func (r chatRoutes) handleResolve(ctx tools.TUMLiveContext, msg []byte) {
 		logger.Warn("could not unmarshal message delete request", "err", err)
 		return
 	}
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionModerateChat) {
 		return
 	}
 
@@ -272,7 +272,7 @@ func (r chatRoutes) handleDelete(ctx tools.TUMLiveContext, msg []byte) {
 		logger.Warn("could not unmarshal message delete request", "err", err)
 		return
 	}
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionModerateChat) {
 		return
 	}
 	err = r.ChatDao.DeleteChat(req.Id)
@@ -297,7 +297,7 @@ func (r chatRoutes) handleApprove(ctx tools.TUMLiveContext, msg []byte) {
 		logger.Warn("could not unmarshal message approve request", "err", err)
 		return
 	}
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionModerateChat) {
 		return
 	}
 
@@ -369,7 +369,7 @@ func (r chatRoutes) handleRetract(ctx tools.TUMLiveContext, msg []byte) {
 		logger.Warn("could not unmarshal message retract request", "err", err)
 		return
 	}
-	if ctx.User == nil || !ctx.User.IsAdminOfCourse(*ctx.Course) {
+	if !ctx.UserHasCoursePermission(model.CoursePermissionModerateChat) {
 		return
 	}
 
@@ -424,9 +424,10 @@ func (r chatRoutes) handleMessage(ctx tools.TUMLiveContext, context *realtime.Co
 	}
 
 	isAdmin := ctx.User.IsAdminOfCourse(*ctx.Course)
+	canModerate := ctx.UserHasCoursePermission(model.CoursePermissionModerateChat)
 
 	isVisible := sql.NullBool{Valid: true, Bool: true}
-	if ctx.Course.ModeratedChatEnabled && !isAdmin {
+	if ctx.Course.ModeratedChatEnabled && !canModerate {
 		isVisible.Bool = false
 	}
 	chatForDb := model.Chat{
@@ -450,9 +451,9 @@ func (r chatRoutes) handleMessage(ctx tools.TUMLiveContext, context *realtime.Co
 	}
 
 	if msg, err := json.Marshal(chatForDb); err == nil {
-		if ctx.Course.ModeratedChatEnabled && !isAdmin {
+		if ctx.Course.ModeratedChatEnabled && !canModerate {
 			_ = context.Send(msg)                       // send message back to sender
-			broadcastStreamToAdmins(ctx.Stream.ID, msg) // send message to course admins
+			broadcastStreamToAdmins(ctx.Stream.ID, msg) // send message to course admins and moderators
 		} else {
 			broadcastStream(ctx.Stream.ID, msg)
 		}
@@ -470,16 +471,16 @@ func (r chatRoutes) getMessages(c *gin.Context) {
 	}
 	tumLiveContext := foundContext.(tools.TUMLiveContext)
 
-	isAdmin := false
+	canModerate := false
 	var uid uint = 0 // 0 = not logged in. -> doesn't match a user
 	if tumLiveContext.User != nil {
 		uid = tumLiveContext.User.ID
-		isAdmin = tumLiveContext.User.IsAdminOfCourse(*tumLiveContext.Course)
+		canModerate = tumLiveContext.UserHasCoursePermission(model.CoursePermissionModerateChat)
 	}
 
 	var err error
 	var chats []model.Chat
-	if isAdmin {
+	if canModerate {
 		chats, err = r.ChatDao.GetAllChats(uid, tumLiveContext.Stream.ID)
 	} else {
 		chats, err = r.ChatDao.GetVisibleChats(uid, tumLiveContext.Stream.ID)
@@ -568,12 +569,12 @@ func (r chatRoutes) getActivePoll(c *gin.Context) {
 		return
 	}
 
-	isAdminOfCourse := tumLiveContext.User.IsAdminOfCourse(*tumLiveContext.Course)
+	canManagePolls := tumLiveContext.UserHasCoursePermission(model.CoursePermissionManagePolls)
 	var pollOptions []gin.H
 	for _, option := range poll.PollOptions {
 		voteCount := int64(0)
 
-		if isAdminOfCourse {
+		if canManagePolls {
 			voteCount, err = r.ChatDao.GetPollOptionVoteCount(option.ID)
 			if err != nil {
 				logger.Warn("could not get poll option vote count", "err", err)
@@ -692,6 +693,12 @@ func NotifyViewersLiveState(streamId uint, live bool) {
 	broadcastStream(streamId, req)
 }
 
+// NotifyViewersVodPublished tells viewers of a premiere that it can now be watched as VoD
+func NotifyViewersVodPublished(streamId uint) {
+	req, _ := json.Marshal(gin.H{"vod": true})
+	broadcastStream(streamId, req)
+}
+
 func chatOnSubscribe(psc *realtime.Context) {
 	joinTime := time.Now()
 	psc.Set("chat.joinTime", joinTime)
@@ -724,15 +731,29 @@ func chatOnUnsubscribe(psc *realtime.Context) {
 		return
 	}
 
-	defer afterUnsubscribe(psc.Param("streamID"), joinTime, tumLiveContext.Stream.Recording, daoWrapper)
+	var userID *uint
+	if tumLiveContext.User != nil {
+		userID = &tumLiveContext.User.ID
+	}
+	defer afterUnsubscribe(*tumLiveContext.Stream, userID, joinTime, daoWrapper)
 }
 
-func afterUnsubscribe(id string, joinTime time.Time, recording bool, daoWrapper dao.DaoWrapper) {
+func afterUnsubscribe(stream model.Stream, userID *uint, joinTime time.Time, daoWrapper dao.DaoWrapper) {
 	// watched at least 5 minutes of the lecture and stream is VoD? Count as view.
-	if recording && joinTime.Before(time.Now().Add(time.Minute*-5)) {
-		err := daoWrapper.AddVodView(id)
+	if stream.Recording && joinTime.Before(time.Now().Add(time.Minute*-5)) {
+		err := daoWrapper.AddVodView(fmt.Sprintf("%d", stream.ID))
 		if err != nil {
 			logger.Error("Can't save vod view", "err", err)
 		}
 	}
+	err := daoWrapper.AddViewSession(model.ViewSession{
+		StreamID: stream.ID,
+		UserID:   userID,
+		JoinedAt: joinTime,
+		LeftAt:   time.Now(),
+		Live:     !stream.Recording,
+	})
+	if err != nil {
+		logger.Error("Can't save view session", "err", err)
+	}
 }
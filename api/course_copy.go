@@ -0,0 +1,393 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// Parts of a course that can be copied. The parts of lectures are only copied along with the lectures.
+const (
+	copyLectures    = "lectures"    // the lectures, as planned lectures without recordings
+	copyRecordings  = "recordings"  // the recordings of the lectures
+	copySections    = "sections"    // the video sections of the lectures
+	copyAttachments = "attachments" // the files attached to the lectures
+	copyUnits       = "units"       // the units the lectures are split into
+	copySubtitles   = "subtitles"   // the subtitles of the lectures
+	copyAdmins      = "admins"      // the admins of the course
+	copySettings    = "settings"    // the settings of the course like visibility, chat and camera presets
+)
+
+var courseCopyParts = []string{copyLectures, copyRecordings, copySections, copyAttachments, copyUnits, copySubtitles, copyAdmins, copySettings}
+
+type copyCourseRequest struct {
+	Semester string
+	Year     string
+	YearW    string
+
+	// Parts to copy, everything if empty
+	Parts []string `json:"parts"`
+	// FirstLecture moves the lectures by whole weeks so that the first one is in the week of this date (YYYY-MM-DD)
+	FirstLecture string `json:"firstLecture"`
+	// DryRun only returns what would be copied
+	DryRun bool `json:"dryRun"`
+	// Force copies lectures even if their lecture halls are already booked
+	Force bool `json:"force"`
+}
+
+type copiedLecture struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// courseCopyPreview summarizes a copy of a course
+type courseCopyPreview struct {
+	Name         string          `json:"name"`
+	Year         int             `json:"year"`
+	TeachingTerm string          `json:"teachingTerm"`
+	Lectures     []copiedLecture `json:"lectures"`
+	Sections     int             `json:"sections"`
+	Attachments  int             `json:"attachments"`
+	Units        int             `json:"units"`
+	Subtitles    int             `json:"subtitles"`
+	Admins       int             `json:"admins"`
+	Conflicts    []string        `json:"conflicts"` // booking conflicts of the lecture halls of the copied lectures
+}
+
+// parts returns the parts to copy
+func (r copyCourseRequest) parts() (map[string]bool, error) {
+	parts := make(map[string]bool)
+	if len(r.Parts) == 0 {
+		r.Parts = courseCopyParts
+	}
+	for _, part := range r.Parts {
+		if !slices.Contains(courseCopyParts, part) {
+			return nil, fmt.Errorf("unknown part %s", part)
+		}
+		parts[part] = true
+	}
+	return parts, nil
+}
+
+// semester returns the semester to copy the course to
+func (r copyCourseRequest) semester() (year int, term string, err error) {
+	year, err = strconv.Atoi(r.Year)
+	if err != nil {
+		return 0, "", errors.New("year must be a number")
+	}
+	switch r.Semester {
+	case "Sommersemester":
+		return year, "S", nil
+	case "Wintersemester":
+		return year, "W", nil
+	}
+	return 0, "", errors.New("semester must be 'Sommersemester' or 'Wintersemester'")
+}
+
+// lectureShift returns the number of days lectures are moved so that the first one is in the week of firstLecture
+func lectureShift(streams []model.Stream, firstLecture string) (int, error) {
+	if firstLecture == "" || len(streams) == 0 {
+		return 0, nil
+	}
+	target, err := time.ParseInLocation(time.DateOnly, firstLecture, tools.Loc)
+	if err != nil {
+		return 0, err
+	}
+	first := streams[0].Start
+	for _, stream := range streams {
+		if stream.Start.Before(first) {
+			first = stream.Start
+		}
+	}
+	first = first.In(tools.Loc)
+	monday := func(t time.Time) time.Time {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.UTC)
+		return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	}
+	return int(monday(target).Sub(monday(first)).Hours() / 24), nil
+}
+
+// planCourseCopy returns the copy of course for the semester and with the parts of the request
+func planCourseCopy(course model.Course, streams []model.Stream, subtitles map[uint][]model.Subtitles, admins []model.User, request copyCourseRequest) (dao.CourseCopy, error) {
+	parts, err := request.parts()
+	if err != nil {
+		return dao.CourseCopy{}, err
+	}
+	year, term, err := request.semester()
+	if err != nil {
+		return dao.CourseCopy{}, err
+	}
+	shift, err := lectureShift(streams, request.FirstLecture)
+	if err != nil {
+		return dao.CourseCopy{}, errors.New("first lecture must be a date like 2006-01-02")
+	}
+
+	course.Model = gorm.Model{}
+	course.Year, course.TeachingTerm = year, term
+	course.Streams, course.Users, course.Admins = nil, nil, nil
+	if !parts[copySettings] {
+		// zero values are replaced by the defaults of the columns
+		course.VODEnabled, course.DownloadsEnabled, course.Visibility = false, false, ""
+		course.ChatEnabled, course.AnonymousChatEnabled, course.ModeratedChatEnabled, course.VodChatEnabled = false, false, false, false
+		course.CameraPresetPreferences, course.SourcePreferences = "", ""
+		course.LivePrivate, course.VodPrivate = false, false
+		course.LivePlayback, course.DvrEnabled, course.DvrWindow = "", false, 0
+	}
+	res := dao.CourseCopy{Course: course}
+	if parts[copyAdmins] {
+		for _, admin := range admins {
+			res.AdminIDs = append(res.AdminIDs, admin.ID)
+		}
+	}
+	if !parts[copyLectures] {
+		return res, nil
+	}
+	for _, stream := range streams {
+		copied := model.Stream{
+			Name:             stream.Name,
+			Description:      stream.Description,
+			Start:            stream.Start.In(tools.Loc).AddDate(0, 0, shift),
+			End:              stream.End.In(tools.Loc).AddDate(0, 0, shift),
+			ChatEnabled:      stream.ChatEnabled,
+			RoomName:         stream.RoomName,
+			RoomCode:         stream.RoomCode,
+			EventTypeName:    stream.EventTypeName,
+			TUMOnlineEventID: stream.TUMOnlineEventID,
+			SeriesIdentifier: stream.SeriesIdentifier,
+			StreamKey:        strings.ReplaceAll(uuid.NewV4().String(), "-", ""),
+			LectureHallID:    stream.LectureHallID,
+			StreamName:       stream.StreamName,
+			Private:          stream.Private,
+		}
+		if parts[copyRecordings] {
+			copied.PlaylistUrl, copied.PlaylistUrlPRES, copied.PlaylistUrlCAM = stream.PlaylistUrl, stream.PlaylistUrlPRES, stream.PlaylistUrlCAM
+			copied.Recording, copied.Ended, copied.Duration = stream.Recording, stream.Ended, stream.Duration
+			copied.StartOffset, copied.EndOffset, copied.ThumbInterval = stream.StartOffset, stream.EndOffset, stream.ThumbInterval
+		} else {
+			copied.Duration = sql.NullInt32{}
+		}
+		if parts[copyUnits] {
+			for _, unit := range stream.Units {
+				unit.Model, unit.StreamID = gorm.Model{}, 0
+				copied.Units = append(copied.Units, unit)
+			}
+		}
+		if parts[copyAttachments] {
+			for _, file := range stream.Files {
+				if file.Type != model.FILETYPE_ATTACHMENT {
+					continue // recordings, images and old versions of attachments belong to the original
+				}
+				// sections get new ids and previews are generated for the copied file, see generateCopiedAttachmentPreviews
+				file.Model, file.StreamID, file.VideoSectionID, file.PreviewPath = gorm.Model{}, 0, nil, ""
+				copied.Files = append(copied.Files, file)
+			}
+		}
+		if parts[copySections] {
+			for _, section := range stream.VideoSections {
+				// the thumbnails of sections are regenerated for the recording of the copy, see generateCopiedSectionImages
+				section.Model, section.StreamID, section.FileID = gorm.Model{}, 0, 0
+				copied.VideoSections = append(copied.VideoSections, section)
+			}
+		}
+		streamCopy := dao.StreamCopy{Stream: copied}
+		if parts[copySubtitles] {
+			for _, s := range subtitles[stream.ID] {
+				streamCopy.Subtitles = append(streamCopy.Subtitles, model.Subtitles{Content: s.Content, Language: s.Language, Edited: s.Edited})
+			}
+		}
+		res.Streams = append(res.Streams, streamCopy)
+	}
+	return res, nil
+}
+
+func (p *courseCopyPreview) add(c dao.CourseCopy) {
+	p.Name, p.Year, p.TeachingTerm = c.Course.Name, c.Course.Year, c.Course.TeachingTerm
+	p.Conflicts = []string{}
+	p.Admins = len(c.AdminIDs)
+	for _, s := range c.Streams {
+		p.Lectures = append(p.Lectures, copiedLecture{s.Stream.Name, s.Stream.Start, s.Stream.End})
+		p.Sections += len(s.Stream.VideoSections)
+		p.Attachments += len(s.Stream.Files)
+		p.Units += len(s.Stream.Units)
+		p.Subtitles += len(s.Subtitles)
+	}
+}
+
+// copyBookingConflicts returns the booking conflicts of the lecture halls of the copied lectures
+func copyBookingConflicts(lectureHallsDao dao.LectureHallsDao, c dao.CourseCopy) ([]string, error) {
+	byLectureHall := make(map[uint][]model.Stream)
+	var lectureHalls []uint
+	for _, s := range c.Streams {
+		if s.Stream.LectureHallID == 0 {
+			continue
+		}
+		if _, ok := byLectureHall[s.Stream.LectureHallID]; !ok {
+			lectureHalls = append(lectureHalls, s.Stream.LectureHallID)
+		}
+		byLectureHall[s.Stream.LectureHallID] = append(byLectureHall[s.Stream.LectureHallID], s.Stream)
+	}
+	conflicts := []string{}
+	for _, id := range lectureHalls {
+		res, err := findBookingConflicts(lectureHallsDao, id, byLectureHall[id])
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, res...)
+	}
+	return conflicts, nil
+}
+
+// generateCopiedSectionImages generates the thumbnails of the sections of copied recordings
+func generateCopiedSectionImages(daoWrapper dao.DaoWrapper, c dao.CourseCopy) {
+	for _, s := range c.Streams {
+		stream := s.Stream
+		if !stream.Recording || len(stream.VideoSections) == 0 {
+			continue
+		}
+		if err := tools.SetSignedPlaylists(&stream, nil, false); err != nil {
+			logger.Error("can not set signed playlists of copied lecture", "err", err, "stream", stream.ID)
+			continue
+		}
+		parameters := generateVideoSectionImagesParameters{
+			sections:           stream.VideoSections,
+			playlistUrl:        stream.PlaylistUrl,
+			courseName:         c.Course.Name,
+			courseTeachingTerm: c.Course.TeachingTerm,
+			courseYear:         uint32(c.Course.Year),
+		}
+		if err := GenerateVideoSectionImages(daoWrapper, &parameters); err != nil {
+			logger.Error("failed to generate video section images of copied lecture", "err", err, "stream", stream.ID)
+		}
+	}
+}
+
+// generateCopiedAttachmentPreviews renders the previews of the copied pdf attachments
+func generateCopiedAttachmentPreviews(fileDao dao.FileDao, c dao.CourseCopy) {
+	for _, s := range c.Streams {
+		for _, file := range s.Stream.Files {
+			generateAttachmentPreview(fileDao, file)
+		}
+	}
+}
+
+// copyAttachmentFiles copies the uploaded attachments of the copy to its course's folder so deleting them in one
+// course doesn't affect the other. It returns the paths of the copies.
+func copyAttachmentFiles(c *dao.CourseCopy) (copies []string, err error) {
+	folder := fmt.Sprintf("%s/%s.%d/%s.%s/files", tools.Cfg.Paths.Mass, c.Course.Name, c.Course.Year, c.Course.Name, c.Course.TeachingTerm)
+	for i := range c.Streams {
+		for j := range c.Streams[i].Stream.Files {
+			file := &c.Streams[i].Stream.Files[j]
+			if file.IsURL() {
+				continue
+			}
+			path := fmt.Sprintf("%s/%s%s", folder, uuid.NewV1(), filepath.Ext(file.Path))
+			if err = copyFile(file.Path, path); err != nil {
+				return copies, err
+			}
+			copies = append(copies, path)
+			file.Path = path
+		}
+	}
+	return copies, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err = os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyCourse copies the course to another semester. The copy is created completely or not at all.
+func (r coursesRoutes) copyCourse(c *gin.Context) {
+	var request copyCourseRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "Bad request", Err: err})
+		return
+	}
+	if _, err = request.parts(); err == nil {
+		_, _, err = request.semester()
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: err.Error(), Err: err})
+		return
+	}
+	tlctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := *tlctx.Course
+
+	admins, err := r.CoursesDao.GetCourseAdmins(course.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "Can't get course admins", Err: err})
+		return
+	}
+	streams, subtitles, err := r.CoursesDao.GetCopySource(c, course.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "Can't get lectures", Err: err})
+		return
+	}
+	courseCopy, err := planCourseCopy(course, streams, subtitles, admins, request)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: err.Error(), Err: err})
+		return
+	}
+	var preview courseCopyPreview
+	preview.add(courseCopy)
+	preview.Conflicts, err = copyBookingConflicts(r.LectureHallsDao, courseCopy)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "Can't check for booking conflicts", Err: err})
+		return
+	}
+	if request.DryRun {
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+	if len(preview.Conflicts) > 0 && !request.Force {
+		_ = c.Error(bookingConflictError(preview.Conflicts))
+		return
+	}
+
+	files, err := copyAttachmentFiles(&courseCopy)
+	if err == nil {
+		err = r.CoursesDao.CopyCourse(c, &courseCopy)
+	}
+	if err != nil {
+		for _, file := range files {
+			_ = os.Remove(file)
+		}
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "Can't copy course", Err: err})
+		return
+	}
+	go generateCopiedSectionImages(r.DaoWrapper, courseCopy)
+	go generateCopiedAttachmentPreviews(r.FileDao, courseCopy)
+	c.JSON(http.StatusOK, gin.H{"newCourse": courseCopy.Course.ID, "copied": preview})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"gorm.io/gorm"
+)
+
+func TestLectureShift(t *testing.T) {
+	tools.Loc, _ = time.LoadLocation("Europe/Berlin")
+	// Thursday before the change to summer time
+	first := time.Date(2023, 3, 23, 14, 15, 0, 0, tools.Loc)
+	streams := []model.Stream{{Start: first.AddDate(0, 0, 7)}, {Start: first}}
+	tests := map[string]int{
+		"":           0,
+		"2024-04-15": 392, // Monday, lands on Thursday 2024-04-18
+		"2024-04-21": 392, // Sunday of the same week
+		"2023-03-20": 0,
+		"2023-03-13": -7,
+	}
+	for date, expected := range tests {
+		shift, err := lectureShift(streams, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shift != expected {
+			t.Errorf("%s: expected shift of %d days, got %d", date, expected, shift)
+		}
+	}
+	if _, err := lectureShift(streams, "15.04.2024"); err == nil {
+		t.Error("expected invalid date to be rejected")
+	}
+
+	shifted := first.AddDate(0, 0, 392)
+	if shifted.Hour() != 14 || shifted.Weekday() != time.Thursday {
+		t.Errorf("expected lecture to keep its time and weekday, got %v", shifted)
+	}
+}
+
+func TestPlanCourseCopy(t *testing.T) {
+	tools.Loc, _ = time.LoadLocation("Europe/Berlin")
+	course := testutils.CourseFPV
+	course.CameraPresetPreferences = `[{"lectureHallID":1,"presetID":4}]`
+	stream := testutils.StreamFPVLive
+	attachmentID := uint(4)
+	stream.Units = []model.StreamUnit{{Model: gorm.Model{ID: 3}, UnitName: "Intro", StreamID: stream.ID}}
+	stream.Files = []model.File{
+		{Model: gorm.Model{ID: 4}, Path: "https://example.com/slides.pdf", StreamID: stream.ID, Type: model.FILETYPE_ATTACHMENT},
+		{Model: gorm.Model{ID: 8}, Path: "/mass/vod.mp4", StreamID: stream.ID, Type: model.FILETYPE_VOD},
+		{Model: gorm.Model{ID: 9}, Path: "/mass/section.jpg", StreamID: stream.ID, Type: model.FILETYPE_IMAGE_JPG},
+		{Model: gorm.Model{ID: 10}, Path: "/mass/slides-v1.pdf", StreamID: stream.ID, Type: model.FILETYPE_ATTACHMENT_VERSION, AttachmentID: &attachmentID},
+	}
+	stream.VideoSections = []model.VideoSection{{Model: gorm.Model{ID: 5}, Description: "Recap", StreamID: stream.ID, FileID: 6}}
+	subtitles := map[uint][]model.Subtitles{stream.ID: {{Model: gorm.Model{ID: 7}, StreamID: stream.ID, Language: "en", Content: "WEBVTT"}}}
+	admins := []model.User{testutils.Admin}
+
+	t.Run("everything", func(t *testing.T) {
+		c, err := planCourseCopy(course, []model.Stream{stream}, subtitles, admins, copyCourseRequest{Year: "2024", Semester: "Wintersemester"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Course.ID != 0 || c.Course.Year != 2024 || c.Course.TeachingTerm != "W" || c.Course.CameraPresetPreferences != course.CameraPresetPreferences {
+			t.Errorf("unexpected course %+v", c.Course)
+		}
+		if len(c.AdminIDs) != 1 || c.AdminIDs[0] != testutils.Admin.ID {
+			t.Errorf("expected admins to be copied, got %v", c.AdminIDs)
+		}
+		if len(c.Streams) != 1 {
+			t.Fatalf("expected one lecture, got %d", len(c.Streams))
+		}
+		s := c.Streams[0]
+		if s.Stream.ID != 0 || s.Stream.StreamKey == stream.StreamKey || !s.Stream.Start.Equal(stream.Start) {
+			t.Errorf("unexpected lecture %+v", s.Stream)
+		}
+		if len(s.Stream.Units) != 1 || s.Stream.Units[0].ID != 0 || s.Stream.Units[0].StreamID != 0 {
+			t.Errorf("expected units to be copied, got %+v", s.Stream.Units)
+		}
+		if len(s.Stream.Files) != 1 || s.Stream.Files[0].ID != 0 || s.Stream.Files[0].Path != stream.Files[0].Path {
+			t.Errorf("expected only attachments to be copied, got %+v", s.Stream.Files)
+		}
+		if len(s.Stream.VideoSections) != 1 || s.Stream.VideoSections[0].ID != 0 || s.Stream.VideoSections[0].FileID != 0 {
+			t.Errorf("expected sections to be copied, got %+v", s.Stream.VideoSections)
+		}
+		if len(s.Subtitles) != 1 || s.Subtitles[0].ID != 0 || s.Subtitles[0].Language != "en" {
+			t.Errorf("expected subtitles to be copied, got %+v", s.Subtitles)
+		}
+	})
+
+	t.Run("selected parts", func(t *testing.T) {
+		c, err := planCourseCopy(course, []model.Stream{stream}, subtitles, admins, copyCourseRequest{
+			Year: "2024", Semester: "Sommersemester", Parts: []string{copyLectures, copyUnits},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Course.CameraPresetPreferences != "" || len(c.AdminIDs) != 0 {
+			t.Errorf("expected settings and admins not to be copied, got %+v", c)
+		}
+		s := c.Streams[0]
+		if len(s.Stream.Units) != 1 || len(s.Stream.Files) != 0 || len(s.Stream.VideoSections) != 0 || len(s.Subtitles) != 0 {
+			t.Errorf("expected only units to be copied, got %+v", s)
+		}
+		if s.Stream.PlaylistUrl != "" || s.Stream.Recording {
+			t.Errorf("expected a planned lecture without recording, got %+v", s.Stream)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, request := range map[string]copyCourseRequest{
+			"part":     {Year: "2024", Semester: "Sommersemester", Parts: []string{"chats"}},
+			"year":     {Year: "next", Semester: "Sommersemester"},
+			"semester": {Year: "2024", Semester: "Herbst"},
+			"date":     {Year: "2024", Semester: "Sommersemester", FirstLecture: "soon"},
+		} {
+			if _, err := planCourseCopy(course, []model.Stream{stream}, subtitles, admins, request); err == nil {
+				t.Errorf("%s: expected request to be rejected", name)
+			}
+		}
+	})
+}
+
+func TestCopyBookingConflicts(t *testing.T) {
+	start := time.Date(2024, 4, 18, 14, 15, 0, 0, time.UTC)
+	c := dao.CourseCopy{Streams: []dao.StreamCopy{
+		{Stream: model.Stream{LectureHallID: testutils.LectureHall.ID, Start: start, End: start.Add(90 * time.Minute)}},
+		{Stream: model.Stream{Start: start, End: start.Add(90 * time.Minute)}},
+	}}
+
+	conflicts, err := copyBookingConflicts(lectureHallMockWithBookings(t, nil), c)
+	if err != nil || len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v, %v", conflicts, err)
+	}
+
+	booking := dao.Booking{CourseName: "Other", LectureHallName: testutils.LectureHall.Name, Start: start, End: start.Add(time.Hour)}
+	conflicts, err = copyBookingConflicts(lectureHallMockWithBookings(t, []dao.Booking{booking}), c)
+	if err != nil || len(conflicts) != 1 {
+		t.Errorf("expected the lecture in the lecture hall to conflict, got %v, %v", conflicts, err)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+)
+
+type courseRoleDto struct {
+	userForLecturerDto
+	Role model.CourseRoleType `json:"role"`
+}
+
+// getRoles returns the owner, admins and other roles of the course
+func (r coursesRoutes) getRoles(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := tumLiveContext.Course
+	admins, err := r.CoursesDao.GetCourseAdmins(course.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "could not get course admins", Err: err})
+		return
+	}
+	roles, err := r.CourseRolesDao.GetCourseRoles(c, course.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "could not get course roles", Err: err})
+		return
+	}
+
+	res := []courseRoleDto{}
+	seen := make(map[uint]bool)
+	add := func(u model.User, role model.CourseRoleType) {
+		if seen[u.ID] {
+			return
+		}
+		seen[u.ID] = true
+		res = append(res, courseRoleDto{userForLecturerDto{ID: u.ID, Name: u.Name, LastName: u.LastName, Login: u.GetLoginString()}, role})
+	}
+	if owner, err := r.UsersDao.GetUserByID(c, course.UserID); err == nil && owner.ID != 0 {
+		add(owner, model.CourseRoleOwner)
+	}
+	for _, admin := range admins {
+		add(admin, model.CourseRoleAdmin)
+	}
+	for _, role := range roles {
+		add(role.User, role.Role) // admins keep their role if they had one before
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// setRole makes a user moderator or viewer of the course
+func (r coursesRoutes) setRole(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := tumLiveContext.Course
+	var req struct {
+		Role model.CourseRoleType `json:"role"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid body", Err: err})
+		return
+	}
+	if !req.Role.IsAssignable() {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "role must be moderator or viewer"})
+		return
+	}
+	user, ok := r.roleUser(c)
+	if !ok {
+		return
+	}
+	if role, ok := user.GetCourseRole(*course); ok && (role == model.CourseRoleOwner || role == model.CourseRoleAdmin) {
+		_ = c.Error(tools.RequestError{Status: http.StatusConflict, CustomMessage: "user is an admin of the course, remove them from the admins first"})
+		return
+	}
+
+	if err := r.CourseRolesDao.SetCourseRole(c, model.CourseRole{UserID: user.ID, CourseID: course.ID, Role: req.Role}); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "could not set role", Err: err})
+		return
+	}
+	r.auditCourseChange(tumLiveContext, fmt.Sprintf("%s:'%s' %s: %s (%d)", course.Name, course.Slug, req.Role, user.GetPreferredName(), user.ID))
+	c.JSON(http.StatusOK, courseRoleDto{userForLecturerDto{ID: user.ID, Name: user.Name, LastName: user.LastName, Login: user.GetLoginString()}, req.Role})
+}
+
+// removeRole removes the moderator or viewer role of a user
+func (r coursesRoutes) removeRole(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := tumLiveContext.Course
+	user, ok := r.roleUser(c)
+	if !ok {
+		return
+	}
+	if err := r.CourseRolesDao.RemoveCourseRole(c, course.ID, user.ID); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "could not remove role", Err: err})
+		return
+	}
+	r.auditCourseChange(tumLiveContext, fmt.Sprintf("%s:'%s' remove role: %s (%d)", course.Name, course.Slug, user.GetPreferredName(), user.ID))
+	c.Status(http.StatusOK)
+}
+
+// roleUser returns the user whose role is changed
+func (r coursesRoutes) roleUser(c *gin.Context) (model.User, bool) {
+	id, err := strconv.ParseUint(c.Param("userID"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid userID", Err: err})
+		return model.User{}, false
+	}
+	user, err := r.UsersDao.GetUserByID(c, uint(id))
+	if err == nil && user.ID == 0 {
+		err = errors.New("user not found")
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusNotFound, CustomMessage: "can not find user", Err: err})
+		return model.User{}, false
+	}
+	return user, true
+}
+
+func (r coursesRoutes) auditCourseChange(ctx tools.TUMLiveContext, message string) {
+	if err := r.AuditDao.Create(&model.Audit{User: ctx.User, Message: message, Type: model.AuditCourseEdit}); err != nil {
+		logger.Error("Create Audit:", "err", err)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+func TestCourseRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	owner := model.User{Model: gorm.Model{ID: testutils.CourseFPV.UserID}, Name: "Owner", LrzID: "ow12ner"}
+	tutor := model.User{Model: gorm.Model{ID: 12}, Name: "Tutor", LrzID: "tu12tor"}
+	courseAdmin := model.User{Model: gorm.Model{ID: 20}, Role: model.LecturerType, AdministeredCourses: []model.Course{testutils.CourseFPV}}
+	moderatorContext := tools.TUMLiveContext{User: &model.User{
+		Model:       gorm.Model{ID: tutor.ID},
+		Role:        model.StudentType,
+		CourseRoles: []model.CourseRole{{UserID: tutor.ID, CourseID: testutils.CourseFPV.ID, Role: model.CourseRoleModerator}},
+	}}
+
+	t.Run("GET/api/course/:courseID/roles", func(t *testing.T) {
+		gomino.TestCases{
+			"moderator": {
+				Router: func(r *gin.Engine) {
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(moderatorContext)),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					ctrl := gomock.NewController(t)
+					usersMock := mock_dao.NewMockUsersDao(ctrl)
+					usersMock.EXPECT().GetUserByID(gomock.Any(), owner.ID).Return(owner, nil)
+					rolesMock := mock_dao.NewMockCourseRolesDao(ctrl)
+					rolesMock.EXPECT().GetCourseRoles(gomock.Any(), testutils.CourseFPV.ID).Return([]model.CourseRole{
+						{UserID: tutor.ID, CourseID: testutils.CourseFPV.ID, Role: model.CourseRoleModerator, User: tutor},
+					}, nil)
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t), UsersDao: usersMock, CourseRolesDao: rolesMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: []courseRoleDto{
+					{userForLecturerDto{ID: owner.ID, Name: owner.Name, Login: owner.LrzID}, model.CourseRoleOwner},
+					{userForLecturerDto{ID: testutils.Admin.ID, Name: testutils.Admin.Name, Login: testutils.Admin.GetLoginString()}, model.CourseRoleAdmin},
+					{userForLecturerDto{ID: tutor.ID, Name: tutor.Name, Login: tutor.LrzID}, model.CourseRoleModerator},
+				},
+			},
+		}.Method(http.MethodGet).Url(fmt.Sprintf("/api/course/%d/roles", testutils.CourseFPV.ID)).Run(t, testutils.Equal)
+	})
+
+	t.Run("PUT/api/course/:courseID/roles/:userID", func(t *testing.T) {
+		url := fmt.Sprintf("/api/course/%d/roles/%d", testutils.CourseFPV.ID, tutor.ID)
+		usersMock := func() dao.UsersDao {
+			usersMock := mock_dao.NewMockUsersDao(gomock.NewController(t))
+			usersMock.EXPECT().GetUserByID(gomock.Any(), tutor.ID).Return(tutor, nil).AnyTimes()
+			usersMock.EXPECT().GetUserByID(gomock.Any(), courseAdmin.ID).Return(courseAdmin, nil).AnyTimes()
+			return usersMock
+		}
+		gomino.TestCases{
+			"invalid role": {
+				Router: func(r *gin.Engine) {
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+				},
+				Body:         gin.H{"role": model.CourseRoleOwner},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"admin of course": {
+				Router: func(r *gin.Engine) {
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t), UsersDao: usersMock()})
+				},
+				Url:          fmt.Sprintf("/api/course/%d/roles/%d", testutils.CourseFPV.ID, courseAdmin.ID),
+				Body:         gin.H{"role": model.CourseRoleModerator},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusConflict,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					rolesMock := mock_dao.NewMockCourseRolesDao(gomock.NewController(t))
+					rolesMock.EXPECT().SetCourseRole(gomock.Any(), model.CourseRole{UserID: tutor.ID, CourseID: testutils.CourseFPV.ID, Role: model.CourseRoleModerator}).Return(nil)
+					configGinCourseRouter(r, dao.DaoWrapper{
+						CoursesDao:     testutils.GetCoursesMock(t),
+						UsersDao:       usersMock(),
+						CourseRolesDao: rolesMock,
+						AuditDao:       testutils.GetAuditMock(t),
+					})
+				},
+				Body:             gin.H{"role": model.CourseRoleModerator},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: courseRoleDto{userForLecturerDto{ID: tutor.ID, Name: tutor.Name, Login: tutor.LrzID}, model.CourseRoleModerator},
+			},
+		}.Method(http.MethodPut).Url(url).Run(t, testutils.Equal)
+	})
+
+	t.Run("DELETE/api/course/:courseID/roles/:userID", func(t *testing.T) {
+		gomino.TestCases{
+			"success": {
+				Router: func(r *gin.Engine) {
+					ctrl := gomock.NewController(t)
+					usersMock := mock_dao.NewMockUsersDao(ctrl)
+					usersMock.EXPECT().GetUserByID(gomock.Any(), tutor.ID).Return(tutor, nil)
+					rolesMock := mock_dao.NewMockCourseRolesDao(ctrl)
+					rolesMock.EXPECT().RemoveCourseRole(gomock.Any(), testutils.CourseFPV.ID, tutor.ID).Return(nil)
+					configGinCourseRouter(r, dao.DaoWrapper{
+						CoursesDao:     testutils.GetCoursesMock(t),
+						UsersDao:       usersMock,
+						CourseRolesDao: rolesMock,
+						AuditDao:       testutils.GetAuditMock(t),
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.Method(http.MethodDelete).Url(fmt.Sprintf("/api/course/%d/roles/%d", testutils.CourseFPV.ID, tutor.ID)).Run(t, testutils.Equal)
+	})
+}
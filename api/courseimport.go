@@ -65,6 +65,9 @@ func (r lectureHallRoutes) postSchedule(c *gin.Context) {
 		})
 		return
 	}
+	// streams assigned to a lecture hall during this import, checked for conflicts in addition to the existing ones.
+	imported := map[uint][]model.Stream{}
+	var conflicts []string
 	for _, courseReq := range req.Courses {
 		if !courseReq.Import {
 			continue
@@ -98,14 +101,33 @@ func (r lectureHallRoutes) postSchedule(c *gin.Context) {
 			if err == nil {
 				eventID = uint(eventIDInt)
 			}
-			streams = append(streams, model.Stream{
+			stream := model.Stream{
 				Start:            event.Start,
 				End:              event.End,
 				RoomName:         event.RoomName,
 				LectureHallID:    lectureHall.ID,
 				StreamKey:        strings.ReplaceAll(uuid.NewV4().String(), "-", "")[:15],
 				TUMOnlineEventID: eventID,
-			})
+			}
+			if lectureHall.ID != 0 {
+				eventConflicts, err := findBookingConflicts(r.LectureHallsDao, lectureHall.ID, []model.Stream{stream})
+				if err != nil {
+					logger.Error("can not check for booking conflicts", "err", err)
+				}
+				for _, other := range imported[lectureHall.ID] {
+					if stream.Overlaps(other) {
+						eventConflicts = append(eventConflicts, fmt.Sprintf("%s overlaps with another imported event", stream.Start.Format("02.01.2006 15:04")))
+					}
+				}
+				if len(eventConflicts) > 0 {
+					// import the event without lecture hall, admins have to resolve the conflict manually.
+					conflicts = append(conflicts, fmt.Sprintf("%s (%s): %s", courseReq.Title, lectureHall.Name, strings.Join(eventConflicts, "; ")))
+					stream.LectureHallID = 0
+				} else {
+					imported[lectureHall.ID] = append(imported[lectureHall.ID], stream)
+				}
+			}
+			streams = append(streams, stream)
 		}
 		course.Streams = streams
 		err := r.CoursesDao.CreateCourse(c, &course, !req.OptIn)
@@ -154,6 +176,10 @@ func (r lectureHallRoutes) postSchedule(c *gin.Context) {
 	}
 	if resp != "" {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+		return
+	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusOK, gin.H{"conflicts": conflicts})
 	}
 }
 
@@ -95,6 +95,27 @@ func configGinCourseRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 				admins.PUT("/:userID", routes.addAdminToCourse)
 				admins.DELETE("/:userID", routes.removeAdminFromCourse)
 			}
+
+			roles := courses.Group("roles")
+			{
+				roles.GET("", routes.getRoles)
+				roles.PUT("/:userID", routes.setRole)
+				roles.DELETE("/:userID", routes.removeRole)
+			}
+
+			accessGroups := courses.Group("accessGroups")
+			{
+				accessGroups.GET("", routes.getCourseAccessGroups)
+				accessGroups.PUT("/:groupID", routes.attachAccessGroup)
+				accessGroups.DELETE("/:groupID", routes.detachAccessGroup)
+			}
+
+			guestLinks := courses.Group("guestLinks")
+			{
+				guestLinks.GET("", routes.getGuestLinks)
+				guestLinks.POST("", routes.createGuestLink)
+				guestLinks.DELETE("/:linkID", routes.revokeGuestLink)
+			}
 		}
 	}
 }
@@ -360,8 +381,10 @@ func (r coursesRoutes) getCourseBySlug(c *gin.Context) {
 		return
 	}
 
-	if (course.IsLoggedIn() && tumLiveContext.User == nil) || (course.IsEnrolled() && !tumLiveContext.User.IsEligibleToWatchCourse(course)) {
+	guestAllowed := tumLiveContext.Guest.AllowsCourse(course)
+	if !guestAllowed && ((course.IsLoggedIn() && tumLiveContext.User == nil) || (course.IsEnrolled() && (tumLiveContext.User == nil || !tumLiveContext.User.IsEligibleToWatchCourse(course)))) {
 		c.AbortWithStatus(http.StatusUnauthorized)
+		return
 	}
 
 	user := tumLiveContext.User
@@ -478,6 +501,13 @@ func (r coursesRoutes) uploadVODMedia(c *gin.Context) {
 		})
 		return
 	}
+	if stream.Premiere && req.VideoType != model.VideoTypeCombined {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "premieres consist of a single combined video",
+		})
+		return
+	}
 
 	key := uuid.NewV4().String()
 	err = r.UploadKeyDao.CreateUploadKey(key, stream.ID, req.VideoType)
@@ -1189,6 +1219,15 @@ func (r coursesRoutes) createLecture(c *gin.Context) {
 		return
 	}
 
+	// premieres are streamed once from a file uploaded after creating the lecture
+	if req.Premiere && (req.AdHoc || len(req.DateSeries) > 0 || !req.Start.After(time.Now())) {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "premieres must be scheduled once in the future",
+		})
+		return
+	}
+
 	// try parse lectureHallId
 	lectureHallId, err := strconv.ParseInt(req.LectureHallId, 10, 32)
 	if err != nil {
@@ -1201,6 +1240,31 @@ func (r coursesRoutes) createLecture(c *gin.Context) {
 		return
 	}
 
+	if lectureHallId != 0 && !req.Force {
+		planned := make([]model.Stream, 0, len(req.DateSeries)+1)
+		for _, date := range append([]time.Time{req.Start}, req.DateSeries...) {
+			lecture := model.Stream{Start: date, End: date.Add(time.Minute * time.Duration(req.Duration))}
+			if req.AdHoc {
+				lecture.Start = time.Now()
+			}
+			planned = append(planned, lecture)
+		}
+		conflicts, err := findBookingConflicts(r.LectureHallsDao, uint(lectureHallId), planned)
+		if err != nil {
+			logger.Error("can not check for booking conflicts", "err", err)
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not check for booking conflicts",
+				Err:           err,
+			})
+			return
+		}
+		if len(conflicts) > 0 {
+			_ = c.Error(bookingConflictError(conflicts))
+			return
+		}
+	}
+
 	// name for folder for premiere file if needed
 	premiereFolder := fmt.Sprintf("%s/%d/%s/%s",
 		tools.Cfg.Paths.Mass,
@@ -1210,7 +1274,7 @@ func (r coursesRoutes) createLecture(c *gin.Context) {
 	premiereFileName := fmt.Sprintf("%s_%s.mp4",
 		tumLiveContext.Course.Slug,
 		req.Start.Format("2006-01-02_15-04"))
-	if req.Premiere || req.Vodup {
+	if req.Vodup {
 		err = os.MkdirAll(premiereFolder, os.ModePerm)
 		if err != nil {
 			logger.Error("can not create folder for premiere", "err", err)
@@ -1275,8 +1339,8 @@ func (r coursesRoutes) createLecture(c *gin.Context) {
 			lecture.SeriesIdentifier = seriesIdentifier
 		}
 
-		// add file if premiere
-		if req.Premiere || req.Vodup {
+		// add file if vod upload, premieres get their file once the upload is transcoded
+		if req.Vodup {
 			lecture.Files = []model.File{{Path: fmt.Sprintf("%s/%s", premiereFolder, premiereFileName)}}
 		}
 
@@ -1335,6 +1399,7 @@ type createLectureRequest struct {
 	Vodup         bool        `json:"vodup"`
 	AdHoc         bool        `json:"adHoc"`
 	DateSeries    []time.Time `json:"dateSeries"`
+	Force         bool        `json:"force"` // create the lectures even if the lecture hall is already booked
 }
 
 func (r coursesRoutes) createCourse(c *gin.Context) {
@@ -1550,72 +1615,6 @@ func (r coursesRoutes) getTranscodingProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, p.Progress)
 }
 
-type copyCourseRequest struct {
-	Semester string
-	Year     string
-	YearW    string
-}
-
-func (r coursesRoutes) copyCourse(c *gin.Context) {
-	var request copyCourseRequest
-	err := c.BindJSON(&request)
-	if err != nil {
-		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "Bad request", Err: err})
-		return
-	}
-	tlctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
-	course := tlctx.Course
-	streams := course.Streams
-
-	admins, err := r.DaoWrapper.CoursesDao.GetCourseAdmins(course.ID)
-	if err != nil {
-		logger.Error("Error getting course admins", "err", err)
-		admins = []model.User{}
-	}
-	course.Model = gorm.Model{}
-	course.Streams = nil
-	yearInt, err := strconv.Atoi(request.Year)
-	if err != nil {
-		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "Semester must be a number", Err: err})
-		return
-	}
-	course.Year = yearInt
-	switch request.Semester {
-	case "Sommersemester":
-		course.TeachingTerm = "S"
-	case "Wintersemester":
-		course.TeachingTerm = "W"
-	default:
-		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "Teaching must be a 'Sommmersemester' or 'Wintersemester'", Err: err})
-		return
-	}
-
-	err = r.CoursesDao.CreateCourse(c, course, true)
-	if err != nil {
-		logger.Error("Can't create course", "err", err)
-		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "Can't create course", Err: err})
-		return
-	}
-	numErrors := 0
-	for _, stream := range streams {
-		stream.CourseID = course.ID
-		stream.Model = gorm.Model{}
-		err := r.StreamsDao.CreateStream(&stream)
-		if err != nil {
-			logger.Error("Can't create stream", "err", err)
-			numErrors++
-		}
-	}
-	for _, admin := range admins {
-		err := r.CoursesDao.AddAdminToCourse(admin.ID, course.ID)
-		if err != nil {
-			logger.Error("Can't add admin to course", "err", err)
-			numErrors++
-		}
-	}
-	c.JSON(http.StatusOK, gin.H{"numErrs": numErrors, "newCourse": course.ID})
-}
-
 func (r coursesRoutes) searchCourse(c *gin.Context) {
 	client, err := tools.Cfg.GetMeiliClient()
 	if err != nil {
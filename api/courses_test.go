@@ -729,6 +729,7 @@ func TestCoursesCRUD(t *testing.T) {
 
 	t.Run("POST/api/course/:courseID/copy", func(t *testing.T) {
 		url := fmt.Sprintf("/api/course/%d/copy", testutils.CourseFPV.ID)
+		tools.Loc, _ = time.LoadLocation("Europe/Berlin")
 
 		gomino.TestCases{
 			"no context": {
@@ -785,9 +786,14 @@ func TestCoursesCRUD(t *testing.T) {
 								AnyTimes()
 							coursesMock.
 								EXPECT().
-								CreateCourse(gomock.Any(), gomock.Any(), true).
+								GetCopySource(gomock.Any(), testutils.CourseFPV.ID).
+								Return([]model.Stream{{Name: "Lecture 1", Start: testutils.StartTime}}, nil, nil).
+								MinTimes(1).MaxTimes(1)
+							coursesMock.
+								EXPECT().
+								CopyCourse(gomock.Any(), gomock.Any()).
 								Return(errors.New("")).
-								AnyTimes()
+								MinTimes(1).MaxTimes(1)
 							coursesMock.
 								EXPECT().GetCourseAdmins(testutils.CourseFPV.ID).
 								Return([]model.User{testutils.Admin}, nil).
@@ -823,9 +829,14 @@ func TestCoursesCRUD(t *testing.T) {
 								AnyTimes()
 							coursesMock.
 								EXPECT().
-								CreateCourse(gomock.Any(), gomock.Any(), true).
+								GetCopySource(gomock.Any(), testutils.CourseFPV.ID).
+								Return([]model.Stream{{Name: "Lecture 1", Start: testutils.StartTime}}, nil, nil).
+								MinTimes(1).MaxTimes(1)
+							coursesMock.
+								EXPECT().
+								CopyCourse(gomock.Any(), gomock.Any()).
 								Return(nil).
-								AnyTimes()
+								MinTimes(1).MaxTimes(1)
 							coursesMock.
 								EXPECT().GetCourseAdmins(testutils.CourseFPV.ID).
 								Return([]model.User{testutils.Admin}, nil).
@@ -845,6 +856,41 @@ func TestCoursesCRUD(t *testing.T) {
 				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
 				ExpectedCode: http.StatusOK,
 			},
+			"dry run": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						CoursesDao: func() dao.CoursesDao {
+							coursesMock := mock_dao.NewMockCoursesDao(gomock.NewController(t))
+							coursesMock.
+								EXPECT().
+								GetCourseById(gomock.Any(), testutils.CourseFPV.ID).
+								Return(testutils.CourseFPV, nil).
+								AnyTimes()
+							coursesMock.
+								EXPECT().GetCourseAdmins(testutils.CourseFPV.ID).
+								Return([]model.User{testutils.Admin}, nil).
+								MinTimes(1).MaxTimes(1)
+							coursesMock.
+								EXPECT().
+								GetCopySource(gomock.Any(), testutils.CourseFPV.ID).
+								Return([]model.Stream{{Name: "Lecture 1", Start: testutils.StartTime, End: testutils.StartTime}}, nil, nil).
+								MinTimes(1).MaxTimes(1)
+							return coursesMock
+						}(),
+					}
+					configGinCourseRouter(r, wrapper)
+				},
+				Body:         copyCourseRequest{Year: "2023", Semester: "Sommersemester", Parts: []string{copyLectures}, DryRun: true},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: courseCopyPreview{
+					Name:         testutils.CourseFPV.Name,
+					Year:         2023,
+					TeachingTerm: "S",
+					Lectures:     []copiedLecture{{"Lecture 1", testutils.StartTime.In(tools.Loc), testutils.StartTime.In(tools.Loc)}},
+					Conflicts:    []string{},
+				},
+			},
 		}.Method(http.MethodPost).Url(url).Run(t, testutils.Equal)
 	})
 }
@@ -896,6 +942,39 @@ func TestCoursesLectureActions(t *testing.T) {
 				},
 				ExpectedCode: http.StatusBadRequest,
 			},
+			"premiere in the past": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						CoursesDao: testutils.GetCoursesMock(t),
+					}
+					configGinCourseRouter(r, wrapper)
+				},
+				Middlewares: testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body: createLectureRequest{
+					Title:         "Premiere",
+					LectureHallId: "0",
+					Start:         time.Now().Add(-time.Hour),
+					Premiere:      true,
+				},
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"premiere series": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						CoursesDao: testutils.GetCoursesMock(t),
+					}
+					configGinCourseRouter(r, wrapper)
+				},
+				Middlewares: testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body: createLectureRequest{
+					Title:         "Premiere",
+					LectureHallId: "0",
+					Start:         time.Now().Add(time.Hour),
+					Premiere:      true,
+					DateSeries:    []time.Time{time.Now().Add(time.Hour * 24 * 7)},
+				},
+				ExpectedCode: http.StatusBadRequest,
+			},
 			"invalid lectureHallId": {
 				Router: func(r *gin.Engine) {
 					wrapper := dao.DaoWrapper{
@@ -915,6 +994,42 @@ func TestCoursesLectureActions(t *testing.T) {
 				},
 				ExpectedCode: http.StatusBadRequest,
 			},
+			"lecture hall already booked": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						CoursesDao:      testutils.GetCoursesMock(t),
+						LectureHallsDao: lectureHallMockWithBookings(t, []dao.Booking{{StreamID: 2, CourseName: "GBS"}}),
+					}
+					configGinCourseRouter(r, wrapper)
+				},
+				Middlewares: testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body: createLectureRequest{
+					Title:         "Lecture 1",
+					LectureHallId: "1",
+					Start:         time.Now(),
+					Duration:      90,
+					DateSeries:    []time.Time{},
+				},
+				ExpectedCode: http.StatusConflict,
+			},
+			"series overlaps itself": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						CoursesDao:      testutils.GetCoursesMock(t),
+						LectureHallsDao: lectureHallMockWithBookings(t, nil),
+					}
+					configGinCourseRouter(r, wrapper)
+				},
+				Middlewares: testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body: createLectureRequest{
+					Title:         "Lecture 1",
+					LectureHallId: "1",
+					Start:         time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+					Duration:      90,
+					DateSeries:    []time.Time{time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)},
+				},
+				ExpectedCode: http.StatusConflict,
+			},
 			"can not update course": {
 				Router: func(r *gin.Engine) {
 					wrapper := dao.DaoWrapper{
@@ -941,6 +1056,7 @@ func TestCoursesLectureActions(t *testing.T) {
 							auditMock.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
 							return auditMock
 						}(),
+						LectureHallsDao: lectureHallMockWithBookings(t, nil),
 					}
 					configGinCourseRouter(r, wrapper)
 				},
@@ -953,7 +1069,7 @@ func TestCoursesLectureActions(t *testing.T) {
 					Premiere:      false,
 					Vodup:         false,
 					DateSeries: []time.Time{
-						time.Now(),
+						time.Now().Add(time.Hour * 24 * 7),
 					},
 				},
 				ExpectedCode: http.StatusInternalServerError,
@@ -984,6 +1100,7 @@ func TestCoursesLectureActions(t *testing.T) {
 							auditMock.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
 							return auditMock
 						}(),
+						LectureHallsDao: lectureHallMockWithBookings(t, nil),
 					}
 					configGinCourseRouter(r, wrapper)
 				},
@@ -996,7 +1113,7 @@ func TestCoursesLectureActions(t *testing.T) {
 					Premiere:      false,
 					Vodup:         false,
 					DateSeries: []time.Time{
-						time.Now(),
+						time.Now().Add(time.Hour * 24 * 7),
 					},
 				},
 				ExpectedCode: http.StatusOK,
@@ -16,6 +16,9 @@ import (
 func configGinDownloadRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 	routes := downloadRoutes{daoWrapper}
 	router.GET("/api/download/:id", routes.download)
+	router.GET("/api/download/course/:courseID/attachments", tools.InitCourse(daoWrapper), routes.downloadCourseAttachments)
+	router.GET("/api/download/course/:courseID/bundle", tools.InitCourse(daoWrapper), routes.downloadBundle)
+	router.GET("/api/download/subtitles/:streamID/:lang", routes.downloadSubtitles)
 }
 
 type downloadRoutes struct {
@@ -73,37 +76,46 @@ func (r downloadRoutes) download(c *gin.Context) {
 		return
 	}
 
+	if file.Type == model.FILETYPE_ATTACHMENT_VERSION && !tumLiveContext.User.HasCoursePermission(course, model.CoursePermissionManage) {
+		_ = c.Error(dlErr)
+		return
+	}
+
 	switch c.Query("type") {
 	case "serve":
 		sendImageContent(c, file)
+	case "preview":
+		if file.PreviewPath == "" || (course.Visibility == "enrolled" && !tumLiveContext.User.IsEligibleToWatchCourse(course)) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Header("Cache-Control", "private, max-age=3600")
+		c.File(file.PreviewPath)
 	case "download":
 		fallthrough
 	default:
-		if tumLiveContext.User.IsAdminOfCourse(course) {
-			sendDownloadFile(c, file, tumLiveContext)
-			return
-		}
-		if !course.DownloadsEnabled {
+		if !mayDownload(tumLiveContext, course) {
 			_ = c.Error(dlErr)
 			return
 		}
-		if course.Visibility == "loggedin" || course.Visibility == "enrolled" {
-			if tumLiveContext.User == nil {
-				_ = c.Error(dlErr)
-				return
-			}
-			if course.Visibility == "enrolled" {
-				if !tumLiveContext.User.IsEligibleToWatchCourse(course) {
-					_ = c.Error(dlErr)
-					return
-				}
-			}
-		}
-
 		sendDownloadFile(c, file, tumLiveContext)
 	}
 }
 
+// mayDownload returns whether the user may download the files of the course
+func mayDownload(tumLiveContext tools.TUMLiveContext, course model.Course) bool {
+	if tumLiveContext.User == nil {
+		return false
+	}
+	if tumLiveContext.User.HasCoursePermission(course, model.CoursePermissionDownload) {
+		return true
+	}
+	if !course.DownloadsEnabled {
+		return false
+	}
+	return course.Visibility != "enrolled" || tumLiveContext.User.IsEligibleToWatchCourse(course)
+}
+
 func sendImageContent(c *gin.Context, file model.File) {
 	image, err := os.ReadFile(file.Path)
 	if err != nil {
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// bundleValidity is how long the links of a download bundle can be used, long enough to resume interrupted downloads
+const bundleValidity = 7 * 24 * time.Hour
+
+// Versions of lectures that can be downloaded with their names in file names
+var bundleVersions = map[string]string{"COMB": "Combined", "PRES": "Presentation", "CAM": "Camera"}
+
+// Qualities the edge provides downloads in. Original downloads aren't transcoded.
+var bundleQualities = []string{"original", "720p", "480p"}
+
+type bundleRequest struct {
+	Versions  []string // versions to download, the first available one is downloaded if empty
+	Quality   string
+	Languages []string // languages of the subtitles to download, all if "all"
+	StreamIDs []uint   // lectures to download, all if empty
+}
+
+type bundleSubtitles struct {
+	Language string `json:"language"`
+	Filename string `json:"filename"`
+	URL      string `json:"url"`
+}
+
+type bundleItem struct {
+	StreamID  uint                    `json:"streamID"`
+	Name      string                  `json:"name"`
+	Start     time.Time               `json:"start"`
+	Version   string                  `json:"version"`
+	Filename  string                  `json:"filename"`
+	URL       string                  `json:"url"`
+	Chapters  []tools.DownloadChapter `json:"chapters"`
+	Subtitles []bundleSubtitles       `json:"subtitles"`
+}
+
+type downloadBundle struct {
+	Course    string       `json:"course"`
+	Quality   string       `json:"quality"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+	Items     []bundleItem `json:"items"`
+}
+
+// parseBundleRequest reads the selection of a download bundle from the query
+func parseBundleRequest(c *gin.Context) (req bundleRequest, err error) {
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	}
+	req.Versions = split(c.Query("versions"))
+	for _, v := range req.Versions {
+		if _, ok := bundleVersions[v]; !ok {
+			return req, fmt.Errorf("unknown version %s", v)
+		}
+	}
+	req.Quality = c.DefaultQuery("quality", "original")
+	if !slices.Contains(bundleQualities, req.Quality) {
+		return req, errors.New("quality must be original, 720p or 480p")
+	}
+	req.Languages = split(c.Query("subtitles"))
+	for _, lang := range req.Languages {
+		if lang != "all" && !model.IsValidSubtitleLanguage(lang) {
+			return req, fmt.Errorf("invalid subtitle language %s", lang)
+		}
+	}
+	for _, id := range split(c.Query("streams")) {
+		streamID, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return req, fmt.Errorf("invalid stream id %s", id)
+		}
+		req.StreamIDs = append(req.StreamIDs, uint(streamID))
+	}
+	return req, nil
+}
+
+// playlists returns the playlists of the requested versions the stream has
+func (req bundleRequest) playlists(stream model.Stream) (versions []string, playlists []string) {
+	available := map[string]string{"COMB": stream.PlaylistUrl, "PRES": stream.PlaylistUrlPRES, "CAM": stream.PlaylistUrlCAM}
+	wanted := req.Versions
+	if len(wanted) == 0 {
+		wanted = []string{"COMB", "PRES", "CAM"}
+	}
+	for _, v := range wanted {
+		if available[v] == "" {
+			continue
+		}
+		versions, playlists = append(versions, v), append(playlists, available[v])
+		if len(req.Versions) == 0 {
+			break
+		}
+	}
+	return versions, playlists
+}
+
+// downloadChapters returns the video sections of a stream as chapters. The last one ends with the recording.
+func downloadChapters(stream model.Stream, sections []model.VideoSection) []tools.DownloadChapter {
+	sections = slices.Clone(sections)
+	start := func(s model.VideoSection) int64 {
+		return int64(s.StartHours*3600+s.StartMinutes*60+s.StartSeconds) * 1000
+	}
+	slices.SortFunc(sections, func(a, b model.VideoSection) int { return int(start(a) - start(b)) })
+	end := stream.End.Sub(stream.Start).Milliseconds()
+	if stream.Duration.Valid {
+		end = int64(stream.Duration.Int32) * 1000
+	}
+	chapters := []tools.DownloadChapter{}
+	for i, s := range sections {
+		chapter := tools.DownloadChapter{Title: s.Description, Start: start(s), End: end}
+		if i < len(sections)-1 {
+			chapter.End = min(start(sections[i+1]), end)
+		}
+		if chapter.End <= chapter.Start {
+			continue
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters
+}
+
+// downloadBundle returns the links to download the lectures of a course for offline use, as json or as input file
+// for download managers like aria2 (?format=aria2). The videos are downloaded from the edge, which supports
+// resuming downloads with range requests.
+func (r downloadRoutes) downloadBundle(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := *tumLiveContext.Course
+	if !mayDownload(tumLiveContext, course) {
+		_ = c.Error(dlErr)
+		return
+	}
+	req, err := parseBundleRequest(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: err.Error(), Err: err})
+		return
+	}
+
+	streams := slices.Clone(course.Streams)
+	slices.SortFunc(streams, func(a, b model.Stream) int { return a.Start.Compare(b.Start) })
+	bundle := downloadBundle{Course: course.Name, Quality: req.Quality, ExpiresAt: time.Now().Add(bundleValidity), Items: []bundleItem{}}
+	index := 0
+	for _, stream := range streams {
+		if !stream.Recording || stream.Private || (len(req.StreamIDs) > 0 && !slices.Contains(req.StreamIDs, stream.ID)) {
+			continue
+		}
+		index++
+		versions, playlists := req.playlists(stream)
+		if len(versions) == 0 {
+			continue
+		}
+		sections, err := r.VideoSectionDao.GetByStreamId(stream.ID)
+		if err != nil {
+			_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get video sections", Err: err})
+			return
+		}
+		var languages []string
+		if len(req.Languages) > 0 {
+			if languages, err = r.SubtitlesDao.GetLanguages(c, stream.ID); err != nil {
+				_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get subtitles", Err: err})
+				return
+			}
+		}
+		chapters := downloadChapters(stream, sections)
+		for i, version := range versions {
+			token, err := tools.DownloadToken(stream, playlists[i], tumLiveContext.User, chapters, bundleValidity)
+			if err != nil {
+				_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not sign download", Err: err})
+				return
+			}
+			name := fmt.Sprintf("%02d %s - %s", index, cleanFileName(lectureName(stream)), bundleVersions[version])
+			item := bundleItem{
+				StreamID:  stream.ID,
+				Name:      stream.Name,
+				Start:     stream.Start,
+				Version:   version,
+				Filename:  name + ".mp4",
+				URL:       fmt.Sprintf("%s?jwt=%s&download=1&quality=%s", playlists[i], token, req.Quality),
+				Chapters:  chapters,
+				Subtitles: []bundleSubtitles{},
+			}
+			for _, lang := range languages {
+				if !slices.Contains(req.Languages, "all") && !slices.Contains(req.Languages, lang) {
+					continue
+				}
+				item.Subtitles = append(item.Subtitles, bundleSubtitles{
+					Language: lang,
+					Filename: fmt.Sprintf("%s.%s.vtt", name, lang),
+					URL:      fmt.Sprintf("%s/api/download/subtitles/%d/%s?jwt=%s", tools.Cfg.WebUrl, stream.ID, lang, token),
+				})
+			}
+			bundle.Items = append(bundle.Items, item)
+		}
+	}
+
+	if c.Query("format") != "aria2" {
+		c.JSON(http.StatusOK, bundle)
+		return
+	}
+	folder := cleanFileName(course.Name)
+	var input strings.Builder
+	for _, item := range bundle.Items {
+		// the edge responds with 503 until transcoded videos are ready
+		fmt.Fprintf(&input, "%s\n  dir=%s\n  out=%s\n  retry-wait=60\n  max-tries=60\n", item.URL, folder, item.Filename)
+		for _, s := range item.Subtitles {
+			fmt.Fprintf(&input, "%s\n  dir=%s\n  out=%s\n", s.URL, folder, s.Filename)
+		}
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-downloads.txt", course.Slug))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(input.String()))
+}
+
+// downloadSubtitles sends the subtitles of a lecture in a download bundle. They're authorized by the token of
+// the video, so download managers can get them without a session.
+func (r downloadRoutes) downloadSubtitles(c *gin.Context) {
+	claims, err := tools.ParseDownloadToken(c.Query("jwt"))
+	if err != nil || claims.StreamID != c.Param("streamID") {
+		_ = c.Error(tools.RequestError{Status: http.StatusForbidden, CustomMessage: "invalid download token", Err: err})
+		return
+	}
+	streamID, err := strconv.ParseUint(claims.StreamID, 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid stream id", Err: err})
+		return
+	}
+	subtitles, err := r.SubtitlesDao.GetByStreamIDandLang(c, uint(streamID), c.Param("lang"))
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusNotFound, CustomMessage: "can not find subtitles", Err: err})
+		return
+	}
+	c.Data(http.StatusOK, "text/vtt", []byte(subtitles.Content))
+}
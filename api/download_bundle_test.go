@@ -0,0 +1,93 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/matthiasreumann/gomino"
+)
+
+func TestDownloadBundle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := func(r *gin.Engine) {
+		configGinDownloadRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+	}
+	url := fmt.Sprintf("/api/download/course/%d/bundle", testutils.CourseFPV.ID)
+
+	gomino.TestCases{
+		"downloads disabled": {
+			Router:       router,
+			Url:          url,
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+			ExpectedCode: http.StatusForbidden,
+		},
+		"invalid quality": {
+			Router:       router,
+			Url:          url + "?quality=4k",
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+			ExpectedCode: http.StatusBadRequest,
+		},
+		"invalid version": {
+			Router:       router,
+			Url:          url + "?versions=COMB,ALL",
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+			ExpectedCode: http.StatusBadRequest,
+		},
+		"no selected lectures": {
+			Router:       router,
+			Url:          url + "?streams=999999",
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+			ExpectedCode: http.StatusOK,
+		},
+	}.
+		Method(http.MethodGet).
+		Run(t, testutils.Equal)
+
+	gomino.TestCases{
+		"invalid token": {
+			Router:       router,
+			Url:          "/api/download/subtitles/1969/en?jwt=abc",
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextEmpty)),
+			ExpectedCode: http.StatusForbidden,
+		},
+	}.
+		Method(http.MethodGet).
+		Run(t, testutils.Equal)
+}
+
+func TestBundlePlaylists(t *testing.T) {
+	stream := model.Stream{PlaylistUrl: "comb", PlaylistUrlCAM: "cam"}
+	if versions, playlists := (bundleRequest{}).playlists(stream); !reflect.DeepEqual(versions, []string{"COMB"}) || playlists[0] != "comb" {
+		t.Errorf("expected only the combined version, got %v", versions)
+	}
+	if versions, _ := (bundleRequest{Versions: []string{"PRES", "CAM", "COMB"}}).playlists(stream); !reflect.DeepEqual(versions, []string{"CAM", "COMB"}) {
+		t.Errorf("expected available versions, got %v", versions)
+	}
+}
+
+func TestDownloadChapters(t *testing.T) {
+	start := time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC)
+	stream := model.Stream{Start: start, End: start.Add(time.Hour), Duration: sql.NullInt32{Int32: 1800, Valid: true}}
+	sections := []model.VideoSection{
+		{Description: "Dynamic programming", StartMinutes: 10},
+		{Description: "Intro"},
+		{Description: "Too late", StartHours: 1},
+	}
+	expected := []tools.DownloadChapter{
+		{Title: "Intro", Start: 0, End: 600000},
+		{Title: "Dynamic programming", Start: 600000, End: 1800000},
+	}
+	if chapters := downloadChapters(stream, sections); !reflect.DeepEqual(chapters, expected) {
+		t.Errorf("expected %v, got %v", expected, chapters)
+	}
+}
@@ -1,17 +1,23 @@
 package api
 
 import (
-	"html/template"
+	"database/sql"
 	"net/http"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
 	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
 )
 
+// calendarFeedHistory is how long past (and cancelled) lectures stay in a calendar feed
+const calendarFeedHistory = time.Hour * 24 * 180
+
 func configGinDownloadICSRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 	templates, err := template.ParseFS(staticFS, "template/*.gotemplate")
 	if err != nil {
@@ -20,6 +26,12 @@ func configGinDownloadICSRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 	}
 	routes := downloadICSRoutes{daoWrapper, templates}
 	router.GET("/api/download_ics/:year/:term/:slug/events.ics", routes.downloadICS)
+	router.GET("/api/calendar/:token/feed.ics", routes.calendarFeed)
+
+	calendarToken := router.Group("/api/users/calendar")
+	calendarToken.Use(tools.LoggedIn)
+	calendarToken.POST("/token", routes.createCalendarToken)
+	calendarToken.DELETE("/token", routes.revokeCalendarToken)
 }
 
 type downloadICSRoutes struct {
@@ -28,6 +40,10 @@ type downloadICSRoutes struct {
 }
 
 func (r downloadICSRoutes) downloadICS(c *gin.Context) {
+	var user *model.User
+	if tumLiveContext, exists := c.Get("TUMLiveContext"); exists {
+		user = tumLiveContext.(tools.TUMLiveContext).User
+	}
 	slug, term := c.Param("slug"), c.Param("term")
 	year, err := strconv.Atoi(c.Param("year"))
 	if err != nil {
@@ -48,9 +64,19 @@ func (r downloadICSRoutes) downloadICS(c *gin.Context) {
 		})
 		return
 	}
+	if course.Visibility != "public" && (user == nil || !user.IsEligibleToWatchCourse(course)) {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusForbidden,
+			CustomMessage: "not allowed to see this course",
+		})
+		return
+	}
 
 	var acc []CalendarEntry
 	for _, s := range course.Streams {
+		if s.Private && !user.IsAdminOfCourse(course) {
+			continue
+		}
 		acc = append(acc, streamToCalendarEntry(s, course))
 	}
 
@@ -67,15 +93,131 @@ func (r downloadICSRoutes) downloadICS(c *gin.Context) {
 	}
 }
 
+// calendarFeed renders a subscribable calendar with the lectures of all courses the owner of the token pinned or is enrolled in.
+// Lectures deleted within the history window are included as cancelled events.
+func (r downloadICSRoutes) calendarFeed(c *gin.Context) {
+	token, err := r.TokenDao.GetToken(c.Param("token"))
+	if err != nil || token.Scope != model.TokenScopeCalendar {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusForbidden,
+			CustomMessage: "invalid token",
+			Err:           err,
+		})
+		return
+	}
+	if err = r.TokenDao.TokenUsed(token); err != nil {
+		logger.Warn("error marking token as used", "err", err)
+	}
+	user, err := r.UsersDao.GetUserByID(c, token.UserID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get user",
+			Err:           err,
+		})
+		return
+	}
+
+	courses := map[uint]model.Course{}
+	for _, course := range append(user.PinnedCourses, user.EnrolledCourses()...) {
+		if user.IsEligibleToWatchCourse(course) {
+			courses[course.ID] = course
+		}
+	}
+	courseIDs := make([]uint, 0, len(courses))
+	for id := range courses {
+		courseIDs = append(courseIDs, id)
+	}
+
+	acc := []CalendarEntry{}
+	if len(courseIDs) > 0 {
+		streams, err := r.StreamsDao.GetStreamsForCalendar(courseIDs, time.Now().Add(-calendarFeedHistory))
+		if err != nil {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not get streams",
+				Err:           err,
+			})
+			return
+		}
+		for _, s := range streams {
+			course := courses[s.CourseID]
+			if s.Private && !user.IsAdminOfCourse(course) {
+				continue
+			}
+			acc = append(acc, streamToCalendarEntry(s, course))
+		}
+	}
+
+	c.Header("content-type", "text/calendar")
+	err = r.templates.ExecuteTemplate(c.Writer, "ics.gotemplate", acc)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get generate .ics",
+			Err:           err,
+		})
+	}
+}
+
+// createCalendarToken creates a new calendar feed token for the user. Existing calendar tokens are revoked.
+func (r downloadICSRoutes) createCalendarToken(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+
+	err := r.TokenDao.DeleteTokensOfUser(tumLiveContext.User.ID, model.TokenScopeCalendar)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not revoke old calendar token",
+			Err:           err,
+		})
+		return
+	}
+	token := model.Token{
+		UserID:  tumLiveContext.User.ID,
+		Token:   strings.ReplaceAll(uuid.NewV4().String(), "-", ""),
+		Expires: sql.NullTime{},
+		Scope:   model.TokenScopeCalendar,
+	}
+	err = r.TokenDao.AddToken(token)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not create calendar token",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": tools.Cfg.WebUrl + "/api/calendar/" + token.Token + "/feed.ics"})
+}
+
+// revokeCalendarToken deletes the calendar token of the user, the feed url stops working immediately.
+func (r downloadICSRoutes) revokeCalendarToken(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+
+	err := r.TokenDao.DeleteTokensOfUser(tumLiveContext.User.ID, model.TokenScopeCalendar)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not revoke calendar token",
+			Err:           err,
+		})
+		return
+	}
+}
+
 type CalendarEntry struct {
-	CreatedAt   string
-	Start       string
-	End         string
-	ID          string
-	Url         string
-	Location    string
-	Summary     string
-	Description string
+	CreatedAt    string
+	LastModified string
+	Sequence     int64
+	Start        string
+	End          string
+	ID           string
+	Status       string // CONFIRMED or CANCELLED
+	Url          string
+	Location     string
+	Summary      string
+	Description  string
 }
 
 func streamToCalendarEntry(s model.Stream, c model.Course) CalendarEntry {
@@ -87,14 +229,36 @@ func streamToCalendarEntry(s model.Stream, c model.Course) CalendarEntry {
 	if len(s.RoomCode) > 0 {
 		location += s.RoomCode
 	}
+	watchUrl := tools.Cfg.WebUrl + c.GetStreamUrl(s)
+	description := []string{icsEscape(s.Name)}
+	if s.Recording {
+		description = append(description, "Recording: "+watchUrl)
+	} else {
+		description = append(description, "Livestream: "+watchUrl)
+	}
+	status := "CONFIRMED"
+	lastModified := s.UpdatedAt
+	if s.DeletedAt.Valid {
+		status = "CANCELLED"
+		lastModified = s.DeletedAt.Time
+	}
 	return CalendarEntry{
-		CreatedAt:   s.CreatedAt.Format(layout),
+		CreatedAt:    s.CreatedAt.Format(layout),
+		LastModified: lastModified.UTC().Format(layout + "Z"),
+		// must increase with every change of the event, seconds since creation do.
+		Sequence:    max(0, int64(lastModified.Sub(s.CreatedAt).Seconds())),
 		Start:       s.Start.Format(layout),
 		End:         s.End.Format(layout),
 		ID:          strconv.Itoa(int(s.ID)),
-		Url:         strings.Join([]string{tools.Cfg.WebUrl, strconv.Itoa(c.Year), c.TeachingTerm, c.Slug}, "/"),
-		Location:    location,
-		Summary:     c.Name,
-		Description: s.Name,
+		Status:      status,
+		Url:         watchUrl,
+		Location:    icsEscape(location),
+		Summary:     icsEscape(c.Name),
+		Description: strings.Join(description, "\\n"),
 	}
 }
+
+// icsEscape escapes text for use in an iCalendar TEXT property (RFC 5545, 3.3.11)
+func icsEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n").Replace(s)
+}
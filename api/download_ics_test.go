@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"html/template"
 	"net/http"
 	"testing"
+	"text/template"
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/mock_dao"
@@ -90,3 +90,129 @@ func TestDownloadICS(t *testing.T) {
 			Run(t, testutils.Equal)
 	})
 }
+
+func TestCalendarFeed(t *testing.T) {
+	templates, _ := template.ParseFS(staticFS, "template/*.gotemplate")
+
+	t.Run("GET/api/calendar/:token/feed.ics", func(t *testing.T) {
+		token := "abc"
+		url := fmt.Sprintf("/api/calendar/%s/feed.ics", token)
+
+		var res bytes.Buffer
+		calendarEntries := []CalendarEntry{}
+		for _, s := range testutils.CourseFPV.Streams {
+			if !s.Private {
+				calendarEntries = append(calendarEntries, streamToCalendarEntry(s, testutils.CourseFPV))
+			}
+		}
+		_ = templates.ExecuteTemplate(&res, "ics.gotemplate", calendarEntries)
+
+		gomino.TestCases{
+			"invalid token": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						TokenDao: func() dao.TokenDao {
+							tokenMock := mock_dao.NewMockTokenDao(gomock.NewController(t))
+							tokenMock.EXPECT().GetToken(token).Return(model.Token{}, errors.New("")).AnyTimes()
+							return tokenMock
+						}(),
+					}
+					configGinDownloadICSRouter(r, wrapper)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"wrong scope": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						TokenDao: func() dao.TokenDao {
+							tokenMock := mock_dao.NewMockTokenDao(gomock.NewController(t))
+							tokenMock.EXPECT().GetToken(token).Return(model.Token{Scope: model.TokenScopeAdmin}, nil).AnyTimes()
+							return tokenMock
+						}(),
+					}
+					configGinDownloadICSRouter(r, wrapper)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					calendarToken := model.Token{UserID: testutils.Student.ID, Scope: model.TokenScopeCalendar}
+					wrapper := dao.DaoWrapper{
+						TokenDao: func() dao.TokenDao {
+							tokenMock := mock_dao.NewMockTokenDao(gomock.NewController(t))
+							tokenMock.EXPECT().GetToken(token).Return(calendarToken, nil).AnyTimes()
+							tokenMock.EXPECT().TokenUsed(calendarToken).Return(nil).AnyTimes()
+							return tokenMock
+						}(),
+						UsersDao: func() dao.UsersDao {
+							usersMock := mock_dao.NewMockUsersDao(gomock.NewController(t))
+							usersMock.EXPECT().GetUserByID(gomock.Any(), testutils.Student.ID).Return(testutils.Student, nil).AnyTimes()
+							return usersMock
+						}(),
+						StreamsDao: func() dao.StreamsDao {
+							streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
+							streamsMock.EXPECT().
+								GetStreamsForCalendar([]uint{testutils.CourseFPV.ID}, gomock.Any()).
+								Return(testutils.CourseFPV.Streams, nil).AnyTimes()
+							return streamsMock
+						}(),
+					}
+					configGinDownloadICSRouter(r, wrapper)
+				},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler),
+				ExpectedHeader:   gomino.HttpHeader{"content-type": "text/calendar"},
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: res.Bytes(),
+			},
+		}.
+			Method(http.MethodGet).
+			Url(url).
+			Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/users/calendar/token", func(t *testing.T) {
+		gomino.TestCases{
+			"not logged in": {
+				Router: func(r *gin.Engine) {
+					configGinDownloadICSRouter(r, dao.DaoWrapper{})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(tools.TUMLiveContext{})),
+				ExpectedCode: http.StatusFound,
+			},
+			"can not revoke old token": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						TokenDao: func() dao.TokenDao {
+							tokenMock := mock_dao.NewMockTokenDao(gomock.NewController(t))
+							tokenMock.EXPECT().DeleteTokensOfUser(testutils.Student.ID, model.TokenScopeCalendar).Return(errors.New("")).AnyTimes()
+							return tokenMock
+						}(),
+					}
+					configGinDownloadICSRouter(r, wrapper)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusInternalServerError,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						TokenDao: func() dao.TokenDao {
+							tokenMock := mock_dao.NewMockTokenDao(gomock.NewController(t))
+							tokenMock.EXPECT().DeleteTokensOfUser(testutils.Student.ID, model.TokenScopeCalendar).Return(nil).AnyTimes()
+							tokenMock.EXPECT().AddToken(gomock.Any()).Return(nil).AnyTimes()
+							return tokenMock
+						}(),
+					}
+					configGinDownloadICSRouter(r, wrapper)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.
+			Method(http.MethodPost).
+			Url("/api/users/calendar/token").
+			Run(t, testutils.Equal)
+	})
+}
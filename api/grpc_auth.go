@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/grpcauth"
+	"github.com/TUM-Dev/gocast/worker/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	grpcAuthOnce sync.Once
+	// grpcCA issues the certificates of workers, nil if gRPC isn't configured to use TLS
+	grpcCA *grpcauth.CA
+	// grpcIdentity is the certificate TUM-Live authenticates itself to workers and the voice-service with
+	grpcIdentity *grpcauth.Identity
+)
+
+// initGRPCAuth loads the CA of TUM-Live and its workers if configured. It panics if the CA can't be loaded.
+func initGRPCAuth() {
+	grpcAuthOnce.Do(func() {
+		if tools.Cfg.GRPC == nil {
+			logger.Warn("grpc is not configured, connections to workers are neither encrypted nor authenticated")
+			return
+		}
+		ca, err := grpcauth.LoadOrCreateCA(tools.Cfg.GRPC.CACert, tools.Cfg.GRPC.CAKey)
+		if err != nil {
+			// don't fall back to plaintext if TLS is configured
+			logger.Error("Can't load grpc CA", "err", err)
+			panic(fmt.Errorf("can't load grpc CA: %w", err))
+		}
+		grpcCA = ca
+		grpcIdentity = ca.NewIdentity(tools.Cfg.GRPC.Hostnames)
+	})
+}
+
+// workerServerOptions returns the options of the server workers connect to. All calls but joining
+// the worker pool require the certificate issued to the calling worker.
+func workerServerOptions() []grpc.ServerOption {
+	if grpcIdentity == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(grpcIdentity.ServerTLSConfig(tls.VerifyClientCertIfGiven))),
+		grpc.ChainUnaryInterceptor(grpcauth.WorkerUnaryInterceptor(pb.FromWorker_JoinWorkers_FullMethodName)),
+		grpc.ChainStreamInterceptor(grpcauth.WorkerStreamInterceptor()),
+	}
+}
+
+// workerCredentials returns the credentials for connecting to the worker with the id workerID
+func workerCredentials(workerID string) credentials.TransportCredentials {
+	if grpcIdentity == nil {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(grpcIdentity.ClientTLSConfig(workerID))
+}
+
+// issueWorkerCertificate signs the certificate signing request of a worker. It returns no certificate if
+// TLS isn't configured or the worker sent no request.
+func issueWorkerCertificate(workerID string, csr []byte) (cert []byte, ca []byte, err error) {
+	if grpcCA == nil || len(csr) == 0 {
+		return nil, nil, nil
+	}
+	cert, err = grpcCA.SignCSR(csr, workerID, grpcauth.WorkerCertValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, grpcCA.CertPEM(), nil
+}
+
+// voiceServerOptions returns the options of the server the voice-service sends subtitles to
+func voiceServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if grpcIdentity != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(grpcIdentity.ServerTLSConfig(tls.NoClientCert))))
+	}
+	if tools.Cfg.VoiceService != nil && tools.Cfg.VoiceService.Token != "" {
+		opts = append(opts, grpc.ChainUnaryInterceptor(grpcauth.TokenUnaryInterceptor(tools.Cfg.VoiceService.Token)))
+	} else {
+		logger.Warn("voiceService.token is not set, anyone can send subtitles")
+	}
+	return opts
+}
+
+// voiceDialOptions returns the options for connecting to the voice-service
+func voiceDialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if tools.Cfg.VoiceService.TLS {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		if grpcCA != nil {
+			pool.AppendCertsFromPEM(grpcCA.CertPEM())
+		}
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool})
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if tools.Cfg.VoiceService.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcauth.TokenCredentials{
+			Token:  tools.Cfg.VoiceService.Token,
+			Secure: tools.Cfg.VoiceService.TLS,
+		}))
+	}
+	return opts, nil
+}
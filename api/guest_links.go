@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// maxGuestLinkValidity is how long guest links can be valid at most
+const maxGuestLinkValidity = time.Hour * 24 * 180
+
+type guestLinkDto struct {
+	ID           uint      `json:"id"`
+	StreamID     *uint     `json:"streamID"`
+	Note         string    `json:"note"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	MaxUses      int       `json:"maxUses"`
+	Uses         int       `json:"uses"`
+	RequireEmail bool      `json:"requireEmail"`
+	URL          string    `json:"url"`
+}
+
+func toGuestLinkDto(link model.GuestLink) (guestLinkDto, error) {
+	token, err := tools.GuestLinkToken(link)
+	if err != nil {
+		return guestLinkDto{}, err
+	}
+	return guestLinkDto{
+		ID:           link.ID,
+		StreamID:     link.StreamID,
+		Note:         link.Note,
+		ExpiresAt:    link.ExpiresAt,
+		MaxUses:      link.MaxUses,
+		Uses:         link.Uses,
+		RequireEmail: link.RequireEmail,
+		URL:          tools.Cfg.WebUrl + "/guest/" + token,
+	}, nil
+}
+
+// getGuestLinks returns the guest links of the course that weren't revoked
+func (r coursesRoutes) getGuestLinks(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	links, err := r.GuestLinksDao.GetGuestLinksForCourse(c, tumLiveContext.Course.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get guest links", Err: err})
+		return
+	}
+	res := make([]guestLinkDto, len(links))
+	for i, link := range links {
+		if res[i], err = toGuestLinkDto(link); err != nil {
+			_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not sign guest link", Err: err})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// createGuestLink creates a link that lets people without account watch the course or one of its lectures
+func (r coursesRoutes) createGuestLink(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	course := tumLiveContext.Course
+	var req struct {
+		StreamID     *uint     `json:"streamID"`
+		Note         string    `json:"note"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+		MaxUses      int       `json:"maxUses"`
+		RequireEmail bool      `json:"requireEmail"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid body", Err: err})
+		return
+	}
+	now := time.Now()
+	if !req.ExpiresAt.After(now) || req.ExpiresAt.Sub(now) > maxGuestLinkValidity {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "guest links must expire within 180 days"})
+		return
+	}
+	if req.MaxUses < 0 {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "maxUses must not be negative"})
+		return
+	}
+	target := course.Name
+	if req.StreamID != nil {
+		stream, err := r.StreamsDao.GetStreamByID(c, strconv.FormatUint(uint64(*req.StreamID), 10))
+		if err != nil || stream.CourseID != course.ID {
+			_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "lecture is not part of this course", Err: err})
+			return
+		}
+		target = fmt.Sprintf("%s, lecture %s (%d)", course.Name, stream.Name, stream.ID)
+	}
+
+	link := model.GuestLink{
+		CourseID:     course.ID,
+		StreamID:     req.StreamID,
+		CreatedByID:  tumLiveContext.User.ID,
+		Note:         strings.TrimSpace(req.Note),
+		ExpiresAt:    req.ExpiresAt,
+		MaxUses:      req.MaxUses,
+		RequireEmail: req.RequireEmail,
+	}
+	if err := r.GuestLinksDao.CreateGuestLink(c, &link); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not create guest link", Err: err})
+		return
+	}
+	r.auditCourseChange(tumLiveContext, fmt.Sprintf("created guest link %d for %s, expires %s", link.ID, target, link.ExpiresAt.Format(time.DateTime)))
+	dto, err := toGuestLinkDto(link)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not sign guest link", Err: err})
+		return
+	}
+	c.JSON(http.StatusCreated, dto)
+}
+
+// revokeGuestLink makes a guest link and the sessions of guests who used it invalid
+func (r coursesRoutes) revokeGuestLink(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	id, err := strconv.ParseUint(c.Param("linkID"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid linkID", Err: err})
+		return
+	}
+	link, err := r.GuestLinksDao.GetGuestLinkByID(c, uint(id))
+	if err != nil || link.CourseID != tumLiveContext.Course.ID {
+		_ = c.Error(tools.RequestError{Status: http.StatusNotFound, CustomMessage: "guest link not found", Err: err})
+		return
+	}
+	if err = r.GuestLinksDao.RevokeGuestLink(c, link.ID); err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not revoke guest link", Err: err})
+		return
+	}
+	r.auditCourseChange(tumLiveContext, fmt.Sprintf("revoked guest link %d of %s (used %d times)", link.ID, tumLiveContext.Course.Name, link.Uses))
+	c.Status(http.StatusOK)
+}
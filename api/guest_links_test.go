@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+func TestGuestLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("POST/api/course/:courseID/guestLinks", func(t *testing.T) {
+		gomino.TestCases{
+			"expired": {
+				Router: func(r *gin.Engine) {
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+				},
+				Body:         gin.H{"note": "Prof. Visitor", "expiresAt": time.Now().Add(-time.Hour)},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"valid too long": {
+				Router: func(r *gin.Engine) {
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+				},
+				Body:         gin.H{"note": "Prof. Visitor", "expiresAt": time.Now().AddDate(1, 0, 0)},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"lecture of other course": {
+				Router: func(r *gin.Engine) {
+					streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
+					streamsMock.EXPECT().GetStreamByID(gomock.Any(), "99").Return(model.Stream{Model: gorm.Model{ID: 99}, CourseID: 1234}, nil)
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t), StreamsDao: streamsMock})
+				},
+				Body:         gin.H{"note": "Prof. Visitor", "streamID": 99, "expiresAt": time.Now().Add(time.Hour)},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+		}.Method(http.MethodPost).Url(fmt.Sprintf("/api/course/%d/guestLinks", testutils.CourseFPV.ID)).Run(t, testutils.Equal)
+	})
+
+	t.Run("DELETE/api/course/:courseID/guestLinks/:linkID", func(t *testing.T) {
+		gomino.TestCases{
+			"link of other course": {
+				Router: func(r *gin.Engine) {
+					linksMock := mock_dao.NewMockGuestLinksDao(gomock.NewController(t))
+					linksMock.EXPECT().GetGuestLinkByID(gomock.Any(), uint(5)).Return(model.GuestLink{Model: gorm.Model{ID: 5}, CourseID: 1234}, nil)
+					configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t), GuestLinksDao: linksMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusNotFound,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					linksMock := mock_dao.NewMockGuestLinksDao(gomock.NewController(t))
+					linksMock.EXPECT().GetGuestLinkByID(gomock.Any(), uint(5)).Return(model.GuestLink{Model: gorm.Model{ID: 5}, CourseID: testutils.CourseFPV.ID}, nil)
+					linksMock.EXPECT().RevokeGuestLink(gomock.Any(), uint(5)).Return(nil)
+					configGinCourseRouter(r, dao.DaoWrapper{
+						CoursesDao:    testutils.GetCoursesMock(t),
+						GuestLinksDao: linksMock,
+						AuditDao:      testutils.GetAuditMock(t),
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.Method(http.MethodDelete).Url(fmt.Sprintf("/api/course/%d/guestLinks/5", testutils.CourseFPV.ID)).Run(t, testutils.Equal)
+	})
+}
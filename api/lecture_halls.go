@@ -13,6 +13,7 @@ import (
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/camera"
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
 )
@@ -24,9 +25,13 @@ func configGinLectureHallApiRouter(router *gin.Engine, daoWrapper dao.DaoWrapper
 	admins.Use(tools.Admin)
 	admins.PUT("/lectureHall/:id", routes.updateLectureHall)
 	admins.POST("/lectureHall/:id/defaultPreset", routes.updateLectureHallsDefaultPreset)
+	admins.POST("/lectureHall/:id/move", routes.moveCamera)
+	admins.POST("/lectureHall/:id/presets", routes.storePreset)
 	admins.DELETE("/lectureHall/:id", routes.deleteLectureHall)
 	admins.POST("/createLectureHall", routes.createLectureHall)
 	admins.POST("/takeSnapshot/:lectureHallID/:presetID", routes.takeSnapshot)
+	admins.GET("/lectureHalls/bookings", routes.getBookings)
+	admins.GET("/lectureHalls/conflicts", routes.getBookingConflicts)
 	admins.GET("/course-schedule", routes.getSchedule)
 	admins.POST("/course-schedule/:year/:term", routes.postSchedule)
 	admins.GET("/refreshLectureHallPresets/:lectureHallID", routes.refreshLectureHallPresets)
@@ -47,11 +52,12 @@ type lectureHallRoutes struct {
 }
 
 type updateLectureHallReq struct {
-	CamIp     string `json:"camIp"`
-	CombIp    string `json:"combIp"`
-	PresIP    string `json:"presIp"`
-	CameraIp  string `json:"cameraIp"`
-	PwrCtrlIp string `json:"pwrCtrlIp"`
+	CamIp      string           `json:"camIp"`
+	CombIp     string           `json:"combIp"`
+	PresIP     string           `json:"presIp"`
+	CameraIp   string           `json:"cameraIp"`
+	CameraType model.CameraType `json:"cameraType"` // 0 keeps the current type
+	PwrCtrlIp  string           `json:"pwrCtrlIp"`
 }
 
 func (r lectureHallRoutes) updateLectureHall(c *gin.Context) {
@@ -84,11 +90,21 @@ func (r lectureHallRoutes) updateLectureHall(c *gin.Context) {
 		})
 		return
 	}
+	if req.CameraType != 0 && !req.CameraType.Valid() {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid camera type",
+		})
+		return
+	}
 	lectureHall.CamIP = req.CamIp
 	lectureHall.CombIP = req.CombIp
 	lectureHall.PresIP = req.PresIP
 	lectureHall.CameraIP = req.CameraIp
 	lectureHall.PwrCtrlIp = req.PwrCtrlIp
+	if req.CameraType != 0 {
+		lectureHall.CameraType = req.CameraType
+	}
 	err = r.LectureHallsDao.SaveLectureHall(lectureHall)
 	if err != nil {
 		logger.Error("error while updating lecture hall", "err", err)
@@ -146,6 +162,108 @@ func (r lectureHallRoutes) updateLectureHallsDefaultPreset(c *gin.Context) {
 	}
 }
 
+type moveCameraRequest struct {
+	Pan  float64 `json:"pan"`
+	Tilt float64 `json:"tilt"`
+	Zoom float64 `json:"zoom"`
+}
+
+// moveCamera moves the camera of a lecture hall relative to its current position, e.g. to set up a new preset.
+func (r lectureHallRoutes) moveCamera(c *gin.Context) {
+	var req moveCameraRequest
+	err := c.BindJSON(&req)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not bind body",
+			Err:           err,
+		})
+		return
+	}
+	lectureHall, ok := r.lectureHallWithCamera(c)
+	if !ok {
+		return
+	}
+	err = r.presetUtility.MoveCamera(lectureHall, req.Pan, req.Tilt, req.Zoom)
+	if err != nil {
+		r.cameraError(c, "can not move camera", err)
+		return
+	}
+}
+
+type storePresetRequest struct {
+	PresetID int    `json:"presetID"`
+	Name     string `json:"name"`
+}
+
+// storePreset saves the current position of the lecture halls camera as a preset
+func (r lectureHallRoutes) storePreset(c *gin.Context) {
+	var req storePresetRequest
+	err := c.BindJSON(&req)
+	if err != nil || req.PresetID < 0 || req.Name == "" {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not bind body",
+			Err:           err,
+		})
+		return
+	}
+	lectureHall, ok := r.lectureHallWithCamera(c)
+	if !ok {
+		return
+	}
+	preset, err := r.presetUtility.StorePreset(lectureHall, req.PresetID, req.Name)
+	if err != nil {
+		r.cameraError(c, "can not store preset", err)
+		return
+	}
+	c.JSON(http.StatusOK, preset)
+}
+
+// lectureHallWithCamera fetches the lecture hall identified by the id param and makes sure it has a camera.
+// Aborts the request with an error if not.
+func (r lectureHallRoutes) lectureHallWithCamera(c *gin.Context) (model.LectureHall, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid param 'id'",
+			Err:           err,
+		})
+		return model.LectureHall{}, false
+	}
+	lectureHall, err := r.LectureHallsDao.GetLectureHallByID(uint(id))
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "can not find lecture hall",
+			Err:           err,
+		})
+		return model.LectureHall{}, false
+	}
+	if lectureHall.CameraIP == "" {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "lecture hall has no camera",
+		})
+		return model.LectureHall{}, false
+	}
+	return lectureHall, true
+}
+
+func (r lectureHallRoutes) cameraError(c *gin.Context, msg string, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, camera.ErrUnsupported) {
+		status = http.StatusNotImplemented
+	}
+	logger.Error(msg, "err", err)
+	_ = c.Error(tools.RequestError{
+		Status:        status,
+		CustomMessage: msg,
+		Err:           err,
+	})
+}
+
 func (r lectureHallRoutes) deleteLectureHall(c *gin.Context) {
 	lhIDStr := c.Param("id")
 	lhID, err := strconv.Atoi(lhIDStr)
@@ -341,6 +459,22 @@ func (r lectureHallRoutes) setLectureHall(c *gin.Context) {
 		})
 		return
 	}
+	if !req.Force {
+		conflicts, err := findBookingConflicts(r.LectureHallsDao, req.LectureHallID, streams)
+		if err != nil {
+			logger.Error("can not check for booking conflicts", "err", err)
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not check for booking conflicts",
+				Err:           err,
+			})
+			return
+		}
+		if len(conflicts) > 0 {
+			_ = c.Error(bookingConflictError(conflicts))
+			return
+		}
+	}
 	err = r.StreamsDao.SetLectureHall(req.StreamIDs, req.LectureHallID)
 	if err != nil {
 		logger.Error("can not update lecture hall", "err", err)
@@ -364,26 +498,138 @@ func (r lectureHallRoutes) createLectureHall(c *gin.Context) {
 		})
 		return
 	}
+	if req.CameraType == 0 {
+		req.CameraType = model.Axis
+	}
+	if !req.CameraType.Valid() {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid camera type",
+		})
+		return
+	}
 	r.LectureHallsDao.CreateLectureHall(model.LectureHall{
-		Name:      req.Name,
-		CombIP:    req.CombIP,
-		PresIP:    req.PresIP,
-		CamIP:     req.CamIP,
-		CameraIP:  req.CameraIP,
-		PwrCtrlIp: req.PwrCtrlIP,
+		Name:       req.Name,
+		CombIP:     req.CombIP,
+		PresIP:     req.PresIP,
+		CamIP:      req.CamIP,
+		CameraIP:   req.CameraIP,
+		CameraType: req.CameraType,
+		PwrCtrlIp:  req.PwrCtrlIP,
 	})
 }
 
 type createLectureHallRequest struct {
-	Name      string `json:"name"`
-	CombIP    string `json:"combIP"`
-	PresIP    string `json:"presIP"`
-	CamIP     string `json:"camIP"`
-	CameraIP  string `json:"cameraIP"`
-	PwrCtrlIP string `json:"pwrCtrlIp"`
+	Name       string           `json:"name"`
+	CombIP     string           `json:"combIP"`
+	PresIP     string           `json:"presIP"`
+	CamIP      string           `json:"camIP"`
+	CameraIP   string           `json:"cameraIP"`
+	CameraType model.CameraType `json:"cameraType"`
+	PwrCtrlIP  string           `json:"pwrCtrlIp"`
 }
 
 type setLectureHallRequest struct {
 	StreamIDs     []uint `json:"streamIDs"`
 	LectureHallID uint   `json:"lectureHall"`
+	Force         bool   `json:"force"` // assign the lecture hall even if it's already booked
+}
+
+// getBookings returns all streams assigned to the requested lecture halls (all if none are requested)
+// between from and to (RFC3339, defaults to the next 4 weeks).
+func (r lectureHallRoutes) getBookings(c *gin.Context) {
+	from, to, err := parseBookingRange(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid time range", Err: err})
+		return
+	}
+	var lectureHalls []uint
+	for _, l := range strings.Split(c.Query("lecturehalls"), ",") {
+		if l == "" {
+			continue
+		}
+		id, err := strconv.Atoi(l)
+		if err != nil {
+			_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "Lecture Hall ID must be a number.", Err: err})
+			return
+		}
+		lectureHalls = append(lectureHalls, uint(id))
+	}
+	bookings, err := r.LectureHallsDao.GetBookings(lectureHalls, from, to)
+	if err != nil {
+		logger.Error("can not get bookings", "err", err)
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get bookings", Err: err})
+		return
+	}
+	c.JSON(http.StatusOK, bookings)
+}
+
+// getBookingConflicts returns all pairs of streams that overlap in the same lecture hall between from and to.
+func (r lectureHallRoutes) getBookingConflicts(c *gin.Context) {
+	from, to, err := parseBookingRange(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{Status: http.StatusBadRequest, CustomMessage: "invalid time range", Err: err})
+		return
+	}
+	conflicts, err := r.LectureHallsDao.GetBookingConflicts(from, to)
+	if err != nil {
+		logger.Error("can not get booking conflicts", "err", err)
+		_ = c.Error(tools.RequestError{Status: http.StatusInternalServerError, CustomMessage: "can not get booking conflicts", Err: err})
+		return
+	}
+	c.JSON(http.StatusOK, conflicts)
+}
+
+// parseBookingRange parses the from and to query params. Defaults to now and 4 weeks from now.
+func parseBookingRange(c *gin.Context) (from time.Time, to time.Time, err error) {
+	from, to = time.Now(), time.Now().Add(time.Hour*24*7*4)
+	if f := c.Query("from"); f != "" {
+		if from, err = time.Parse(time.RFC3339, f); err != nil {
+			return
+		}
+	}
+	if t := c.Query("to"); t != "" {
+		if to, err = time.Parse(time.RFC3339, t); err != nil {
+			return
+		}
+	}
+	if !from.Before(to) {
+		err = errors.New("from must be before to")
+	}
+	return
+}
+
+// findBookingConflicts returns a description of every conflict that would arise from holding streams in the lecture hall,
+// either with existing bookings or between the streams themselves.
+func findBookingConflicts(lectureHallsDao dao.LectureHallsDao, lectureHallID uint, streams []model.Stream) ([]string, error) {
+	var exclude []uint
+	for _, s := range streams {
+		if s.ID != 0 {
+			exclude = append(exclude, s.ID)
+		}
+	}
+	var conflicts []string
+	for i, s := range streams {
+		bookings, err := lectureHallsDao.FindConflictingBookings(lectureHallID, s.Start, s.End, exclude)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bookings {
+			conflicts = append(conflicts, fmt.Sprintf("%s overlaps with %s", s.Start.Format("02.01.2006 15:04"), b))
+		}
+		for _, other := range streams[i+1:] {
+			if s.Overlaps(other) {
+				conflicts = append(conflicts, fmt.Sprintf("%s overlaps with %s", s.Start.Format("02.01.2006 15:04"), other.Start.Format("02.01.2006 15:04")))
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+func bookingConflictError(conflicts []string) tools.RequestError {
+	return tools.RequestError{
+		Status:        http.StatusConflict,
+		CustomMessage: "lecture hall is already booked",
+		Err:           errors.New(strings.Join(conflicts, "; ")),
+	}
 }
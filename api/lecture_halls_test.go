@@ -12,8 +12,10 @@ import (
 	campusonline "github.com/RBG-TUM/CAMPUSOnline"
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/mock_tools"
 	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/camera"
 	"github.com/TUM-Dev/gocast/tools/testutils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -1102,7 +1104,7 @@ func TestLectureHallSetLH(t *testing.T) {
 			"can not set lecture hall": {
 				Router: func(r *gin.Engine) {
 					wrapper := dao.DaoWrapper{
-						LectureHallsDao: testutils.GetLectureHallMock(t),
+						LectureHallsDao: lectureHallMockWithBookings(t, nil),
 						StreamsDao: func() dao.StreamsDao {
 							streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
 							streamsMock.
@@ -1129,10 +1131,58 @@ func TestLectureHallSetLH(t *testing.T) {
 				Body:         request,
 				ExpectedCode: http.StatusInternalServerError,
 			},
-			"success": {
+			"lecture hall already booked": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						LectureHallsDao: lectureHallMockWithBookings(t, []dao.Booking{{StreamID: 2, CourseName: "GBS"}}),
+						StreamsDao: func() dao.StreamsDao {
+							streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
+							streamsMock.
+								EXPECT().
+								GetStreamsByIds(request.StreamIDs).
+								Return([]model.Stream{fpvStream}, nil).
+								AnyTimes()
+							return streamsMock
+						}(),
+					}
+					configGinLectureHallApiRouter(r, wrapper, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         request,
+				ExpectedCode: http.StatusConflict,
+			},
+			"force despite booking": {
 				Router: func(r *gin.Engine) {
 					wrapper := dao.DaoWrapper{
 						LectureHallsDao: testutils.GetLectureHallMock(t),
+						StreamsDao: func() dao.StreamsDao {
+							streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
+							streamsMock.
+								EXPECT().
+								GetStreamsByIds(request.StreamIDs).
+								Return([]model.Stream{fpvStream}, nil).
+								AnyTimes()
+							streamsMock.
+								EXPECT().
+								SetLectureHall(request.StreamIDs, request.LectureHallID).
+								Return(nil)
+							return streamsMock
+						}(),
+					}
+					configGinLectureHallApiRouter(r, wrapper, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Middlewares: testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body: setLectureHallRequest{
+					StreamIDs:     request.StreamIDs,
+					LectureHallID: request.LectureHallID,
+					Force:         true,
+				},
+				ExpectedCode: http.StatusOK,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						LectureHallsDao: lectureHallMockWithBookings(t, nil),
 						StreamsDao: func() dao.StreamsDao {
 							streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
 							streamsMock.
@@ -1165,3 +1215,206 @@ func TestLectureHallSetLH(t *testing.T) {
 			Run(t, testutils.Equal)
 	})
 }
+
+// lectureHallMockWithBookings returns a mock that finds testutils.LectureHall and the given conflicting bookings
+func lectureHallMockWithBookings(t *testing.T, bookings []dao.Booking) dao.LectureHallsDao {
+	lectureHallMock := mock_dao.NewMockLectureHallsDao(gomock.NewController(t))
+	lectureHallMock.
+		EXPECT().
+		GetLectureHallByID(testutils.LectureHall.ID).
+		Return(testutils.LectureHall, nil).
+		AnyTimes()
+	lectureHallMock.
+		EXPECT().
+		FindConflictingBookings(testutils.LectureHall.ID, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(bookings, nil).
+		AnyTimes()
+	return lectureHallMock
+}
+
+func TestLectureHallBookings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("GET/api/lectureHalls/bookings", func(t *testing.T) {
+		bookings := []dao.Booking{{StreamID: 1, LectureHallID: testutils.LectureHall.ID, CourseName: "FPV", OwnerName: "Prof"}}
+		gomino.TestCases{
+			"invalid range": {
+				Router:       LectureHallRouterWrapper(t),
+				Url:          "/api/lectureHalls/bookings?from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z",
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"invalid lecture hall": {
+				Router:       LectureHallRouterWrapper(t),
+				Url:          "/api/lectureHalls/bookings?lecturehalls=abc",
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					lectureHallMock := mock_dao.NewMockLectureHallsDao(gomock.NewController(t))
+					lectureHallMock.
+						EXPECT().
+						GetBookings([]uint{1, 2}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)).
+						Return(bookings, nil)
+					configGinLectureHallApiRouter(r, dao.DaoWrapper{LectureHallsDao: lectureHallMock}, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Url:              "/api/lectureHalls/bookings?lecturehalls=1,2&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z",
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: bookings,
+			},
+		}.
+			Method(http.MethodGet).
+			Run(t, testutils.Equal)
+	})
+
+	t.Run("GET/api/lectureHalls/conflicts", func(t *testing.T) {
+		conflicts := []dao.BookingConflict{{A: dao.Booking{StreamID: 1}, B: dao.Booking{StreamID: 2}}}
+		gomino.TestCases{
+			"can not get conflicts": {
+				Router: func(r *gin.Engine) {
+					lectureHallMock := mock_dao.NewMockLectureHallsDao(gomock.NewController(t))
+					lectureHallMock.EXPECT().GetBookingConflicts(gomock.Any(), gomock.Any()).Return(nil, errors.New(""))
+					configGinLectureHallApiRouter(r, dao.DaoWrapper{LectureHallsDao: lectureHallMock}, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusInternalServerError,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					lectureHallMock := mock_dao.NewMockLectureHallsDao(gomock.NewController(t))
+					lectureHallMock.EXPECT().GetBookingConflicts(gomock.Any(), gomock.Any()).Return(conflicts, nil)
+					configGinLectureHallApiRouter(r, dao.DaoWrapper{LectureHallsDao: lectureHallMock}, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: conflicts,
+			},
+		}.
+			Method(http.MethodGet).
+			Url("/api/lectureHalls/conflicts").
+			Run(t, testutils.Equal)
+	})
+}
+
+func TestLectureHallCameraControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("POST/api/lectureHall/:id/move", func(t *testing.T) {
+		url := fmt.Sprintf("/api/lectureHall/%d/move", testutils.LectureHall.ID)
+		body := moveCameraRequest{Pan: 0.1, Tilt: -0.1}
+
+		gomino.TestCases{
+			"invalid body": {
+				Router:       LectureHallRouterWrapper(t),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"invalid id": {
+				Router:       LectureHallRouterWrapper(t),
+				Url:          "/api/lectureHall/abc/move",
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         body,
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"lecture hall without camera": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{
+						LectureHallsDao: func() dao.LectureHallsDao {
+							lectureHallMock := mock_dao.NewMockLectureHallsDao(gomock.NewController(t))
+							lectureHallMock.
+								EXPECT().
+								GetLectureHallByID(testutils.LectureHall.ID).
+								Return(testutils.EmptyLectureHall, nil).
+								AnyTimes()
+							return lectureHallMock
+						}(),
+					}
+					configGinLectureHallApiRouter(r, wrapper, testutils.GetPresetUtilityMock(gomock.NewController(t)))
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         body,
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"unsupported by camera": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{LectureHallsDao: testutils.GetLectureHallMock(t)}
+					presetUtility := mock_tools.NewMockPresetUtility(gomock.NewController(t))
+					presetUtility.
+						EXPECT().
+						MoveCamera(testutils.LectureHall, body.Pan, body.Tilt, body.Zoom).
+						Return(camera.ErrUnsupported)
+					configGinLectureHallApiRouter(r, wrapper, presetUtility)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         body,
+				ExpectedCode: http.StatusNotImplemented,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{LectureHallsDao: testutils.GetLectureHallMock(t)}
+					presetUtility := mock_tools.NewMockPresetUtility(gomock.NewController(t))
+					presetUtility.
+						EXPECT().
+						MoveCamera(testutils.LectureHall, body.Pan, body.Tilt, body.Zoom).
+						Return(nil)
+					configGinLectureHallApiRouter(r, wrapper, presetUtility)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         body,
+				ExpectedCode: http.StatusOK,
+			},
+		}.
+			Method(http.MethodPost).
+			Url(url).
+			Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/lectureHall/:id/presets", func(t *testing.T) {
+		url := fmt.Sprintf("/api/lectureHall/%d/presets", testutils.LectureHall.ID)
+		body := storePresetRequest{PresetID: 5, Name: "Blackboard"}
+		preset := model.CameraPreset{Name: body.Name, PresetID: body.PresetID, LectureHallID: testutils.LectureHall.ID}
+
+		gomino.TestCases{
+			"missing name": {
+				Router:       LectureHallRouterWrapper(t),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         storePresetRequest{PresetID: 5},
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"can not store preset": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{LectureHallsDao: testutils.GetLectureHallMock(t)}
+					presetUtility := mock_tools.NewMockPresetUtility(gomock.NewController(t))
+					presetUtility.
+						EXPECT().
+						StorePreset(testutils.LectureHall, body.PresetID, body.Name).
+						Return(model.CameraPreset{}, errors.New(""))
+					configGinLectureHallApiRouter(r, wrapper, presetUtility)
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         body,
+				ExpectedCode: http.StatusInternalServerError,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					wrapper := dao.DaoWrapper{LectureHallsDao: testutils.GetLectureHallMock(t)}
+					presetUtility := mock_tools.NewMockPresetUtility(gomock.NewController(t))
+					presetUtility.
+						EXPECT().
+						StorePreset(testutils.LectureHall, body.PresetID, body.Name).
+						Return(preset, nil)
+					configGinLectureHallApiRouter(r, wrapper, presetUtility)
+				},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:             body,
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: preset,
+			},
+		}.
+			Method(http.MethodPost).
+			Url(url).
+			Run(t, testutils.Equal)
+	})
+}
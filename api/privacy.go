@@ -0,0 +1,480 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// userSettingNames are the names of user settings in exports
+var userSettingNames = map[model.UserSettingType]string{
+	model.PreferredName:        "preferred_name",
+	model.Greeting:             "greeting",
+	model.CustomPlaybackSpeeds: "playback_speeds",
+	model.SeekingTime:          "seeking_time",
+	model.UserDefinedSpeeds:    "custom_speeds",
+	model.AutoSkip:             "auto_skip",
+	model.DefaultMode:          "default_mode",
+}
+
+type exportedCourse struct {
+	Year   int    `json:"year,omitempty"`
+	Term   string `json:"term,omitempty"`
+	Course string `json:"course,omitempty"`
+}
+
+type personalData struct {
+	UserData struct {
+		Name      string    `json:"name,omitempty"`
+		LastName  *string   `json:"last_name,omitempty"`
+		Email     string    `json:"email,omitempty"`
+		LrzID     string    `json:"lrz_id,omitempty"`
+		MatrNr    string    `json:"matr_nr,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"user_data"`
+	Settings []struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	} `json:"settings,omitempty"`
+	Enrollments         []exportedCourse `json:"enrollments,omitempty"`
+	AdministeredCourses []exportedCourse `json:"administered_courses,omitempty"`
+	PinnedCourses       []exportedCourse `json:"pinned_courses,omitempty"`
+	VideoViews          []struct {
+		StreamID       uint    `json:"stream_id"`
+		Progress       float64 `json:"progress"`
+		MarkedFinished bool    `json:"marked_finished,omitempty"`
+	} `json:"video_views,omitempty"`
+	ViewSessions []struct {
+		StreamID uint      `json:"stream_id"`
+		JoinedAt time.Time `json:"joined_at"`
+		LeftAt   time.Time `json:"left_at"`
+		Live     bool      `json:"live,omitempty"`
+	} `json:"view_sessions,omitempty"`
+	Bookmarks []struct {
+		StreamID    uint      `json:"stream_id"`
+		Description string    `json:"description"`
+		Timestamp   string    `json:"timestamp"`
+		CreatedAt   time.Time `json:"created_at"`
+	} `json:"bookmarks,omitempty"`
+	Chats []struct {
+		StreamId  uint      `json:"stream_id,omitempty"`
+		Message   string    `json:"message,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"chats,omitempty"`
+	ChatReactions []struct {
+		ChatID uint   `json:"chat_id"`
+		Emoji  string `json:"emoji"`
+	} `json:"chat_reactions,omitempty"`
+	PollVotes []struct {
+		StreamID uint   `json:"stream_id"`
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	} `json:"poll_votes,omitempty"`
+	Tokens []struct {
+		Scope     string     `json:"scope"`
+		CreatedAt time.Time  `json:"created_at"`
+		Expires   *time.Time `json:"expires,omitempty"`
+		LastUse   *time.Time `json:"last_use,omitempty"`
+	} `json:"tokens,omitempty"`
+	SubtitleEdits []struct {
+		ID          uint      `json:"id"`
+		SubtitlesID uint      `json:"subtitles_id"`
+		CreatedAt   time.Time `json:"created_at"`
+	} `json:"subtitle_edits,omitempty"`
+	AuditEntries []struct {
+		Type      string    `json:"type"`
+		Message   string    `json:"message"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"audit_entries,omitempty"`
+	DeletionRequest *model.DeletionRequest `json:"deletion_request,omitempty"`
+}
+
+// exportReadme explains the files of the ZIP export
+const exportReadme = `Personal data stored by TUM-Live
+
+personal_data.json  all data stored about you:
+  user_data             your account
+  settings              your preferences, values are JSON encoded
+  enrollments           courses you are enrolled in
+  administered_courses  courses you are an admin of
+  pinned_courses        courses you pinned
+  video_views           your progress in recordings
+  view_sessions         when you watched which lecture
+  bookmarks             your bookmarks in recordings
+  chats                 your chat messages
+  chat_reactions        your reactions to chat messages
+  poll_votes            your answers to polls
+  tokens                your tokens, e.g. calendar feeds, without their secrets
+  subtitle_edits        versions of subtitles you edited, see subtitle_edits/
+  audit_entries         changes you made as lecturer or admin
+  deletion_request      your pending account deletion
+subtitle_edits/     the subtitles you edited as WebVTT, named by the id in subtitle_edits
+
+Seek reports are collected without any reference to you and therefore aren't part of this export.
+`
+
+func exportCourses(courses []model.Course) []exportedCourse {
+	res := make([]exportedCourse, len(courses))
+	for i, course := range courses {
+		res[i] = exportedCourse{course.Year, course.TeachingTerm, course.Name}
+	}
+	return res
+}
+
+// collectPersonalData returns all data stored about u and the content of the subtitles u edited by revision id
+func (r usersRoutes) collectPersonalData(ctx context.Context, u *model.User) (resp personalData, subtitles map[uint]string, err error) {
+	resp.UserData.Name = u.Name
+	resp.UserData.LastName = u.LastName
+	resp.UserData.Email = u.Email.String
+	resp.UserData.LrzID = u.LrzID
+	resp.UserData.MatrNr = u.MatriculationNumber
+	resp.UserData.CreatedAt = u.CreatedAt
+	for _, setting := range u.Settings {
+		value := json.RawMessage(setting.Value)
+		if !json.Valid(value) {
+			value, _ = json.Marshal(setting.Value) // e.g. preferred names are stored unencoded
+		}
+		resp.Settings = append(resp.Settings, struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		}{userSettingNames[setting.Type], value})
+	}
+	resp.Enrollments = exportCourses(u.Courses)
+	resp.AdministeredCourses = exportCourses(u.AdministeredCourses)
+	resp.PinnedCourses = exportCourses(u.PinnedCourses)
+
+	progresses, err := r.ProgressDao.GetProgressesForUser(u.ID)
+	if err != nil {
+		return resp, nil, fmt.Errorf("get progresses: %w", err)
+	}
+	for _, progress := range progresses {
+		resp.VideoViews = append(resp.VideoViews, struct {
+			StreamID       uint    `json:"stream_id"`
+			Progress       float64 `json:"progress"`
+			MarkedFinished bool    `json:"marked_finished,omitempty"`
+		}{StreamID: progress.StreamID, Progress: progress.Progress, MarkedFinished: progress.Watched})
+	}
+	chats, err := r.ChatDao.GetChatsByUser(u.ID)
+	if err != nil {
+		return resp, nil, fmt.Errorf("get chats: %w", err)
+	}
+	for _, chat := range chats {
+		resp.Chats = append(resp.Chats, struct {
+			StreamId  uint      `json:"stream_id,omitempty"`
+			Message   string    `json:"message,omitempty"`
+			CreatedAt time.Time `json:"created_at"`
+		}{chat.StreamID, chat.Message, chat.CreatedAt})
+	}
+
+	data, err := r.PrivacyDao.GetPersonalData(ctx, u.ID)
+	if err != nil {
+		return resp, nil, fmt.Errorf("get personal data: %w", err)
+	}
+	for _, s := range data.ViewSessions {
+		resp.ViewSessions = append(resp.ViewSessions, struct {
+			StreamID uint      `json:"stream_id"`
+			JoinedAt time.Time `json:"joined_at"`
+			LeftAt   time.Time `json:"left_at"`
+			Live     bool      `json:"live,omitempty"`
+		}{s.StreamID, s.JoinedAt, s.LeftAt, s.Live})
+	}
+	for _, b := range data.Bookmarks {
+		resp.Bookmarks = append(resp.Bookmarks, struct {
+			StreamID    uint      `json:"stream_id"`
+			Description string    `json:"description"`
+			Timestamp   string    `json:"timestamp"`
+			CreatedAt   time.Time `json:"created_at"`
+		}{b.StreamID, b.Description, fmt.Sprintf("%02d:%02d:%02d", b.Hours, b.Minutes, b.Seconds), b.CreatedAt})
+	}
+	for _, reaction := range data.ChatReactions {
+		resp.ChatReactions = append(resp.ChatReactions, struct {
+			ChatID uint   `json:"chat_id"`
+			Emoji  string `json:"emoji"`
+		}{reaction.ChatID, reaction.Emoji})
+	}
+	for _, vote := range data.PollVotes {
+		resp.PollVotes = append(resp.PollVotes, struct {
+			StreamID uint   `json:"stream_id"`
+			Question string `json:"question"`
+			Answer   string `json:"answer"`
+		}{vote.StreamID, vote.Question, vote.Answer})
+	}
+	for _, token := range data.Tokens {
+		t := struct {
+			Scope     string     `json:"scope"`
+			CreatedAt time.Time  `json:"created_at"`
+			Expires   *time.Time `json:"expires,omitempty"`
+			LastUse   *time.Time `json:"last_use,omitempty"`
+		}{Scope: token.Scope, CreatedAt: token.CreatedAt}
+		if token.Expires.Valid {
+			t.Expires = &token.Expires.Time
+		}
+		if token.LastUse.Valid {
+			t.LastUse = &token.LastUse.Time
+		}
+		resp.Tokens = append(resp.Tokens, t)
+	}
+	subtitles = map[uint]string{}
+	for _, edit := range data.SubtitleEdits {
+		resp.SubtitleEdits = append(resp.SubtitleEdits, struct {
+			ID          uint      `json:"id"`
+			SubtitlesID uint      `json:"subtitles_id"`
+			CreatedAt   time.Time `json:"created_at"`
+		}{edit.ID, edit.SubtitlesID, edit.CreatedAt})
+		subtitles[edit.ID] = edit.Content
+	}
+	for _, audit := range data.Audits {
+		resp.AuditEntries = append(resp.AuditEntries, struct {
+			Type      string    `json:"type"`
+			Message   string    `json:"message"`
+			CreatedAt time.Time `json:"created_at"`
+		}{audit.Type.String(), audit.Message, audit.CreatedAt})
+	}
+
+	request, err := r.PrivacyDao.GetDeletionRequest(ctx, u.ID)
+	if err == nil {
+		resp.DeletionRequest = &request
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return resp, nil, fmt.Errorf("get deletion request: %w", err)
+	}
+	return resp, subtitles, nil
+}
+
+// exportPersonalData exports all data stored about the user as JSON or, with format=zip, as ZIP
+// including the files the user created.
+func (r usersRoutes) exportPersonalData(c *gin.Context) {
+	u := getUserFromContext(c)
+	if u == nil {
+		return
+	}
+	resp, subtitles, err := r.collectPersonalData(c, u)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not collect personal data",
+			Err:           err,
+		})
+		return
+	}
+	marshal, err := json.MarshalIndent(resp, "", "    ")
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not marshal response",
+			Err:           err,
+		})
+		return
+	}
+	if c.Query("format") != "zip" {
+		c.Header("Content-Disposition", `attachment; filename="personal_data.json"`)
+		c.Data(http.StatusOK, "application/json;charset=utf-8", marshal)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="personal_data.zip"`)
+	c.Header("Content-Type", "application/zip")
+	w := zip.NewWriter(c.Writer)
+	files := map[string]string{"personal_data.json": string(marshal), "README.txt": exportReadme}
+	for id, content := range subtitles {
+		files[fmt.Sprintf("subtitle_edits/%d.vtt", id)] = content
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err == nil {
+			_, err = f.Write([]byte(content))
+		}
+		if err != nil {
+			logger.Error("can not write personal data export", "err", err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		logger.Error("can not write personal data export", "err", err)
+	}
+}
+
+// getDeletionRequest returns the pending deletion of the user's account
+func (r usersRoutes) getDeletionRequest(c *gin.Context) {
+	u := getUserFromContext(c)
+	if u == nil {
+		return
+	}
+	request, err := r.PrivacyDao.GetDeletionRequest(c, u.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, nil)
+		return
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get deletion request",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// requestDeletion schedules the deletion of the user's account after the grace period
+func (r usersRoutes) requestDeletion(c *gin.Context) {
+	u := getUserFromContext(c)
+	if u == nil {
+		return
+	}
+	if u.Role == model.AdminType || u.Role == model.LecturerType {
+		// their courses and streams must be handed over first
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusForbidden,
+			CustomMessage: "accounts of admins and lecturers can only be deleted by an admin",
+		})
+		return
+	}
+	request := model.NewDeletionRequest(u.ID, time.Now())
+	if err := r.PrivacyDao.CreateDeletionRequest(c, &request); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not create deletion request",
+			Err:           err,
+		})
+		return
+	}
+	if u.Email.Valid {
+		err := r.EmailDao.Create(c, &model.Email{
+			From:    tools.Cfg.Mail.Sender,
+			To:      u.Email.String,
+			Subject: "TUM-Live: Your account will be deleted",
+			Body: "Hi!\n\nYour TUM-Live account and your personal data will be deleted on " + request.DeleteAt.In(tools.Loc).Format("02.01.2006 15:04") +
+				". Until then, you can cancel the deletion in your settings: " + tools.Cfg.WebUrl + "/settings\n\nBest regards",
+		})
+		if err != nil {
+			logger.Error("can not send deletion request email", "err", err)
+		}
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// cancelDeletion cancels the pending deletion of the user's account
+func (r usersRoutes) cancelDeletion(c *gin.Context) {
+	u := getUserFromContext(c)
+	if u == nil {
+		return
+	}
+	if err := r.PrivacyDao.DeleteDeletionRequest(c, u.ID); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not cancel deletion request",
+			Err:           err,
+		})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// getDeletionRequests returns all pending account deletions
+func (r usersRoutes) getDeletionRequests(c *gin.Context) {
+	requests, err := r.PrivacyDao.GetDeletionRequests(c)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get deletion requests",
+			Err:           err,
+		})
+		return
+	}
+	res := make([]gin.H, len(requests))
+	for i, request := range requests {
+		res[i] = gin.H{
+			"userID":    request.UserID,
+			"name":      request.User.Name,
+			"login":     request.User.GetLoginString(),
+			"createdAt": request.CreatedAt,
+			"deleteAt":  request.DeleteAt,
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func parseUserIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("userID"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid user id",
+			Err:           err,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// cancelDeletionRequest cancels the pending deletion of an account on behalf of its user
+func (r usersRoutes) cancelDeletionRequest(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+	if err := r.PrivacyDao.DeleteDeletionRequest(c, id); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not cancel deletion request",
+			Err:           err,
+		})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// executeDeletionRequest deletes an account with a pending deletion request before the grace period ends
+func (r usersRoutes) executeDeletionRequest(c *gin.Context) {
+	id, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+	if _, err := r.PrivacyDao.GetDeletionRequest(c, id); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "no deletion requested",
+			Err:           err,
+		})
+		return
+	}
+	if err := r.PrivacyDao.DeleteUserData(c, id); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not delete user",
+			Err:           err,
+		})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// DeleteDueAccounts deletes the accounts whose grace period ended
+func DeleteDueAccounts(daoWrapper dao.DaoWrapper) func() {
+	return func() {
+		ctx := context.Background()
+		requests, err := daoWrapper.PrivacyDao.GetDueDeletionRequests(ctx, time.Now())
+		if err != nil {
+			logger.Error("can not get due deletion requests", "err", err)
+			return
+		}
+		for _, request := range requests {
+			if err := daoWrapper.PrivacyDao.DeleteUserData(ctx, request.UserID); err != nil {
+				logger.Error("can not delete user", "err", err, "user", request.UserID)
+				continue
+			}
+			logger.Info("deleted account after deletion request", "user", request.UserID)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+func TestAccountDeletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("POST/api/users/deletion", func(t *testing.T) {
+		url := "/api/users/deletion"
+		gomino.TestCases{
+			"not logged in": {
+				Router:       func(r *gin.Engine) { configGinUsersRouter(r, dao.DaoWrapper{}) },
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextUserNil)),
+				ExpectedCode: http.StatusUnauthorized,
+			},
+			"admin": {
+				Router:       func(r *gin.Engine) { configGinUsersRouter(r, dao.DaoWrapper{}) },
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					privacyMock := mock_dao.NewMockPrivacyDao(gomock.NewController(t))
+					privacyMock.EXPECT().CreateDeletionRequest(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ any, request *model.DeletionRequest) error {
+							if request.UserID != testutils.Student.ID || time.Until(request.DeleteAt) < model.AccountDeletionGracePeriod-time.Minute {
+								t.Errorf("unexpected deletion request %+v", request)
+							}
+							return nil
+						})
+					configGinUsersRouter(r, dao.DaoWrapper{PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusCreated,
+			},
+		}.Method(http.MethodPost).Url(url).Run(t, testutils.Equal)
+	})
+
+	t.Run("GET/api/users/deletionRequests", func(t *testing.T) {
+		url := "/api/users/deletionRequests"
+		deleteAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		gomino.TestCases{
+			"database error": {
+				Router: func(r *gin.Engine) {
+					privacyMock := mock_dao.NewMockPrivacyDao(gomock.NewController(t))
+					privacyMock.EXPECT().GetDeletionRequests(gomock.Any()).Return(nil, errors.New(""))
+					configGinUsersRouter(r, dao.DaoWrapper{PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusInternalServerError,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					privacyMock := mock_dao.NewMockPrivacyDao(gomock.NewController(t))
+					privacyMock.EXPECT().GetDeletionRequests(gomock.Any()).Return([]model.DeletionRequest{{
+						Model:    gorm.Model{CreatedAt: deleteAt.Add(-model.AccountDeletionGracePeriod)},
+						UserID:   testutils.Student.ID,
+						User:     model.User{Name: "Hansi", LrzID: "ab12cde"},
+						DeleteAt: deleteAt,
+					}}, nil)
+					configGinUsersRouter(r, dao.DaoWrapper{PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: []gin.H{{
+					"userID":    testutils.Student.ID,
+					"name":      "Hansi",
+					"login":     "ab12cde",
+					"createdAt": deleteAt.Add(-model.AccountDeletionGracePeriod),
+					"deleteAt":  deleteAt,
+				}},
+			},
+		}.Method(http.MethodGet).Url(url).Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/users/deletionRequests/:userID/execute", func(t *testing.T) {
+		gomino.TestCases{
+			"no request": {
+				Router: func(r *gin.Engine) {
+					privacyMock := mock_dao.NewMockPrivacyDao(gomock.NewController(t))
+					privacyMock.EXPECT().GetDeletionRequest(gomock.Any(), uint(42)).Return(model.DeletionRequest{}, gorm.ErrRecordNotFound)
+					configGinUsersRouter(r, dao.DaoWrapper{PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusNotFound,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					privacyMock := mock_dao.NewMockPrivacyDao(gomock.NewController(t))
+					privacyMock.EXPECT().GetDeletionRequest(gomock.Any(), uint(42)).Return(model.DeletionRequest{UserID: 42}, nil)
+					privacyMock.EXPECT().DeleteUserData(gomock.Any(), uint(42)).Return(nil)
+					configGinUsersRouter(r, dao.DaoWrapper{PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.Method(http.MethodPost).Url("/api/users/deletionRequests/42/execute").Run(t, testutils.Equal)
+	})
+}
+
+func TestExportPersonalData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("GET/api/users/exportData", func(t *testing.T) {
+		gomino.TestCases{
+			"not logged in": {
+				Router:       func(r *gin.Engine) { configGinUsersRouter(r, dao.DaoWrapper{}) },
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextUserNil)),
+				ExpectedCode: http.StatusUnauthorized,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					ctrl := gomock.NewController(t)
+					progressMock := mock_dao.NewMockProgressDao(ctrl)
+					progressMock.EXPECT().GetProgressesForUser(testutils.Student.ID).Return(nil, nil)
+					chatMock := mock_dao.NewMockChatDao(ctrl)
+					chatMock.EXPECT().GetChatsByUser(testutils.Student.ID).Return([]model.Chat{{StreamID: 1, Message: "hi"}}, nil)
+					privacyMock := mock_dao.NewMockPrivacyDao(ctrl)
+					privacyMock.EXPECT().GetPersonalData(gomock.Any(), testutils.Student.ID).Return(dao.PersonalData{
+						Bookmarks: []model.Bookmark{{StreamID: 1, Description: "exam", Hours: 1, Minutes: 2, Seconds: 3}},
+						PollVotes: []dao.PollVote{{StreamID: 1, Question: "?", Answer: "yes"}},
+					}, nil)
+					privacyMock.EXPECT().GetDeletionRequest(gomock.Any(), testutils.Student.ID).Return(model.DeletionRequest{}, gorm.ErrRecordNotFound)
+					configGinUsersRouter(r, dao.DaoWrapper{ProgressDao: progressMock, ChatDao: chatMock, PrivacyDao: privacyMock})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: indentedExport(t, gin.H{
+					"user_data":      gin.H{"created_at": time.Time{}},
+					"pinned_courses": []gin.H{{"year": testutils.CourseFPV.Year, "term": testutils.CourseFPV.TeachingTerm, "course": testutils.CourseFPV.Name}},
+					"bookmarks":      []gin.H{{"stream_id": 1, "description": "exam", "timestamp": "01:02:03", "created_at": time.Time{}}},
+					"chats":          []gin.H{{"stream_id": 1, "message": "hi", "created_at": time.Time{}}},
+					"poll_votes":     []gin.H{{"stream_id": 1, "question": "?", "answer": "yes"}},
+				}),
+			},
+		}.Method(http.MethodGet).Url("/api/users/exportData").Run(t, testutils.Equal)
+	})
+}
+
+// indentedExport returns data formatted like the export
+func indentedExport(t *testing.T, data gin.H) []byte {
+	var export personalData
+	j, _ := json.Marshal(data)
+	if err := json.Unmarshal(j, &export); err != nil {
+		t.Fatal(err)
+	}
+	j, _ = json.MarshalIndent(export, "", "    ")
+	return j
+}
@@ -47,4 +47,5 @@ func ConfigGinRouter(router *gin.Engine) {
 	configGinBookmarksRouter(router, daoWrapper)
 	configMaintenanceRouter(router, daoWrapper)
 	configSemestersRouter(router, daoWrapper)
+	configAccessGroupsRouter(router, daoWrapper)
 }
@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/model"
@@ -19,6 +21,7 @@ type statReq struct {
 type statExportReq struct {
 	Format   string   `form:"format" binding:"required"`
 	Interval []string `form:"interval[]"  binding:"required"`
+	Lecture  string   `form:"lecture"` // required for lecture specific intervals like "retention"
 }
 
 func (r coursesRoutes) getStats(c *gin.Context) {
@@ -64,6 +67,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 	} else {
 		sid = ^uint(0)
 	}
+	key := statsKey(cid, req.Interval, req.Lecture)
 
 	switch req.Interval {
 	case "week":
@@ -91,7 +95,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			Options:   newChartJsOptions(),
 		}
 		resp.Data.Datasets[0].Label = "Sum(viewers)"
-		resp.Data.Datasets[0].Data = res
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, res)
 		c.JSON(http.StatusOK, resp)
 	case "hour":
 		var res []dao.Stat
@@ -116,7 +120,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			Options:   newChartJsOptions(),
 		}
 		resp.Data.Datasets[0].Label = "Sum(viewers)"
-		resp.Data.Datasets[0].Data = res
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, res)
 		c.JSON(http.StatusOK, resp)
 	case "lecture":
 		res, err := r.StatisticsDao.GetLectureStats(cid, sid)
@@ -135,7 +139,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			Options:   newChartJsOptions(),
 		}
 		resp.Data.Datasets[0].Label = "View Count"
-		resp.Data.Datasets[0].Data = res
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, res)
 		c.JSON(http.StatusOK, resp)
 	case "activity-live":
 		resLive, err := r.StatisticsDao.GetStudentActivityCourseStats(cid, true)
@@ -154,7 +158,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			Options:   newChartJsOptions(),
 		}
 		resp.Data.Datasets[0].Label = "Live"
-		resp.Data.Datasets[0].Data = resLive
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, resLive)
 		resp.Data.Datasets[0].BorderColor = "#d12a5c"
 		resp.Data.Datasets[0].BackgroundColor = ""
 
@@ -176,7 +180,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			Options:   newChartJsOptions(),
 		}
 		resp.Data.Datasets[0].Label = "VoD"
-		resp.Data.Datasets[0].Data = resVod
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, resVod)
 		resp.Data.Datasets[0].BorderColor = "#2a7dd1"
 		resp.Data.Datasets[0].BackgroundColor = ""
 		c.JSON(http.StatusOK, resp)
@@ -191,7 +195,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			})
 			return
 		} else {
-			c.JSON(http.StatusOK, gin.H{"res": res})
+			c.JSON(http.StatusOK, anonymizedCount(key, int(res)))
 		}
 	case "vodViews":
 		var res int
@@ -210,7 +214,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			})
 			return
 		} else {
-			c.JSON(http.StatusOK, gin.H{"res": res})
+			c.JSON(http.StatusOK, anonymizedCount(key, int(res)))
 		}
 	case "liveViews":
 		var res int
@@ -229,7 +233,7 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 			})
 			return
 		} else {
-			c.JSON(http.StatusOK, gin.H{"res": res})
+			c.JSON(http.StatusOK, anonymizedCount(key, int(res)))
 		}
 	case "allDays":
 		{
@@ -255,11 +259,56 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 					Options:   newChartJsOptions(),
 				}
 				resp.Data.Datasets[0].Label = "views"
-				resp.Data.Datasets[0].Data = res
+				resp.Data.Datasets[0].Data = tools.AnonymizeStats(key, res)
 				resp.Data.Datasets[0].BackgroundColor = "#d12a5c"
 				c.JSON(http.StatusOK, resp)
 			}
 		}
+	case "retention":
+		live, vod, err := r.getLectureRetention(c, cid, req.Lecture)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		resp := chartJs{
+			ChartType: "line",
+			Data:      chartJsData{Datasets: []chartJsDataset{newChartJsDataset(), newChartJsDataset()}},
+			Options:   newChartJsOptions(),
+		}
+		resp.Data.Datasets[0].Label = "Live"
+		resp.Data.Datasets[0].Data = tools.AnonymizeStats(key+"/live", live)
+		resp.Data.Datasets[0].BorderColor = "#d12a5c"
+		resp.Data.Datasets[0].BackgroundColor = ""
+		resp.Data.Datasets[1].Label = "VoD"
+		resp.Data.Datasets[1].Data = tools.AnonymizeStats(key+"/vod", vod)
+		resp.Data.Datasets[1].BorderColor = "#2a7dd1"
+		resp.Data.Datasets[1].BackgroundColor = ""
+		c.JSON(http.StatusOK, resp)
+	case "engagement":
+		res, err := r.StatisticsDao.GetLectureEngagement(cid)
+		if err != nil {
+			logger.Warn("GetLectureEngagement failed", "err", err, "courseId", cid)
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not get lecture engagement",
+				Err:           err,
+			})
+			return
+		}
+		resp := chartJs{
+			ChartType: "bar",
+			Data:      chartJsData{},
+			Options:   newChartJsOptions(),
+		}
+		for i, stats := range engagementStats(key, res) {
+			dataset := newChartJsDataset()
+			dataset.Label = engagementMetrics[i].label
+			dataset.Data = stats
+			dataset.BorderColor = engagementMetrics[i].color
+			dataset.BackgroundColor = engagementMetrics[i].color
+			resp.Data.Datasets = append(resp.Data.Datasets, dataset)
+		}
+		c.JSON(http.StatusOK, resp)
 	default:
 		_ = c.Error(tools.RequestError{
 			Status:        http.StatusBadRequest,
@@ -269,6 +318,119 @@ func (r coursesRoutes) getStats(c *gin.Context) {
 	}
 }
 
+// getLectureRetention returns how many viewers were still watching at each minute of the lecture, live and as VoD.
+// The returned error is a tools.RequestError.
+func (r coursesRoutes) getLectureRetention(c *gin.Context, cid uint, lecture string) (live []dao.Stat, vod []dao.Stat, err error) {
+	if lecture == "" {
+		return nil, nil, tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "retention requires a lecture",
+		}
+	}
+	stream, err := r.StreamsDao.GetStreamByID(c, lecture)
+	if err != nil || (cid != 0 && stream.CourseID != cid) {
+		return nil, nil, tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "can not find lecture",
+			Err:           err,
+		}
+	}
+	sessions, err := r.StatisticsDao.GetViewSessions(stream.ID, true)
+	if err != nil {
+		logger.Warn("GetViewSessions failed", "err", err, "streamId", stream.ID)
+		return nil, nil, tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get view sessions",
+			Err:           err,
+		}
+	}
+	progresses, err := r.StatisticsDao.GetLectureProgresses(stream.ID)
+	if err != nil {
+		logger.Warn("GetLectureProgresses failed", "err", err, "streamId", stream.ID)
+		return nil, nil, tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get lecture progresses",
+			Err:           err,
+		}
+	}
+
+	liveMinutes := int(stream.End.Sub(stream.Start).Minutes())
+	vodMinutes := liveMinutes
+	if stream.Duration.Valid {
+		vodMinutes = int(stream.Duration.Int32 / 60)
+	}
+	return minuteStats(model.LiveRetention(sessions, stream.Start, liveMinutes)),
+		minuteStats(model.VodRetention(progresses, vodMinutes)), nil
+}
+
+func minuteStats(values []int) []dao.Stat {
+	res := make([]dao.Stat, len(values))
+	for i, v := range values {
+		res[i] = dao.Stat{X: strconv.Itoa(i), Y: v}
+	}
+	return res
+}
+
+var engagementMetrics = []struct {
+	label string
+	color string
+	rate  bool // percentage of viewers instead of a count
+	value func(dao.LectureEngagement) int
+}{
+	{"Peak live viewers", "#d12a5c", false, func(e dao.LectureEngagement) int { return e.PeakLiveViewers }},
+	{"VoD views", "#2a7dd1", false, func(e dao.LectureEngagement) int { return e.VodViews }},
+	{"Chat participation (%)", "#2ad19e", true, dao.LectureEngagement.ChatParticipation},
+	{"Poll participation (%)", "#d1a42a", true, dao.LectureEngagement.PollParticipation},
+}
+
+// engagementStats returns one list of stats per engagementMetrics entry, labeled with the lectures.
+// Participation rates of lectures with fewer logged-in viewers than the minimum group size are left out.
+func engagementStats(key string, lectures []dao.LectureEngagement) [][]dao.Stat {
+	res := make([][]dao.Stat, len(engagementMetrics))
+	for i, metric := range engagementMetrics {
+		res[i] = make([]dao.Stat, 0, len(lectures))
+		for _, lecture := range lectures {
+			if metric.rate && lecture.LiveUsers < tools.Cfg.Stats.MinGroupSize {
+				continue
+			}
+			label := lecture.Start.Format("02.01.2006")
+			if lecture.Name != "" {
+				label += " " + lecture.Name
+			}
+			res[i] = append(res[i], dao.Stat{X: label, Y: metric.value(lecture)})
+		}
+		if !metric.rate {
+			res[i] = tools.AnonymizeStats(key+"/"+metric.label, res[i])
+		}
+	}
+	return res
+}
+
+// statsKey identifies a series of stats for tools.AnonymizeStats. The same data gets the same key in the
+// stats and the export endpoint, so the noise can't be averaged out by requesting both.
+func statsKey(cid uint, interval string, lecture string) string {
+	if interval == "week" {
+		interval = "day" // same data, see getStats
+	}
+	return fmt.Sprintf("%d/%s/%s", cid, interval, lecture)
+}
+
+// appendAnonymizedCount appends the count labeled with name unless it has to be suppressed
+func appendAnonymizedCount(stats []dao.Stat, key string, name string, count int) []dao.Stat {
+	if res, ok := tools.AnonymizeCount(key, count); ok {
+		return append(stats, dao.Stat{X: name, Y: res})
+	}
+	return stats
+}
+
+// anonymizedCount returns the response for a count. Counts below the minimum group size are 0 and flagged as anonymized.
+func anonymizedCount(key string, count int) gin.H {
+	if res, ok := tools.AnonymizeCount(key, count); ok {
+		return gin.H{"res": res, "anonymized": false}
+	}
+	return gin.H{"res": 0, "anonymized": true, "minGroupSize": tools.Cfg.Stats.MinGroupSize}
+}
+
 func (r coursesRoutes) exportStats(c *gin.Context) {
 	ctx, _ := c.Get("TUMLiveContext")
 
@@ -321,7 +483,7 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Name:  interval,
 				XName: "Weekday",
 				YName: "Sum(viewers)",
-				Data:  res,
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, ""), res),
 			})
 
 		case "hour":
@@ -333,7 +495,7 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Name:  interval,
 				XName: "Hour",
 				YName: "Sum(viewers)",
-				Data:  res,
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, ""), res),
 			})
 
 		case "activity-live":
@@ -345,7 +507,7 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Name:  interval,
 				XName: "Week",
 				YName: "Live",
-				Data:  resLive,
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, ""), resLive),
 			})
 
 		case "activity-vod":
@@ -357,7 +519,7 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Name:  interval,
 				XName: "Week",
 				YName: "VoD",
-				Data:  resVod,
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, ""), resVod),
 			})
 
 		case "allDays":
@@ -369,7 +531,7 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Name:  interval,
 				XName: "Week",
 				YName: "VoD",
-				Data:  res,
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, ""), res),
 			})
 
 		case "quickStats":
@@ -379,21 +541,21 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 			if err != nil {
 				logger.Warn("GetCourseNumStudents failed", "err", err, "courseId", cid)
 			} else {
-				quickStats = append(quickStats, dao.Stat{X: "Enrolled Students", Y: int(numStudents)})
+				quickStats = appendAnonymizedCount(quickStats, statsKey(cid, "numStudents", ""), "Enrolled Students", int(numStudents))
 			}
 
 			vodViews, err := r.StatisticsDao.GetCourseNumVodViews(cid)
 			if err != nil {
 				logger.Warn("GetCourseNumVodViews failed", "err", err, "courseId", cid)
 			} else {
-				quickStats = append(quickStats, dao.Stat{X: "Vod Views", Y: int(vodViews)})
+				quickStats = appendAnonymizedCount(quickStats, statsKey(cid, "vodViews", ""), "Vod Views", vodViews)
 			}
 
 			liveViews, err := r.StatisticsDao.GetCourseNumLiveViews(cid)
 			if err != nil {
 				logger.Warn("GetCourseNumLiveViews failed", "err", err, "courseId", cid)
 			} else {
-				quickStats = append(quickStats, dao.Stat{X: "Live Views", Y: int(liveViews)})
+				quickStats = appendAnonymizedCount(quickStats, statsKey(cid, "liveViews", ""), "Live Views", liveViews)
 			}
 			result = result.AddDataEntry(&tools.ExportDataEntry{
 				Name:  interval,
@@ -402,6 +564,37 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 				Data:  quickStats,
 			})
 
+		case "retention":
+			live, vod, err := r.getLectureRetention(c, cid, req.Lecture)
+			if err != nil {
+				logger.Warn("getLectureRetention failed", "err", err, "courseId", cid)
+			}
+			result = result.AddDataEntry(&tools.ExportDataEntry{
+				Name:  "retention-live",
+				XName: "Minute",
+				YName: "Viewers",
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, req.Lecture)+"/live", live),
+			}).AddDataEntry(&tools.ExportDataEntry{
+				Name:  "retention-vod",
+				XName: "Minute",
+				YName: "Viewers",
+				Data:  tools.AnonymizeStats(statsKey(cid, interval, req.Lecture)+"/vod", vod),
+			})
+
+		case "engagement":
+			res, err := r.StatisticsDao.GetLectureEngagement(cid)
+			if err != nil {
+				logger.Warn("GetLectureEngagement failed", "err", err, "courseId", cid)
+			}
+			for i, stats := range engagementStats(statsKey(cid, interval, ""), res) {
+				result = result.AddDataEntry(&tools.ExportDataEntry{
+					Name:  interval,
+					XName: "Lecture",
+					YName: engagementMetrics[i].label,
+					Data:  stats,
+				})
+			}
+
 		default:
 			logger.Warn("Invalid export interval", "courseId", cid)
 		}
@@ -427,6 +620,29 @@ func (r coursesRoutes) exportStats(c *gin.Context) {
 	}
 }
 
+// AggregateStats aggregates and deletes raw statistics older than the configured retention period.
+func AggregateStats(daoWrapper dao.DaoWrapper) func() {
+	return func() {
+		if tools.Cfg.Stats.RetentionDays <= 0 {
+			return
+		}
+		before := aggregateStatsBefore(time.Now(), tools.Cfg.Stats.RetentionDays)
+		if err := daoWrapper.StatisticsDao.AggregateStats(before); err != nil {
+			logger.Error("Can't aggregate stats", "err", err)
+		}
+		if err := daoWrapper.StatisticsDao.AggregateProgresses(before); err != nil {
+			logger.Error("Can't aggregate progresses", "err", err)
+		}
+	}
+}
+
+// aggregateStatsBefore returns the start of the hour the retention period ends in. Stats are aggregated per hour,
+// aggregating only a part of an hour would leave two aggregated stats for it after the next run.
+func aggregateStatsBefore(now time.Time, retentionDays int) time.Time {
+	before := now.AddDate(0, 0, -retentionDays)
+	return time.Date(before.Year(), before.Month(), before.Day(), before.Hour(), 0, 0, 0, before.Location())
+}
+
 //
 // Chart.js datastructures:
 //
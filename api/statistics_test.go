@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/mock_dao"
@@ -37,7 +38,7 @@ func TestStatistics(t *testing.T) {
 		numStudents := int64(1337)
 		views := 1001
 
-		intervals := []string{"week", "day", "hour", "activity-live", "activity-vod", "numStudents", "vodViews", "liveViews", "allDays"}
+		intervals := []string{"week", "day", "hour", "activity-live", "activity-vod", "numStudents", "vodViews", "liveViews", "allDays", "engagement"}
 
 		testCases := gomino.TestCases{
 			"invalid body": {
@@ -247,7 +248,7 @@ func TestStatistics(t *testing.T) {
 				Url:              fmt.Sprintf("%s?interval=numStudents", baseUrl),
 				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
 				ExpectedCode:     http.StatusOK,
-				ExpectedResponse: gin.H{"res": numStudents},
+				ExpectedResponse: gin.H{"res": numStudents, "anonymized": false},
 			},
 			"success vodViews": {
 				Router: func(r *gin.Engine) {
@@ -268,7 +269,7 @@ func TestStatistics(t *testing.T) {
 				Url:              fmt.Sprintf("%s?interval=vodViews", baseUrl),
 				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
 				ExpectedCode:     http.StatusOK,
-				ExpectedResponse: gin.H{"res": views},
+				ExpectedResponse: gin.H{"res": views, "anonymized": false},
 			},
 			"success liveViews": {
 				Router: func(r *gin.Engine) {
@@ -289,7 +290,7 @@ func TestStatistics(t *testing.T) {
 				Url:              fmt.Sprintf("%s?interval=liveViews", baseUrl),
 				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
 				ExpectedCode:     http.StatusOK,
-				ExpectedResponse: gin.H{"res": views},
+				ExpectedResponse: gin.H{"res": views, "anonymized": false},
 			},
 			"success allDays": {
 				Router: func(r *gin.Engine) {
@@ -376,6 +377,12 @@ func TestStatistics(t *testing.T) {
 								GetCourseNumVodViewsPerDay(testutils.CourseFPV.ID).
 								Return([]dao.Stat{}, errors.New("")).
 								AnyTimes()
+
+							statisticsMock.
+								EXPECT().
+								GetLectureEngagement(testutils.CourseFPV.ID).
+								Return(nil, errors.New("")).
+								AnyTimes()
 							return statisticsMock
 						}(),
 					}
@@ -390,3 +397,167 @@ func TestStatistics(t *testing.T) {
 		testCases.Method(http.MethodGet).Run(t, testutils.Equal)
 	})
 }
+
+func TestLectureAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	baseUrl := fmt.Sprintf("/api/course/%d/stats", testutils.CourseFPV.ID)
+	stream := testutils.StreamFPVLive
+	user := testutils.Student.ID
+	middlewares := testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin))
+
+	statisticsMock := func() dao.StatisticsDao {
+		statisticsMock := mock_dao.NewMockStatisticsDao(gomock.NewController(t))
+		statisticsMock.
+			EXPECT().
+			GetViewSessions(stream.ID, true).
+			Return([]model.ViewSession{{UserID: &user, JoinedAt: stream.Start, LeftAt: stream.Start.Add(time.Minute * 30)}}, nil).
+			AnyTimes()
+		statisticsMock.
+			EXPECT().
+			GetLectureProgresses(stream.ID).
+			Return([]float64{0.5}, nil).
+			AnyTimes()
+		statisticsMock.
+			EXPECT().
+			GetLectureEngagement(testutils.CourseFPV.ID).
+			Return([]dao.LectureEngagement{{StreamID: stream.ID, Start: stream.Start, PeakLiveViewers: 20, VodViews: 5, LiveUsers: 10, Chatters: 2, PollVoters: 15}}, nil).
+			AnyTimes()
+		return statisticsMock
+	}
+
+	halfWatched := make([]dao.Stat, 61)
+	for i := range halfWatched {
+		halfWatched[i] = dao.Stat{X: fmt.Sprint(i)}
+		if i <= 30 {
+			halfWatched[i].Y = 1
+		}
+	}
+	retention := chartJs{
+		ChartType: "line",
+		Data:      chartJsData{Datasets: []chartJsDataset{newChartJsDataset(), newChartJsDataset()}},
+		Options:   newChartJsOptions(),
+	}
+	retention.Data.Datasets[0].Label = "Live"
+	retention.Data.Datasets[0].Data = halfWatched
+	retention.Data.Datasets[0].BorderColor = "#d12a5c"
+	retention.Data.Datasets[0].BackgroundColor = ""
+	retention.Data.Datasets[1].Label = "VoD"
+	retention.Data.Datasets[1].Data = halfWatched
+	retention.Data.Datasets[1].BorderColor = "#2a7dd1"
+	retention.Data.Datasets[1].BackgroundColor = ""
+
+	lectureLabel := stream.Start.Format("02.01.2006")
+	engagement := chartJs{ChartType: "bar", Options: newChartJsOptions()}
+	for i, y := range []int{20, 5, 20, 100} {
+		dataset := newChartJsDataset()
+		dataset.Label = engagementMetrics[i].label
+		dataset.Data = []dao.Stat{{X: lectureLabel, Y: y}}
+		dataset.BorderColor = engagementMetrics[i].color
+		dataset.BackgroundColor = engagementMetrics[i].color
+		engagement.Data.Datasets = append(engagement.Data.Datasets, dataset)
+	}
+
+	gomino.TestCases{
+		"retention without lecture": {
+			Router: func(r *gin.Engine) {
+				configGinCourseRouter(r, dao.DaoWrapper{CoursesDao: testutils.GetCoursesMock(t)})
+			},
+			Url:          fmt.Sprintf("%s?interval=retention", baseUrl),
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		"retention of lecture in other course": {
+			Router: func(r *gin.Engine) {
+				wrapper := dao.DaoWrapper{
+					CoursesDao: testutils.GetCoursesMock(t),
+					StreamsDao: func() dao.StreamsDao {
+						streamsMock := mock_dao.NewMockStreamsDao(gomock.NewController(t))
+						streamsMock.
+							EXPECT().
+							GetStreamByID(gomock.Any(), "1").
+							Return(model.Stream{Model: gorm.Model{ID: 1}, CourseID: testutils.CourseFPV.ID + 1}, nil).
+							AnyTimes()
+						return streamsMock
+					}(),
+				}
+				configGinCourseRouter(r, wrapper)
+			},
+			Url:          fmt.Sprintf("%s?interval=retention&lecture=1", baseUrl),
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusNotFound,
+		},
+		"success retention": {
+			Router: func(r *gin.Engine) {
+				wrapper := dao.DaoWrapper{
+					CoursesDao:    testutils.GetCoursesMock(t),
+					StreamsDao:    testutils.GetStreamMock(t),
+					StatisticsDao: statisticsMock(),
+				}
+				configGinCourseRouter(r, wrapper)
+			},
+			Url:              fmt.Sprintf("%s?interval=retention&lecture=%d", baseUrl, stream.ID),
+			Middlewares:      middlewares,
+			ExpectedCode:     http.StatusOK,
+			ExpectedResponse: retention,
+		},
+		"success engagement": {
+			Router: func(r *gin.Engine) {
+				wrapper := dao.DaoWrapper{
+					CoursesDao:    testutils.GetCoursesMock(t),
+					StatisticsDao: statisticsMock(),
+				}
+				configGinCourseRouter(r, wrapper)
+			},
+			Url:              fmt.Sprintf("%s?interval=engagement", baseUrl),
+			Middlewares:      middlewares,
+			ExpectedCode:     http.StatusOK,
+			ExpectedResponse: engagement,
+		},
+	}.
+		Method(http.MethodGet).
+		Run(t, testutils.Equal)
+}
+
+func TestAnonymizedCount(t *testing.T) {
+	cfg := tools.Cfg.Stats
+	defer func() { tools.Cfg.Stats = cfg }()
+	tools.Cfg.Stats = tools.StatsConfig{MinGroupSize: 5}
+
+	if res := anonymizedCount("1/numStudents/", 7); res["res"] != 7 || res["anonymized"] != false {
+		t.Errorf("count above the minimum group size: got %v", res)
+	}
+	if res := anonymizedCount("1/numStudents/", 3); res["res"] != 0 || res["anonymized"] != true || res["minGroupSize"] != 5 {
+		t.Errorf("count below the minimum group size: got %v", res)
+	}
+}
+
+func TestAggregateStats(t *testing.T) {
+	cfg := tools.Cfg.Stats
+	defer func() { tools.Cfg.Stats = cfg }()
+	tools.Cfg.Stats = tools.StatsConfig{RetentionDays: 30}
+
+	var befores []time.Time
+	statisticsMock := mock_dao.NewMockStatisticsDao(gomock.NewController(t))
+	statisticsMock.EXPECT().AggregateStats(gomock.Any()).DoAndReturn(func(before time.Time) error {
+		befores = append(befores, before)
+		return nil
+	}).Times(2)
+	statisticsMock.EXPECT().AggregateProgresses(gomock.Any()).Return(nil).Times(2)
+	aggregate := AggregateStats(dao.DaoWrapper{StatisticsDao: statisticsMock})
+	aggregate()
+	aggregate()
+	for _, before := range befores {
+		if before.Minute() != 0 || before.Second() != 0 || before.Nanosecond() != 0 {
+			t.Errorf("expected stats to be aggregated until the start of an hour, got %v", before)
+		}
+	}
+
+	// runs on consecutive nights continue where the previous one stopped
+	loc := time.FixedZone("UTC+5:30", 5*3600+1800)
+	first := aggregateStatsBefore(time.Date(2024, 3, 1, 3, 15, 0, 0, loc), 30)
+	second := aggregateStatsBefore(time.Date(2024, 3, 2, 3, 15, 0, 0, loc), 30)
+	if !first.Equal(time.Date(2024, 1, 31, 3, 0, 0, 0, loc)) || !second.Equal(time.Date(2024, 2, 1, 3, 0, 0, 0, loc)) {
+		t.Errorf("expected runs to aggregate whole hours, got %v and %v", first, second)
+	}
+}
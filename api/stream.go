@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -20,7 +18,6 @@ import (
 	"github.com/TUM-Dev/gocast/voice-service/pb"
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
-	uuid "github.com/satori/go.uuid"
 	"gorm.io/gorm"
 )
 
@@ -41,9 +38,15 @@ func configGinStreamRestRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 		{
 			// All User Endpoints
 			streamById.GET("/sections", routes.getVideoSections)
+			streamById.GET("/subtitles", routes.getSubtitleLanguages)
 			streamById.GET("/subtitles/:lang", routes.getSubtitles)
+			streamById.GET("/transcript/:lang", routes.getTranscript)
+			streamById.GET("/transcript/:lang/search", routes.searchTranscript)
+			streamById.GET("/transcript/:lang/keywords", routes.getTranscriptKeywords)
+			streamById.GET("/transcript/:lang/export", routes.exportTranscript)
 
 			streamById.GET("/playlist", routes.getStreamPlaylist)
+			streamById.GET("/vod", routes.getVodPlaylist)
 
 			thumbs := streamById.Group("/thumbs")
 			{
@@ -68,16 +71,30 @@ func configGinStreamRestRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 				sections.PUT("/:id", routes.updateVideoSection)
 				sections.DELETE("/:id", routes.deleteVideoSection)
 			}
+			suggestions := admins.Group("/suggestions")
+			{
+				suggestions.POST("/:id/accept", routes.acceptSectionSuggestion)
+				suggestions.DELETE("/:id", routes.rejectSectionSuggestion)
+			}
 
 			files := admins.Group("files")
 			{
 				files.POST("", routes.newAttachment)
+				files.PUT("/:fid", routes.replaceAttachment)
+				files.PATCH("/:fid/placement", routes.setAttachmentPlacement)
+				files.GET("/:fid/versions", routes.getAttachmentVersions)
 				files.DELETE("/:fid", routes.deleteAttachment)
 			}
 
 			subtitles := admins.Group("subtitles")
 			{
 				subtitles.POST("", routes.requestSubtitles)
+				subtitles.GET("/jobs", routes.getSubtitleJobs)
+				subtitles.POST("/translate", routes.translateSubtitles)
+				subtitles.GET("/:lang/cues", routes.getSubtitleCues)
+				subtitles.PUT("/:lang/cues/:index", routes.updateSubtitleCue)
+				subtitles.GET("/:lang/revisions", routes.getSubtitleRevisions)
+				subtitles.POST("/:lang/revisions/:rid/restore", routes.restoreSubtitleRevision)
 			}
 		}
 	}
@@ -371,6 +388,7 @@ func (r streamRoutes) getStreamPlaylist(c *gin.Context) {
 		Start          time.Time            `json:"start"`
 		StreamProgress model.StreamProgress `json:"streamProgress"`
 		CreatedAt      time.Time            `json:"createdAt"`
+		Playback       *LivePlayback        `json:"playback,omitempty"`
 	}
 
 	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
@@ -409,12 +427,78 @@ func (r streamRoutes) getStreamPlaylist(c *gin.Context) {
 			Start:          stream.Start,
 			StreamProgress: streamProgresses[stream.ID],
 			CreatedAt:      stream.CreatedAt,
+			Playback:       GetLivePlayback(r.DaoWrapper, stream, course),
 		})
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
+// getVodPlaylist returns the signed playlist of the combined VoD, e.g. for viewers of the live stream switching to the VoD.
+func (r streamRoutes) getVodPlaylist(c *gin.Context) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	stream := tumLiveContext.Stream
+	if !stream.Recording || stream.PlaylistUrl == "" {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "stream has no VoD",
+		})
+		return
+	}
+	if err := tools.SetSignedPlaylists(stream, tumLiveContext.User, false); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not create signed stream playlists",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"playlist": stream.PlaylistUrl})
+}
+
+// LivePlayback contains the urls a live stream can be played with in the playback mode of its course.
+type LivePlayback struct {
+	Mode string `json:"mode"`           // hls, llhls or webrtc
+	Hls  string `json:"hls"`            // (LL-)HLS playlist, also the fallback for WebRTC
+	Whep string `json:"whep,omitempty"` // WHEP endpoint for WebRTC playback
+	Dvr  string `json:"dvr,omitempty"`  // playlist viewers can rewind in, see model.Stream.DvrUrl
+}
+
+// GetLivePlayback returns the playback urls of a live stream or nil if the stream isn't live.
+// Falls back to hls if the ingest server of the stream doesn't support the mode of the course.
+func GetLivePlayback(daoWrapper dao.DaoWrapper, stream model.Stream, course model.Course) *LivePlayback {
+	if !stream.LiveNow {
+		return nil
+	}
+	playback := LivePlayback{Mode: model.LivePlaybackHLS, Hls: stream.PlaylistUrl, Dvr: stream.DvrUrl}
+	if course.LivePlayback == "" || course.LivePlayback == model.LivePlaybackHLS {
+		return &playback
+	}
+	slot, err := daoWrapper.IngestServerDao.GetSlotByStreamID(stream.ID)
+	if err != nil {
+		logger.Warn("Can't find stream slot for live playback", "err", err, "streamID", stream.ID)
+		return &playback
+	}
+	server, err := daoWrapper.IngestServerDao.GetIngestServerByID(slot.IngestServerID)
+	if err != nil {
+		logger.Warn("Can't find ingest server for live playback", "err", err, "streamID", stream.ID)
+		return &playback
+	}
+	switch course.LivePlayback {
+	case model.LivePlaybackLLHLS:
+		if server.LowLatencyOutUrl != "" {
+			playback.Mode = model.LivePlaybackLLHLS
+			playback.Hls = fmt.Sprintf(server.LowLatencyOutUrl, slot.StreamName)
+		}
+	case model.LivePlaybackWebRTC:
+		if whep := server.GetWhepUrl(slot.StreamName); whep != "" {
+			playback.Mode = model.LivePlaybackWebRTC
+			playback.Whep = whep
+		}
+	}
+	return &playback
+}
+
 func (r streamRoutes) getVideoSections(c *gin.Context) {
 	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
 	sections, err := r.VideoSectionDao.GetByStreamId(tumLiveContext.Stream.ID)
@@ -637,135 +721,112 @@ func (r streamRoutes) deleteVideoSection(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
-func (r streamRoutes) newAttachment(c *gin.Context) {
-	foundContext, _ := c.Get("TUMLiveContext")
-	tumLiveContext := foundContext.(tools.TUMLiveContext)
-	stream := *tumLiveContext.Stream
-	course := *tumLiveContext.Course
-
-	var path string
-	var filename string
-
-	switch c.Query("type") {
-	case "file":
-		file, err := c.FormFile("file")
-		if err != nil {
-			_ = c.Error(tools.RequestError{
-				Status:        http.StatusBadRequest,
-				CustomMessage: "missing form parameter 'file'",
-				Err:           err,
-			})
-			return
-		}
-
-		if file.Size > MAX_FILE_SIZE {
-			_ = c.Error(tools.RequestError{
-				Status:        http.StatusBadRequest,
-				CustomMessage: "file too large (limit is 50mb)",
-			})
-			return
-		}
-
-		filename = file.Filename
-		fileUuid := uuid.NewV1()
-
-		filesFolder := fmt.Sprintf("%s/%s.%d/%s.%s/files",
-			tools.Cfg.Paths.Mass,
-			course.Name, course.Year,
-			course.Name, course.TeachingTerm)
-		path = fmt.Sprintf("%s/%s%s", filesFolder, fileUuid, filepath.Ext(file.Filename))
-
-		err = os.MkdirAll(filesFolder, os.ModePerm)
-		if err != nil {
-			_ = c.Error(tools.RequestError{
-				Status:        http.StatusInternalServerError,
-				CustomMessage: "couldn't create folder: " + filesFolder,
-				Err:           err,
-			})
-			return
-		}
-
-		if err = c.SaveUploadedFile(file, path); err != nil {
-			logger.Error("could not save file with path: "+path, "err", err)
-			_ = c.Error(tools.RequestError{
-				Status:        http.StatusInternalServerError,
-				CustomMessage: "could not save file with path: " + path,
-				Err:           err,
-			})
-			return
-		}
-	case "url":
-		path = c.PostForm("file_url")
-		_, filename = filepath.Split(path)
-		if path == "" {
-			_ = c.Error(tools.RequestError{
-				Status:        http.StatusBadRequest,
-				CustomMessage: "missing form parameter 'file_url'",
-			})
-			return
-		}
-	default:
+// getSectionSuggestion returns the section suggestion of the url parameter id if it belongs to the stream of the request
+func (r streamRoutes) getSectionSuggestion(c *gin.Context) (model.SectionSuggestion, bool) {
+	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
 		_ = c.Error(tools.RequestError{
 			Status:        http.StatusBadRequest,
-			CustomMessage: "missing or invalid query parameter 'type'",
+			CustomMessage: "can not parse section suggestion id in request url",
+			Err:           err,
 		})
-		return
+		return model.SectionSuggestion{}, false
 	}
-
-	file := model.File{StreamID: stream.ID, Path: path, Filename: filename, Type: model.FILETYPE_ATTACHMENT}
-	if err := r.FileDao.NewFile(&file); err != nil {
+	suggestion, err := r.VideoSectionDao.GetSuggestion(uint(id))
+	if err != nil || suggestion.StreamID != tumLiveContext.Stream.ID {
 		_ = c.Error(tools.RequestError{
-			Status:        http.StatusInternalServerError,
-			CustomMessage: "can not save file in database",
+			Status:        http.StatusNotFound,
+			CustomMessage: "section suggestion not found",
 			Err:           err,
 		})
-		return
+		return model.SectionSuggestion{}, false
 	}
+	return suggestion, true
+}
 
-	c.JSON(http.StatusOK, file.ID)
+type acceptSectionSuggestionRequest struct {
+	Description string `json:"description"`
 }
 
-func (r streamRoutes) deleteAttachment(c *gin.Context) {
-	toDelete, err := r.FileDao.GetFileById(c.Param("fid"))
-	if err != nil {
-		_ = c.Error(tools.RequestError{
-			Status:        http.StatusBadRequest,
-			CustomMessage: "can not find file",
-			Err:           err,
-		})
+// acceptSectionSuggestion turns a section suggestion into a video section, optionally with a new description
+func (r streamRoutes) acceptSectionSuggestion(c *gin.Context) {
+	suggestion, ok := r.getSectionSuggestion(c)
+	if !ok {
 		return
 	}
-	if !toDelete.IsURL() {
-		err = os.Remove(toDelete.Path)
-		if err != nil {
-			logger.Error("can not delete file with path: "+toDelete.Path, "err", err)
+	var req acceptSectionSuggestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&req); err != nil {
 			_ = c.Error(tools.RequestError{
-				Status:        http.StatusInternalServerError,
-				CustomMessage: "can not delete file with path: " + toDelete.Path,
+				Status:        http.StatusBadRequest,
+				CustomMessage: "can not bind body",
 				Err:           err,
 			})
 			return
 		}
 	}
-	err = r.FileDao.DeleteFile(toDelete.ID)
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		description = suggestion.Description
+	}
+	section, err := r.VideoSectionDao.AcceptSuggestion(suggestion, description)
 	if err != nil {
-		logger.Error("can not delete file from database", "err", err)
+		logger.Error("can not accept section suggestion", "err", err)
 		_ = c.Error(tools.RequestError{
 			Status:        http.StatusInternalServerError,
-			CustomMessage: "can not delete file from database",
+			CustomMessage: "can not accept section suggestion",
 			Err:           err,
 		})
 		return
 	}
+	c.JSON(http.StatusOK, section)
 }
 
+// rejectSectionSuggestion deletes a section suggestion and its image
+func (r streamRoutes) rejectSectionSuggestion(c *gin.Context) {
+	suggestion, ok := r.getSectionSuggestion(c)
+	if !ok {
+		return
+	}
+	if err := r.VideoSectionDao.DeleteSuggestion(suggestion.ID); err != nil {
+		logger.Error("can not delete section suggestion", "err", err)
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not delete section suggestion",
+			Err:           err,
+		})
+		return
+	}
+	file, err := r.FileDao.GetFileById(fmt.Sprintf("%d", suggestion.FileID))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("can not get section suggestion image", "err", err)
+		}
+		c.Status(http.StatusAccepted)
+		return
+	}
+	if err := r.FileDao.DeleteFile(file.ID); err != nil {
+		logger.Error("can not delete section suggestion image", "err", err)
+	}
+	go func() {
+		if err := DeleteVideoSectionImage(r.DaoWrapper.WorkerDao, file.Path); err != nil {
+			logger.Error("failed to delete section suggestion image", "err", err)
+		}
+	}()
+	c.Status(http.StatusAccepted)
+}
+
+// requestSubtitles creates a job generating subtitles of the stream with the voice-service.
+// The generated subtitles are translated to the requested translations once they arrive.
 func (r streamRoutes) requestSubtitles(c *gin.Context) {
 	tumLiveContext := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
 
 	stream := tumLiveContext.Stream
 
 	type subtitleRequest struct {
-		Language string `json:"language"`
+		Language     string   `json:"language"`
+		Translations []string `json:"translations"`
 	}
 
 	var request subtitleRequest
@@ -779,6 +840,15 @@ func (r streamRoutes) requestSubtitles(c *gin.Context) {
 		})
 		return
 	}
+	for _, lang := range append(request.Translations, request.Language) {
+		if !model.IsValidSubtitleLanguage(lang) {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusBadRequest,
+				CustomMessage: "invalid language",
+			})
+			return
+		}
+	}
 
 	err = tools.SetSignedPlaylists(stream, tumLiveContext.User, false)
 	if err != nil {
@@ -805,26 +875,44 @@ func (r streamRoutes) requestSubtitles(c *gin.Context) {
 		return
 	}
 
-	// request to voice-service for subtitles
-	client, err := GetSubtitleGeneratorClient()
-	if err != nil {
-		sentry.CaptureException(err)
+	job := model.SubtitleJob{StreamID: stream.ID, Language: request.Language, State: model.SubtitleJobQueued}
+	if err := r.SubtitlesDao.CreateJob(c, &job); err != nil {
 		_ = c.Error(tools.RequestError{
 			Status:        http.StatusInternalServerError,
-			CustomMessage: "could not connect to voice-service",
+			CustomMessage: "can not create subtitle job",
 			Err:           err,
 		})
 		return
 	}
-	defer client.CloseConn()
+	for _, lang := range request.Translations {
+		if lang == request.Language {
+			continue
+		}
+		translation := model.SubtitleJob{StreamID: stream.ID, Language: lang, SourceLanguage: request.Language, State: model.SubtitleJobWaiting}
+		if err := r.SubtitlesDao.CreateJob(c, &translation); err != nil {
+			logger.Error("can not create subtitle translation job", "err", err)
+		}
+	}
 
-	_, err = client.Generate(context.Background(), &pb.GenerateRequest{
-		StreamId:   int32(stream.ID),
-		SourceFile: playlist,
-		Language:   request.Language,
-	})
+	// request to voice-service for subtitles
+	client, err := GetSubtitleGeneratorClient()
+	if err == nil {
+		defer client.CloseConn()
+		_, err = client.Generate(context.Background(), &pb.GenerateRequest{
+			StreamId:   int32(stream.ID),
+			SourceFile: playlist,
+			Language:   request.Language,
+			JobId:      uint32(job.ID),
+		})
+	}
 	if err != nil {
 		sentry.CaptureException(err)
+		job.State = model.SubtitleJobFailed
+		job.Error = fmt.Sprintf("could not request subtitles: %v", err)
+		if err := r.SubtitlesDao.UpdateJob(c, &job); err != nil {
+			logger.Error("can not update subtitle job", "err", err)
+		}
+		failWaitingTranslations(c, r.DaoWrapper, stream.ID, request.Language)
 		_ = c.Error(tools.RequestError{
 			Status:        http.StatusInternalServerError,
 			CustomMessage: "could not call generate on voice_client",
@@ -833,7 +921,7 @@ func (r streamRoutes) requestSubtitles(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, job)
 }
 
 func (r streamRoutes) updateStreamVisibility(c *gin.Context) {
@@ -621,6 +621,71 @@ func TestStreamVideoSections(t *testing.T) {
 			Url(url).
 			Run(t, testutils.Equal)
 	})
+	t.Run("POST/api/stream/:streamID/suggestions/:id/accept", func(t *testing.T) {
+		baseUrl := fmt.Sprintf("/api/stream/%d/suggestions", testutils.StreamFPVLive.ID)
+		suggestion := model.SectionSuggestion{
+			Model:        gorm.Model{ID: 3},
+			Description:  "Slide 2",
+			StartMinutes: 12,
+			StreamID:     testutils.StreamFPVLive.ID,
+			FileID:       7,
+		}
+		otherStream := suggestion
+		otherStream.StreamID = testutils.StreamFPVLive.ID + 1
+		suggestionRouter := func(s model.SectionSuggestion, accept func(*mock_dao.MockVideoSectionDao)) func(r *gin.Engine) {
+			return func(r *gin.Engine) {
+				sectionMock := mock_dao.NewMockVideoSectionDao(gomock.NewController(t))
+				sectionMock.EXPECT().GetSuggestion(suggestion.ID).Return(s, nil).AnyTimes()
+				if accept != nil {
+					accept(sectionMock)
+				}
+				configGinStreamRestRouter(r, dao.DaoWrapper{
+					StreamsDao:      testutils.GetStreamMock(t),
+					CoursesDao:      testutils.GetCoursesMock(t),
+					VideoSectionDao: sectionMock,
+				})
+			}
+		}
+		gomino.TestCases{
+			"Not Admin": {
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"Invalid ID": {
+				Url:          fmt.Sprintf("%s/abc/accept", baseUrl),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"Suggestion of other stream": {
+				Router:       suggestionRouter(otherStream, nil),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusNotFound,
+			},
+			"success with new description": {
+				Router: suggestionRouter(suggestion, func(m *mock_dao.MockVideoSectionDao) {
+					m.EXPECT().
+						AcceptSuggestion(suggestion, "Dynamic Programming").
+						Return(model.VideoSection{Description: "Dynamic Programming", StartMinutes: 12}, nil)
+				}),
+				Body:         acceptSectionSuggestionRequest{Description: " Dynamic Programming "},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+			"success keeps description": {
+				Router: suggestionRouter(suggestion, func(m *mock_dao.MockVideoSectionDao) {
+					m.EXPECT().
+						AcceptSuggestion(suggestion, "Slide 2").
+						Return(model.VideoSection{Description: "Slide 2", StartMinutes: 12}, nil)
+				}),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+			},
+		}.
+			Router(StreamDefaultRouter(t)).
+			Method(http.MethodPost).
+			Url(fmt.Sprintf("%s/%d/accept", baseUrl, suggestion.ID)).
+			Run(t, testutils.Equal)
+	})
 }
 
 func TestAttachments(t *testing.T) {
@@ -936,7 +1001,11 @@ func TestAttachments(t *testing.T) {
 								Return(testFile, nil)
 							fileMock.
 								EXPECT().
-								DeleteFile(testFile.ID).
+								GetAttachmentVersions(testFile.ID).
+								Return(nil, nil)
+							fileMock.
+								EXPECT().
+								DeleteAttachment(testFile).
 								Return(errors.New(""))
 							return fileMock
 						}(),
@@ -1049,3 +1118,55 @@ func TestSubtitles(t *testing.T) {
 		Url(endpoint).
 		Run(t, testutils.Equal)
 }
+
+func TestVodPlaylist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	gomino.TestCases{
+		"no VoD": {
+			Router:       StreamDefaultRouter(t),
+			Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+			ExpectedCode: http.StatusNotFound,
+		},
+	}.
+		Method(http.MethodGet).
+		Url(fmt.Sprintf("/api/stream/%d/vod", testutils.StreamFPVLive.ID)).
+		Run(t, testutils.Equal)
+}
+
+func TestGetLivePlayback(t *testing.T) {
+	stream := model.Stream{Model: gorm.Model{ID: 1}, LiveNow: true, PlaylistUrl: "https://out.tum.live/streams/abc/playlist.m3u8", DvrUrl: "https://edge.tum.live/worker1/dvr/abc/playlist.m3u8"}
+	server := model.IngestServer{
+		Model:            gorm.Model{ID: 2},
+		OutUrl:           "https://out.tum.live/streams/%s/playlist.m3u8",
+		LowLatencyOutUrl: "https://out.tum.live/ll/%s/index.m3u8",
+		WhepUrl:          "https://out.tum.live/%s/whep",
+	}
+	wrapper := func(server model.IngestServer) dao.DaoWrapper {
+		ingestMock := mock_dao.NewMockIngestServerDao(gomock.NewController(t))
+		ingestMock.EXPECT().GetSlotByStreamID(stream.ID).Return(model.StreamName{StreamName: "abc", IngestServerID: 2}, nil).AnyTimes()
+		ingestMock.EXPECT().GetIngestServerByID(uint(2)).Return(server, nil).AnyTimes()
+		return dao.DaoWrapper{IngestServerDao: ingestMock}
+	}
+
+	if p := GetLivePlayback(wrapper(server), model.Stream{}, model.Course{}); p != nil {
+		t.Fatalf("expected no playback for streams that aren't live, got %v", p)
+	}
+	tests := []struct {
+		mode     string
+		server   model.IngestServer
+		expected LivePlayback
+	}{
+		{model.LivePlaybackHLS, server, LivePlayback{Mode: "hls", Hls: stream.PlaylistUrl, Dvr: stream.DvrUrl}},
+		{model.LivePlaybackLLHLS, server, LivePlayback{Mode: "llhls", Hls: "https://out.tum.live/ll/abc/index.m3u8", Dvr: stream.DvrUrl}},
+		{model.LivePlaybackWebRTC, server, LivePlayback{Mode: "webrtc", Hls: stream.PlaylistUrl, Whep: "https://out.tum.live/abc/whep", Dvr: stream.DvrUrl}},
+		{model.LivePlaybackLLHLS, model.IngestServer{OutUrl: server.OutUrl}, LivePlayback{Mode: "hls", Hls: stream.PlaylistUrl, Dvr: stream.DvrUrl}},
+		{model.LivePlaybackWebRTC, model.IngestServer{OutUrl: server.OutUrl}, LivePlayback{Mode: "hls", Hls: stream.PlaylistUrl, Dvr: stream.DvrUrl}},
+	}
+	for _, test := range tests {
+		p := GetLivePlayback(wrapper(test.server), stream, model.Course{LivePlayback: test.mode})
+		if p == nil || *p != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.mode, test.expected, p)
+		}
+	}
+}
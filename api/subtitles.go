@@ -0,0 +1,366 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/voice-service/pb"
+	"github.com/asticode/go-astisub"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SubtitleCue is a single cue of WebVTT subtitles, start and end are in milliseconds
+type SubtitleCue struct {
+	Index int    `json:"index"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Text  string `json:"text"`
+}
+
+// parseCues returns the cues of WebVTT subtitles
+func parseCues(content string) ([]SubtitleCue, error) {
+	vtt, err := astisub.ReadFromWebVTT(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	cues := make([]SubtitleCue, len(vtt.Items))
+	for i, item := range vtt.Items {
+		lines := make([]string, len(item.Lines))
+		for j, line := range item.Lines {
+			lines[j] = line.String()
+		}
+		cues[i] = SubtitleCue{
+			Index: i,
+			Start: item.StartAt.Milliseconds(),
+			End:   item.EndAt.Milliseconds(),
+			Text:  strings.Join(lines, "\n"),
+		}
+	}
+	return cues, nil
+}
+
+// editCue replaces the text and timing of a cue of WebVTT subtitles. The timing is kept if cue has none.
+func editCue(content string, cue SubtitleCue) (string, error) {
+	vtt, err := astisub.ReadFromWebVTT(strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	if cue.Index < 0 || cue.Index >= len(vtt.Items) {
+		return "", errors.New("cue index out of range")
+	}
+	if strings.TrimSpace(cue.Text) == "" {
+		return "", errors.New("cue text is empty")
+	}
+	item := vtt.Items[cue.Index]
+	if cue.Start != 0 || cue.End != 0 {
+		if cue.Start < 0 || cue.End <= cue.Start {
+			return "", errors.New("cue ends before it starts")
+		}
+		item.StartAt = time.Duration(cue.Start) * time.Millisecond
+		item.EndAt = time.Duration(cue.End) * time.Millisecond
+	}
+	item.Lines = nil
+	for _, line := range strings.Split(strings.TrimSpace(cue.Text), "\n") {
+		item.Lines = append(item.Lines, astisub.Line{Items: []astisub.LineItem{{Text: strings.TrimSpace(line)}}})
+	}
+	var buf bytes.Buffer
+	if err := vtt.WriteToWebVTT(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// saveReceivedSubtitles stores subtitles from the voice-service. Subtitles edited by lecturers are kept,
+// the received ones are only added as revision the lecturers can restore.
+func saveReceivedSubtitles(ctx context.Context, daoWrapper dao.DaoWrapper, streamID uint, language, content, source string) error {
+	subtitles, err := daoWrapper.SubtitlesDao.GetByStreamIDandLang(ctx, streamID, language)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		subtitles = model.Subtitles{StreamID: streamID, Language: language}
+	} else if err != nil {
+		return err
+	}
+	revision := model.SubtitlesRevision{Content: content, Source: source}
+	if err := daoWrapper.SubtitlesDao.SaveRevision(ctx, &subtitles, &revision, !subtitles.Edited); err != nil {
+		return err
+	}
+	if !subtitles.Edited {
+		indexTranscript(ctx, daoWrapper, subtitles)
+	}
+	return nil
+}
+
+// startTranslation requests the translation of subtitles from the voice-service. The job fails if that's impossible.
+func startTranslation(ctx context.Context, daoWrapper dao.DaoWrapper, job *model.SubtitleJob, subtitles model.Subtitles) error {
+	client, err := GetSubtitleGeneratorClient()
+	if err == nil {
+		defer client.CloseConn()
+		_, err = client.Translate(ctx, &pb.TranslateRequest{
+			JobId:          uint32(job.ID),
+			StreamId:       int32(job.StreamID),
+			Subtitles:      subtitles.Content,
+			SourceLanguage: job.SourceLanguage,
+			TargetLanguage: job.Language,
+		})
+	}
+	job.State = model.SubtitleJobQueued
+	if err != nil {
+		job.State = model.SubtitleJobFailed
+		job.Error = fmt.Sprintf("could not request translation: %v", err)
+	}
+	if updateErr := daoWrapper.SubtitlesDao.UpdateJob(ctx, job); updateErr != nil {
+		logger.Error("can not update subtitle job", "err", updateErr, "job", job.ID)
+	}
+	return err
+}
+
+// failWaitingTranslations fails the translations waiting for subtitles that won't be generated
+func failWaitingTranslations(ctx context.Context, daoWrapper dao.DaoWrapper, streamID uint, language string) {
+	waiting, err := daoWrapper.SubtitlesDao.GetWaitingJobs(ctx, streamID, language)
+	if err != nil {
+		logger.Error("can not get waiting subtitle jobs", "err", err)
+		return
+	}
+	for i := range waiting {
+		waiting[i].State = model.SubtitleJobFailed
+		waiting[i].Error = fmt.Sprintf("generating %s subtitles failed", language)
+		if err := daoWrapper.SubtitlesDao.UpdateJob(ctx, &waiting[i]); err != nil {
+			logger.Error("can not update subtitle job", "err", err, "job", waiting[i].ID)
+		}
+	}
+}
+
+// getSubtitleLanguages returns the languages subtitles of the stream are available in
+func (r streamRoutes) getSubtitleLanguages(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	languages, err := r.SubtitlesDao.GetLanguages(c, ctx.Stream.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get subtitle languages",
+			Err:           err,
+		})
+		return
+	}
+	if languages == nil {
+		languages = []string{}
+	}
+	c.JSON(http.StatusOK, languages)
+}
+
+// getSubtitleJobs returns the subtitle jobs of the stream, latest first
+func (r streamRoutes) getSubtitleJobs(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	jobs, err := r.SubtitlesDao.GetJobsByStreamID(c, ctx.Stream.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get subtitle jobs",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// translateSubtitles requests a translation of existing subtitles of the stream into another language
+func (r streamRoutes) translateSubtitles(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	var req struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not bind body",
+			Err:           err,
+		})
+		return
+	}
+	if !model.IsValidSubtitleLanguage(req.From) || !model.IsValidSubtitleLanguage(req.To) || req.From == req.To {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid languages",
+		})
+		return
+	}
+	subtitles, ok := r.getSubtitlesOfLanguage(c, req.From)
+	if !ok {
+		return
+	}
+	job := model.SubtitleJob{StreamID: ctx.Stream.ID, Language: req.To, SourceLanguage: req.From, State: model.SubtitleJobQueued}
+	if err := r.SubtitlesDao.CreateJob(c, &job); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not create subtitle job",
+			Err:           err,
+		})
+		return
+	}
+	if err := startTranslation(c, r.DaoWrapper, &job, subtitles); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "could not call translate on voice_client",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, job)
+}
+
+// getSubtitlesOfLanguage returns the stream's subtitles in lang or writes an error if there are none
+func (r streamRoutes) getSubtitlesOfLanguage(c *gin.Context, lang string) (model.Subtitles, bool) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	subtitles, err := r.SubtitlesDao.GetByStreamIDandLang(c, ctx.Stream.ID, lang)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "invalid streamID or language",
+		})
+		return subtitles, false
+	}
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get by streamID and language",
+			Err:           err,
+		})
+		return subtitles, false
+	}
+	return subtitles, true
+}
+
+// getSubtitleCues returns the cues of the stream's subtitles in a language for editing
+func (r streamRoutes) getSubtitleCues(c *gin.Context) {
+	subtitles, ok := r.getSubtitlesOfLanguage(c, c.Param("lang"))
+	if !ok {
+		return
+	}
+	cues, err := parseCues(subtitles.Content)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not parse subtitles",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"edited": subtitles.Edited, "cues": cues})
+}
+
+// updateSubtitleCue corrects a single cue. The subtitles are marked as edited, so regenerating them doesn't overwrite the correction.
+func (r streamRoutes) updateSubtitleCue(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not parse cue index",
+			Err:           err,
+		})
+		return
+	}
+	var cue SubtitleCue
+	if err := c.BindJSON(&cue); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not bind body",
+			Err:           err,
+		})
+		return
+	}
+	cue.Index = index
+	subtitles, ok := r.getSubtitlesOfLanguage(c, c.Param("lang"))
+	if !ok {
+		return
+	}
+	content, err := editCue(subtitles.Content, cue)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "invalid cue",
+			Err:           err,
+		})
+		return
+	}
+	subtitles.Edited = true
+	revision := model.SubtitlesRevision{Content: content, Source: model.SubtitlesSourceEdited, UserID: &ctx.User.ID}
+	if err := r.SubtitlesDao.SaveRevision(c, &subtitles, &revision, true); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not save subtitles",
+			Err:           err,
+		})
+		return
+	}
+	indexTranscript(c, r.DaoWrapper, subtitles)
+	c.JSON(http.StatusOK, cue)
+}
+
+// getSubtitleRevisions returns the version history of the stream's subtitles in a language
+func (r streamRoutes) getSubtitleRevisions(c *gin.Context) {
+	subtitles, ok := r.getSubtitlesOfLanguage(c, c.Param("lang"))
+	if !ok {
+		return
+	}
+	revisions, err := r.SubtitlesDao.GetRevisions(c, subtitles.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get subtitle revisions",
+			Err:           err,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// restoreSubtitleRevision makes a revision the current version of the subtitles. As this is a decision
+// of the lecturer, regenerating the subtitles doesn't overwrite the restored version.
+func (r streamRoutes) restoreSubtitleRevision(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	subtitles, ok := r.getSubtitlesOfLanguage(c, c.Param("lang"))
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("rid"), 10, 32)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not parse revision id",
+			Err:           err,
+		})
+		return
+	}
+	old, err := r.SubtitlesDao.GetRevision(c, uint(id))
+	if err != nil || old.SubtitlesID != subtitles.ID {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "revision not found",
+			Err:           err,
+		})
+		return
+	}
+	subtitles.Edited = true
+	revision := model.SubtitlesRevision{Content: old.Content, Source: model.SubtitlesSourceEdited, UserID: &ctx.User.ID}
+	if err := r.SubtitlesDao.SaveRevision(c, &subtitles, &revision, true); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not save subtitles",
+			Err:           err,
+		})
+		return
+	}
+	indexTranscript(c, r.DaoWrapper, subtitles)
+	c.JSON(http.StatusOK, revision)
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/TUM-Dev/gocast/voice-service/pb"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+const testVTT = `WEBVTT
+
+00:00:01.000 --> 00:00:04.000
+Welcome to the lecture
+
+00:00:05.000 --> 00:00:08.500
+Todays topic are
+dynamic programs
+`
+
+func TestEditCue(t *testing.T) {
+	content, err := editCue(testVTT, SubtitleCue{Index: 1, Text: "Today's topic is\ndynamic programming"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues, err := parseCues(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []SubtitleCue{
+		{Index: 0, Start: 1000, End: 4000, Text: "Welcome to the lecture"},
+		{Index: 1, Start: 5000, End: 8500, Text: "Today's topic is\ndynamic programming"},
+	}
+	if fmt.Sprint(cues) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, cues)
+	}
+
+	content, err = editCue(testVTT, SubtitleCue{Index: 0, Start: 500, End: 3000, Text: "Welcome"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cues, _ = parseCues(content); cues[0].Start != 500 || cues[0].End != 3000 {
+		t.Fatalf("expected timing to be changed, got %v", cues[0])
+	}
+
+	for _, cue := range []SubtitleCue{{Index: 2, Text: "a"}, {Index: 0, Text: " "}, {Index: 0, Start: 3000, End: 1000, Text: "a"}} {
+		if _, err := editCue(testVTT, cue); err == nil {
+			t.Errorf("expected error for %v", cue)
+		}
+	}
+}
+
+func TestSubtitleCues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	subtitles := model.Subtitles{Model: gorm.Model{ID: 1}, StreamID: testutils.StreamFPVLive.ID, Language: "en", Content: testVTT}
+	subtitlesMock := func(t *testing.T) *mock_dao.MockSubtitlesDao {
+		subMock := mock_dao.NewMockSubtitlesDao(gomock.NewController(t))
+		subMock.
+			EXPECT().
+			GetByStreamIDandLang(gomock.Any(), testutils.StreamFPVLive.ID, "en").
+			Return(subtitles, nil).
+			AnyTimes()
+		return subMock
+	}
+
+	t.Run("GET/api/stream/:streamID/subtitles/:lang/cues", func(t *testing.T) {
+		gomino.TestCases{
+			"not admin": {
+				Router:       StreamDefaultRouter(t),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextStudent)),
+				ExpectedCode: http.StatusForbidden,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					configGinStreamRestRouter(r, dao.DaoWrapper{
+						StreamsDao:   testutils.GetStreamMock(t),
+						CoursesDao:   testutils.GetCoursesMock(t),
+						SubtitlesDao: subtitlesMock(t),
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				ExpectedCode: http.StatusOK,
+				ExpectedResponse: gin.H{"edited": false, "cues": []SubtitleCue{
+					{Index: 0, Start: 1000, End: 4000, Text: "Welcome to the lecture"},
+					{Index: 1, Start: 5000, End: 8500, Text: "Todays topic are\ndynamic programs"},
+				}},
+			},
+		}.
+			Method(http.MethodGet).
+			Url(fmt.Sprintf("/api/stream/%d/subtitles/en/cues", testutils.StreamFPVLive.ID)).
+			Run(t, testutils.Equal)
+	})
+
+	t.Run("PUT/api/stream/:streamID/subtitles/:lang/cues/:index", func(t *testing.T) {
+		url := fmt.Sprintf("/api/stream/%d/subtitles/en/cues/1", testutils.StreamFPVLive.ID)
+		gomino.TestCases{
+			"invalid cue": {
+				Router: func(r *gin.Engine) {
+					configGinStreamRestRouter(r, dao.DaoWrapper{
+						StreamsDao:   testutils.GetStreamMock(t),
+						CoursesDao:   testutils.GetCoursesMock(t),
+						SubtitlesDao: subtitlesMock(t),
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         SubtitleCue{Text: ""},
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					subMock := subtitlesMock(t)
+					subMock.
+						EXPECT().
+						SaveRevision(gomock.Any(), gomock.Any(), gomock.Any(), true).
+						DoAndReturn(func(_ context.Context, s *model.Subtitles, rev *model.SubtitlesRevision, _ bool) error {
+							if !s.Edited || rev.Source != model.SubtitlesSourceEdited || rev.UserID == nil {
+								t.Errorf("expected edited revision, got %v %v", s, rev)
+							}
+							return nil
+						})
+					subMock.EXPECT().SetKeywords(gomock.Any(), uint(1), gomock.Any()).Return(nil)
+					configGinStreamRestRouter(r, dao.DaoWrapper{
+						StreamsDao:   testutils.GetStreamMock(t),
+						CoursesDao:   testutils.GetCoursesMock(t),
+						SubtitlesDao: subMock,
+					})
+				},
+				Middlewares:      testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:             SubtitleCue{Text: "Today's topic is dynamic programming"},
+				ExpectedCode:     http.StatusOK,
+				ExpectedResponse: SubtitleCue{Index: 1, Text: "Today's topic is dynamic programming"},
+			},
+		}.
+			Method(http.MethodPut).
+			Url(url).
+			Run(t, testutils.Equal)
+	})
+
+	t.Run("POST/api/stream/:streamID/subtitles/translate", func(t *testing.T) {
+		gomino.TestCases{
+			"same language": {
+				Router:       StreamDefaultRouter(t),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         gin.H{"from": "en", "to": "en"},
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"invalid language": {
+				Router:       StreamDefaultRouter(t),
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         gin.H{"from": "en", "to": "../de"},
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"source not found": {
+				Router: func(r *gin.Engine) {
+					subMock := mock_dao.NewMockSubtitlesDao(gomock.NewController(t))
+					subMock.
+						EXPECT().
+						GetByStreamIDandLang(gomock.Any(), testutils.StreamFPVLive.ID, "de").
+						Return(model.Subtitles{}, gorm.ErrRecordNotFound)
+					configGinStreamRestRouter(r, dao.DaoWrapper{
+						StreamsDao:   testutils.GetStreamMock(t),
+						CoursesDao:   testutils.GetCoursesMock(t),
+						SubtitlesDao: subMock,
+					})
+				},
+				Middlewares:  testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin)),
+				Body:         gin.H{"from": "de", "to": "en"},
+				ExpectedCode: http.StatusNotFound,
+			},
+		}.
+			Method(http.MethodPost).
+			Url(fmt.Sprintf("/api/stream/%d/subtitles/translate", testutils.StreamFPVLive.ID)).
+			Run(t, testutils.Equal)
+	})
+}
+
+func TestSubtitleReceiver(t *testing.T) {
+	t.Run("edited subtitles are kept", func(t *testing.T) {
+		subMock := mock_dao.NewMockSubtitlesDao(gomock.NewController(t))
+		edited := model.Subtitles{Model: gorm.Model{ID: 1}, StreamID: 1, Language: "en", Content: testVTT, Edited: true}
+		subMock.EXPECT().GetJob(gomock.Any(), uint(3)).Return(model.SubtitleJob{Model: gorm.Model{ID: 3}, StreamID: 1, Language: "en"}, nil)
+		subMock.EXPECT().GetByStreamIDandLang(gomock.Any(), uint(1), "en").Return(edited, nil)
+		subMock.
+			EXPECT().
+			SaveRevision(gomock.Any(), gomock.Any(), gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, _ *model.Subtitles, rev *model.SubtitlesRevision, _ bool) error {
+				if rev.Content != "WEBVTT" || rev.Source != model.SubtitlesSourceGenerated {
+					t.Errorf("unexpected revision %v", rev)
+				}
+				return nil
+			})
+		subMock.
+			EXPECT().
+			UpdateJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, job *model.SubtitleJob) error {
+				if job.State != model.SubtitleJobDone || job.Progress != 100 {
+					t.Errorf("expected job to be done, got %v", job)
+				}
+				return nil
+			})
+		subMock.EXPECT().GetWaitingJobs(gomock.Any(), uint(1), "en").Return(nil, nil)
+
+		s := subtitleReceiverServer{DaoWrapper: dao.DaoWrapper{SubtitlesDao: subMock}}
+		_, err := s.Receive(context.Background(), &pb.ReceiveRequest{StreamId: 1, Subtitles: "WEBVTT", Language: "en", JobId: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("failed generation fails waiting translations", func(t *testing.T) {
+		subMock := mock_dao.NewMockSubtitlesDao(gomock.NewController(t))
+		subMock.EXPECT().GetJob(gomock.Any(), uint(3)).Return(model.SubtitleJob{Model: gorm.Model{ID: 3}, StreamID: 1, Language: "en"}, nil)
+		subMock.EXPECT().GetWaitingJobs(gomock.Any(), uint(1), "en").Return([]model.SubtitleJob{{Model: gorm.Model{ID: 4}, StreamID: 1, Language: "de", SourceLanguage: "en"}}, nil)
+		var states []string
+		subMock.
+			EXPECT().
+			UpdateJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, job *model.SubtitleJob) error {
+				states = append(states, job.State)
+				return nil
+			}).
+			Times(2)
+
+		s := subtitleReceiverServer{DaoWrapper: dao.DaoWrapper{SubtitlesDao: subMock}}
+		_, err := s.ReportStatus(context.Background(), &pb.JobStatus{JobId: 3, State: pb.JobState_JOB_STATE_FAILED, Error: "out of memory"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(states) != fmt.Sprint([]string{model.SubtitleJobFailed, model.SubtitleJobFailed}) {
+			t.Fatalf("expected job and translation to fail, got %v", states)
+		}
+	})
+}
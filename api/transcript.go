@@ -0,0 +1,193 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+)
+
+const transcriptSearchLimit = 50
+
+// transcriptHit has the fields of the hits of the meili subtitle search, so the player can show both
+type transcriptHit struct {
+	Timestamp int64  `json:"timestamp"`
+	Text      string `json:"text"`
+	TextPrev  string `json:"textPrev"`
+	TextNext  string `json:"textNext"`
+}
+
+type transcriptKeywordDto struct {
+	Keyword string  `json:"keyword"`
+	Starts  []int64 `json:"starts"` // milliseconds
+}
+
+// indexTranscript stores the keywords of the current content of subtitles. Failing to do so doesn't
+// fail saving the subtitles, the keywords are computed on request then.
+func indexTranscript(ctx context.Context, daoWrapper dao.DaoWrapper, subtitles model.Subtitles) {
+	transcript, err := model.ParseTranscript(subtitles.Content)
+	if err != nil {
+		logger.Warn("can not parse subtitles for keywords", "err", err, "subtitles", subtitles.ID)
+		return
+	}
+	if err := daoWrapper.SubtitlesDao.SetKeywords(ctx, subtitles.ID, transcript.Keywords()); err != nil {
+		logger.Error("can not save transcript keywords", "err", err, "subtitles", subtitles.ID)
+	}
+}
+
+// transcript returns the parsed subtitles of the stream in the language of the request or writes an error
+func (r streamRoutes) transcript(c *gin.Context) (model.Subtitles, model.Transcript, bool) {
+	subtitles, ok := r.getSubtitlesOfLanguage(c, c.Param("lang"))
+	if !ok {
+		return subtitles, model.Transcript{}, false
+	}
+	transcript, err := model.ParseTranscript(subtitles.Content)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not parse subtitles",
+			Err:           err,
+		})
+		return subtitles, transcript, false
+	}
+	transcript.Language = subtitles.Language
+	return subtitles, transcript, true
+}
+
+// getTranscript returns the cues and paragraphs of the stream's subtitles in a language
+func (r streamRoutes) getTranscript(c *gin.Context) {
+	if _, transcript, ok := r.transcript(c); ok {
+		c.JSON(http.StatusOK, transcript)
+	}
+}
+
+// searchTranscript returns the cues containing all words of the query with their neighbours
+func (r streamRoutes) searchTranscript(c *gin.Context) {
+	_, transcript, ok := r.transcript(c)
+	if !ok {
+		return
+	}
+	hits := []transcriptHit{}
+	for _, i := range transcript.Search(c.Query("q")) {
+		if len(hits) == transcriptSearchLimit {
+			break
+		}
+		hit := transcriptHit{Timestamp: transcript.Cues[i].Start, Text: transcript.Cues[i].Text}
+		if i > 0 {
+			hit.TextPrev = transcript.Cues[i-1].Text
+		}
+		if i < len(transcript.Cues)-1 {
+			hit.TextNext = transcript.Cues[i+1].Text
+		}
+		hits = append(hits, hit)
+	}
+	c.JSON(http.StatusOK, gin.H{"hits": hits})
+}
+
+// getTranscriptKeywords returns the keywords of the stream's subtitles, most frequent first, with the timestamps to jump to
+func (r streamRoutes) getTranscriptKeywords(c *gin.Context) {
+	subtitles, transcript, ok := r.transcript(c)
+	if !ok {
+		return
+	}
+	keywords, err := r.SubtitlesDao.GetKeywords(c, subtitles.ID)
+	if err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not get keywords",
+			Err:           err,
+		})
+		return
+	}
+	if len(keywords) == 0 {
+		// subtitles received before keywords were stored
+		keywords = transcript.Keywords()
+	}
+	res := []transcriptKeywordDto{}
+	for _, k := range keywords {
+		if len(res) == 0 || res[len(res)-1].Keyword != k.Keyword {
+			res = append(res, transcriptKeywordDto{Keyword: k.Keyword})
+		}
+		res[len(res)-1].Starts = append(res[len(res)-1].Starts, k.Start)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// exportTranscript sends the transcript as markdown, plain text or as html page to print as pdf
+func (r streamRoutes) exportTranscript(c *gin.Context) {
+	ctx := c.MustGet("TUMLiveContext").(tools.TUMLiveContext)
+	_, transcript, ok := r.transcript(c)
+	if !ok {
+		return
+	}
+	title := ctx.Stream.Name
+	if ctx.Course != nil {
+		title = fmt.Sprintf("%s: %s", ctx.Course.Name, ctx.Stream.Name)
+	}
+	filename := fmt.Sprintf("transcript-%d-%s", ctx.Stream.ID, transcript.Language)
+
+	var buf bytes.Buffer
+	switch format := c.DefaultQuery("format", "md"); format {
+	case "md":
+		fmt.Fprintf(&buf, "# %s\n\n", title)
+		for _, p := range transcript.Paragraphs {
+			fmt.Fprintf(&buf, "**[%s]** %s\n\n", transcriptTime(p.Start), p.Text)
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.md", filename))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", buf.Bytes())
+	case "txt":
+		fmt.Fprintf(&buf, "%s\n%s\n\n", title, strings.Repeat("=", len([]rune(title))))
+		for _, p := range transcript.Paragraphs {
+			fmt.Fprintf(&buf, "[%s]\n%s\n\n", transcriptTime(p.Start), p.Text)
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.txt", filename))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", buf.Bytes())
+	case "html":
+		err := transcriptPrintTemplate.Execute(&buf, gin.H{"Title": title, "Language": transcript.Language, "Paragraphs": transcript.Paragraphs})
+		if err != nil {
+			_ = c.Error(tools.RequestError{
+				Status:        http.StatusInternalServerError,
+				CustomMessage: "can not render transcript",
+				Err:           err,
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	default:
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "format must be md, txt or html",
+		})
+	}
+}
+
+// transcriptTime formats milliseconds as hh:mm:ss
+func transcriptTime(ms int64) string {
+	s := ms / 1000
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+var transcriptPrintTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{"time": transcriptTime}).Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+    <meta charset="utf-8">
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; line-height: 1.5; }
+        time { font-weight: bold; font-variant-numeric: tabular-nums; margin-right: .5rem; }
+        p { break-inside: avoid; }
+    </style>
+</head>
+<body onload="window.print()">
+<h1>{{.Title}}</h1>
+{{range .Paragraphs}}<p><time>{{time .Start}}</time>{{.Text}}</p>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/TUM-Dev/gocast/tools/testutils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
+)
+
+func TestTranscript(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	subtitles := model.Subtitles{Model: gorm.Model{ID: 1}, StreamID: testutils.StreamFPVLive.ID, Language: "en", Content: testVTT}
+	router := func(t *testing.T, keywords []model.TranscriptKeyword) func(r *gin.Engine) {
+		return func(r *gin.Engine) {
+			subMock := mock_dao.NewMockSubtitlesDao(gomock.NewController(t))
+			subMock.EXPECT().GetByStreamIDandLang(gomock.Any(), testutils.StreamFPVLive.ID, "en").Return(subtitles, nil).AnyTimes()
+			subMock.EXPECT().GetByStreamIDandLang(gomock.Any(), testutils.StreamFPVLive.ID, "de").Return(model.Subtitles{}, gorm.ErrRecordNotFound).AnyTimes()
+			subMock.EXPECT().GetKeywords(gomock.Any(), uint(1)).Return(keywords, nil).AnyTimes()
+			configGinStreamRestRouter(r, dao.DaoWrapper{
+				StreamsDao:   testutils.GetStreamMock(t),
+				CoursesDao:   testutils.GetCoursesMock(t),
+				SubtitlesDao: subMock,
+			})
+		}
+	}
+	url := fmt.Sprintf("/api/stream/%d/transcript", testutils.StreamFPVLive.ID)
+	middlewares := testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin))
+
+	gomino.TestCases{
+		"no subtitles": {
+			Router:       router(t, nil),
+			Url:          url + "/de",
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusNotFound,
+		},
+		"transcript": {
+			Router:       router(t, nil),
+			Url:          url + "/en",
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusOK,
+			ExpectedResponse: model.Transcript{
+				Language: "en",
+				Cues: []model.TranscriptCue{
+					{Start: 1000, End: 4000, Text: "Welcome to the lecture"},
+					{Start: 5000, End: 8500, Text: "Todays topic are dynamic programs"},
+				},
+				Paragraphs: []model.TranscriptParagraph{{Start: 1000, End: 8500, Text: "Welcome to the lecture Todays topic are dynamic programs"}},
+			},
+		},
+		"search": {
+			Router:       router(t, nil),
+			Url:          url + "/en/search?q=Dynamic",
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusOK,
+			ExpectedResponse: gin.H{"hits": []transcriptHit{
+				{Timestamp: 5000, Text: "Todays topic are dynamic programs", TextPrev: "Welcome to the lecture"},
+			}},
+		},
+		"keywords": {
+			Router:       router(t, []model.TranscriptKeyword{{Keyword: "lecture", Start: 1000}, {Keyword: "lecture", Start: 60000}, {Keyword: "programs", Start: 5000}}),
+			Url:          url + "/en/keywords",
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusOK,
+			ExpectedResponse: []transcriptKeywordDto{
+				{Keyword: "lecture", Starts: []int64{1000, 60000}},
+				{Keyword: "programs", Starts: []int64{5000}},
+			},
+		},
+		"markdown export": {
+			Router:           router(t, nil),
+			Url:              url + "/en/export?format=md",
+			Middlewares:      middlewares,
+			ExpectedCode:     http.StatusOK,
+			ExpectedResponse: []byte("# " + testutils.CourseFPV.Name + ": " + testutils.StreamFPVLive.Name + "\n\n**[00:00:01]** Welcome to the lecture Todays topic are dynamic programs\n\n"),
+		},
+		"invalid format": {
+			Router:       router(t, nil),
+			Url:          url + "/en/export?format=docx",
+			Middlewares:  middlewares,
+			ExpectedCode: http.StatusBadRequest,
+		},
+	}.
+		Method(http.MethodGet).
+		Run(t, testutils.Equal)
+}
+
+func TestTranscriptTime(t *testing.T) {
+	if s := transcriptTime(3723500); s != "01:02:03" {
+		t.Errorf("expected 01:02:03, got %s", s)
+	}
+}
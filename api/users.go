@@ -40,6 +40,9 @@ func configGinUsersRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 	}
 
 	router.GET("/api/users/exportData", routes.exportPersonalData)
+	router.GET("/api/users/deletion", routes.getDeletionRequest)
+	router.POST("/api/users/deletion", routes.requestDeletion)
+	router.DELETE("/api/users/deletion", routes.cancelDeletion)
 
 	router.POST("/api/users/init", routes.InitUser)
 
@@ -50,6 +53,9 @@ func configGinUsersRouter(router *gin.Engine, daoWrapper dao.DaoWrapper) {
 	admins.GET("/searchUser", routes.SearchUser)
 	admins.POST("/users/update", routes.updateUser)
 	admins.POST("/users/impersonate", routes.impersonateUser)
+	admins.GET("/users/deletionRequests", routes.getDeletionRequests)
+	admins.DELETE("/users/deletionRequests/:userID", routes.cancelDeletionRequest)
+	admins.POST("/users/deletionRequests/:userID/execute", routes.executeDeletionRequest)
 
 	lecturers := router.Group("/api")
 	lecturers.Use(tools.AtLeastLecturer)
@@ -782,65 +788,6 @@ func (r usersRoutes) updateDefaultMode(c *gin.Context) {
 	}
 }
 
-func (r usersRoutes) exportPersonalData(c *gin.Context) {
-	var resp personalData
-	u := c.MustGet("TUMLiveContext").(tools.TUMLiveContext).User
-	resp.UserData = struct {
-		Name      string    `json:"name,omitempty"`
-		LastName  *string   `json:"last_name,omitempty"`
-		Email     string    `json:"email,omitempty"`
-		LrzID     string    `json:"lrz_id,omitempty"`
-		MatrNr    string    `json:"matr_nr,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
-	}{Name: u.Name, LastName: u.LastName, Email: u.Email.String, LrzID: u.LrzID, MatrNr: u.MatriculationNumber, CreatedAt: u.CreatedAt}
-	for _, course := range u.Courses {
-		resp.Enrollments = append(resp.Enrollments, struct {
-			Year   int    `json:"year,omitempty"`
-			Term   string `json:"term,omitempty"`
-			Course string `json:"course,omitempty"`
-		}{course.Year, course.TeachingTerm, course.Name})
-	}
-	progresses, err := r.ProgressDao.GetProgressesForUser(u.ID)
-	if err != nil {
-		_ = c.Error(tools.RequestError{
-			Status:        http.StatusInternalServerError,
-			CustomMessage: "can not get progresses for user",
-			Err:           err,
-		})
-		return
-	}
-	for _, progress := range progresses {
-		resp.VideoViews = append(resp.VideoViews, struct {
-			StreamID       uint    `json:"stream_id"`
-			Progress       float64 `json:"progress"`
-			MarkedFinished bool    `json:"marked_finished,omitempty"`
-		}{StreamID: progress.StreamID, Progress: progress.Progress, MarkedFinished: progress.Watched})
-	}
-	chats, err := r.ChatDao.GetChatsByUser(u.ID)
-	if err != nil {
-		chats = []model.Chat{}
-	}
-	for _, chat := range chats {
-		resp.Chats = append(resp.Chats, struct {
-			StreamId  uint      `json:"stream_id,omitempty"`
-			Message   string    `json:"message,omitempty"`
-			CreatedAt time.Time `json:"created_at"`
-		}{chat.StreamID, chat.Message, chat.CreatedAt})
-	}
-	c.Header("Content-Disposition:", `attachment; filename="personal_data.json"`)
-	c.Header("Content-Type", "application/json;charset=utf-8")
-	marshal, err := json.MarshalIndent(resp, "", "    ")
-	if err != nil {
-		_ = c.Error(tools.RequestError{
-			Status:        http.StatusInternalServerError,
-			CustomMessage: "can not marshal response",
-			Err:           err,
-		})
-		return
-	}
-	_, _ = c.Writer.Write(marshal)
-}
-
 func (r usersRoutes) resetPassword(c *gin.Context) {
 	type resetPasswordRequest struct {
 		Username string `json:"username"`
@@ -881,32 +828,6 @@ func (r usersRoutes) resetPassword(c *gin.Context) {
 	}
 }
 
-type personalData struct {
-	UserData struct {
-		Name      string    `json:"name,omitempty"`
-		LastName  *string   `json:"last_name,omitempty"`
-		Email     string    `json:"email,omitempty"`
-		LrzID     string    `json:"lrz_id,omitempty"`
-		MatrNr    string    `json:"matr_nr,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
-	} `json:"user_data"`
-	Enrollments []struct {
-		Year   int    `json:"year,omitempty"`
-		Term   string `json:"term,omitempty"`
-		Course string `json:"course,omitempty"`
-	} `json:"enrollments,omitempty"`
-	VideoViews []struct {
-		StreamID       uint    `json:"stream_id"`
-		Progress       float64 `json:"progress"`
-		MarkedFinished bool    `json:"marked_finished,omitempty"`
-	} `json:"video_views,omitempty"`
-	Chats []struct {
-		StreamId  uint      `json:"stream_id,omitempty"`
-		Message   string    `json:"message,omitempty"`
-		CreatedAt time.Time `json:"created_at"`
-	} `json:"chats,omitempty"`
-}
-
 type deleteUserRequest struct {
 	Id uint `json:"id"`
 }
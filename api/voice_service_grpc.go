@@ -13,9 +13,10 @@ import (
 	"github.com/TUM-Dev/gocast/tools"
 	"github.com/TUM-Dev/gocast/voice-service/pb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -24,33 +25,92 @@ type subtitleReceiverServer struct {
 	dao.DaoWrapper
 }
 
-func (s subtitleReceiverServer) Receive(_ context.Context, request *pb.ReceiveRequest) (*emptypb.Empty, error) {
-	subtitlesEntry := model.Subtitles{
-		StreamID: uint(request.GetStreamId()),
-		Content:  request.GetSubtitles(),
-		Language: request.GetLanguage(),
+// Receive stores subtitles generated or translated by the voice-service and starts the translations waiting for them
+func (s subtitleReceiverServer) Receive(ctx context.Context, request *pb.ReceiveRequest) (*emptypb.Empty, error) {
+	streamID := uint(request.GetStreamId())
+	source := model.SubtitlesSourceGenerated
+	var job model.SubtitleJob
+	if request.GetJobId() != 0 {
+		var err error
+		job, err = s.SubtitlesDao.GetJob(ctx, uint(request.GetJobId()))
+		if err != nil {
+			return nil, err
+		}
+		if job.IsTranslation() {
+			source = model.SubtitlesSourceTranslated
+		}
+	}
+	err := saveReceivedSubtitles(ctx, s.DaoWrapper, streamID, request.GetLanguage(), request.GetSubtitles(), source)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID != 0 {
+		job.State, job.Progress, job.Error = model.SubtitleJobDone, 100, ""
+		if err := s.SubtitlesDao.UpdateJob(ctx, &job); err != nil {
+			return nil, err
+		}
+	}
+
+	waiting, err := s.SubtitlesDao.GetWaitingJobs(ctx, streamID, request.GetLanguage())
+	if err != nil || len(waiting) == 0 {
+		return &emptypb.Empty{}, err
+	}
+	// edited subtitles are translated rather than the received ones
+	subtitles, err := s.SubtitlesDao.GetByStreamIDandLang(ctx, streamID, request.GetLanguage())
+	if err != nil {
+		return nil, err
+	}
+	for i := range waiting {
+		if err := startTranslation(ctx, s.DaoWrapper, &waiting[i], subtitles); err != nil {
+			logger.Error("can not start subtitle translation", "err", err, "job", waiting[i].ID)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+var subtitleJobStates = map[pb.JobState]string{
+	pb.JobState_JOB_STATE_QUEUED:  model.SubtitleJobQueued,
+	pb.JobState_JOB_STATE_RUNNING: model.SubtitleJobRunning,
+	pb.JobState_JOB_STATE_FAILED:  model.SubtitleJobFailed,
+	pb.JobState_JOB_STATE_DONE:    model.SubtitleJobDone,
+}
+
+// ReportStatus updates the state and progress of a subtitle job
+func (s subtitleReceiverServer) ReportStatus(ctx context.Context, request *pb.JobStatus) (*emptypb.Empty, error) {
+	state, ok := subtitleJobStates[request.GetState()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid job state")
 	}
-	err := s.SubtitlesDao.CreateOrUpsert(context.Background(), &subtitlesEntry)
+	job, err := s.SubtitlesDao.GetJob(ctx, uint(request.GetJobId()))
 	if err != nil {
 		return nil, err
 	}
+	job.State, job.Progress, job.Error = state, uint(min(request.GetProgress(), 100)), request.GetError()
+	if err := s.SubtitlesDao.UpdateJob(ctx, &job); err != nil {
+		return nil, err
+	}
+	if state == model.SubtitleJobFailed {
+		failWaitingTranslations(ctx, s.DaoWrapper, job.StreamID, job.Language)
+	}
 	return &emptypb.Empty{}, nil
 }
 
-func init() {
+// ServeSubtitleReceiverGRPC initializes the gRPC server the voice-service sends subtitles to on port 50053
+func ServeSubtitleReceiverGRPC() {
 	logger.Info("starting grpc voice-receiver")
+	initGRPCAuth()
 	lis, err := net.Listen("tcp", ":50053")
 	if err != nil {
 		logger.Error("failed to init voice-receiver server", "err", err)
 		return
 	}
-	grpcServer := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{
+	grpcServer := grpc.NewServer(append(voiceServerOptions(), grpc.KeepaliveParams(keepalive.ServerParameters{
 		MaxConnectionIdle:     time.Minute,
 		MaxConnectionAge:      time.Minute,
 		MaxConnectionAgeGrace: time.Second * 5,
 		Time:                  time.Minute * 10,
 		Timeout:               time.Second * 20,
-	}))
+	}))...)
 	pb.RegisterSubtitleReceiverServer(grpcServer, &subtitleReceiverServer{DaoWrapper: dao.NewDaoWrapper()})
 
 	reflection.Register(grpcServer)
@@ -68,7 +128,11 @@ type SubtitleGeneratorClient struct {
 
 func GetSubtitleGeneratorClient() (SubtitleGeneratorClient, error) {
 	voiceAddr := fmt.Sprintf("%s:%s", tools.Cfg.VoiceService.Host, tools.Cfg.VoiceService.Port)
-	conn, err := grpc.Dial(voiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts, err := voiceDialOptions()
+	if err != nil {
+		return SubtitleGeneratorClient{}, err
+	}
+	conn, err := grpc.Dial(voiceAddr, opts...)
 	if err != nil {
 		return SubtitleGeneratorClient{}, err
 	}
@@ -15,6 +15,7 @@ func configWorkerRouter(r *gin.Engine, daoWrapper dao.DaoWrapper) {
 	routes := workerRoutes{dao: daoWrapper.WorkerDao}
 
 	g.DELETE("/:id", routes.deleteWorker)
+	g.PUT("/:id/drain", routes.setDraining)
 }
 
 type workerRoutes struct {
@@ -34,3 +35,37 @@ func (r workerRoutes) deleteWorker(c *gin.Context) {
 		return
 	}
 }
+
+type setDrainingRequest struct {
+	Draining bool `json:"draining"`
+}
+
+// setDraining puts a worker in or out of the draining state. Draining workers finish their jobs but don't get new ones.
+func (r workerRoutes) setDraining(c *gin.Context) {
+	var req setDrainingRequest
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusBadRequest,
+			CustomMessage: "can not bind body",
+			Err:           err,
+		})
+		return
+	}
+	id := c.Param("id")
+	if _, err := r.dao.GetWorkerByID(c, id); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusNotFound,
+			CustomMessage: "worker not found",
+			Err:           err,
+		})
+		return
+	}
+	if err := r.dao.SetDraining(id, req.Draining); err != nil {
+		_ = c.Error(tools.RequestError{
+			Status:        http.StatusInternalServerError,
+			CustomMessage: "can not update worker",
+			Err:           err,
+		})
+		return
+	}
+}
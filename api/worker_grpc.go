@@ -23,13 +23,11 @@ import (
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
-	"github.com/TUM-Dev/gocast/tools/camera"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	"github.com/getsentry/sentry-go"
 	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
@@ -47,9 +45,8 @@ type server struct {
 }
 
 func dialIn(targetWorker model.Worker) (*grpc.ClientConn, error) {
-	credentials := insecure.NewCredentials()
 	logger.Info("Connecting to:" + fmt.Sprintf("%s:50051", targetWorker.Host))
-	conn, err := grpc.Dial(fmt.Sprintf("%s:50051", targetWorker.Host), grpc.WithTransportCredentials(credentials))
+	conn, err := grpc.Dial(fmt.Sprintf("%s:50051", targetWorker.Host), grpc.WithTransportCredentials(workerCredentials(targetWorker.WorkerID)))
 	return conn, err
 }
 
@@ -60,6 +57,7 @@ func endConnection(conn *grpc.ClientConn) {
 }
 
 // JoinWorkers is a request from a worker to join the pool. On success, the workerID is returned.
+// If the worker sent a certificate signing request, its certificate is issued as well.
 func (s server) JoinWorkers(ctx context.Context, request *pb.JoinWorkersRequest) (*pb.JoinWorkersResponse, error) {
 	logger.Info("JoinWorkers called", "host", request.Hostname)
 	if request.Token != tools.Cfg.WorkerToken {
@@ -70,29 +68,46 @@ func (s server) JoinWorkers(ctx context.Context, request *pb.JoinWorkersRequest)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, status.Errorf(codes.Internal, "get worker by hostname: %v", err)
 	}
-	if err == nil {
-		// worker already exists, return its ID
-		return &pb.JoinWorkersResponse{
-			WorkerId: worker.WorkerID,
-		}, nil
+	if err == gorm.ErrRecordNotFound {
+		// worker does not exist, create it
+		worker = model.Worker{
+			Host:     request.Hostname,
+			WorkerID: uuid.NewV4().String(),
+			LastSeen: time.Now(),
+		}
+		if err := s.DaoWrapper.WorkerDao.CreateWorker(&worker); err != nil {
+			logger.Error("Could not add worker to database", "err", err)
+			return nil, status.Errorf(codes.Internal, "Could not add worker to database")
+		}
+		logger.Info("Added worker to database")
 	}
 
-	// worker does not exist, create it
-	worker = model.Worker{
-		Host:     request.Hostname,
-		WorkerID: uuid.NewV4().String(),
-		LastSeen: time.Now(),
-	}
-	if err := s.DaoWrapper.WorkerDao.CreateWorker(&worker); err != nil {
-		logger.Error("Could not add worker to database", "err", err)
-		return nil, status.Errorf(codes.Internal, "Could not add worker to database")
+	cert, ca, err := issueWorkerCertificate(worker.WorkerID, request.GetCsr())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "issue certificate: %v", err)
 	}
-	logger.Info("Added worker to database")
 	return &pb.JoinWorkersResponse{
-		WorkerId: worker.WorkerID,
+		WorkerId:      worker.WorkerID,
+		Certificate:   cert,
+		CaCertificate: ca,
 	}, nil
 }
 
+// RenewCertificate issues a new certificate to a worker before its current one expires
+func (s server) RenewCertificate(ctx context.Context, request *pb.RenewCertificateRequest) (*pb.RenewCertificateResponse, error) {
+	if grpcCA == nil {
+		return nil, status.Error(codes.FailedPrecondition, "grpc is not configured to use TLS")
+	}
+	if _, err := s.DaoWrapper.WorkerDao.GetWorkerByID(ctx, request.GetWorkerID()); err != nil {
+		return nil, status.Error(codes.NotFound, "worker not found")
+	}
+	cert, ca, err := issueWorkerCertificate(request.GetWorkerID(), request.GetCsr())
+	if err != nil || cert == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "issue certificate: %v", err)
+	}
+	return &pb.RenewCertificateResponse{Certificate: cert, CaCertificate: ca}, nil
+}
+
 // NotifySilenceResults handles the results of silence detection sent by a worker
 func (s server) NotifySilenceResults(ctx context.Context, request *pb.SilenceResults) (*pb.Status, error) {
 	if _, err := s.DaoWrapper.WorkerDao.GetWorkerByID(ctx, request.WorkerID); err != nil {
@@ -118,6 +133,73 @@ func (s server) NotifySilenceResults(ctx context.Context, request *pb.SilenceRes
 	return &pb.Status{Ok: true}, nil
 }
 
+// NotifySlideSuggestions stores the sections a worker suggests at the slide changes of a presentation recording.
+// Suggestions of earlier runs are replaced, lecturers accept or reject them in the lecture settings.
+func (s server) NotifySlideSuggestions(ctx context.Context, request *pb.SlideSuggestions) (*pb.Status, error) {
+	if _, err := s.DaoWrapper.WorkerDao.GetWorkerByID(ctx, request.GetWorkerID()); err != nil {
+		return nil, err
+	}
+	stream, err := s.StreamsDao.GetStreamByID(ctx, fmt.Sprintf("%d", request.GetStreamID()))
+	if err != nil {
+		return nil, err
+	}
+	old, err := s.VideoSectionDao.GetSuggestionsByStreamId(stream.ID)
+	if err != nil {
+		return nil, err
+	}
+	newPaths := make(map[string]bool, len(request.GetSlides()))
+	for _, slide := range request.GetSlides() {
+		newPaths[slide.GetImagePath()] = true
+	}
+	var oldPaths []string
+	for _, suggestion := range old {
+		file, err := s.FileDao.GetFileById(fmt.Sprintf("%d", suggestion.FileID))
+		if err != nil {
+			logger.Warn("Can't get image of old section suggestion", "err", err, "file", suggestion.FileID)
+			continue
+		}
+		if err := s.FileDao.DeleteFile(file.ID); err != nil {
+			logger.Warn("Can't delete image of old section suggestion", "err", err, "file", file.ID)
+		}
+		// images of slides starting at the same second were overwritten by the worker
+		if !newPaths[file.Path] {
+			oldPaths = append(oldPaths, file.Path)
+		}
+	}
+	if len(oldPaths) > 0 {
+		go func() {
+			for _, path := range oldPaths {
+				if err := DeleteVideoSectionImage(s.DaoWrapper.WorkerDao, path); err != nil {
+					logger.Error("failed to delete image of old section suggestion", "err", err, "path", path)
+				}
+			}
+		}()
+	}
+	suggestions := make([]model.SectionSuggestion, 0, len(request.GetSlides()))
+	for i, slide := range request.GetSlides() {
+		image := model.File{StreamID: stream.ID, Path: slide.GetImagePath(), Type: model.FILETYPE_IMAGE_JPG}
+		if err := s.FileDao.NewFile(&image); err != nil {
+			return nil, err
+		}
+		description := slide.GetTitle()
+		if description == "" {
+			description = fmt.Sprintf("Slide %d", i+1)
+		}
+		suggestions = append(suggestions, model.SectionSuggestion{
+			Description:  description,
+			StartHours:   uint(slide.GetSecond() / 3600),
+			StartMinutes: uint(slide.GetSecond() / 60 % 60),
+			StartSeconds: uint(slide.GetSecond() % 60),
+			StreamID:     stream.ID,
+			FileID:       image.ID,
+		})
+	}
+	if err := s.VideoSectionDao.ReplaceSuggestions(stream.ID, suggestions); err != nil {
+		return nil, err
+	}
+	return &pb.Status{Ok: true}, nil
+}
+
 // SendSelfStreamRequest handles the request from a worker when a stream starts publishing via obs, etc.
 // returns an error if anything goes wrong OR the stream may not be published.
 func (s server) SendSelfStreamRequest(ctx context.Context, request *pb.SelfStreamRequest) (*pb.SelfStreamResponse, error) {
@@ -163,7 +245,9 @@ func (s server) SendSelfStreamRequest(ctx context.Context, request *pb.SelfStrea
 		UploadVoD:    course.VODEnabled,
 		IngestServer: ingestServer.Url,
 		StreamName:   slot.StreamName,
-		OutUrl:       ingestServer.OutUrl,
+		OutUrl:       ingestServer.LiveOutUrl(course.LivePlayback),
+		Dvr:          course.DvrEnabled,
+		DvrWindow:    uint32(course.DvrWindow * 60),
 	}, nil
 }
 
@@ -231,11 +315,32 @@ func (s server) NotifyStreamFinished(ctx context.Context, request *pb.StreamFini
 		if err != nil {
 			logger.Error("Can't set stream not live", "err", err)
 		}
+		if stream.IsPremiereReady() {
+			if err = publishPremiere(ctx, s.DaoWrapper, stream); err != nil {
+				logger.Error("Can't publish VoD of premiere", "err", err)
+			} else {
+				NotifyViewersVodPublished(stream.ID)
+			}
+		}
 		NotifyViewersLiveState(uint(request.StreamID), false)
 	}
 	return &pb.Status{Ok: true}, nil
 }
 
+// publishPremiere turns a finished premiere into a VoD. The file was transcoded before the premiere, so this only
+// replaces the live playlist with the VoD.
+func publishPremiere(ctx context.Context, daoWrapper dao.DaoWrapper, stream model.Stream) error {
+	course, err := daoWrapper.CoursesDao.GetCourseById(ctx, stream.CourseID)
+	if err != nil {
+		return err
+	}
+	stream.PlaylistUrl = stream.PremiereVodUrl
+	stream.LiveNow = false
+	stream.Recording = true
+	stream.Private = course.VodPrivate
+	return daoWrapper.StreamsDao.SaveStream(&stream)
+}
+
 func handleCameraPositionSwitch(stream model.Stream, daoWrapper dao.DaoWrapper) error {
 	if stream.LectureHallID == 0 {
 		return nil
@@ -248,6 +353,11 @@ func handleCameraPositionSwitch(stream model.Stream, daoWrapper dao.DaoWrapper)
 	if err != nil {
 		return err
 	}
+	cam, err := tools.NewPresetUtility(daoWrapper.LectureHallsDao).ProvideCamera(lectureHall.CameraType, lectureHall.CameraIP)
+	if err != nil {
+		logger.Warn("Can't switch camera preset of lecture hall", "err", err, "lectureHall", lectureHall.ID, "cameraType", lectureHall.CameraType)
+		return nil // no camera that supports presets
+	}
 	var preferences []model.CameraPresetPreference
 	// make sure there is an empty list if no preferences are found (null or empty string in db)
 	if course.CameraPresetPreferences == "" {
@@ -259,12 +369,7 @@ func handleCameraPositionSwitch(stream model.Stream, daoWrapper dao.DaoWrapper)
 	}
 	for _, preference := range preferences {
 		if preference.LectureHallID == stream.LectureHallID {
-			switch lectureHall.CameraType {
-			case model.Axis:
-				return camera.NewAxisCam(lectureHall.CameraIP, tools.Cfg.Auths.CamAuth).SetPreset(preference.PresetID)
-			case model.Panasonic:
-				return camera.NewPanasonicCam(lectureHall.CameraIP, nil).SetPreset(preference.PresetID)
-			}
+			return cam.SetPreset(preference.PresetID)
 		}
 	}
 	// no preset found for this lecture hall, use default
@@ -272,13 +377,7 @@ func handleCameraPositionSwitch(stream model.Stream, daoWrapper dao.DaoWrapper)
 	if err != nil {
 		return err
 	}
-	switch lectureHall.CameraType {
-	case model.Axis:
-		return camera.NewAxisCam(lectureHall.CameraIP, tools.Cfg.Auths.CamAuth).SetPreset(defaultPreset.PresetID)
-	case model.Panasonic:
-		return camera.NewPanasonicCam(lectureHall.CameraIP, nil).SetPreset(defaultPreset.PresetID)
-	}
-	return nil
+	return cam.SetPreset(defaultPreset.PresetID)
 }
 
 func handleLightOnSwitch(stream model.Stream, daoWrapper dao.DaoWrapper) error {
@@ -351,6 +450,7 @@ func (s server) SendHeartBeat(ctx context.Context, request *pb.HeartBeat) (*pb.S
 		worker.Disk = request.Disk
 		worker.Uptime = request.Uptime
 		worker.Version = request.Version
+		worker.ShuttingDown = request.Draining
 		err := s.DaoWrapper.SaveWorker(worker)
 		if err != nil {
 			return nil, err
@@ -376,14 +476,25 @@ func (s server) NotifyTranscodingFinished(ctx context.Context, request *pb.Trans
 
 	// look for file to prevent duplication
 	shouldAddFile := true
-	for _, file := range stream.Files {
-		if file.Path == request.FilePath {
+	for i := range stream.Files {
+		if stream.Files[i].Path == request.FilePath {
 			shouldAddFile = false
+			// re-transcoded, record the latest verification
+			stream.Files[i].Verified = request.Verified
+			stream.Files[i].Verification = request.Verification
+			if err = s.DaoWrapper.FileDao.UpdateFile(fmt.Sprintf("%d", stream.Files[i].ID), &stream.Files[i]); err != nil {
+				logger.Error("Can't save verification of file", "err", err)
+			}
 			break
 		}
 	}
 	if shouldAddFile {
-		stream.Files = append(stream.Files, model.File{StreamID: stream.ID, Path: request.FilePath})
+		stream.Files = append(stream.Files, model.File{
+			StreamID:     stream.ID,
+			Path:         request.FilePath,
+			Verified:     request.Verified,
+			Verification: request.Verification,
+		})
 	}
 
 	if request.Duration != 0 {
@@ -416,6 +527,17 @@ func (s server) NotifyUploadFinished(ctx context.Context, req *pb.UploadFinished
 		logger.Warn("VoD not saved, stream is live.", "req", req)
 		return nil, nil
 	}
+	if stream.Premiere && !stream.Ended && stream.Start.After(time.Now()) {
+		// keep the VoD until the premiere ended, it is streamed from the transcoded file
+		stream.PremiereVodUrl = req.HLSUrl
+		if stream.Duration.Valid {
+			stream.End = stream.Start.Add(time.Duration(stream.Duration.Int32) * time.Second)
+		}
+		if err = s.StreamsDao.SaveStream(&stream); err != nil {
+			return nil, err
+		}
+		return &pb.Status{Ok: true}, nil
+	}
 	stream.Recording = true
 	stream.Private = course.VodPrivate
 	switch req.SourceType {
@@ -429,6 +551,13 @@ func (s server) NotifyUploadFinished(ctx context.Context, req *pb.UploadFinished
 	if err = s.StreamsDao.SaveStream(&stream); err != nil {
 		return nil, err
 	}
+	if req.SourceType != "CAM" && req.SourceType != "PRES" {
+		// viewers of the DVR recording switch to the VoD
+		if err = s.StreamsDao.SaveDvrUrl(stream.ID, ""); err != nil {
+			logger.Error("Can't remove DVR url", "err", err)
+		}
+		NotifyViewersVodPublished(stream.ID)
+	}
 	return &pb.Status{Ok: true}, nil
 }
 
@@ -614,6 +743,9 @@ func (s server) NotifyStreamStarted(ctx context.Context, request *pb.StreamStart
 			s.StreamsDao.SavePRESURL(&stream, request.HlsUrl)
 		default:
 			s.StreamsDao.SaveCOMBURL(&stream, request.HlsUrl)
+			if err := s.StreamsDao.SaveDvrUrl(stream.ID, request.DvrUrl); err != nil {
+				logger.Error("Can't save DVR url", "err", err)
+			}
 		}
 		NotifyViewersLiveState(stream.Model.ID, true)
 		NotifyLiveUpdateCourseWentLive(stream.Model.ID)
@@ -703,7 +835,9 @@ func CreateStreamRequest(daoWrapper dao.DaoWrapper, stream model.Stream, course
 		CourseYear:   uint32(course.Year),
 		StreamName:   slot.StreamName,
 		IngestServer: server.Url,
-		OutUrl:       server.OutUrl,
+		OutUrl:       server.LiveOutUrl(course.LivePlayback),
+		Dvr:          course.DvrEnabled,
+		DvrWindow:    uint32(course.DvrWindow * 60),
 	}
 	workerIndex := getWorkerWithLeastWorkload(workers)
 	workers[workerIndex].Workload += 3
@@ -795,22 +929,36 @@ func notifyWorkersPremieres(daoWrapper dao.DaoWrapper) {
 			sentry.CaptureException(err)
 			continue
 		}
-		if len(streams[i].Files) == 0 {
-			logger.Warn("Request to self stream without file", "streamID", streams[i].ID)
+		file, ok := streams[i].PremiereFile()
+		if !streams[i].IsPremiereReady() || !ok {
+			logger.Warn("Premiere is due but its file isn't transcoded yet", "streamID", streams[i].ID)
 			continue
 		}
-		workerIndex := getWorkerWithLeastWorkload(workers)
-		workers[workerIndex].Workload += 3
 		ingestServer, err := daoWrapper.IngestServerDao.GetBestIngestServer()
 		if err != nil {
 			logger.Error("Can't find ingest server", "err", err)
 			continue
 		}
+		slot, err := daoWrapper.IngestServerDao.GetStreamSlot(ingestServer.ID)
+		if err != nil {
+			logger.Error("No free stream slot", "err", err)
+			continue
+		}
+		slot.StreamID = streams[i].ID
+		daoWrapper.IngestServerDao.SaveSlot(slot)
+		workerIndex := getWorkerWithLeastWorkload(workers)
+		workers[workerIndex].Workload += 3
+		err = daoWrapper.StreamsDao.SaveWorkerForStream(streams[i], workers[workerIndex])
+		if err != nil {
+			logger.Error("Could not save worker for stream", "err", err)
+		}
 		req := pb.PremiereRequest{
 			StreamID:     uint32(streams[i].ID),
-			FilePath:     streams[i].Files[0].Path,
+			FilePath:     file.Path,
+			StreamName:   slot.StreamName,
 			IngestServer: ingestServer.Url,
 			OutUrl:       ingestServer.OutUrl,
+			Start:        timestamppb.New(streams[i].Start),
 		}
 		conn, err := dialIn(workers[workerIndex])
 		if err != nil {
@@ -1092,18 +1240,19 @@ func getWorkerWithLeastWorkload(workers []model.Worker) int {
 // ServeWorkerGRPC initializes a gRPC server on port 50052
 func ServeWorkerGRPC() {
 	logger.Info("Serving heartbeat")
+	initGRPCAuth()
 	lis, err := net.Listen("tcp", ":50052")
 	if err != nil {
 		logger.Error("Failed to init grpc server", "err", err)
 		return
 	}
-	grpcServer := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{
+	grpcServer := grpc.NewServer(append(workerServerOptions(), grpc.KeepaliveParams(keepalive.ServerParameters{
 		MaxConnectionIdle:     time.Minute,
 		MaxConnectionAge:      time.Minute * 5,
 		MaxConnectionAgeGrace: time.Second * 5,
 		Time:                  time.Minute * 10,
 		Timeout:               time.Second * 20,
-	}))
+	}))...)
 	pb.RegisterFromWorkerServer(grpcServer, &server{DaoWrapper: dao.NewDaoWrapper()})
 	reflection.Register(grpcServer)
 	go func() {
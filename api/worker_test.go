@@ -8,11 +8,13 @@ import (
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/mock_dao"
+	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
 	"github.com/TUM-Dev/gocast/tools/testutils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/matthiasreumann/gomino"
+	"gorm.io/gorm"
 )
 
 func TestWorker(t *testing.T) {
@@ -62,4 +64,53 @@ func TestWorker(t *testing.T) {
 			Url(url).
 			Run(t, testutils.Equal)
 	})
+	t.Run("PUT/api/workers/:workerID/drain", func(t *testing.T) {
+		url := fmt.Sprintf("/api/workers/%s/drain", testutils.Worker1.WorkerID)
+		middlewares := testutils.GetMiddlewares(tools.ErrorHandler, testutils.TUMLiveContext(testutils.TUMLiveContextAdmin))
+		gomino.TestCases{
+			"invalid body": {
+				Router: func(r *gin.Engine) {
+					configWorkerRouter(r, dao.DaoWrapper{})
+				},
+				Middlewares:  middlewares,
+				Body:         nil,
+				ExpectedCode: http.StatusBadRequest,
+			},
+			"worker not found": {
+				Router: func(r *gin.Engine) {
+					workerDaoMock := mock_dao.NewMockWorkerDao(gomock.NewController(t))
+					workerDaoMock.EXPECT().GetWorkerByID(gomock.Any(), testutils.Worker1.WorkerID).Return(model.Worker{}, gorm.ErrRecordNotFound)
+					configWorkerRouter(r, dao.DaoWrapper{WorkerDao: workerDaoMock})
+				},
+				Middlewares:  middlewares,
+				Body:         setDrainingRequest{Draining: true},
+				ExpectedCode: http.StatusNotFound,
+			},
+			"can not update worker": {
+				Router: func(r *gin.Engine) {
+					workerDaoMock := mock_dao.NewMockWorkerDao(gomock.NewController(t))
+					workerDaoMock.EXPECT().GetWorkerByID(gomock.Any(), testutils.Worker1.WorkerID).Return(testutils.Worker1, nil)
+					workerDaoMock.EXPECT().SetDraining(testutils.Worker1.WorkerID, true).Return(errors.New(""))
+					configWorkerRouter(r, dao.DaoWrapper{WorkerDao: workerDaoMock})
+				},
+				Middlewares:  middlewares,
+				Body:         setDrainingRequest{Draining: true},
+				ExpectedCode: http.StatusInternalServerError,
+			},
+			"success": {
+				Router: func(r *gin.Engine) {
+					workerDaoMock := mock_dao.NewMockWorkerDao(gomock.NewController(t))
+					workerDaoMock.EXPECT().GetWorkerByID(gomock.Any(), testutils.Worker1.WorkerID).Return(testutils.Worker1, nil)
+					workerDaoMock.EXPECT().SetDraining(testutils.Worker1.WorkerID, false).Return(nil)
+					configWorkerRouter(r, dao.DaoWrapper{WorkerDao: workerDaoMock})
+				},
+				Middlewares:  middlewares,
+				Body:         setDrainingRequest{Draining: false},
+				ExpectedCode: http.StatusOK,
+			},
+		}.
+			Method(http.MethodPut).
+			Url(url).
+			Run(t, testutils.Equal)
+	})
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools"
 	"github.com/TUM-Dev/gocast/tools/realtime"
 	"github.com/getsentry/sentry-go"
@@ -28,8 +29,8 @@ const (
 )
 
 type sessionWrapper struct {
-	session         *realtime.Context
-	isAdminOfCourse bool
+	session     *realtime.Context
+	canModerate bool // whether the user sees messages waiting for approval
 }
 
 var connHandler = func(context *realtime.Context) {
@@ -39,11 +40,8 @@ var connHandler = func(context *realtime.Context) {
 		return
 	}
 	tumLiveContext := foundContext.(tools.TUMLiveContext)
-	isAdmin := false
-	if tumLiveContext.User != nil {
-		isAdmin = tumLiveContext.User.IsAdminOfCourse(*tumLiveContext.Course)
-	}
-	sessionData := sessionWrapper{context, isAdmin}
+	canModerate := tumLiveContext.UserHasCoursePermission(model.CoursePermissionModerateChat)
+	sessionData := sessionWrapper{context, canModerate}
 
 	wsMapLock.Lock()
 	sessionsMap[tumLiveContext.Stream.ID] = append(sessionsMap[tumLiveContext.Stream.ID], &sessionData)
@@ -54,6 +52,14 @@ var connHandler = func(context *realtime.Context) {
 	if err != nil {
 		logger.Error("can't write initial stats to session", "err", err)
 	}
+
+	// viewers waiting for a premiere count down to its start
+	if stream := tumLiveContext.Stream; stream.Premiere && !stream.LiveNow && !stream.Recording {
+		msg, _ = json.Marshal(gin.H{"premiere": gin.H{"start": stream.Start, "ready": stream.IsPremiereReady()}})
+		if err = context.Send(msg); err != nil {
+			logger.Error("can't write premiere to session", "err", err)
+		}
+	}
 }
 
 // sendServerMessageWithBackoff sends a message to the client(if it didn't send a message to this user in the last 10 Minutes and the client is logged in)
@@ -142,7 +148,7 @@ func broadcastStreamToAdmins(streamID uint, msg []byte) {
 	wsMapLock.Unlock()
 
 	for _, wrapper := range sessions {
-		if wrapper.isAdminOfCourse {
+		if wrapper.canModerate {
 			_ = wrapper.session.Send(msg)
 		}
 	}
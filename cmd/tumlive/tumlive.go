@@ -38,6 +38,7 @@ var logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 var initializers = []initializer{
 	tools.LoadConfig,
 	api.ServeWorkerGRPC,
+	api.ServeSubtitleReceiverGRPC,
 	tools.InitBranding,
 }
 
@@ -72,6 +73,7 @@ func GinServer() (err error) {
 	}))
 
 	router.Use(tools.InitContext(dao.NewDaoWrapper()))
+	router.Use(tools.InitGuest(dao.NewDaoWrapper()))
 
 	liveUpdates := router.Group("/api/pub-sub")
 	api.ConfigRealtimeRouter(liveUpdates)
@@ -163,6 +165,7 @@ func main() {
 		&model.Silence{},
 		&model.ShortLink{},
 		&model.Stat{},
+		&model.ViewSession{},
 		&model.StreamUnit{},
 		&model.LectureHall{},
 		&model.IngestServer{},
@@ -173,10 +176,12 @@ func main() {
 		&model.ServerNotification{},
 		&model.File{},
 		&model.StreamProgress{},
+		&model.ProgressRollup{},
 		&model.Token{},
 		&model.Poll{},
 		&model.PollOption{},
 		&model.VideoSection{},
+		&model.SectionSuggestion{},
 		&model.VideoSeekChunk{},
 		&model.Notification{},
 		&model.UploadKey{},
@@ -187,8 +192,16 @@ func main() {
 		&model.TranscodingProgress{},
 		&model.ChatReaction{},
 		&model.Subtitles{},
+		&model.SubtitlesRevision{},
+		&model.TranscriptKeyword{},
+		&model.SubtitleJob{},
 		&model.TranscodingFailure{},
 		&model.Email{},
+		&model.DeletionRequest{},
+		&model.CourseRole{},
+		&model.AccessGroup{},
+		&model.AccessGroupMember{},
+		&model.GuestLink{},
 	)
 	if err != nil {
 		sentry.CaptureException(err)
@@ -241,6 +254,8 @@ func initCron() {
 	_ = tools.Cron.AddFunc("fetchCourses", tum.FetchCourses(daoWrapper), "0 */12 * * *")
 	// Collect livestream stats (viewers) every minute
 	_ = tools.Cron.AddFunc("collectStats", api.CollectStats(daoWrapper), "0-59 * * * *")
+	// Aggregate statistics older than the retention period daily
+	_ = tools.Cron.AddFunc("aggregateStats", api.AggregateStats(daoWrapper), "15 3 * * *")
 	// Flush stale sentry exceptions and transactions every 5 minutes
 	_ = tools.Cron.AddFunc("sentryFlush", func() { sentry.Flush(time.Minute * 2) }, "0-59/5 * * * *")
 	// Look for due streams and notify workers about them
@@ -251,6 +266,10 @@ func initCron() {
 	_ = tools.Cron.AddFunc("exportToMeili", tools.NewMeiliExporter(daoWrapper).Export, "30 4 * * *")
 	// fetch live stream previews
 	_ = tools.Cron.AddFunc("fetchLivePreviews", api.FetchLivePreviews(daoWrapper), "*/1 * * * *")
+	// Delete accounts whose deletion grace period ended
+	_ = tools.Cron.AddFunc("deleteAccounts", api.DeleteDueAccounts(daoWrapper), "20 * * * *")
+	// Sync the members of access groups with their LDAP groups every 6 hours
+	_ = tools.Cron.AddFunc("syncAccessGroups", tum.SyncAccessGroups(daoWrapper), "40 */6 * * *")
 	tools.Cron.Run()
 }
 
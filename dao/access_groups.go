@@ -0,0 +1,140 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=access_groups.go -destination ../mock_dao/access_groups.go
+
+type AccessGroupsDao interface {
+	// GetAccessGroups returns all access groups with their member count
+	GetAccessGroups(ctx context.Context) ([]model.AccessGroup, error)
+	// GetAccessGroup returns the access group with its courses
+	GetAccessGroup(ctx context.Context, id uint) (model.AccessGroup, error)
+	CreateAccessGroup(ctx context.Context, group *model.AccessGroup) error
+	UpdateAccessGroup(ctx context.Context, group *model.AccessGroup) error
+	DeleteAccessGroup(ctx context.Context, id uint) error
+
+	// GetMembers returns the LRZ IDs of the members of the group
+	GetMembers(ctx context.Context, groupID uint) ([]string, error)
+	// SetMembers replaces the members of the group and returns the added and removed LRZ IDs
+	SetMembers(ctx context.Context, groupID uint, lrzIDs []string) (added []string, removed []string, err error)
+	// SaveSyncResult stores the result of the last sync of the group
+	SaveSyncResult(ctx context.Context, groupID uint, added int, removed int, syncErr error) error
+
+	// AddCourse lets the members of the group watch the course
+	AddCourse(ctx context.Context, groupID uint, courseID uint) error
+	RemoveCourse(ctx context.Context, groupID uint, courseID uint) error
+}
+
+type accessGroupsDao struct {
+	db *gorm.DB
+}
+
+func NewAccessGroupsDao() AccessGroupsDao {
+	return accessGroupsDao{db: DB}
+}
+
+func (d accessGroupsDao) GetAccessGroups(ctx context.Context) (groups []model.AccessGroup, err error) {
+	err = d.db.WithContext(ctx).Preload("Courses").Order("name").Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		AccessGroupID uint
+		Count         int
+	}
+	err = d.db.WithContext(ctx).Model(&model.AccessGroupMember{}).
+		Select("access_group_id, count(*) AS count").Group("access_group_id").Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	countByGroup := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		countByGroup[c.AccessGroupID] = c.Count
+	}
+	for i := range groups {
+		groups[i].MemberCount = countByGroup[groups[i].ID]
+	}
+	return groups, nil
+}
+
+func (d accessGroupsDao) GetAccessGroup(ctx context.Context, id uint) (group model.AccessGroup, err error) {
+	err = d.db.WithContext(ctx).Preload("Courses").First(&group, id).Error
+	if err != nil {
+		return group, err
+	}
+	var count int64
+	err = d.db.WithContext(ctx).Model(&model.AccessGroupMember{}).Where("access_group_id = ?", id).Count(&count).Error
+	group.MemberCount = int(count)
+	return group, err
+}
+
+func (d accessGroupsDao) CreateAccessGroup(ctx context.Context, group *model.AccessGroup) error {
+	return d.db.WithContext(ctx).Omit("Courses").Create(group).Error
+}
+
+func (d accessGroupsDao) UpdateAccessGroup(ctx context.Context, group *model.AccessGroup) error {
+	return d.db.WithContext(ctx).Model(group).Select("name", "ldap_group").Updates(group).Error
+}
+
+func (d accessGroupsDao) DeleteAccessGroup(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.AccessGroupMember{}, "access_group_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Select("Courses").Delete(&model.AccessGroup{Model: gorm.Model{ID: id}}).Error
+	})
+}
+
+func (d accessGroupsDao) GetMembers(ctx context.Context, groupID uint) (members []string, err error) {
+	err = d.db.WithContext(ctx).Model(&model.AccessGroupMember{}).
+		Where("access_group_id = ?", groupID).Order("lrz_id").Pluck("lrz_id", &members).Error
+	return members, err
+}
+
+func (d accessGroupsDao) SetMembers(ctx context.Context, groupID uint, lrzIDs []string) (added []string, removed []string, err error) {
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []string
+		if err := tx.Model(&model.AccessGroupMember{}).Where("access_group_id = ?", groupID).Pluck("lrz_id", &current).Error; err != nil {
+			return err
+		}
+		added, removed = model.DiffMembers(current, lrzIDs)
+		if len(removed) > 0 {
+			if err := tx.Delete(&model.AccessGroupMember{}, "access_group_id = ? AND lrz_id IN ?", groupID, removed).Error; err != nil {
+				return err
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		members := make([]model.AccessGroupMember, len(added))
+		for i, lrzID := range added {
+			members[i] = model.AccessGroupMember{AccessGroupID: groupID, LrzID: lrzID}
+		}
+		return tx.CreateInBatches(members, 500).Error
+	})
+	return added, removed, err
+}
+
+func (d accessGroupsDao) SaveSyncResult(ctx context.Context, groupID uint, added int, removed int, syncErr error) error {
+	updates := map[string]interface{}{"synced_at": time.Now(), "sync_added": added, "sync_removed": removed, "sync_error": ""}
+	if syncErr != nil {
+		updates["sync_error"] = syncErr.Error()
+	}
+	return d.db.WithContext(ctx).Model(&model.AccessGroup{}).Where("id = ?", groupID).Updates(updates).Error
+}
+
+func (d accessGroupsDao) AddCourse(ctx context.Context, groupID uint, courseID uint) error {
+	return d.db.WithContext(ctx).Model(&model.AccessGroup{Model: gorm.Model{ID: groupID}}).
+		Association("Courses").Append(&model.Course{Model: gorm.Model{ID: courseID}})
+}
+
+func (d accessGroupsDao) RemoveCourse(ctx context.Context, groupID uint, courseID uint) error {
+	return d.db.WithContext(ctx).Model(&model.AccessGroup{Model: gorm.Model{ID: groupID}}).
+		Association("Courses").Delete(&model.Course{Model: gorm.Model{ID: courseID}})
+}
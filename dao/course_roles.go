@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TUM-Dev/gocast/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=course_roles.go -destination ../mock_dao/course_roles.go
+
+type CourseRolesDao interface {
+	// GetCourseRoles returns the roles of a course with their users
+	GetCourseRoles(ctx context.Context, courseID uint) ([]model.CourseRole, error)
+	// SetCourseRole creates the role or changes the role of the user if they already have one
+	SetCourseRole(ctx context.Context, role model.CourseRole) error
+	RemoveCourseRole(ctx context.Context, courseID uint, userID uint) error
+}
+
+type courseRolesDao struct {
+	db *gorm.DB
+}
+
+func NewCourseRolesDao() CourseRolesDao {
+	return courseRolesDao{db: DB}
+}
+
+func (d courseRolesDao) GetCourseRoles(ctx context.Context, courseID uint) (roles []model.CourseRole, err error) {
+	return roles, d.db.WithContext(ctx).Preload("User").Find(&roles, "course_id = ?", courseID).Error
+}
+
+func (d courseRolesDao) SetCourseRole(ctx context.Context, role model.CourseRole) error {
+	defer Cache.Del(fmt.Sprintf("userById%d", role.UserID))
+	return d.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "course_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&role).Error
+}
+
+func (d courseRolesDao) RemoveCourseRole(ctx context.Context, courseID uint, userID uint) error {
+	defer Cache.Del(fmt.Sprintf("userById%d", userID))
+	return d.db.WithContext(ctx).Delete(&model.CourseRole{}, "course_id = ? AND user_id = ?", courseID, userID).Error
+}
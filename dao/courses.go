@@ -18,6 +18,10 @@ import (
 type CoursesDao interface {
 	CreateCourse(ctx context.Context, course *model.Course, keep bool) error
 	AddAdminToCourse(userID uint, courseID uint) error
+	// GetCopySource returns the lectures of a course with their units, attachments and sections and their subtitles by lecture
+	GetCopySource(ctx context.Context, courseID uint) ([]model.Stream, map[uint][]model.Subtitles, error)
+	// CopyCourse creates the copy of a course in a single transaction
+	CopyCourse(ctx context.Context, c *CourseCopy) error
 
 	GetCurrentOrNextLectureForCourse(ctx context.Context, courseID uint) (model.Stream, error)
 	GetAllCourses() ([]model.Course, error)
@@ -67,6 +71,75 @@ func (d coursesDao) CreateCourse(ctx context.Context, course *model.Course, keep
 	return nil
 }
 
+// CourseCopy is a copy of a course and its lectures that isn't stored yet
+type CourseCopy struct {
+	Course   model.Course
+	Streams  []StreamCopy
+	AdminIDs []uint
+}
+
+// StreamCopy is a copied lecture, its Units, Files and VideoSections are created with it
+type StreamCopy struct {
+	Stream    model.Stream
+	Subtitles []model.Subtitles
+}
+
+func (d coursesDao) GetCopySource(ctx context.Context, courseID uint) (streams []model.Stream, subtitles map[uint][]model.Subtitles, err error) {
+	err = DB.WithContext(ctx).
+		Preload("Units").
+		Preload("Files", "type = ?", model.FILETYPE_ATTACHMENT).
+		Preload("VideoSections").
+		Order("start").
+		Find(&streams, "course_id = ?", courseID).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uint, len(streams))
+	for i, stream := range streams {
+		ids[i] = stream.ID
+	}
+	var all []model.Subtitles
+	if err = DB.WithContext(ctx).Find(&all, "stream_id IN ?", ids).Error; err != nil {
+		return nil, nil, err
+	}
+	subtitles = make(map[uint][]model.Subtitles)
+	for _, s := range all {
+		subtitles[s.StreamID] = append(subtitles[s.StreamID], s)
+	}
+	return streams, subtitles, nil
+}
+
+func (d coursesDao) CopyCourse(ctx context.Context, c *CourseCopy) error {
+	defer Cache.Clear()
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&c.Course).Error; err != nil {
+			return err
+		}
+		for i := range c.Streams {
+			stream := &c.Streams[i]
+			stream.Stream.CourseID = c.Course.ID
+			if err := tx.Create(&stream.Stream).Error; err != nil {
+				return err
+			}
+			for j := range stream.Subtitles {
+				stream.Subtitles[j].StreamID = stream.Stream.ID
+			}
+			if len(stream.Subtitles) > 0 {
+				if err := tx.Create(&stream.Subtitles).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for _, id := range c.AdminIDs {
+			err := tx.Exec("insert into course_admins (user_id, course_id) values (?, ?) on duplicate key update user_id = user_id", id, c.Course.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (d coursesDao) AddAdminToCourse(userID uint, courseID uint) error {
 	defer Cache.Clear()
 	return DB.Exec("insert into course_admins (user_id, course_id) values (?, ?) on duplicate key update user_id = user_id", userID, courseID).Error
@@ -201,6 +274,7 @@ func (d coursesDao) GetCourseById(ctx context.Context, id uint) (course model.Co
 	var foundCourse model.Course
 	dbErr := DB.Preload("Streams.TranscodingProgresses").
 		Preload("Streams.VideoSections").
+		Preload("Streams.SectionSuggestions").
 		Preload("Streams.Files").
 		Preload("Streams", func(db *gorm.DB) *gorm.DB {
 			return db.Order("streams.start desc")
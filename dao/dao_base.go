@@ -36,6 +36,10 @@ type DaoWrapper struct {
 	SubtitlesDao
 	TranscodingFailureDao
 	EmailDao
+	PrivacyDao
+	CourseRolesDao
+	AccessGroupsDao
+	GuestLinksDao
 }
 
 func NewDaoWrapper() DaoWrapper {
@@ -63,5 +67,9 @@ func NewDaoWrapper() DaoWrapper {
 		SubtitlesDao:          NewSubtitlesDao(),
 		TranscodingFailureDao: NewTranscodingFailureDao(),
 		EmailDao:              NewEmailDao(),
+		CourseRolesDao:        NewCourseRolesDao(),
+		PrivacyDao:            NewPrivacyDao(),
+		AccessGroupsDao:       NewAccessGroupsDao(),
+		GuestLinksDao:         NewGuestLinksDao(),
 	}
 }
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"fmt"
+
 	"github.com/TUM-Dev/gocast/model"
 	"gorm.io/gorm"
 )
@@ -14,6 +16,15 @@ type FileDao interface {
 	DeleteFile(id uint) error
 	CountVoDFiles() (int64, error)
 	SetThumbnail(streamId uint, thumb model.File) error
+
+	// ReplaceAttachment keeps the current content of the attachment as old version and replaces it with path and filename
+	ReplaceAttachment(attachment *model.File, path string, filename string) error
+	// GetAttachmentVersions returns the old versions of an attachment, the newest first
+	GetAttachmentVersions(attachmentID uint) ([]model.File, error)
+	// DeleteAttachment deletes an attachment with its old versions
+	DeleteAttachment(attachment model.File) error
+	SetAttachmentPlacement(attachment model.File, videoSectionID *uint, timestamp *uint) error
+	SetPreviewPath(file model.File, previewPath string) error
 }
 
 type fileDao struct {
@@ -56,3 +67,51 @@ func (d fileDao) SetThumbnail(streamId uint, thumb model.File) error {
 		return tx.Create(&thumb).Error
 	})
 }
+
+func (d fileDao) ReplaceAttachment(attachment *model.File, path string, filename string) error {
+	defer Cache.Del(fmt.Sprintf("streambyid%d", attachment.StreamID))
+	return DB.Transaction(func(tx *gorm.DB) error {
+		old := model.File{
+			StreamID:     attachment.StreamID,
+			Path:         attachment.Path,
+			Filename:     attachment.Filename,
+			Type:         model.FILETYPE_ATTACHMENT_VERSION,
+			Version:      attachment.Version,
+			AttachmentID: &attachment.ID,
+			PreviewPath:  attachment.PreviewPath,
+		}
+		if err := tx.Create(&old).Error; err != nil {
+			return err
+		}
+		attachment.Path, attachment.Filename, attachment.PreviewPath = path, filename, ""
+		attachment.Version++
+		return tx.Model(attachment).Select("path", "filename", "preview_path", "version").Updates(attachment).Error
+	})
+}
+
+func (d fileDao) GetAttachmentVersions(attachmentID uint) (versions []model.File, err error) {
+	err = DB.Where("attachment_id = ? AND type = ?", attachmentID, model.FILETYPE_ATTACHMENT_VERSION).
+		Order("version desc").Find(&versions).Error
+	return versions, err
+}
+
+func (d fileDao) DeleteAttachment(attachment model.File) error {
+	defer Cache.Del(fmt.Sprintf("streambyid%d", attachment.StreamID))
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attachment_id = ? AND type = ?", attachment.ID, model.FILETYPE_ATTACHMENT_VERSION).Delete(&model.File{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.File{}, attachment.ID).Error
+	})
+}
+
+func (d fileDao) SetAttachmentPlacement(attachment model.File, videoSectionID *uint, timestamp *uint) error {
+	defer Cache.Del(fmt.Sprintf("streambyid%d", attachment.StreamID))
+	return DB.Model(&model.File{}).Where("id = ?", attachment.ID).
+		Updates(map[string]interface{}{"video_section_id": videoSectionID, "timestamp": timestamp}).Error
+}
+
+func (d fileDao) SetPreviewPath(file model.File, previewPath string) error {
+	defer Cache.Del(fmt.Sprintf("streambyid%d", file.StreamID))
+	return DB.Model(&model.File{}).Where("id = ?", file.ID).Update("preview_path", previewPath).Error
+}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=guest_links.go -destination ../mock_dao/guest_links.go
+
+type GuestLinksDao interface {
+	CreateGuestLink(ctx context.Context, link *model.GuestLink) error
+	GetGuestLinkByID(ctx context.Context, id uint) (model.GuestLink, error)
+	// GetGuestLinksForCourse returns the links of a course that weren't revoked, the newest first
+	GetGuestLinksForCourse(ctx context.Context, courseID uint) ([]model.GuestLink, error)
+	RevokeGuestLink(ctx context.Context, id uint) error
+	// UseGuestLink counts a use of the link and returns model.ErrGuestLinkUsedUp if it has no uses left
+	UseGuestLink(ctx context.Context, id uint) error
+}
+
+type guestLinksDao struct {
+	db *gorm.DB
+}
+
+func NewGuestLinksDao() GuestLinksDao {
+	return guestLinksDao{db: DB}
+}
+
+func (d guestLinksDao) CreateGuestLink(ctx context.Context, link *model.GuestLink) error {
+	return d.db.WithContext(ctx).Create(link).Error
+}
+
+func (d guestLinksDao) GetGuestLinkByID(ctx context.Context, id uint) (link model.GuestLink, err error) {
+	return link, d.db.WithContext(ctx).First(&link, id).Error
+}
+
+func (d guestLinksDao) GetGuestLinksForCourse(ctx context.Context, courseID uint) (links []model.GuestLink, err error) {
+	return links, d.db.WithContext(ctx).
+		Where("course_id = ? AND revoked_at IS NULL", courseID).Order("created_at DESC").Find(&links).Error
+}
+
+func (d guestLinksDao) RevokeGuestLink(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Model(&model.GuestLink{}).Where("id = ?", id).Update("revoked_at", time.Now()).Error
+}
+
+func (d guestLinksDao) UseGuestLink(ctx context.Context, id uint) error {
+	res := d.db.WithContext(ctx).Model(&model.GuestLink{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", id).
+		Update("uses", gorm.Expr("uses + 1"))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.ErrGuestLinkUsedUp
+	}
+	return nil
+}
@@ -16,6 +16,8 @@ type IngestServerDao interface {
 	GetBestIngestServer() (server model.IngestServer, err error)
 	GetTranscodedStreamSlot(ingestServerID uint) (sn model.StreamName, err error)
 	GetStreamSlot(ingestServerID uint) (sn model.StreamName, err error)
+	GetSlotByStreamID(streamID uint) (sn model.StreamName, err error)
+	GetIngestServerByID(id uint) (server model.IngestServer, err error)
 
 	RemoveStreamFromSlot(streamID uint) error
 }
@@ -61,6 +63,17 @@ func (d ingestServerDao) GetStreamSlot(ingestServerID uint) (sn model.StreamName
 	return
 }
 
+// GetSlotByStreamID returns the slot a stream is currently using, preferring transcoded slots (COMB) over others.
+func (d ingestServerDao) GetSlotByStreamID(streamID uint) (sn model.StreamName, err error) {
+	err = DB.Order("is_transcoding desc").First(&sn, "stream_id = ?", streamID).Error
+	return
+}
+
+func (d ingestServerDao) GetIngestServerByID(id uint) (server model.IngestServer, err error) {
+	err = DB.First(&server, id).Error
+	return
+}
+
 func (d ingestServerDao) RemoveStreamFromSlot(streamID uint) error {
 	return DB.
 		Model(&model.StreamName{}).
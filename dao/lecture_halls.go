@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/TUM-Dev/gocast/model"
@@ -21,6 +22,9 @@ type LectureHallsDao interface {
 	GetLectureHallByPartialName(name string) (model.LectureHall, error)
 	GetLectureHallByID(id uint) (model.LectureHall, error)
 	GetStreamsForLectureHallIcal(userId uint, lectureHalls []uint, all bool) ([]CalendarResult, error)
+	GetBookings(lectureHalls []uint, from time.Time, to time.Time) ([]Booking, error)
+	FindConflictingBookings(lectureHallID uint, start time.Time, end time.Time, excludeStreamIDs []uint) ([]Booking, error)
+	GetBookingConflicts(from time.Time, to time.Time) ([]BookingConflict, error)
 
 	UnsetDefaults(lectureHallID string) error
 
@@ -85,9 +89,11 @@ func (d lectureHallsDao) GetStreamsForLectureHallIcal(userId uint, lectureHalls
 		Joins("LEFT JOIN lecture_halls ON lecture_halls.id = streams.lecture_hall_id").
 		Joins("JOIN courses ON courses.id = streams.course_id").
 		Joins("LEFT JOIN course_admins ON courses.id = course_admins.course_id").
+		Joins("LEFT JOIN users owners ON owners.id = courses.user_id").
 		Select("streams.id as stream_id, streams.created_at as created, "+
 			"lecture_halls.name as lecture_hall_name, "+
-			"streams.start, streams.end, courses.name as course_name").
+			"streams.start, streams.end, courses.name as course_name, "+
+			"COALESCE(owners.name, '') as owner_name, COALESCE(owners.email, '') as owner_email").
 		Where("(streams.start BETWEEN DATE_SUB(NOW(), INTERVAL 1 MONTH) and DATE_ADD(NOW(), INTERVAL 3 MONTH)) "+
 			"AND (courses.user_id = ? OR 0 = ? OR course_admins.user_id = ?) AND courses.deleted_at IS NULL "+
 			"AND (streams.lecture_hall_id IN ? OR (0 in ? AND streams.lecture_hall_id is null) OR ?)", userId, userId, userId, lectureHalls, lectureHalls, all).
@@ -96,6 +102,78 @@ func (d lectureHallsDao) GetStreamsForLectureHallIcal(userId uint, lectureHalls
 	return res, err
 }
 
+// bookingsQuery selects all streams that are assigned to a lecture hall as Booking.
+func (d lectureHallsDao) bookingsQuery() *gorm.DB {
+	return DB.Model(&model.Stream{}).
+		Joins("JOIN courses ON courses.id = streams.course_id AND courses.deleted_at IS NULL").
+		Joins("JOIN lecture_halls ON lecture_halls.id = streams.lecture_hall_id AND lecture_halls.deleted_at IS NULL").
+		Joins("LEFT JOIN users owners ON owners.id = courses.user_id").
+		Select("streams.id AS stream_id, streams.name AS stream_name, streams.start, streams.end, " +
+			"streams.lecture_hall_id, lecture_halls.name AS lecture_hall_name, " +
+			"courses.id AS course_id, courses.name AS course_name, courses.slug AS course_slug, " +
+			"courses.year AS course_year, courses.teaching_term AS course_teaching_term, " +
+			"COALESCE(owners.name, '') AS owner_name, COALESCE(owners.email, '') AS owner_email").
+		Order("streams.start")
+}
+
+// GetBookings returns all bookings of the given lecture halls (all lecture halls if empty) that overlap with [from, to).
+func (d lectureHallsDao) GetBookings(lectureHalls []uint, from time.Time, to time.Time) ([]Booking, error) {
+	var res []Booking
+	query := d.bookingsQuery().Where("streams.start < ? AND streams.end > ?", to, from)
+	if len(lectureHalls) > 0 {
+		query = query.Where("streams.lecture_hall_id IN ?", lectureHalls)
+	}
+	err := query.Scan(&res).Error
+	return res, err
+}
+
+// FindConflictingBookings returns all bookings of the lecture hall that overlap with [start, end).
+// Streams with an id in excludeStreamIDs are ignored, e.g. the streams that are being rescheduled.
+func (d lectureHallsDao) FindConflictingBookings(lectureHallID uint, start time.Time, end time.Time, excludeStreamIDs []uint) ([]Booking, error) {
+	var res []Booking
+	query := d.bookingsQuery().Where("streams.lecture_hall_id = ? AND streams.start < ? AND streams.end > ?", lectureHallID, end, start)
+	if len(excludeStreamIDs) > 0 {
+		query = query.Where("streams.id NOT IN ?", excludeStreamIDs)
+	}
+	err := query.Scan(&res).Error
+	return res, err
+}
+
+// GetBookingConflicts returns all pairs of overlapping bookings in the same lecture hall between from and to.
+func (d lectureHallsDao) GetBookingConflicts(from time.Time, to time.Time) ([]BookingConflict, error) {
+	var pairs []struct{ A, B uint }
+	err := DB.Table("streams s1").
+		Select("s1.id AS a, s2.id AS b").
+		Joins("JOIN streams s2 ON s2.lecture_hall_id = s1.lecture_hall_id AND s1.id < s2.id AND s1.start < s2.end AND s2.start < s1.end").
+		Joins("JOIN courses c1 ON c1.id = s1.course_id AND c1.deleted_at IS NULL").
+		Joins("JOIN courses c2 ON c2.id = s2.course_id AND c2.deleted_at IS NULL").
+		Where("s1.deleted_at IS NULL AND s2.deleted_at IS NULL AND s1.lecture_hall_id IS NOT NULL AND s1.lecture_hall_id != 0").
+		Where("s1.start < ? AND s1.end > ?", to, from).
+		Order("s1.start").
+		Scan(&pairs).Error
+	if err != nil || len(pairs) == 0 {
+		return []BookingConflict{}, err
+	}
+	ids := make([]uint, 0, len(pairs)*2)
+	for _, p := range pairs {
+		ids = append(ids, p.A, p.B)
+	}
+	var bookings []Booking
+	err = d.bookingsQuery().Where("streams.id IN ?", ids).Scan(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]Booking, len(bookings))
+	for _, b := range bookings {
+		byID[b.StreamID] = b
+	}
+	res := make([]BookingConflict, 0, len(pairs))
+	for _, p := range pairs {
+		res = append(res, BookingConflict{A: byID[p.A], B: byID[p.B]})
+	}
+	return res, nil
+}
+
 // UnsetDefaults makes all camera presets not default
 func (d lectureHallsDao) UnsetDefaults(lectureHallID string) error {
 	return DB.Model(&model.CameraPreset{}).Where("lecture_hall_id = ?", lectureHallID).Update("default", nil).Error
@@ -119,6 +197,36 @@ type CalendarResult struct {
 	End             time.Time
 	CourseName      string
 	LectureHallName string
+	OwnerName       string
+	OwnerEmail      string
+}
+
+// Booking is a stream that occupies a lecture hall
+type Booking struct {
+	StreamID           uint      `json:"streamID"`
+	StreamName         string    `json:"streamName"`
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	LectureHallID      uint      `json:"lectureHallID"`
+	LectureHallName    string    `json:"lectureHallName"`
+	CourseID           uint      `json:"courseID"`
+	CourseName         string    `json:"courseName"`
+	CourseSlug         string    `json:"courseSlug"`
+	CourseYear         int       `json:"courseYear"`
+	CourseTeachingTerm string    `json:"courseTeachingTerm"`
+	OwnerName          string    `json:"ownerName"`
+	OwnerEmail         string    `json:"ownerEmail"`
+}
+
+// String returns a short human-readable description of the booking, e.g. for error messages
+func (b Booking) String() string {
+	return fmt.Sprintf("'%s' in %s (%s - %s)", b.CourseName, b.LectureHallName, b.Start.Format("02.01.2006 15:04"), b.End.Format("15:04"))
+}
+
+// BookingConflict is a pair of bookings that overlap in the same lecture hall
+type BookingConflict struct {
+	A Booking `json:"a"`
+	B Booking `json:"b"`
 }
 
 func (r CalendarResult) IsoStart() string {
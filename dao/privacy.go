@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=privacy.go -destination ../mock_dao/privacy.go
+
+type PrivacyDao interface {
+	// GetPersonalData returns the data of a user that isn't loaded with the user itself
+	GetPersonalData(ctx context.Context, userID uint) (PersonalData, error)
+
+	// CreateDeletionRequest creates the request or updates the deletion date of an existing one
+	CreateDeletionRequest(ctx context.Context, request *model.DeletionRequest) error
+	GetDeletionRequest(ctx context.Context, userID uint) (model.DeletionRequest, error)
+	// GetDeletionRequests returns all pending requests with their users, due first
+	GetDeletionRequests(ctx context.Context) ([]model.DeletionRequest, error)
+	GetDueDeletionRequests(ctx context.Context, now time.Time) ([]model.DeletionRequest, error)
+	DeleteDeletionRequest(ctx context.Context, userID uint) error
+
+	// DeleteUserData deletes a user and their personal data. Chat messages and audit entries are kept anonymized.
+	DeleteUserData(ctx context.Context, userID uint) error
+}
+
+// PersonalData is the data of a user stored outside the users table
+type PersonalData struct {
+	Bookmarks     []model.Bookmark
+	ChatReactions []model.ChatReaction
+	PollVotes     []PollVote
+	ViewSessions  []model.ViewSession
+	Tokens        []model.Token
+	SubtitleEdits []model.SubtitlesRevision
+	Audits        []model.Audit
+}
+
+// PollVote is the answer a user voted for in a poll
+type PollVote struct {
+	StreamID uint
+	Question string
+	Answer   string
+}
+
+type privacyDao struct {
+	db *gorm.DB
+}
+
+func NewPrivacyDao() PrivacyDao {
+	return privacyDao{db: DB}
+}
+
+func (d privacyDao) GetPersonalData(ctx context.Context, userID uint) (data PersonalData, err error) {
+	db := d.db.WithContext(ctx)
+	if err = db.Find(&data.Bookmarks, "user_id = ?", userID).Error; err != nil {
+		return data, err
+	}
+	if err = db.Find(&data.ChatReactions, "user_id = ?", userID).Error; err != nil {
+		return data, err
+	}
+	err = db.Table("poll_option_user_votes v").
+		Select("p.stream_id, p.question, o.answer").
+		Joins("JOIN poll_options o ON o.id = v.poll_option_id").
+		Joins("JOIN chat_poll_options cpo ON cpo.poll_option_id = o.id").
+		Joins("JOIN polls p ON p.id = cpo.poll_id").
+		Where("v.user_id = ?", userID).
+		Scan(&data.PollVotes).Error
+	if err != nil {
+		return data, err
+	}
+	if err = db.Find(&data.ViewSessions, "user_id = ?", userID).Error; err != nil {
+		return data, err
+	}
+	if err = db.Find(&data.Tokens, "user_id = ?", userID).Error; err != nil {
+		return data, err
+	}
+	if err = db.Find(&data.SubtitleEdits, "user_id = ?", userID).Error; err != nil {
+		return data, err
+	}
+	return data, db.Order("created_at").Find(&data.Audits, "user_id = ?", userID).Error
+}
+
+func (d privacyDao) CreateDeletionRequest(ctx context.Context, request *model.DeletionRequest) error {
+	return d.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"delete_at", "updated_at"}),
+	}).Create(request).Error
+}
+
+func (d privacyDao) GetDeletionRequest(ctx context.Context, userID uint) (request model.DeletionRequest, err error) {
+	return request, d.db.WithContext(ctx).First(&request, "user_id = ?", userID).Error
+}
+
+func (d privacyDao) GetDeletionRequests(ctx context.Context) (requests []model.DeletionRequest, err error) {
+	return requests, d.db.WithContext(ctx).Preload("User").Order("delete_at").Find(&requests).Error
+}
+
+func (d privacyDao) GetDueDeletionRequests(ctx context.Context, now time.Time) (requests []model.DeletionRequest, err error) {
+	return requests, d.db.WithContext(ctx).Find(&requests, "delete_at <= ?", now).Error
+}
+
+func (d privacyDao) DeleteDeletionRequest(ctx context.Context, userID uint) error {
+	return d.db.WithContext(ctx).Unscoped().Delete(&model.DeletionRequest{}, "user_id = ?", userID).Error
+}
+
+func (d privacyDao) DeleteUserData(ctx context.Context, userID uint) error {
+	var user model.User
+	if err := d.db.WithContext(ctx).Unscoped().First(&user, userID).Error; err != nil {
+		return err
+	}
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// keep messages and audits as other users' messages and the audit log would lose context otherwise
+		err := tx.Unscoped().Model(&model.Chat{}).
+			Where("user_id = ?", fmt.Sprint(userID)).
+			Updates(map[string]any{"user_id": "0", "user_name": model.DeletedUserName}).Error
+		if err != nil {
+			return err
+		}
+		for _, m := range []any{&model.Audit{}, &model.ViewSession{}, &model.SubtitlesRevision{}} {
+			if err := tx.Unscoped().Model(m).Where("user_id = ?", userID).Update("user_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.GuestLink{}).Where("created_by_id = ?", userID).Update("created_by_id", 0).Error; err != nil {
+			return err
+		}
+		for _, table := range []string{"chat_user_addressedto", "poll_option_user_votes", "pinned_courses", "course_users", "course_admins"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+		}
+		for _, m := range []any{&model.ChatReaction{}, &model.Bookmark{}, &model.UserSetting{}, &model.StreamProgress{}, &model.RegisterLink{}, &model.Token{}, &model.DeletionRequest{}, &model.CourseRole{}} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		if user.LrzID != "" {
+			if err := tx.Where("lrz_id = ?", user.LrzID).Delete(&model.AccessGroupMember{}).Error; err != nil {
+				return err
+			}
+		}
+		if user.Email.Valid {
+			if err := tx.Unscoped().Where(map[string]any{"to": user.Email.String}).Delete(&model.Email{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&user).Error
+	})
+	if err == nil {
+		Cache.Del(fmt.Sprintf("userById%d", userID))
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/TUM-Dev/gocast/model"
 	"github.com/TUM-Dev/gocast/tools/timing"
@@ -27,6 +28,14 @@ type StatisticsDao interface {
 	GetLectureStats(courseID uint, lectureID uint) ([]Stat, error)
 	GetStudentActivityCourseStats(courseID uint, live bool) ([]Stat, error)
 	GetStreamNumLiveViews(streamID uint) (int, error)
+
+	AddViewSession(session model.ViewSession) error
+	GetViewSessions(streamID uint, live bool) ([]model.ViewSession, error)
+	GetLectureProgresses(streamID uint) ([]float64, error)
+	GetLectureEngagement(courseID uint) ([]LectureEngagement, error)
+
+	AggregateStats(before time.Time) error
+	AggregateProgresses(before time.Time) error
 }
 
 type statisticsDao struct {
@@ -229,6 +238,116 @@ func (d statisticsDao) GetLectureLiveStats(streamID uint) ([]model.Stat, error)
 	return res, err
 }
 
+// AddViewSession stores the period a viewer watched a stream
+func (d statisticsDao) AddViewSession(session model.ViewSession) error {
+	return DB.Create(&session).Error
+}
+
+// GetViewSessions returns all live or vod view sessions of a stream
+func (d statisticsDao) GetViewSessions(streamID uint, live bool) ([]model.ViewSession, error) {
+	var res []model.ViewSession
+	err := DB.Where("stream_id = ? AND live = ?", streamID, live).Order("joined_at").Find(&res).Error
+	return res, err
+}
+
+// GetLectureProgresses returns the watch progress (0-1) of all users that watched the recording of a stream
+func (d statisticsDao) GetLectureProgresses(streamID uint) ([]float64, error) {
+	var res []float64
+	err := DB.Model(&model.StreamProgress{}).Where("stream_id = ?", streamID).Pluck("progress", &res).Error
+	return res, err
+}
+
+// GetLectureEngagement returns viewer and participation numbers for every lecture of a course
+func (d statisticsDao) GetLectureEngagement(courseID uint) ([]LectureEngagement, error) {
+	var res []LectureEngagement
+	err := DB.Raw(`SELECT s.id AS stream_id, s.name, s.start,
+			(SELECT IFNULL(MAX(viewers), 0) FROM stats WHERE stream_id = s.id AND live = 1) AS peak_live_viewers,
+			(SELECT IFNULL(SUM(viewers), 0) FROM stats WHERE stream_id = s.id AND live = 0) AS vod_views,
+			(SELECT COUNT(DISTINCT user_id) FROM view_sessions
+				WHERE stream_id = s.id AND live = 1 AND user_id IS NOT NULL AND deleted_at IS NULL) AS live_users,
+			(SELECT COUNT(DISTINCT user_id) FROM chats WHERE stream_id = s.id AND deleted_at IS NULL) AS chatters,
+			(SELECT COUNT(DISTINCT v.user_id) FROM polls p
+				JOIN chat_poll_options cpo ON cpo.poll_id = p.id
+				JOIN poll_option_user_votes v ON v.poll_option_id = cpo.poll_option_id
+				WHERE p.stream_id = s.id AND p.deleted_at IS NULL) AS poll_voters
+		FROM streams s
+		WHERE s.course_id = ? AND s.deleted_at IS NULL
+		ORDER BY s.start`, courseID).Scan(&res).Error
+	return res, err
+}
+
+// AggregateStats replaces the raw stats and view sessions before the given time with one aggregated stat
+// per stream and hour. Aggregated stats keep working with all statistics queries.
+func (d statisticsDao) AggregateStats(before time.Time) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO stats (created_at, updated_at, time, stream_id, viewers, live, aggregated)
+			SELECT NOW(), NOW(), DATE_FORMAT(time, '%Y-%m-%d %H:00:00') AS hour, stream_id,
+				IF(live, MAX(viewers), SUM(viewers)), live, 1
+			FROM stats
+			WHERE aggregated = 0 AND time < ? AND deleted_at IS NULL
+			GROUP BY stream_id, live, hour`, before).Error
+		if err != nil {
+			return err
+		}
+		// hard delete, soft deleted rows would still reveal individual viewers
+		err = tx.Exec("DELETE FROM stats WHERE aggregated = 0 AND time < ?", before).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM view_sessions WHERE left_at < ?", before).Error
+	})
+}
+
+// AggregateProgresses adds the progresses of streams that started before the given time to their
+// model.ProgressRollup and deletes them.
+func (d statisticsDao) AggregateProgresses(before time.Time) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO progress_rollups (stream_id, viewers, watched, progress_sum)
+			SELECT p.stream_id, COUNT(*), SUM(p.watched), SUM(p.progress)
+			FROM stream_progresses p
+				JOIN streams s ON s.id = p.stream_id
+			WHERE s.start < ?
+			GROUP BY p.stream_id
+			ON DUPLICATE KEY UPDATE viewers = viewers + VALUES(viewers), watched = watched + VALUES(watched),
+				progress_sum = progress_sum + VALUES(progress_sum)`, before).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`DELETE p FROM stream_progresses p
+			JOIN streams s ON s.id = p.stream_id
+			WHERE s.start < ?`, before).Error
+	})
+}
+
+// LectureEngagement summarizes how many viewers a lecture had and how many of them participated
+type LectureEngagement struct {
+	StreamID        uint
+	Name            string
+	Start           time.Time
+	PeakLiveViewers int
+	VodViews        int
+	LiveUsers       int // distinct logged-in live viewers
+	Chatters        int
+	PollVoters      int
+}
+
+// ChatParticipation returns the percentage of logged-in live viewers that wrote in the chat
+func (e LectureEngagement) ChatParticipation() int {
+	return participation(e.Chatters, e.LiveUsers)
+}
+
+// PollParticipation returns the percentage of logged-in live viewers that voted in a poll
+func (e LectureEngagement) PollParticipation() int {
+	return participation(e.PollVoters, e.LiveUsers)
+}
+
+func participation(participants int, viewers int) int {
+	if viewers == 0 {
+		return 0
+	}
+	return min(100, participants*100/viewers)
+}
+
 // Stat key value struct that is parsable by Chart.js without further modifications.
 // See https://www.chartjs.org/docs/master/general/data-structures.html
 type Stat struct {
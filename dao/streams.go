@@ -27,6 +27,7 @@ type StreamsDao interface {
 	GetUnitByID(id string) (model.StreamUnit, error)
 	GetStreamByTumOnlineID(ctx context.Context, id uint) (stream model.Stream, err error)
 	GetStreamsByIds(ids []uint) ([]model.Stream, error)
+	GetStreamsForCalendar(courseIDs []uint, since time.Time) ([]model.Stream, error)
 	GetStreamByID(ctx context.Context, id string) (stream model.Stream, err error)
 	GetWorkersForStream(stream model.Stream) ([]model.Worker, error)
 	GetAllStreams() ([]model.Stream, error)
@@ -51,6 +52,7 @@ type StreamsDao interface {
 	SaveCOMBURL(stream *model.Stream, url string)
 	SaveCAMURL(stream *model.Stream, url string)
 	SavePRESURL(stream *model.Stream, url string)
+	SaveDvrUrl(streamID uint, url string) error
 	SaveTranscodingProgress(progress model.TranscodingProgress) error
 	RemoveTranscodingProgress(streamVersion model.StreamVersion, streamId uint) error
 	GetTranscodingProgressByVersion(streamVersion model.StreamVersion, streamId uint) (model.TranscodingProgress, error)
@@ -161,6 +163,17 @@ func (d streamsDao) GetStreamsByIds(ids []uint) ([]model.Stream, error) {
 	return streams, err
 }
 
+// GetStreamsForCalendar returns all streams of the courses starting after since,
+// including streams that were deleted after since so they can be marked as cancelled.
+func (d streamsDao) GetStreamsForCalendar(courseIDs []uint, since time.Time) ([]model.Stream, error) {
+	var streams []model.Stream
+	err := DB.Unscoped().
+		Where("course_id IN ? AND start > ? AND (deleted_at IS NULL OR deleted_at > ?)", courseIDs, since, since).
+		Order("start").
+		Find(&streams).Error
+	return streams, err
+}
+
 func (d streamsDao) GetStreamByID(ctx context.Context, id string) (stream model.Stream, err error) {
 	if cached, found := Cache.Get(fmt.Sprintf("streambyid%v", id)); found {
 		return cached.(model.Stream), nil
@@ -497,6 +510,12 @@ func (d streamsDao) SavePRESURL(stream *model.Stream, url string) {
 	Cache.Clear()
 }
 
+// SaveDvrUrl sets the DVR playlist of a live stream, an empty url removes it.
+func (d streamsDao) SaveDvrUrl(streamID uint, url string) error {
+	defer Cache.Clear()
+	return DB.Model(&model.Stream{}).Where("id = ?", streamID).Updates(map[string]interface{}{"dvr_url": url}).Error
+}
+
 func (d streamsDao) ToggleVisibility(streamId uint, private bool) error {
 	return DB.Model(&model.Stream{}).Where("id = ?", streamId).Updates(map[string]interface{}{"private": private}).Error
 }
@@ -530,6 +549,7 @@ func (d streamsDao) SaveStream(vod *model.Stream) error {
 		Duration:         vod.Duration,
 		ThumbInterval:    vod.ThumbInterval,
 		Private:          vod.Private,
+		PremiereVodUrl:   vod.PremiereVodUrl,
 	}).Error
 	return err
 }
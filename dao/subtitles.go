@@ -25,6 +25,40 @@ type SubtitlesDao interface {
 
 	// Delete a Subtitles by id.
 	Delete(context.Context, uint) error
+
+	// GetLanguages returns the languages a stream has subtitles in
+	GetLanguages(context.Context, uint) ([]string, error)
+
+	// SaveRevision stores a revision of subtitles, creating the subtitles if they don't exist yet.
+	// The content of existing subtitles is only replaced if current is true.
+	SaveRevision(c context.Context, subtitles *model.Subtitles, revision *model.SubtitlesRevision, current bool) error
+
+	// GetRevisions returns the revisions of subtitles, latest first
+	GetRevisions(context.Context, uint) ([]model.SubtitlesRevision, error)
+
+	// GetRevision by ID
+	GetRevision(context.Context, uint) (model.SubtitlesRevision, error)
+
+	// CreateJob saves a new subtitle job
+	CreateJob(context.Context, *model.SubtitleJob) error
+
+	// GetJob by ID
+	GetJob(context.Context, uint) (model.SubtitleJob, error)
+
+	// GetJobsByStreamID returns the subtitle jobs of a stream, latest first
+	GetJobsByStreamID(context.Context, uint) ([]model.SubtitleJob, error)
+
+	// GetWaitingJobs returns the translations of a stream that wait for subtitles in a language
+	GetWaitingJobs(c context.Context, streamID uint, language string) ([]model.SubtitleJob, error)
+
+	// UpdateJob saves the state, progress and error of a job
+	UpdateJob(context.Context, *model.SubtitleJob) error
+
+	// SetKeywords replaces the keywords of subtitles
+	SetKeywords(c context.Context, subtitlesID uint, keywords []model.TranscriptKeyword) error
+
+	// GetKeywords returns the keywords of subtitles ordered by keyword and start
+	GetKeywords(c context.Context, subtitlesID uint) ([]model.TranscriptKeyword, error)
 }
 
 type subtitlesDao struct {
@@ -71,7 +105,86 @@ func (d subtitlesDao) Create(c context.Context, it *model.Subtitles) error {
 	return DB.WithContext(c).Create(it).Error
 }
 
-// Delete a Subtitles by id.
+// Delete a Subtitles by id along with its keywords.
 func (d subtitlesDao) Delete(c context.Context, id uint) error {
-	return DB.WithContext(c).Delete(&model.Subtitles{}, id).Error
+	return DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subtitles_id = ?", id).Delete(&model.TranscriptKeyword{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Subtitles{}, id).Error
+	})
+}
+
+func (d subtitlesDao) GetLanguages(c context.Context, streamID uint) (languages []string, err error) {
+	err = DB.WithContext(c).Model(&model.Subtitles{}).Where("stream_id = ?", streamID).Order("language").Pluck("language", &languages).Error
+	return languages, err
+}
+
+func (d subtitlesDao) SaveRevision(c context.Context, subtitles *model.Subtitles, revision *model.SubtitlesRevision, current bool) error {
+	return DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if subtitles.ID == 0 {
+			subtitles.Content = revision.Content
+			if err := tx.Create(subtitles).Error; err != nil {
+				return err
+			}
+		} else if current {
+			subtitles.Content = revision.Content
+			err := tx.Model(&model.Subtitles{}).Where("id = ?", subtitles.ID).
+				Updates(map[string]interface{}{"content": subtitles.Content, "edited": subtitles.Edited}).Error
+			if err != nil {
+				return err
+			}
+		}
+		revision.SubtitlesID = subtitles.ID
+		return tx.Create(revision).Error
+	})
+}
+
+func (d subtitlesDao) GetRevisions(c context.Context, subtitlesID uint) (res []model.SubtitlesRevision, err error) {
+	return res, DB.WithContext(c).Order("id desc").Find(&res, "subtitles_id = ?", subtitlesID).Error
+}
+
+func (d subtitlesDao) GetRevision(c context.Context, id uint) (res model.SubtitlesRevision, err error) {
+	return res, DB.WithContext(c).First(&res, id).Error
+}
+
+func (d subtitlesDao) CreateJob(c context.Context, job *model.SubtitleJob) error {
+	return DB.WithContext(c).Create(job).Error
+}
+
+func (d subtitlesDao) GetJob(c context.Context, id uint) (res model.SubtitleJob, err error) {
+	return res, DB.WithContext(c).First(&res, id).Error
+}
+
+func (d subtitlesDao) GetJobsByStreamID(c context.Context, streamID uint) (res []model.SubtitleJob, err error) {
+	return res, DB.WithContext(c).Order("id desc").Find(&res, "stream_id = ?", streamID).Error
+}
+
+func (d subtitlesDao) GetWaitingJobs(c context.Context, streamID uint, language string) (res []model.SubtitleJob, err error) {
+	return res, DB.WithContext(c).
+		Find(&res, "stream_id = ? AND source_language = ? AND state = ?", streamID, language, model.SubtitleJobWaiting).Error
+}
+
+func (d subtitlesDao) UpdateJob(c context.Context, job *model.SubtitleJob) error {
+	return DB.WithContext(c).Model(&model.SubtitleJob{}).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"state": job.State, "progress": job.Progress, "error": job.Error}).Error
+}
+
+func (d subtitlesDao) SetKeywords(c context.Context, subtitlesID uint, keywords []model.TranscriptKeyword) error {
+	return DB.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subtitles_id = ?", subtitlesID).Delete(&model.TranscriptKeyword{}).Error; err != nil {
+			return err
+		}
+		if len(keywords) == 0 {
+			return nil
+		}
+		for i := range keywords {
+			keywords[i].SubtitlesID = subtitlesID
+		}
+		return tx.CreateInBatches(keywords, 100).Error
+	})
+}
+
+func (d subtitlesDao) GetKeywords(c context.Context, subtitlesID uint) (res []model.TranscriptKeyword, err error) {
+	return res, DB.WithContext(c).Order("keyword_rank, keyword, start").Find(&res, "subtitles_id = ?", subtitlesID).Error
 }
@@ -19,6 +19,7 @@ type TokenDao interface {
 	TokenUsed(token model.Token) error
 
 	DeleteToken(id string) error
+	DeleteTokensOfUser(userID uint, scope string) error
 }
 
 type tokenDao struct {
@@ -77,6 +78,11 @@ func (d tokenDao) DeleteToken(id string) error {
 	return DB.Delete(&model.Token{}, id).Error
 }
 
+// DeleteTokensOfUser deletes all tokens with the given scope created by the user
+func (d tokenDao) DeleteTokensOfUser(userID uint, scope string) error {
+	return DB.Where("user_id = ? AND scope = ?", userID, scope).Delete(&model.Token{}).Error
+}
+
 type AllTokensDto struct {
 	model.Token
 	UserName  string
@@ -95,7 +95,15 @@ func (d usersDao) GetUserByID(ctx context.Context, id uint) (user model.User, er
 		return cached.(model.User), nil
 	}
 	var foundUser model.User
-	dbErr := DB.Preload("AdministeredCourses").Preload("PinnedCourses.Streams").Preload("Courses.Streams").Preload("Settings").Find(&foundUser, "id = ?", id).Error
+	dbErr := DB.Preload("AdministeredCourses").Preload("CourseRoles").Preload("PinnedCourses.Streams").Preload("Courses.Streams").Preload("Settings").Find(&foundUser, "id = ?", id).Error
+	if dbErr == nil && foundUser.LrzID != "" {
+		dbErr = DB.Preload("Streams").
+			Joins("JOIN course_access_groups ON course_access_groups.course_id = courses.id").
+			Joins("JOIN access_group_members ON access_group_members.access_group_id = course_access_groups.access_group_id").
+			Joins("JOIN access_groups ON access_groups.id = course_access_groups.access_group_id AND access_groups.deleted_at IS NULL").
+			Where("access_group_members.lrz_id = ?", foundUser.LrzID).
+			Distinct().Find(&foundUser.GroupCourses).Error
+	}
 	if dbErr == nil {
 		Cache.SetWithTTL(fmt.Sprintf("userById%d", id), foundUser, 1, time.Second*10)
 	}
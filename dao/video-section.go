@@ -13,6 +13,14 @@ type VideoSectionDao interface {
 	Delete(uint) error
 	Get(uint) (model.VideoSection, error)
 	GetByStreamId(uint) ([]model.VideoSection, error)
+
+	// ReplaceSuggestions replaces the section suggestions of a stream, e.g. after slides were detected again.
+	ReplaceSuggestions(streamID uint, suggestions []model.SectionSuggestion) error
+	GetSuggestion(uint) (model.SectionSuggestion, error)
+	GetSuggestionsByStreamId(uint) ([]model.SectionSuggestion, error)
+	DeleteSuggestion(uint) error
+	// AcceptSuggestion turns a suggestion into a section with the given description.
+	AcceptSuggestion(suggestion model.SectionSuggestion, description string) (model.VideoSection, error)
 }
 
 type videoSectionDao struct {
@@ -45,3 +53,48 @@ func (d videoSectionDao) GetByStreamId(streamID uint) ([]model.VideoSection, err
 	err := DB.Order("start_hours, start_minutes, start_seconds ASC").Find(&sections, "stream_id = ?", streamID).Error
 	return sections, err
 }
+
+func (d videoSectionDao) ReplaceSuggestions(streamID uint, suggestions []model.SectionSuggestion) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.SectionSuggestion{}, "stream_id = ?", streamID).Error; err != nil {
+			return err
+		}
+		if len(suggestions) == 0 {
+			return nil
+		}
+		return tx.Create(&suggestions).Error
+	})
+}
+
+func (d videoSectionDao) GetSuggestion(suggestionID uint) (suggestion model.SectionSuggestion, err error) {
+	err = d.db.First(&suggestion, "id = ?", suggestionID).Error
+	return suggestion, err
+}
+
+func (d videoSectionDao) GetSuggestionsByStreamId(streamID uint) ([]model.SectionSuggestion, error) {
+	var suggestions []model.SectionSuggestion
+	err := d.db.Order("start_hours, start_minutes, start_seconds ASC").Find(&suggestions, "stream_id = ?", streamID).Error
+	return suggestions, err
+}
+
+func (d videoSectionDao) DeleteSuggestion(suggestionID uint) error {
+	return d.db.Delete(&model.SectionSuggestion{}, "id = ?", suggestionID).Error
+}
+
+func (d videoSectionDao) AcceptSuggestion(suggestion model.SectionSuggestion, description string) (section model.VideoSection, err error) {
+	section = model.VideoSection{
+		Description:  description,
+		StartHours:   suggestion.StartHours,
+		StartMinutes: suggestion.StartMinutes,
+		StartSeconds: suggestion.StartSeconds,
+		StreamID:     suggestion.StreamID,
+		FileID:       suggestion.FileID,
+	}
+	err = d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&section).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.SectionSuggestion{}, "id = ?", suggestion.ID).Error
+	})
+	return section, err
+}
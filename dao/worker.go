@@ -18,6 +18,8 @@ type WorkerDao interface {
 	GetWorkerByHostname(ctx context.Context, hostname string) (model.Worker, error)
 	GetWorkerByID(ctx context.Context, workerID string) (model.Worker, error)
 
+	SetDraining(workerID string, draining bool) error
+
 	DeleteWorker(workerID string) error
 }
 
@@ -43,10 +45,12 @@ func (d workerDao) GetAllWorkers() ([]model.Worker, error) {
 	return workers, err
 }
 
-// GetAliveWorkers returns all workers that were active within the last 5 minutes
+// GetAliveWorkers returns all workers that were active within the last 5 minutes and aren't draining
 func (d workerDao) GetAliveWorkers() []model.Worker {
 	var workers []model.Worker
-	DB.Model(&model.Worker{}).Where("last_seen > DATE_SUB(NOW(), INTERVAL 5 MINUTE)").Scan(&workers)
+	DB.Model(&model.Worker{}).
+		Where("last_seen > DATE_SUB(NOW(), INTERVAL 5 MINUTE) AND draining = false AND shutting_down = false").
+		Scan(&workers)
 	return workers
 }
 
@@ -62,6 +66,11 @@ func (d workerDao) GetWorkerByID(ctx context.Context, workerID string) (model.Wo
 	return worker, dbErr
 }
 
+// SetDraining takes a worker out of rotation or puts it back
+func (d workerDao) SetDraining(workerID string, draining bool) error {
+	return DB.Model(&model.Worker{}).Where("worker_id = ?", workerID).Update("draining", draining).Error
+}
+
 func (d workerDao) DeleteWorker(workerID string) error {
 	return DB.Where("worker_id = ?", workerID).Delete(&model.Worker{}).Error
 }
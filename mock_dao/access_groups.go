@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: access_groups.go
+
+// Package mock_dao is a generated GoMock package.
+package mock_dao
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/TUM-Dev/gocast/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccessGroupsDao is a mock of AccessGroupsDao interface.
+type MockAccessGroupsDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessGroupsDaoMockRecorder
+}
+
+// MockAccessGroupsDaoMockRecorder is the mock recorder for MockAccessGroupsDao.
+type MockAccessGroupsDaoMockRecorder struct {
+	mock *MockAccessGroupsDao
+}
+
+// NewMockAccessGroupsDao creates a new mock instance.
+func NewMockAccessGroupsDao(ctrl *gomock.Controller) *MockAccessGroupsDao {
+	mock := &MockAccessGroupsDao{ctrl: ctrl}
+	mock.recorder = &MockAccessGroupsDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessGroupsDao) EXPECT() *MockAccessGroupsDaoMockRecorder {
+	return m.recorder
+}
+
+// AddCourse mocks base method.
+func (m *MockAccessGroupsDao) AddCourse(ctx context.Context, groupID, courseID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCourse", ctx, groupID, courseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCourse indicates an expected call of AddCourse.
+func (mr *MockAccessGroupsDaoMockRecorder) AddCourse(ctx, groupID, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCourse", reflect.TypeOf((*MockAccessGroupsDao)(nil).AddCourse), ctx, groupID, courseID)
+}
+
+// CreateAccessGroup mocks base method.
+func (m *MockAccessGroupsDao) CreateAccessGroup(ctx context.Context, group *model.AccessGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccessGroup indicates an expected call of CreateAccessGroup.
+func (mr *MockAccessGroupsDaoMockRecorder) CreateAccessGroup(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessGroup", reflect.TypeOf((*MockAccessGroupsDao)(nil).CreateAccessGroup), ctx, group)
+}
+
+// DeleteAccessGroup mocks base method.
+func (m *MockAccessGroupsDao) DeleteAccessGroup(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessGroup", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccessGroup indicates an expected call of DeleteAccessGroup.
+func (mr *MockAccessGroupsDaoMockRecorder) DeleteAccessGroup(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessGroup", reflect.TypeOf((*MockAccessGroupsDao)(nil).DeleteAccessGroup), ctx, id)
+}
+
+// GetAccessGroup mocks base method.
+func (m *MockAccessGroupsDao) GetAccessGroup(ctx context.Context, id uint) (model.AccessGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessGroup", ctx, id)
+	ret0, _ := ret[0].(model.AccessGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessGroup indicates an expected call of GetAccessGroup.
+func (mr *MockAccessGroupsDaoMockRecorder) GetAccessGroup(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessGroup", reflect.TypeOf((*MockAccessGroupsDao)(nil).GetAccessGroup), ctx, id)
+}
+
+// GetAccessGroups mocks base method.
+func (m *MockAccessGroupsDao) GetAccessGroups(ctx context.Context) ([]model.AccessGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessGroups", ctx)
+	ret0, _ := ret[0].([]model.AccessGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessGroups indicates an expected call of GetAccessGroups.
+func (mr *MockAccessGroupsDaoMockRecorder) GetAccessGroups(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessGroups", reflect.TypeOf((*MockAccessGroupsDao)(nil).GetAccessGroups), ctx)
+}
+
+// GetMembers mocks base method.
+func (m *MockAccessGroupsDao) GetMembers(ctx context.Context, groupID uint) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, groupID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockAccessGroupsDaoMockRecorder) GetMembers(ctx, groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockAccessGroupsDao)(nil).GetMembers), ctx, groupID)
+}
+
+// RemoveCourse mocks base method.
+func (m *MockAccessGroupsDao) RemoveCourse(ctx context.Context, groupID, courseID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCourse", ctx, groupID, courseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCourse indicates an expected call of RemoveCourse.
+func (mr *MockAccessGroupsDaoMockRecorder) RemoveCourse(ctx, groupID, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCourse", reflect.TypeOf((*MockAccessGroupsDao)(nil).RemoveCourse), ctx, groupID, courseID)
+}
+
+// SaveSyncResult mocks base method.
+func (m *MockAccessGroupsDao) SaveSyncResult(ctx context.Context, groupID uint, added, removed int, syncErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSyncResult", ctx, groupID, added, removed, syncErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSyncResult indicates an expected call of SaveSyncResult.
+func (mr *MockAccessGroupsDaoMockRecorder) SaveSyncResult(ctx, groupID, added, removed, syncErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSyncResult", reflect.TypeOf((*MockAccessGroupsDao)(nil).SaveSyncResult), ctx, groupID, added, removed, syncErr)
+}
+
+// SetMembers mocks base method.
+func (m *MockAccessGroupsDao) SetMembers(ctx context.Context, groupID uint, lrzIDs []string) ([]string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMembers", ctx, groupID, lrzIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SetMembers indicates an expected call of SetMembers.
+func (mr *MockAccessGroupsDaoMockRecorder) SetMembers(ctx, groupID, lrzIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMembers", reflect.TypeOf((*MockAccessGroupsDao)(nil).SetMembers), ctx, groupID, lrzIDs)
+}
+
+// UpdateAccessGroup mocks base method.
+func (m *MockAccessGroupsDao) UpdateAccessGroup(ctx context.Context, group *model.AccessGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccessGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccessGroup indicates an expected call of UpdateAccessGroup.
+func (mr *MockAccessGroupsDaoMockRecorder) UpdateAccessGroup(ctx, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccessGroup", reflect.TypeOf((*MockAccessGroupsDao)(nil).UpdateAccessGroup), ctx, group)
+}
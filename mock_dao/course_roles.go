@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: course_roles.go
+
+// Package mock_dao is a generated GoMock package.
+package mock_dao
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/TUM-Dev/gocast/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCourseRolesDao is a mock of CourseRolesDao interface.
+type MockCourseRolesDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourseRolesDaoMockRecorder
+}
+
+// MockCourseRolesDaoMockRecorder is the mock recorder for MockCourseRolesDao.
+type MockCourseRolesDaoMockRecorder struct {
+	mock *MockCourseRolesDao
+}
+
+// NewMockCourseRolesDao creates a new mock instance.
+func NewMockCourseRolesDao(ctrl *gomock.Controller) *MockCourseRolesDao {
+	mock := &MockCourseRolesDao{ctrl: ctrl}
+	mock.recorder = &MockCourseRolesDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourseRolesDao) EXPECT() *MockCourseRolesDaoMockRecorder {
+	return m.recorder
+}
+
+// GetCourseRoles mocks base method.
+func (m *MockCourseRolesDao) GetCourseRoles(ctx context.Context, courseID uint) ([]model.CourseRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourseRoles", ctx, courseID)
+	ret0, _ := ret[0].([]model.CourseRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourseRoles indicates an expected call of GetCourseRoles.
+func (mr *MockCourseRolesDaoMockRecorder) GetCourseRoles(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseRoles", reflect.TypeOf((*MockCourseRolesDao)(nil).GetCourseRoles), ctx, courseID)
+}
+
+// RemoveCourseRole mocks base method.
+func (m *MockCourseRolesDao) RemoveCourseRole(ctx context.Context, courseID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCourseRole", ctx, courseID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCourseRole indicates an expected call of RemoveCourseRole.
+func (mr *MockCourseRolesDaoMockRecorder) RemoveCourseRole(ctx, courseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCourseRole", reflect.TypeOf((*MockCourseRolesDao)(nil).RemoveCourseRole), ctx, courseID, userID)
+}
+
+// SetCourseRole mocks base method.
+func (m *MockCourseRolesDao) SetCourseRole(ctx context.Context, role model.CourseRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCourseRole", ctx, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCourseRole indicates an expected call of SetCourseRole.
+func (mr *MockCourseRolesDaoMockRecorder) SetCourseRole(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCourseRole", reflect.TypeOf((*MockCourseRolesDao)(nil).SetCourseRole), ctx, role)
+}
@@ -50,6 +50,20 @@ func (mr *MockCoursesDaoMockRecorder) AddAdminToCourse(userID, courseID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdminToCourse", reflect.TypeOf((*MockCoursesDao)(nil).AddAdminToCourse), userID, courseID)
 }
 
+// CopyCourse mocks base method.
+func (m *MockCoursesDao) CopyCourse(ctx context.Context, c *dao.CourseCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyCourse", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyCourse indicates an expected call of CopyCourse.
+func (mr *MockCoursesDaoMockRecorder) CopyCourse(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyCourse", reflect.TypeOf((*MockCoursesDao)(nil).CopyCourse), ctx, c)
+}
+
 // CreateCourse mocks base method.
 func (m *MockCoursesDao) CreateCourse(ctx context.Context, course *model.Course, keep bool) error {
 	m.ctrl.T.Helper()
@@ -149,6 +163,22 @@ func (mr *MockCoursesDaoMockRecorder) GetAvailableSemesters(c interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableSemesters", reflect.TypeOf((*MockCoursesDao)(nil).GetAvailableSemesters), c)
 }
 
+// GetCopySource mocks base method.
+func (m *MockCoursesDao) GetCopySource(ctx context.Context, courseID uint) ([]model.Stream, map[uint][]model.Subtitles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopySource", ctx, courseID)
+	ret0, _ := ret[0].([]model.Stream)
+	ret1, _ := ret[1].(map[uint][]model.Subtitles)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCopySource indicates an expected call of GetCopySource.
+func (mr *MockCoursesDaoMockRecorder) GetCopySource(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopySource", reflect.TypeOf((*MockCoursesDao)(nil).GetCopySource), ctx, courseID)
+}
+
 // GetCourseAdmins mocks base method.
 func (m *MockCoursesDao) GetCourseAdmins(courseID uint) ([]model.User, error) {
 	m.ctrl.T.Helper()
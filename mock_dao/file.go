@@ -49,6 +49,20 @@ func (mr *MockFileDaoMockRecorder) CountVoDFiles() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVoDFiles", reflect.TypeOf((*MockFileDao)(nil).CountVoDFiles))
 }
 
+// DeleteAttachment mocks base method.
+func (m *MockFileDao) DeleteAttachment(attachment model.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockFileDaoMockRecorder) DeleteAttachment(attachment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockFileDao)(nil).DeleteAttachment), attachment)
+}
+
 // DeleteFile mocks base method.
 func (m *MockFileDao) DeleteFile(id uint) error {
 	m.ctrl.T.Helper()
@@ -63,6 +77,21 @@ func (mr *MockFileDaoMockRecorder) DeleteFile(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockFileDao)(nil).DeleteFile), id)
 }
 
+// GetAttachmentVersions mocks base method.
+func (m *MockFileDao) GetAttachmentVersions(attachmentID uint) ([]model.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachmentVersions", attachmentID)
+	ret0, _ := ret[0].([]model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachmentVersions indicates an expected call of GetAttachmentVersions.
+func (mr *MockFileDaoMockRecorder) GetAttachmentVersions(attachmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachmentVersions", reflect.TypeOf((*MockFileDao)(nil).GetAttachmentVersions), attachmentID)
+}
+
 // GetFileById mocks base method.
 func (m *MockFileDao) GetFileById(id string) (model.File, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +121,48 @@ func (mr *MockFileDaoMockRecorder) NewFile(f interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFile", reflect.TypeOf((*MockFileDao)(nil).NewFile), f)
 }
 
+// ReplaceAttachment mocks base method.
+func (m *MockFileDao) ReplaceAttachment(attachment *model.File, path, filename string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceAttachment", attachment, path, filename)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceAttachment indicates an expected call of ReplaceAttachment.
+func (mr *MockFileDaoMockRecorder) ReplaceAttachment(attachment, path, filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAttachment", reflect.TypeOf((*MockFileDao)(nil).ReplaceAttachment), attachment, path, filename)
+}
+
+// SetAttachmentPlacement mocks base method.
+func (m *MockFileDao) SetAttachmentPlacement(attachment model.File, videoSectionID, timestamp *uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAttachmentPlacement", attachment, videoSectionID, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAttachmentPlacement indicates an expected call of SetAttachmentPlacement.
+func (mr *MockFileDaoMockRecorder) SetAttachmentPlacement(attachment, videoSectionID, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAttachmentPlacement", reflect.TypeOf((*MockFileDao)(nil).SetAttachmentPlacement), attachment, videoSectionID, timestamp)
+}
+
+// SetPreviewPath mocks base method.
+func (m *MockFileDao) SetPreviewPath(file model.File, previewPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreviewPath", file, previewPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPreviewPath indicates an expected call of SetPreviewPath.
+func (mr *MockFileDaoMockRecorder) SetPreviewPath(file, previewPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreviewPath", reflect.TypeOf((*MockFileDao)(nil).SetPreviewPath), file, previewPath)
+}
+
 // SetThumbnail mocks base method.
 func (m *MockFileDao) SetThumbnail(streamId uint, thumb model.File) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: guest_links.go
+
+// Package mock_dao is a generated GoMock package.
+package mock_dao
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/TUM-Dev/gocast/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGuestLinksDao is a mock of GuestLinksDao interface.
+type MockGuestLinksDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestLinksDaoMockRecorder
+}
+
+// MockGuestLinksDaoMockRecorder is the mock recorder for MockGuestLinksDao.
+type MockGuestLinksDaoMockRecorder struct {
+	mock *MockGuestLinksDao
+}
+
+// NewMockGuestLinksDao creates a new mock instance.
+func NewMockGuestLinksDao(ctrl *gomock.Controller) *MockGuestLinksDao {
+	mock := &MockGuestLinksDao{ctrl: ctrl}
+	mock.recorder = &MockGuestLinksDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestLinksDao) EXPECT() *MockGuestLinksDaoMockRecorder {
+	return m.recorder
+}
+
+// CreateGuestLink mocks base method.
+func (m *MockGuestLinksDao) CreateGuestLink(ctx context.Context, link *model.GuestLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestLink", ctx, link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGuestLink indicates an expected call of CreateGuestLink.
+func (mr *MockGuestLinksDaoMockRecorder) CreateGuestLink(ctx, link interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestLink", reflect.TypeOf((*MockGuestLinksDao)(nil).CreateGuestLink), ctx, link)
+}
+
+// GetGuestLinkByID mocks base method.
+func (m *MockGuestLinksDao) GetGuestLinkByID(ctx context.Context, id uint) (model.GuestLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestLinkByID", ctx, id)
+	ret0, _ := ret[0].(model.GuestLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestLinkByID indicates an expected call of GetGuestLinkByID.
+func (mr *MockGuestLinksDaoMockRecorder) GetGuestLinkByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestLinkByID", reflect.TypeOf((*MockGuestLinksDao)(nil).GetGuestLinkByID), ctx, id)
+}
+
+// GetGuestLinksForCourse mocks base method.
+func (m *MockGuestLinksDao) GetGuestLinksForCourse(ctx context.Context, courseID uint) ([]model.GuestLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestLinksForCourse", ctx, courseID)
+	ret0, _ := ret[0].([]model.GuestLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestLinksForCourse indicates an expected call of GetGuestLinksForCourse.
+func (mr *MockGuestLinksDaoMockRecorder) GetGuestLinksForCourse(ctx, courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestLinksForCourse", reflect.TypeOf((*MockGuestLinksDao)(nil).GetGuestLinksForCourse), ctx, courseID)
+}
+
+// RevokeGuestLink mocks base method.
+func (m *MockGuestLinksDao) RevokeGuestLink(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeGuestLink", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeGuestLink indicates an expected call of RevokeGuestLink.
+func (mr *MockGuestLinksDaoMockRecorder) RevokeGuestLink(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeGuestLink", reflect.TypeOf((*MockGuestLinksDao)(nil).RevokeGuestLink), ctx, id)
+}
+
+// UseGuestLink mocks base method.
+func (m *MockGuestLinksDao) UseGuestLink(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseGuestLink", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseGuestLink indicates an expected call of UseGuestLink.
+func (mr *MockGuestLinksDaoMockRecorder) UseGuestLink(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseGuestLink", reflect.TypeOf((*MockGuestLinksDao)(nil).UseGuestLink), ctx, id)
+}
@@ -49,6 +49,36 @@ func (mr *MockIngestServerDaoMockRecorder) GetBestIngestServer() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestIngestServer", reflect.TypeOf((*MockIngestServerDao)(nil).GetBestIngestServer))
 }
 
+// GetIngestServerByID mocks base method.
+func (m *MockIngestServerDao) GetIngestServerByID(id uint) (model.IngestServer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestServerByID", id)
+	ret0, _ := ret[0].(model.IngestServer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestServerByID indicates an expected call of GetIngestServerByID.
+func (mr *MockIngestServerDaoMockRecorder) GetIngestServerByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestServerByID", reflect.TypeOf((*MockIngestServerDao)(nil).GetIngestServerByID), id)
+}
+
+// GetSlotByStreamID mocks base method.
+func (m *MockIngestServerDao) GetSlotByStreamID(streamID uint) (model.StreamName, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSlotByStreamID", streamID)
+	ret0, _ := ret[0].(model.StreamName)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSlotByStreamID indicates an expected call of GetSlotByStreamID.
+func (mr *MockIngestServerDaoMockRecorder) GetSlotByStreamID(streamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSlotByStreamID", reflect.TypeOf((*MockIngestServerDao)(nil).GetSlotByStreamID), streamID)
+}
+
 // GetStreamSlot mocks base method.
 func (m *MockIngestServerDao) GetStreamSlot(ingestServerID uint) (model.StreamName, error) {
 	m.ctrl.T.Helper()
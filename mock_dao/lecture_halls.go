@@ -6,6 +6,7 @@ package mock_dao
 
 import (
 	reflect "reflect"
+	time "time"
 
 	dao "github.com/TUM-Dev/gocast/dao"
 	model "github.com/TUM-Dev/gocast/model"
@@ -61,6 +62,21 @@ func (mr *MockLectureHallsDaoMockRecorder) DeleteLectureHall(id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLectureHall", reflect.TypeOf((*MockLectureHallsDao)(nil).DeleteLectureHall), id)
 }
 
+// FindConflictingBookings mocks base method.
+func (m *MockLectureHallsDao) FindConflictingBookings(lectureHallID uint, start, end time.Time, excludeStreamIDs []uint) ([]dao.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConflictingBookings", lectureHallID, start, end, excludeStreamIDs)
+	ret0, _ := ret[0].([]dao.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConflictingBookings indicates an expected call of FindConflictingBookings.
+func (mr *MockLectureHallsDaoMockRecorder) FindConflictingBookings(lectureHallID, start, end, excludeStreamIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConflictingBookings", reflect.TypeOf((*MockLectureHallsDao)(nil).FindConflictingBookings), lectureHallID, start, end, excludeStreamIDs)
+}
+
 // FindPreset mocks base method.
 func (m *MockLectureHallsDao) FindPreset(lectureHallID, presetID string) (model.CameraPreset, error) {
 	m.ctrl.T.Helper()
@@ -90,6 +106,36 @@ func (mr *MockLectureHallsDaoMockRecorder) GetAllLectureHalls() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLectureHalls", reflect.TypeOf((*MockLectureHallsDao)(nil).GetAllLectureHalls))
 }
 
+// GetBookingConflicts mocks base method.
+func (m *MockLectureHallsDao) GetBookingConflicts(from, to time.Time) ([]dao.BookingConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookingConflicts", from, to)
+	ret0, _ := ret[0].([]dao.BookingConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookingConflicts indicates an expected call of GetBookingConflicts.
+func (mr *MockLectureHallsDaoMockRecorder) GetBookingConflicts(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingConflicts", reflect.TypeOf((*MockLectureHallsDao)(nil).GetBookingConflicts), from, to)
+}
+
+// GetBookings mocks base method.
+func (m *MockLectureHallsDao) GetBookings(lectureHalls []uint, from, to time.Time) ([]dao.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookings", lectureHalls, from, to)
+	ret0, _ := ret[0].([]dao.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookings indicates an expected call of GetBookings.
+func (mr *MockLectureHallsDaoMockRecorder) GetBookings(lectureHalls, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookings", reflect.TypeOf((*MockLectureHallsDao)(nil).GetBookings), lectureHalls, from, to)
+}
+
 // GetLectureHallByID mocks base method.
 func (m *MockLectureHallsDao) GetLectureHallByID(id uint) (model.LectureHall, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: privacy.go
+
+// Package mock_dao is a generated GoMock package.
+package mock_dao
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dao "github.com/TUM-Dev/gocast/dao"
+	model "github.com/TUM-Dev/gocast/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPrivacyDao is a mock of PrivacyDao interface.
+type MockPrivacyDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivacyDaoMockRecorder
+}
+
+// MockPrivacyDaoMockRecorder is the mock recorder for MockPrivacyDao.
+type MockPrivacyDaoMockRecorder struct {
+	mock *MockPrivacyDao
+}
+
+// NewMockPrivacyDao creates a new mock instance.
+func NewMockPrivacyDao(ctrl *gomock.Controller) *MockPrivacyDao {
+	mock := &MockPrivacyDao{ctrl: ctrl}
+	mock.recorder = &MockPrivacyDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivacyDao) EXPECT() *MockPrivacyDaoMockRecorder {
+	return m.recorder
+}
+
+// CreateDeletionRequest mocks base method.
+func (m *MockPrivacyDao) CreateDeletionRequest(ctx context.Context, request *model.DeletionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeletionRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeletionRequest indicates an expected call of CreateDeletionRequest.
+func (mr *MockPrivacyDaoMockRecorder) CreateDeletionRequest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeletionRequest", reflect.TypeOf((*MockPrivacyDao)(nil).CreateDeletionRequest), ctx, request)
+}
+
+// DeleteDeletionRequest mocks base method.
+func (m *MockPrivacyDao) DeleteDeletionRequest(ctx context.Context, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeletionRequest", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeletionRequest indicates an expected call of DeleteDeletionRequest.
+func (mr *MockPrivacyDaoMockRecorder) DeleteDeletionRequest(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeletionRequest", reflect.TypeOf((*MockPrivacyDao)(nil).DeleteDeletionRequest), ctx, userID)
+}
+
+// DeleteUserData mocks base method.
+func (m *MockPrivacyDao) DeleteUserData(ctx context.Context, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserData", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserData indicates an expected call of DeleteUserData.
+func (mr *MockPrivacyDaoMockRecorder) DeleteUserData(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserData", reflect.TypeOf((*MockPrivacyDao)(nil).DeleteUserData), ctx, userID)
+}
+
+// GetDeletionRequest mocks base method.
+func (m *MockPrivacyDao) GetDeletionRequest(ctx context.Context, userID uint) (model.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletionRequest", ctx, userID)
+	ret0, _ := ret[0].(model.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletionRequest indicates an expected call of GetDeletionRequest.
+func (mr *MockPrivacyDaoMockRecorder) GetDeletionRequest(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletionRequest", reflect.TypeOf((*MockPrivacyDao)(nil).GetDeletionRequest), ctx, userID)
+}
+
+// GetDeletionRequests mocks base method.
+func (m *MockPrivacyDao) GetDeletionRequests(ctx context.Context) ([]model.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletionRequests", ctx)
+	ret0, _ := ret[0].([]model.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletionRequests indicates an expected call of GetDeletionRequests.
+func (mr *MockPrivacyDaoMockRecorder) GetDeletionRequests(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletionRequests", reflect.TypeOf((*MockPrivacyDao)(nil).GetDeletionRequests), ctx)
+}
+
+// GetDueDeletionRequests mocks base method.
+func (m *MockPrivacyDao) GetDueDeletionRequests(ctx context.Context, now time.Time) ([]model.DeletionRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeletionRequests", ctx, now)
+	ret0, _ := ret[0].([]model.DeletionRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeletionRequests indicates an expected call of GetDueDeletionRequests.
+func (mr *MockPrivacyDaoMockRecorder) GetDueDeletionRequests(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeletionRequests", reflect.TypeOf((*MockPrivacyDao)(nil).GetDueDeletionRequests), ctx, now)
+}
+
+// GetPersonalData mocks base method.
+func (m *MockPrivacyDao) GetPersonalData(ctx context.Context, userID uint) (dao.PersonalData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalData", ctx, userID)
+	ret0, _ := ret[0].(dao.PersonalData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalData indicates an expected call of GetPersonalData.
+func (mr *MockPrivacyDaoMockRecorder) GetPersonalData(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalData", reflect.TypeOf((*MockPrivacyDao)(nil).GetPersonalData), ctx, userID)
+}
@@ -1,20 +1,16 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: dao/statistics.go
-//
-// Generated by this command:
-//
-//	mockgen -source dao/statistics.go -destination mock_dao/statistics.go
-//
+// Source: statistics.go
 
 // Package mock_dao is a generated GoMock package.
 package mock_dao
 
 import (
-	"github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 
 	dao "github.com/TUM-Dev/gocast/dao"
 	model "github.com/TUM-Dev/gocast/model"
+	gomock "github.com/golang/mock/gomock"
 )
 
 // MockStatisticsDao is a mock of StatisticsDao interface.
@@ -49,11 +45,53 @@ func (m *MockStatisticsDao) AddStat(stat model.Stat) error {
 }
 
 // AddStat indicates an expected call of AddStat.
-func (mr *MockStatisticsDaoMockRecorder) AddStat(stat any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) AddStat(stat interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStat", reflect.TypeOf((*MockStatisticsDao)(nil).AddStat), stat)
 }
 
+// AddViewSession mocks base method.
+func (m *MockStatisticsDao) AddViewSession(session model.ViewSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddViewSession", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddViewSession indicates an expected call of AddViewSession.
+func (mr *MockStatisticsDaoMockRecorder) AddViewSession(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViewSession", reflect.TypeOf((*MockStatisticsDao)(nil).AddViewSession), session)
+}
+
+// AggregateProgresses mocks base method.
+func (m *MockStatisticsDao) AggregateProgresses(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateProgresses", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AggregateProgresses indicates an expected call of AggregateProgresses.
+func (mr *MockStatisticsDaoMockRecorder) AggregateProgresses(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateProgresses", reflect.TypeOf((*MockStatisticsDao)(nil).AggregateProgresses), before)
+}
+
+// AggregateStats mocks base method.
+func (m *MockStatisticsDao) AggregateStats(before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateStats", before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AggregateStats indicates an expected call of AggregateStats.
+func (mr *MockStatisticsDaoMockRecorder) AggregateStats(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateStats", reflect.TypeOf((*MockStatisticsDao)(nil).AggregateStats), before)
+}
+
 // GetCourseNumLiveViews mocks base method.
 func (m *MockStatisticsDao) GetCourseNumLiveViews(courseID uint) (int, error) {
 	m.ctrl.T.Helper()
@@ -64,7 +102,7 @@ func (m *MockStatisticsDao) GetCourseNumLiveViews(courseID uint) (int, error) {
 }
 
 // GetCourseNumLiveViews indicates an expected call of GetCourseNumLiveViews.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseNumLiveViews(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseNumLiveViews(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseNumLiveViews", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseNumLiveViews), courseID)
 }
@@ -79,7 +117,7 @@ func (m *MockStatisticsDao) GetCourseNumStudents(courseID uint) (int64, error) {
 }
 
 // GetCourseNumStudents indicates an expected call of GetCourseNumStudents.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseNumStudents(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseNumStudents(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseNumStudents", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseNumStudents), courseID)
 }
@@ -94,7 +132,7 @@ func (m *MockStatisticsDao) GetCourseNumVodViews(courseID uint) (int, error) {
 }
 
 // GetCourseNumVodViews indicates an expected call of GetCourseNumVodViews.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseNumVodViews(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseNumVodViews(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseNumVodViews", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseNumVodViews), courseID)
 }
@@ -109,7 +147,7 @@ func (m *MockStatisticsDao) GetCourseNumVodViewsPerDay(courseID uint) ([]dao.Sta
 }
 
 // GetCourseNumVodViewsPerDay indicates an expected call of GetCourseNumVodViewsPerDay.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseNumVodViewsPerDay(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseNumVodViewsPerDay(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseNumVodViewsPerDay", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseNumVodViewsPerDay), courseID)
 }
@@ -124,7 +162,7 @@ func (m *MockStatisticsDao) GetCourseStatsHourly(courseID uint) ([]dao.Stat, err
 }
 
 // GetCourseStatsHourly indicates an expected call of GetCourseStatsHourly.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseStatsHourly(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseStatsHourly(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseStatsHourly", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseStatsHourly), courseID)
 }
@@ -139,11 +177,26 @@ func (m *MockStatisticsDao) GetCourseStatsWeekdays(courseID uint) ([]dao.Stat, e
 }
 
 // GetCourseStatsWeekdays indicates an expected call of GetCourseStatsWeekdays.
-func (mr *MockStatisticsDaoMockRecorder) GetCourseStatsWeekdays(courseID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetCourseStatsWeekdays(courseID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseStatsWeekdays", reflect.TypeOf((*MockStatisticsDao)(nil).GetCourseStatsWeekdays), courseID)
 }
 
+// GetLectureEngagement mocks base method.
+func (m *MockStatisticsDao) GetLectureEngagement(courseID uint) ([]dao.LectureEngagement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLectureEngagement", courseID)
+	ret0, _ := ret[0].([]dao.LectureEngagement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLectureEngagement indicates an expected call of GetLectureEngagement.
+func (mr *MockStatisticsDaoMockRecorder) GetLectureEngagement(courseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureEngagement", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureEngagement), courseID)
+}
+
 // GetLectureNumLiveViews mocks base method.
 func (m *MockStatisticsDao) GetLectureNumLiveViews(streamID uint) (int, error) {
 	m.ctrl.T.Helper()
@@ -154,7 +207,7 @@ func (m *MockStatisticsDao) GetLectureNumLiveViews(streamID uint) (int, error) {
 }
 
 // GetLectureNumLiveViews indicates an expected call of GetLectureNumLiveViews.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureNumLiveViews(streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureNumLiveViews(streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureNumLiveViews", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureNumLiveViews), streamID)
 }
@@ -169,7 +222,7 @@ func (m *MockStatisticsDao) GetLectureNumVodViews(streamID uint) (int, error) {
 }
 
 // GetLectureNumVodViews indicates an expected call of GetLectureNumVodViews.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureNumVodViews(streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureNumVodViews(streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureNumVodViews", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureNumVodViews), streamID)
 }
@@ -184,11 +237,26 @@ func (m *MockStatisticsDao) GetLectureNumVodViewsPerDay(streamID uint) ([]dao.St
 }
 
 // GetLectureNumVodViewsPerDay indicates an expected call of GetLectureNumVodViewsPerDay.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureNumVodViewsPerDay(streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureNumVodViewsPerDay(streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureNumVodViewsPerDay", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureNumVodViewsPerDay), streamID)
 }
 
+// GetLectureProgresses mocks base method.
+func (m *MockStatisticsDao) GetLectureProgresses(streamID uint) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLectureProgresses", streamID)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLectureProgresses indicates an expected call of GetLectureProgresses.
+func (mr *MockStatisticsDaoMockRecorder) GetLectureProgresses(streamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureProgresses", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureProgresses), streamID)
+}
+
 // GetLectureStats mocks base method.
 func (m *MockStatisticsDao) GetLectureStats(courseID, lectureID uint) ([]dao.Stat, error) {
 	m.ctrl.T.Helper()
@@ -199,7 +267,7 @@ func (m *MockStatisticsDao) GetLectureStats(courseID, lectureID uint) ([]dao.Sta
 }
 
 // GetLectureStats indicates an expected call of GetLectureStats.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureStats(courseID, lectureID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureStats(courseID, lectureID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureStats", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureStats), courseID, lectureID)
 }
@@ -214,7 +282,7 @@ func (m *MockStatisticsDao) GetLectureStatsHourly(courseID, streamID uint) ([]da
 }
 
 // GetLectureStatsHourly indicates an expected call of GetLectureStatsHourly.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureStatsHourly(courseID, streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureStatsHourly(courseID, streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureStatsHourly", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureStatsHourly), courseID, streamID)
 }
@@ -229,7 +297,7 @@ func (m *MockStatisticsDao) GetLectureStatsWeekdays(courseID, streamID uint) ([]
 }
 
 // GetLectureStatsWeekdays indicates an expected call of GetLectureStatsWeekdays.
-func (mr *MockStatisticsDaoMockRecorder) GetLectureStatsWeekdays(courseID, streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetLectureStatsWeekdays(courseID, streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLectureStatsWeekdays", reflect.TypeOf((*MockStatisticsDao)(nil).GetLectureStatsWeekdays), courseID, streamID)
 }
@@ -244,7 +312,7 @@ func (m *MockStatisticsDao) GetStreamNumLiveViews(streamID uint) (int, error) {
 }
 
 // GetStreamNumLiveViews indicates an expected call of GetStreamNumLiveViews.
-func (mr *MockStatisticsDaoMockRecorder) GetStreamNumLiveViews(streamID any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetStreamNumLiveViews(streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamNumLiveViews", reflect.TypeOf((*MockStatisticsDao)(nil).GetStreamNumLiveViews), streamID)
 }
@@ -259,7 +327,22 @@ func (m *MockStatisticsDao) GetStudentActivityCourseStats(courseID uint, live bo
 }
 
 // GetStudentActivityCourseStats indicates an expected call of GetStudentActivityCourseStats.
-func (mr *MockStatisticsDaoMockRecorder) GetStudentActivityCourseStats(courseID, live any) *gomock.Call {
+func (mr *MockStatisticsDaoMockRecorder) GetStudentActivityCourseStats(courseID, live interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentActivityCourseStats", reflect.TypeOf((*MockStatisticsDao)(nil).GetStudentActivityCourseStats), courseID, live)
 }
+
+// GetViewSessions mocks base method.
+func (m *MockStatisticsDao) GetViewSessions(streamID uint, live bool) ([]model.ViewSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewSessions", streamID, live)
+	ret0, _ := ret[0].([]model.ViewSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewSessions indicates an expected call of GetViewSessions.
+func (mr *MockStatisticsDaoMockRecorder) GetViewSessions(streamID, live interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewSessions", reflect.TypeOf((*MockStatisticsDao)(nil).GetViewSessions), streamID, live)
+}
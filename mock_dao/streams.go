@@ -319,6 +319,21 @@ func (mr *MockStreamsDaoMockRecorder) GetStreamsByIds(ids interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamsByIds", reflect.TypeOf((*MockStreamsDao)(nil).GetStreamsByIds), ids)
 }
 
+// GetStreamsForCalendar mocks base method.
+func (m *MockStreamsDao) GetStreamsForCalendar(courseIDs []uint, since time.Time) ([]model.Stream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamsForCalendar", courseIDs, since)
+	ret0, _ := ret[0].([]model.Stream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamsForCalendar indicates an expected call of GetStreamsForCalendar.
+func (mr *MockStreamsDaoMockRecorder) GetStreamsForCalendar(courseIDs, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamsForCalendar", reflect.TypeOf((*MockStreamsDao)(nil).GetStreamsForCalendar), courseIDs, since)
+}
+
 // GetStreamsWithWatchState mocks base method.
 func (m *MockStreamsDao) GetStreamsWithWatchState(courseID, userID uint) ([]model.Stream, error) {
 	m.ctrl.T.Helper()
@@ -417,6 +432,20 @@ func (mr *MockStreamsDaoMockRecorder) SaveCOMBURL(stream, url interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCOMBURL", reflect.TypeOf((*MockStreamsDao)(nil).SaveCOMBURL), stream, url)
 }
 
+// SaveDvrUrl mocks base method.
+func (m *MockStreamsDao) SaveDvrUrl(streamID uint, url string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDvrUrl", streamID, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDvrUrl indicates an expected call of SaveDvrUrl.
+func (mr *MockStreamsDaoMockRecorder) SaveDvrUrl(streamID, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDvrUrl", reflect.TypeOf((*MockStreamsDao)(nil).SaveDvrUrl), streamID, url)
+}
+
 // SaveEndedState mocks base method.
 func (m *MockStreamsDao) SaveEndedState(streamID uint, hasEnded bool) error {
 	m.ctrl.T.Helper()
@@ -49,6 +49,20 @@ func (mr *MockSubtitlesDaoMockRecorder) Create(c, it interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubtitlesDao)(nil).Create), c, it)
 }
 
+// CreateJob mocks base method.
+func (m *MockSubtitlesDao) CreateJob(arg0 context.Context, arg1 *model.SubtitleJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockSubtitlesDaoMockRecorder) CreateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockSubtitlesDao)(nil).CreateJob), arg0, arg1)
+}
+
 // CreateOrUpsert mocks base method.
 func (m *MockSubtitlesDao) CreateOrUpsert(arg0 context.Context, arg1 *model.Subtitles) error {
 	m.ctrl.T.Helper()
@@ -106,3 +120,150 @@ func (mr *MockSubtitlesDaoMockRecorder) GetByStreamIDandLang(arg0, arg1, arg2 in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByStreamIDandLang", reflect.TypeOf((*MockSubtitlesDao)(nil).GetByStreamIDandLang), arg0, arg1, arg2)
 }
+
+// GetJob mocks base method.
+func (m *MockSubtitlesDao) GetJob(arg0 context.Context, arg1 uint) (model.SubtitleJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", arg0, arg1)
+	ret0, _ := ret[0].(model.SubtitleJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockSubtitlesDaoMockRecorder) GetJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockSubtitlesDao)(nil).GetJob), arg0, arg1)
+}
+
+// GetJobsByStreamID mocks base method.
+func (m *MockSubtitlesDao) GetJobsByStreamID(arg0 context.Context, arg1 uint) ([]model.SubtitleJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobsByStreamID", arg0, arg1)
+	ret0, _ := ret[0].([]model.SubtitleJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobsByStreamID indicates an expected call of GetJobsByStreamID.
+func (mr *MockSubtitlesDaoMockRecorder) GetJobsByStreamID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobsByStreamID", reflect.TypeOf((*MockSubtitlesDao)(nil).GetJobsByStreamID), arg0, arg1)
+}
+
+// GetKeywords mocks base method.
+func (m *MockSubtitlesDao) GetKeywords(c context.Context, subtitlesID uint) ([]model.TranscriptKeyword, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeywords", c, subtitlesID)
+	ret0, _ := ret[0].([]model.TranscriptKeyword)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeywords indicates an expected call of GetKeywords.
+func (mr *MockSubtitlesDaoMockRecorder) GetKeywords(c, subtitlesID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeywords", reflect.TypeOf((*MockSubtitlesDao)(nil).GetKeywords), c, subtitlesID)
+}
+
+// GetLanguages mocks base method.
+func (m *MockSubtitlesDao) GetLanguages(arg0 context.Context, arg1 uint) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguages", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguages indicates an expected call of GetLanguages.
+func (mr *MockSubtitlesDaoMockRecorder) GetLanguages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguages", reflect.TypeOf((*MockSubtitlesDao)(nil).GetLanguages), arg0, arg1)
+}
+
+// GetRevision mocks base method.
+func (m *MockSubtitlesDao) GetRevision(arg0 context.Context, arg1 uint) (model.SubtitlesRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1)
+	ret0, _ := ret[0].(model.SubtitlesRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockSubtitlesDaoMockRecorder) GetRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockSubtitlesDao)(nil).GetRevision), arg0, arg1)
+}
+
+// GetRevisions mocks base method.
+func (m *MockSubtitlesDao) GetRevisions(arg0 context.Context, arg1 uint) ([]model.SubtitlesRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]model.SubtitlesRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockSubtitlesDaoMockRecorder) GetRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockSubtitlesDao)(nil).GetRevisions), arg0, arg1)
+}
+
+// GetWaitingJobs mocks base method.
+func (m *MockSubtitlesDao) GetWaitingJobs(c context.Context, streamID uint, language string) ([]model.SubtitleJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitingJobs", c, streamID, language)
+	ret0, _ := ret[0].([]model.SubtitleJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitingJobs indicates an expected call of GetWaitingJobs.
+func (mr *MockSubtitlesDaoMockRecorder) GetWaitingJobs(c, streamID, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitingJobs", reflect.TypeOf((*MockSubtitlesDao)(nil).GetWaitingJobs), c, streamID, language)
+}
+
+// SaveRevision mocks base method.
+func (m *MockSubtitlesDao) SaveRevision(c context.Context, subtitles *model.Subtitles, revision *model.SubtitlesRevision, current bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", c, subtitles, revision, current)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockSubtitlesDaoMockRecorder) SaveRevision(c, subtitles, revision, current interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockSubtitlesDao)(nil).SaveRevision), c, subtitles, revision, current)
+}
+
+// SetKeywords mocks base method.
+func (m *MockSubtitlesDao) SetKeywords(c context.Context, subtitlesID uint, keywords []model.TranscriptKeyword) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeywords", c, subtitlesID, keywords)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKeywords indicates an expected call of SetKeywords.
+func (mr *MockSubtitlesDaoMockRecorder) SetKeywords(c, subtitlesID, keywords interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeywords", reflect.TypeOf((*MockSubtitlesDao)(nil).SetKeywords), c, subtitlesID, keywords)
+}
+
+// UpdateJob mocks base method.
+func (m *MockSubtitlesDao) UpdateJob(arg0 context.Context, arg1 *model.SubtitleJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockSubtitlesDaoMockRecorder) UpdateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockSubtitlesDao)(nil).UpdateJob), arg0, arg1)
+}
@@ -63,6 +63,20 @@ func (mr *MockTokenDaoMockRecorder) DeleteToken(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockTokenDao)(nil).DeleteToken), id)
 }
 
+// DeleteTokensOfUser mocks base method.
+func (m *MockTokenDao) DeleteTokensOfUser(userID uint, scope string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTokensOfUser", userID, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTokensOfUser indicates an expected call of DeleteTokensOfUser.
+func (mr *MockTokenDaoMockRecorder) DeleteTokensOfUser(userID, scope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTokensOfUser", reflect.TypeOf((*MockTokenDao)(nil).DeleteTokensOfUser), userID, scope)
+}
+
 // GetAllTokens mocks base method.
 func (m *MockTokenDao) GetAllTokens(user *model.User) ([]dao.AllTokensDto, error) {
 	m.ctrl.T.Helper()
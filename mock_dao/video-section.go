@@ -34,6 +34,21 @@ func (m *MockVideoSectionDao) EXPECT() *MockVideoSectionDaoMockRecorder {
 	return m.recorder
 }
 
+// AcceptSuggestion mocks base method.
+func (m *MockVideoSectionDao) AcceptSuggestion(suggestion model.SectionSuggestion, description string) (model.VideoSection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptSuggestion", suggestion, description)
+	ret0, _ := ret[0].(model.VideoSection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptSuggestion indicates an expected call of AcceptSuggestion.
+func (mr *MockVideoSectionDaoMockRecorder) AcceptSuggestion(suggestion, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptSuggestion", reflect.TypeOf((*MockVideoSectionDao)(nil).AcceptSuggestion), suggestion, description)
+}
+
 // Create mocks base method.
 func (m *MockVideoSectionDao) Create(arg0 []model.VideoSection) error {
 	m.ctrl.T.Helper()
@@ -62,6 +77,20 @@ func (mr *MockVideoSectionDaoMockRecorder) Delete(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVideoSectionDao)(nil).Delete), arg0)
 }
 
+// DeleteSuggestion mocks base method.
+func (m *MockVideoSectionDao) DeleteSuggestion(arg0 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSuggestion", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSuggestion indicates an expected call of DeleteSuggestion.
+func (mr *MockVideoSectionDaoMockRecorder) DeleteSuggestion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSuggestion", reflect.TypeOf((*MockVideoSectionDao)(nil).DeleteSuggestion), arg0)
+}
+
 // Get mocks base method.
 func (m *MockVideoSectionDao) Get(arg0 uint) (model.VideoSection, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +121,50 @@ func (mr *MockVideoSectionDaoMockRecorder) GetByStreamId(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByStreamId", reflect.TypeOf((*MockVideoSectionDao)(nil).GetByStreamId), arg0)
 }
 
+// GetSuggestion mocks base method.
+func (m *MockVideoSectionDao) GetSuggestion(arg0 uint) (model.SectionSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestion", arg0)
+	ret0, _ := ret[0].(model.SectionSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuggestion indicates an expected call of GetSuggestion.
+func (mr *MockVideoSectionDaoMockRecorder) GetSuggestion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestion", reflect.TypeOf((*MockVideoSectionDao)(nil).GetSuggestion), arg0)
+}
+
+// GetSuggestionsByStreamId mocks base method.
+func (m *MockVideoSectionDao) GetSuggestionsByStreamId(arg0 uint) ([]model.SectionSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuggestionsByStreamId", arg0)
+	ret0, _ := ret[0].([]model.SectionSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuggestionsByStreamId indicates an expected call of GetSuggestionsByStreamId.
+func (mr *MockVideoSectionDaoMockRecorder) GetSuggestionsByStreamId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuggestionsByStreamId", reflect.TypeOf((*MockVideoSectionDao)(nil).GetSuggestionsByStreamId), arg0)
+}
+
+// ReplaceSuggestions mocks base method.
+func (m *MockVideoSectionDao) ReplaceSuggestions(streamID uint, suggestions []model.SectionSuggestion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSuggestions", streamID, suggestions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSuggestions indicates an expected call of ReplaceSuggestions.
+func (mr *MockVideoSectionDaoMockRecorder) ReplaceSuggestions(streamID, suggestions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSuggestions", reflect.TypeOf((*MockVideoSectionDao)(nil).ReplaceSuggestions), streamID, suggestions)
+}
+
 // Update mocks base method.
 func (m *MockVideoSectionDao) Update(arg0 *model.VideoSection) error {
 	m.ctrl.T.Helper()
@@ -135,3 +135,17 @@ func (mr *MockWorkerDaoMockRecorder) SaveWorker(worker interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorker", reflect.TypeOf((*MockWorkerDao)(nil).SaveWorker), worker)
 }
+
+// SetDraining mocks base method.
+func (m *MockWorkerDao) SetDraining(workerID string, draining bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDraining", workerID, draining)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDraining indicates an expected call of SetDraining.
+func (mr *MockWorkerDaoMockRecorder) SetDraining(workerID, draining interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDraining", reflect.TypeOf((*MockWorkerDao)(nil).SetDraining), workerID, draining)
+}
@@ -49,6 +49,20 @@ func (mr *MockCamMockRecorder) GetPresets() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresets", reflect.TypeOf((*MockCam)(nil).GetPresets))
 }
 
+// Move mocks base method.
+func (m *MockCam) Move(pan, tilt, zoom float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", pan, tilt, zoom)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockCamMockRecorder) Move(pan, tilt, zoom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCam)(nil).Move), pan, tilt, zoom)
+}
+
 // SetPreset mocks base method.
 func (m *MockCam) SetPreset(presetId int) error {
 	m.ctrl.T.Helper()
@@ -63,6 +77,21 @@ func (mr *MockCamMockRecorder) SetPreset(presetId interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreset", reflect.TypeOf((*MockCam)(nil).SetPreset), presetId)
 }
 
+// StorePreset mocks base method.
+func (m *MockCam) StorePreset(presetId int, name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePreset", presetId, name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorePreset indicates an expected call of StorePreset.
+func (mr *MockCamMockRecorder) StorePreset(presetId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePreset", reflect.TypeOf((*MockCam)(nil).StorePreset), presetId, name)
+}
+
 // TakeSnapshot mocks base method.
 func (m *MockCam) TakeSnapshot(outDir string) (string, error) {
 	m.ctrl.T.Helper()
@@ -60,6 +60,20 @@ func (mr *MockPresetUtilityMockRecorder) FetchLHPresets(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchLHPresets", reflect.TypeOf((*MockPresetUtility)(nil).FetchLHPresets), arg0)
 }
 
+// MoveCamera mocks base method.
+func (m *MockPresetUtility) MoveCamera(lectureHall model.LectureHall, pan, tilt, zoom float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveCamera", lectureHall, pan, tilt, zoom)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveCamera indicates an expected call of MoveCamera.
+func (mr *MockPresetUtilityMockRecorder) MoveCamera(lectureHall, pan, tilt, zoom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveCamera", reflect.TypeOf((*MockPresetUtility)(nil).MoveCamera), lectureHall, pan, tilt, zoom)
+}
+
 // ProvideCamera mocks base method.
 func (m *MockPresetUtility) ProvideCamera(arg0 model.CameraType, arg1 string) (camera.Cam, error) {
 	m.ctrl.T.Helper()
@@ -75,6 +89,21 @@ func (mr *MockPresetUtilityMockRecorder) ProvideCamera(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideCamera", reflect.TypeOf((*MockPresetUtility)(nil).ProvideCamera), arg0, arg1)
 }
 
+// StorePreset mocks base method.
+func (m *MockPresetUtility) StorePreset(lectureHall model.LectureHall, presetID int, name string) (model.CameraPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePreset", lectureHall, presetID, name)
+	ret0, _ := ret[0].(model.CameraPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorePreset indicates an expected call of StorePreset.
+func (mr *MockPresetUtilityMockRecorder) StorePreset(lectureHall, presetID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePreset", reflect.TypeOf((*MockPresetUtility)(nil).StorePreset), lectureHall, presetID, name)
+}
+
 // TakeSnapshot mocks base method.
 func (m *MockPresetUtility) TakeSnapshot(arg0 model.CameraPreset) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"database/sql"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// AccessGroup is a named group of users, e.g. "Master Informatics 2026". Its members can watch the courses with
+// visibility "enrolled" it is attached to. Members are stored by LRZ ID so users don't need to have logged in before.
+type AccessGroup struct {
+	gorm.Model
+
+	Name string `gorm:"type:varchar(128);not null;uniqueIndex" json:"name"`
+	// LdapGroup is the DN of the LDAP group the members are synced with, members are uploaded as CSV if empty
+	LdapGroup string   `json:"ldapGroup"`
+	Courses   []Course `gorm:"many2many:course_access_groups" json:"courses,omitempty"`
+
+	SyncedAt    sql.NullTime `json:"-"`
+	SyncAdded   int          `gorm:"not null;default:0" json:"syncAdded"`
+	SyncRemoved int          `gorm:"not null;default:0" json:"syncRemoved"`
+	SyncError   string       `json:"syncError,omitempty"`
+
+	MemberCount int `gorm:"-" json:"memberCount"`
+}
+
+// AccessGroupMember is a user in an access group
+type AccessGroupMember struct {
+	AccessGroupID uint   `gorm:"primaryKey;autoIncrement:false"`
+	LrzID         string `gorm:"type:varchar(32);primaryKey;index"`
+}
+
+var lrzIDRegex = regexp.MustCompile(`^[a-z]{2}[0-9]{2}[a-z]{3}$`)
+
+// NormalizeLrzID returns the lower-case LRZ ID and whether id is one, e.g. "ab12cde"
+func NormalizeLrzID(id string) (string, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	return id, lrzIDRegex.MatchString(id)
+}
+
+// DiffMembers returns the members of updated not in current and the members of current not in updated
+func DiffMembers(current, updated []string) (added, removed []string) {
+	inCurrent := make(map[string]bool, len(current))
+	for _, m := range current {
+		inCurrent[m] = true
+	}
+	inUpdated := make(map[string]bool, len(updated))
+	for _, m := range updated {
+		if !inUpdated[m] && !inCurrent[m] {
+			added = append(added, m)
+		}
+		inUpdated[m] = true
+	}
+	for _, m := range current {
+		if !inUpdated[m] {
+			removed = append(removed, m)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
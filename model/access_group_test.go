@@ -0,0 +1,27 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMembers(t *testing.T) {
+	added, removed := DiffMembers([]string{"ab12cde", "fg34hij"}, []string{"kl56mno", "ab12cde", "kl56mno"})
+	if !reflect.DeepEqual(added, []string{"kl56mno"}) {
+		t.Errorf("expected kl56mno to be added, got %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"fg34hij"}) {
+		t.Errorf("expected fg34hij to be removed, got %v", removed)
+	}
+	if added, removed := DiffMembers(nil, nil); added != nil || removed != nil {
+		t.Errorf("expected no changes, got %v and %v", added, removed)
+	}
+}
+
+func TestNormalizeLrzID(t *testing.T) {
+	for id, expected := range map[string]bool{" AB12CDE ": true, "ab12cde": true, "ab12cd": false, "hansi@tum.de": false, "": false} {
+		if _, ok := NormalizeLrzID(id); ok != expected {
+			t.Errorf("%q: expected %v, got %v", id, expected, ok)
+		}
+	}
+}
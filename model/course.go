@@ -40,6 +40,22 @@ type Course struct {
 
 	LivePrivate bool `gorm:"not null; default:false"` // whether Livestreams are private
 	VodPrivate  bool `gorm:"not null; default:false"` // Whether VODs are made private after livestreams
+
+	LivePlayback string `gorm:"not null; default:'hls'"` // hls, llhls or webrtc, see LivePlaybackHLS etc.
+	DvrEnabled   bool   `gorm:"not null; default:false"` // whether viewers can rewind while live
+	DvrWindow    uint   `gorm:"not null; default:0"`     // minutes viewers can rewind, 0 for the whole lecture
+}
+
+// Live playback modes selectable per course.
+const (
+	LivePlaybackHLS    = "hls"    // standard HLS, typically 20-30s delay
+	LivePlaybackLLHLS  = "llhls"  // low-latency HLS with partial segments
+	LivePlaybackWebRTC = "webrtc" // WebRTC (WHEP) for small audiences, falls back to HLS
+)
+
+// IsValidLivePlayback returns whether mode is a known live playback mode
+func IsValidLivePlayback(mode string) bool {
+	return mode == LivePlaybackHLS || mode == LivePlaybackLLHLS || mode == LivePlaybackWebRTC
 }
 
 type CourseDTO struct {
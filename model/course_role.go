@@ -0,0 +1,53 @@
+package model
+
+// CourseRoleType is the role of a user in a course. Owners are stored in Course.UserID and admins in
+// Course.Admins, other roles in CourseRole.
+type CourseRoleType string
+
+const (
+	CourseRoleOwner     CourseRoleType = "owner"
+	CourseRoleAdmin     CourseRoleType = "admin"
+	CourseRoleModerator CourseRoleType = "moderator" // e.g. tutors moderating the chat
+	CourseRoleViewer    CourseRoleType = "viewer"    // viewers who may download recordings
+)
+
+// CoursePermission is something a role allows to do in a course
+type CoursePermission uint
+
+const (
+	CoursePermissionManage       CoursePermission = iota // edit the course and its lectures, manage roles
+	CoursePermissionModerateChat                         // approve, retract, resolve and delete messages
+	CoursePermissionManagePolls                          // start and close polls
+	CoursePermissionDownload                             // download recordings even if downloads are disabled
+)
+
+var coursePermissions = map[CourseRoleType][]CoursePermission{
+	CourseRoleOwner:     {CoursePermissionManage, CoursePermissionModerateChat, CoursePermissionManagePolls, CoursePermissionDownload},
+	CourseRoleAdmin:     {CoursePermissionManage, CoursePermissionModerateChat, CoursePermissionManagePolls, CoursePermissionDownload},
+	CourseRoleModerator: {CoursePermissionModerateChat, CoursePermissionManagePolls},
+	CourseRoleViewer:    {CoursePermissionDownload},
+}
+
+// Allows returns whether the role grants the permission p
+func (r CourseRoleType) Allows(p CoursePermission) bool {
+	for _, permission := range coursePermissions[r] {
+		if permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignable returns whether the role can be assigned with a CourseRole
+func (r CourseRoleType) IsAssignable() bool {
+	return r == CourseRoleModerator || r == CourseRoleViewer
+}
+
+// CourseRole is the role of a user in a course besides owner and admin
+type CourseRole struct {
+	UserID   uint           `gorm:"primaryKey;autoIncrement:false" json:"userID"`
+	CourseID uint           `gorm:"primaryKey;autoIncrement:false" json:"courseID"`
+	Role     CourseRoleType `gorm:"type:varchar(16);not null" json:"role"`
+
+	User User `json:"-"`
+}
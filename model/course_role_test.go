@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestCoursePermissions(t *testing.T) {
+	course := Course{Model: gorm.Model{ID: 1}, UserID: 10}
+	owner := &User{Model: gorm.Model{ID: 10}, Role: LecturerType}
+	admin := &User{Model: gorm.Model{ID: 11}, Role: LecturerType, AdministeredCourses: []Course{course}}
+	moderator := &User{Model: gorm.Model{ID: 12}, Role: StudentType, CourseRoles: []CourseRole{{UserID: 12, CourseID: 1, Role: CourseRoleModerator}}}
+	viewer := &User{Model: gorm.Model{ID: 13}, Role: StudentType, CourseRoles: []CourseRole{{UserID: 13, CourseID: 1, Role: CourseRoleViewer}}}
+	otherCourse := &User{Model: gorm.Model{ID: 14}, Role: StudentType, CourseRoles: []CourseRole{{UserID: 14, CourseID: 2, Role: CourseRoleModerator}}}
+	student := &User{Model: gorm.Model{ID: 15}, Role: StudentType}
+
+	tests := map[string]struct {
+		user     *User
+		expected []bool // manage, moderate chat, manage polls, download
+	}{
+		"owner":        {owner, []bool{true, true, true, true}},
+		"admin":        {admin, []bool{true, true, true, true}},
+		"moderator":    {moderator, []bool{false, true, true, false}},
+		"viewer":       {viewer, []bool{false, false, false, true}},
+		"other course": {otherCourse, []bool{false, false, false, false}},
+		"student":      {student, []bool{false, false, false, false}},
+		"logged out":   {nil, []bool{false, false, false, false}},
+	}
+	permissions := []CoursePermission{CoursePermissionManage, CoursePermissionModerateChat, CoursePermissionManagePolls, CoursePermissionDownload}
+	for name, test := range tests {
+		for i, p := range permissions {
+			if got := test.user.HasCoursePermission(course, p); got != test.expected[i] {
+				t.Errorf("%s: expected permission %d to be %v, got %v", name, p, test.expected[i], got)
+			}
+		}
+	}
+	if role, _ := owner.GetCourseRole(course); role != CourseRoleOwner {
+		t.Errorf("expected owner, got %s", role)
+	}
+}
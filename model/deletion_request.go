@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// AccountDeletionGracePeriod is how long users can cancel the deletion of their account
+	AccountDeletionGracePeriod = 14 * 24 * time.Hour
+
+	// DeletedUserName replaces the name of deleted users in chat messages that are kept
+	DeletedUserName = "Deleted user"
+)
+
+// DeletionRequest is the request of a user to delete their account, executed after AccountDeletionGracePeriod
+type DeletionRequest struct {
+	gorm.Model
+
+	UserID   uint      `gorm:"not null;uniqueIndex" json:"userID"`
+	User     User      `json:"-"`
+	DeleteAt time.Time `gorm:"not null;index" json:"deleteAt"`
+}
+
+// NewDeletionRequest returns the request to delete the account of userID after the grace period
+func NewDeletionRequest(userID uint, now time.Time) DeletionRequest {
+	return DeletionRequest{UserID: userID, DeleteAt: now.Add(AccountDeletionGracePeriod)}
+}
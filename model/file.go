@@ -2,6 +2,7 @@ package model
 
 import (
 	"net/url"
+	"path/filepath"
 	"strings"
 
 	"gorm.io/gorm"
@@ -21,7 +22,8 @@ const (
 	FILETYPE_THUMB_LG_COMB
 	FILETYPE_THUMB_LG_CAM
 	FILETYPE_THUMB_LG_PRES
-	FILETYPE_THUMB_LG_CAM_PRES // generated from CAM and PRES, preferred over the others
+	FILETYPE_THUMB_LG_CAM_PRES  // generated from CAM and PRES, preferred over the others
+	FILETYPE_ATTACHMENT_VERSION // replaced version of an attachment, only visible to admins of the course
 )
 
 type File struct {
@@ -31,6 +33,16 @@ type File struct {
 	Path     string `gorm:"not null"`
 	Filename string
 	Type     FileType `gorm:"not null; default: 1"`
+
+	Verified     bool   // Verified is true if the worker checked the transcoded file against the recording
+	Verification string // Verification summarizes the checks of the worker, e.g. the compared durations
+
+	// Attachments only
+	Version        uint   `gorm:"not null;default:1"` // incremented whenever the attachment is replaced
+	AttachmentID   *uint  `gorm:"index"`              // the attachment a FILETYPE_ATTACHMENT_VERSION is an old version of
+	PreviewPath    string // image of the first page of pdf attachments
+	VideoSectionID *uint  // section of the lecture the attachment belongs to (if any)
+	Timestamp      *uint  // second of the lecture the attachment belongs to (if any)
 }
 
 func (f File) GetDownloadFileName() string {
@@ -71,10 +83,15 @@ func (f File) IsThumb() bool {
 	return f.Type == FILETYPE_THUMB_CAM || f.Type == FILETYPE_THUMB_PRES || f.Type == FILETYPE_THUMB_COMB
 }
 
+// IsPDF returns whether the file is an uploaded pdf document
+func (f File) IsPDF() bool {
+	return !f.IsURL() && strings.EqualFold(filepath.Ext(f.Path), ".pdf")
+}
+
 func (f File) IsURL() bool {
 	parsedUrl, err := url.Parse(f.Path)
 	if err != nil {
 		return false
 	}
-	return parsedUrl.Scheme == "https://" || parsedUrl.Scheme == "http://"
+	return parsedUrl.Scheme == "https" || parsedUrl.Scheme == "http"
 }
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestFileIsURL(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"https://example.com/slides.pdf":    true,
+		"http://example.com/slides.pdf":     true,
+		"/srv/cephfs/livestream/slides.pdf": false,
+		"slides.pdf":                        false,
+	} {
+		if (File{Path: path}).IsURL() != expected {
+			t.Errorf("%s: expected IsURL to be %v", path, expected)
+		}
+	}
+	if !(File{Path: "/files/a.PDF"}).IsPDF() || (File{Path: "https://example.com/a.pdf"}).IsPDF() {
+		t.Error("expected only uploaded pdfs to be pdfs")
+	}
+}
+
+func TestAttachmentStart(t *testing.T) {
+	sectionID, timestamp := uint(3), uint(90)
+	stream := Stream{VideoSections: []VideoSection{{Model: gorm.Model{ID: 3}, StartHours: 1, StartMinutes: 2, StartSeconds: 3}}}
+
+	if start := stream.AttachmentStart(File{VideoSectionID: &sectionID, Timestamp: &timestamp}); start == nil || *start != 3723 {
+		t.Errorf("expected start of section, got %v", start)
+	}
+	if start := stream.AttachmentStart(File{Timestamp: &timestamp}); start == nil || *start != 90 {
+		t.Errorf("expected timestamp, got %v", start)
+	}
+	if start := stream.AttachmentStart(File{}); start != nil {
+		t.Error("expected attachment without placement to have no start")
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGuestLinkRevoked  = errors.New("guest link was revoked")
+	ErrGuestLinkExpired  = errors.New("guest link expired")
+	ErrGuestLinkUsedUp   = errors.New("guest link has no uses left")
+	ErrGuestLinkNotFound = errors.New("guest link not found")
+)
+
+// GuestLink grants view-only access to a course or a single lecture of it to people without an account
+type GuestLink struct {
+	gorm.Model
+
+	CourseID    uint  `gorm:"not null;index" json:"courseID"`
+	StreamID    *uint `json:"streamID"` // nil if the link is for the whole course
+	CreatedByID uint  `json:"-"`        // course admin who created the link
+
+	Note         string       `json:"note"` // e.g. who the link is for
+	ExpiresAt    time.Time    `gorm:"not null" json:"expiresAt"`
+	MaxUses      int          `gorm:"not null;default:0" json:"maxUses"` // 0 if the link can be used any number of times
+	Uses         int          `gorm:"not null;default:0" json:"uses"`
+	RequireEmail bool         `gorm:"not null;default:false" json:"requireEmail"`
+	RevokedAt    sql.NullTime `json:"-"`
+}
+
+// Check returns why the link can't be used at now, or nil if it can
+func (l *GuestLink) Check(now time.Time) error {
+	switch {
+	case l.RevokedAt.Valid:
+		return ErrGuestLinkRevoked
+	case !now.Before(l.ExpiresAt):
+		return ErrGuestLinkExpired
+	}
+	return nil
+}
+
+// HasUsesLeft returns whether the link can be redeemed again
+func (l *GuestLink) HasUsesLeft() bool {
+	return l.MaxUses == 0 || l.Uses < l.MaxUses
+}
+
+// AllowsCourse returns whether the link grants access to the whole course
+func (l *GuestLink) AllowsCourse(course Course) bool {
+	return l != nil && l.CourseID == course.ID && l.StreamID == nil
+}
+
+// AllowsStream returns whether the link grants access to the stream. Private streams are only visible to admins.
+func (l *GuestLink) AllowsStream(stream Stream) bool {
+	if l == nil || l.CourseID != stream.CourseID || stream.Private {
+		return false
+	}
+	return l.StreamID == nil || *l.StreamID == stream.ID
+}
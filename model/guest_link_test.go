@@ -0,0 +1,48 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestGuestLink(t *testing.T) {
+	now := time.Now()
+	streamID := uint(7)
+	courseLink := GuestLink{CourseID: 1, ExpiresAt: now.Add(time.Hour)}
+	streamLink := GuestLink{CourseID: 1, StreamID: &streamID, ExpiresAt: now.Add(time.Hour), MaxUses: 2, Uses: 2}
+	course := Course{Model: gorm.Model{ID: 1}}
+	stream := Stream{Model: gorm.Model{ID: 7}, CourseID: 1}
+	otherStream := Stream{Model: gorm.Model{ID: 8}, CourseID: 1}
+
+	if err := courseLink.Check(now); err != nil {
+		t.Errorf("expected link to be valid, got %v", err)
+	}
+	if err := courseLink.Check(now.Add(time.Hour)); err != ErrGuestLinkExpired {
+		t.Errorf("expected link to be expired, got %v", err)
+	}
+	revoked := courseLink
+	revoked.RevokedAt = sql.NullTime{Time: now, Valid: true}
+	if err := revoked.Check(now); err != ErrGuestLinkRevoked {
+		t.Errorf("expected link to be revoked, got %v", err)
+	}
+	if !courseLink.HasUsesLeft() || streamLink.HasUsesLeft() {
+		t.Error("expected only the unlimited link to have uses left")
+	}
+
+	if !courseLink.AllowsCourse(course) || streamLink.AllowsCourse(course) {
+		t.Error("expected only the course link to allow the course")
+	}
+	if !courseLink.AllowsStream(otherStream) || !streamLink.AllowsStream(stream) || streamLink.AllowsStream(otherStream) {
+		t.Error("expected stream links to only allow their stream")
+	}
+	if courseLink.AllowsStream(Stream{CourseID: 1, Private: true}) || courseLink.AllowsStream(Stream{CourseID: 2}) {
+		t.Error("expected private streams and other courses not to be allowed")
+	}
+	var noLink *GuestLink
+	if noLink.AllowsCourse(course) || noLink.AllowsStream(stream) {
+		t.Error("expected nil link to allow nothing")
+	}
+}
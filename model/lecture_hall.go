@@ -25,8 +25,15 @@ type CameraType uint
 const (
 	Axis CameraType = iota + 1
 	Panasonic
+	ONVIF
+	VISCA
 )
 
+// Valid returns whether t is a supported camera type
+func (t CameraType) Valid() bool {
+	return t >= Axis && t <= VISCA
+}
+
 func (l LectureHall) NumSources() int {
 	num := 0
 	if l.CombIP != "" {
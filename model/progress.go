@@ -9,3 +9,11 @@ type StreamProgress struct {
 	StreamID uint `gorm:"primaryKey" json:"streamId"`
 	UserID   uint `gorm:"primaryKey" json:"-"`
 }
+
+// ProgressRollup summarizes the StreamProgress of a stream after the individual progresses were deleted.
+type ProgressRollup struct {
+	StreamID    uint    `gorm:"primaryKey"`
+	Viewers     uint    `gorm:"not null;default:0"` // number of users that had a progress
+	Watched     uint    `gorm:"not null;default:0"` // number of users that marked the stream as watched
+	ProgressSum float64 `gorm:"not null;default:0"` // sum of all progresses, divide by Viewers for the average
+}
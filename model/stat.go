@@ -13,4 +13,8 @@ type Stat struct {
 	StreamID uint      `gorm:"not null"`
 	Viewers  uint      `gorm:"not null;default:0"`
 	Live     bool      `gorm:"not null;default:false"`
+
+	// Aggregated stats replace the raw stats of an hour after the retention period,
+	// Viewers holds the sum of vod views or the peak live viewers of that hour.
+	Aggregated bool `gorm:"not null;default:false"`
 }
@@ -53,9 +53,13 @@ type Stream struct {
 	StreamWorkers         []Worker         `gorm:"many2many:stream_workers;"`
 	StreamProgresses      []StreamProgress `gorm:"foreignKey:StreamID"`
 	VideoSections         []VideoSection
+	SectionSuggestions    []SectionSuggestion
 	TranscodingProgresses []TranscodingProgress `gorm:"foreignKey:StreamID"`
 	Private               bool                  `gorm:"not null;default:false"`
 
+	PremiereVodUrl string // PremiereVodUrl is the pre-transcoded VoD of a premiere, published once the premiere ended
+	DvrUrl         string // DvrUrl is the playlist of the DVR recording viewers can rewind in while live, removed once the VoD is published
+
 	Watched bool `gorm:"-"` // Used to determine if stream is watched when loaded for a specific user.
 }
 
@@ -181,6 +185,21 @@ func (s Stream) IsDownloadable() bool {
 	return s.Recording && (s.PlaylistUrl != "" || s.PlaylistUrlPRES != "" || s.PlaylistUrlCAM != "")
 }
 
+// IsPremiereReady returns whether the file of a premiere was uploaded and transcoded
+func (s Stream) IsPremiereReady() bool {
+	return s.Premiere && s.PremiereVodUrl != ""
+}
+
+// PremiereFile returns the transcoded file streamed during a premiere
+func (s Stream) PremiereFile() (File, bool) {
+	for _, f := range s.Files {
+		if f.Type == FILETYPE_VOD && strings.HasSuffix(f.Path, ".mp4") {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
 // IsSelfStream returns whether the stream is a scheduled stream in a lecture hall
 func (s Stream) IsSelfStream() bool {
 	return s.LectureHallID == 0
@@ -213,6 +232,11 @@ func (s Stream) IsStartingInMoreThanOneDay() bool {
 	return s.Start.After(time.Now().Add(48 * time.Hour))
 }
 
+// Overlaps returns whether the time slots of s and other overlap
+func (s Stream) Overlaps(other Stream) bool {
+	return s.Start.Before(other.End) && other.Start.Before(s.End)
+}
+
 // IsPlanned returns whether the stream is planned or not
 func (s Stream) IsPlanned() bool {
 	return !s.Recording && !s.LiveNow && !s.IsPast() && !s.IsComingUp()
@@ -314,6 +338,10 @@ func (s Stream) getJson(lhs []LectureHall, course Course) gin.H {
 			"id":           file.ID,
 			"fileType":     file.Type,
 			"friendlyName": file.GetFriendlyFileName(),
+			"version":      file.Version,
+			"hasPreview":   file.PreviewPath != "",
+			"sectionID":    file.VideoSectionID,
+			"timestamp":    file.Timestamp,
 		})
 	}
 	lhName := "Selfstreaming"
@@ -336,6 +364,18 @@ func (s Stream) getJson(lhs []LectureHall, course Course) gin.H {
 		})
 	}
 
+	var sectionSuggestions []gin.H
+	for _, suggestion := range s.SectionSuggestions {
+		sectionSuggestions = append(sectionSuggestions, gin.H{
+			"id":           suggestion.ID,
+			"description":  suggestion.Description,
+			"startHours":   suggestion.StartHours,
+			"startMinutes": suggestion.StartMinutes,
+			"startSeconds": suggestion.StartSeconds,
+			"fileID":       suggestion.FileID,
+		})
+	}
+
 	return gin.H{
 		"lectureId":             s.Model.ID,
 		"courseId":              s.CourseID,
@@ -348,6 +388,8 @@ func (s Stream) getJson(lhs []LectureHall, course Course) gin.H {
 		"isLiveNow":             s.LiveNow,
 		"isRecording":           s.Recording,
 		"isConverting":          s.IsConverting(),
+		"isPremiere":            s.Premiere,
+		"isPremiereReady":       s.IsPremiereReady(),
 		"transcodingProgresses": s.TranscodingProgresses,
 		"isPast":                s.IsPast(),
 		"hasStats":              s.Stats != nil,
@@ -360,6 +402,7 @@ func (s Stream) getJson(lhs []LectureHall, course Course) gin.H {
 		"private":               s.Private,
 		"downloadableVods":      s.GetVodFiles(),
 		"videoSections":         videoSections,
+		"sectionSuggestions":    sectionSuggestions,
 	}
 }
 
@@ -373,6 +416,19 @@ func (s Stream) Attachments() []File {
 	return attachments
 }
 
+// AttachmentStart returns the second of the lecture the attachment belongs to or nil if it isn't placed in the lecture
+func (s Stream) AttachmentStart(f File) *uint {
+	if f.VideoSectionID != nil {
+		for _, section := range s.VideoSections {
+			if section.ID == *f.VideoSectionID {
+				start := section.StartHours*3600 + section.StartMinutes*60 + section.StartSeconds
+				return &start
+			}
+		}
+	}
+	return f.Timestamp
+}
+
 type StreamDTO struct {
 	ID          uint
 	Name        string
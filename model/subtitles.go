@@ -1,6 +1,8 @@
 package model
 
 import (
+	"regexp"
+
 	"gorm.io/gorm"
 )
 
@@ -11,6 +13,7 @@ type Subtitles struct {
 	StreamID uint   `gorm:"not null"`
 	Content  string `gorm:"not null"` // the .srt content provided by the voice-service
 	Language string `gorm:"not null"`
+	Edited   bool   `gorm:"not null;default:false"` // Edited subtitles aren't replaced by regenerated ones
 }
 
 // TableName returns the name of the table for the Subtitles model in the database.
@@ -27,3 +30,59 @@ func (s *Subtitles) BeforeCreate(tx *gorm.DB) (err error) {
 func (s *Subtitles) AfterFind(tx *gorm.DB) (err error) {
 	return nil
 }
+
+// Sources of SubtitlesRevisions
+const (
+	SubtitlesSourceGenerated  = "generated"
+	SubtitlesSourceTranslated = "translated"
+	SubtitlesSourceEdited     = "edited"
+)
+
+// SubtitlesRevision is a version of Subtitles. Every change is kept, so lecturers can restore
+// regenerated subtitles or their own edits.
+type SubtitlesRevision struct {
+	gorm.Model
+
+	SubtitlesID uint   `gorm:"not null;index" json:"subtitlesID"`
+	Content     string `gorm:"not null" json:"-"`
+	Source      string `gorm:"not null" json:"source"`
+	UserID      *uint  `json:"userID,omitempty"` // UserID is the lecturer who edited the subtitles
+}
+
+// States of SubtitleJobs
+const (
+	SubtitleJobWaiting = "waiting" // translations wait for the subtitles they translate
+	SubtitleJobQueued  = "queued"
+	SubtitleJobRunning = "running"
+	SubtitleJobFailed  = "failed"
+	SubtitleJobDone    = "done"
+)
+
+// SubtitleJob tracks the generation or translation of subtitles by the voice-service
+type SubtitleJob struct {
+	gorm.Model
+
+	StreamID       uint   `gorm:"not null;index" json:"streamID"`
+	Language       string `gorm:"not null" json:"language"`
+	SourceLanguage string `json:"sourceLanguage,omitempty"` // SourceLanguage is set for translations
+	State          string `gorm:"not null;default:'queued'" json:"state"`
+	Progress       uint   `gorm:"not null;default:0" json:"progress"`
+	Error          string `json:"error,omitempty"`
+}
+
+// IsTranslation returns whether the job translates existing subtitles
+func (j SubtitleJob) IsTranslation() bool {
+	return j.SourceLanguage != ""
+}
+
+// IsFinished returns whether the voice-service is done with the job
+func (j SubtitleJob) IsFinished() bool {
+	return j.State == SubtitleJobDone || j.State == SubtitleJobFailed
+}
+
+var subtitleLanguageRe = regexp.MustCompile(`^[a-z]{2}$`)
+
+// IsValidSubtitleLanguage returns whether lang is an ISO 639-1 code
+func IsValidSubtitleLanguage(lang string) bool {
+	return subtitleLanguageRe.MatchString(lang)
+}
@@ -9,6 +9,7 @@ import (
 const (
 	TokenScopeAdmin    = "admin"
 	TokenScopeLecturer = "lecturer"
+	TokenScopeCalendar = "calendar" // private calendar feed of a user, grants no other access
 )
 
 // Token can be used to authenticate instead of a user account
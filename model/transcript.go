@@ -0,0 +1,210 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/asticode/go-astisub"
+)
+
+// TranscriptCue is a single cue of subtitles, start and end are in milliseconds
+type TranscriptCue struct {
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Text  string `json:"text"`
+}
+
+// TranscriptParagraph is a run of cues without a longer pause in between
+type TranscriptParagraph struct {
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Text  string `json:"text"`
+}
+
+// Transcript is the structured content of subtitles
+type Transcript struct {
+	Language   string                `json:"language"`
+	Cues       []TranscriptCue       `json:"cues"`
+	Paragraphs []TranscriptParagraph `json:"paragraphs"`
+}
+
+// TranscriptKeyword is an occurrence of a frequent word of subtitles. Keywords are stored when subtitles
+// are received or edited, so students can jump to the parts of the lecture a keyword is mentioned in.
+type TranscriptKeyword struct {
+	ID          uint   `gorm:"primaryKey" json:"-"`
+	SubtitlesID uint   `gorm:"not null;index" json:"-"`
+	Keyword     string `gorm:"not null;type:varchar(64)" json:"keyword"`
+	Start       int64  `gorm:"not null" json:"start"`                              // Start of the cue mentioning the keyword in milliseconds
+	Rank        int    `gorm:"column:keyword_rank;not null;default:0" json:"rank"` // 0 for the most frequent keyword
+}
+
+const (
+	paragraphPause    = 2000 // a pause in milliseconds that starts a new paragraph
+	paragraphMaxChars = 800  // paragraphs are split after the next sentence once they are this long
+	keywordMinLength  = 4
+	keywordMinCount   = 3
+	keywordMaxCount   = 40 // number of keywords per subtitles
+	keywordMaxJumps   = 20 // number of occurrences stored per keyword
+	keywordJumpGap    = 30000
+)
+
+// ParseTranscript returns the transcript of WebVTT or SRT subtitles
+func ParseTranscript(content string) (Transcript, error) {
+	var subs *astisub.Subtitles
+	var err error
+	if strings.HasPrefix(strings.TrimLeft(content, "\ufeff \r\n"), "WEBVTT") {
+		subs, err = astisub.ReadFromWebVTT(strings.NewReader(content))
+	} else {
+		subs, err = astisub.ReadFromSRT(strings.NewReader(content))
+	}
+	if err != nil {
+		return Transcript{}, err
+	}
+	t := Transcript{Cues: make([]TranscriptCue, 0, len(subs.Items)), Paragraphs: []TranscriptParagraph{}}
+	for _, item := range subs.Items {
+		lines := make([]string, 0, len(item.Lines))
+		for _, line := range item.Lines {
+			if text := strings.TrimSpace(line.String()); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		t.Cues = append(t.Cues, TranscriptCue{
+			Start: item.StartAt.Milliseconds(),
+			End:   item.EndAt.Milliseconds(),
+			Text:  strings.Join(lines, " "),
+		})
+	}
+	t.Paragraphs = paragraphs(t.Cues)
+	return t, nil
+}
+
+// paragraphs joins cues into paragraphs, independent of who is speaking
+func paragraphs(cues []TranscriptCue) []TranscriptParagraph {
+	res := []TranscriptParagraph{}
+	var current *TranscriptParagraph
+	for _, cue := range cues {
+		if current != nil {
+			endsSentence := strings.ContainsAny(current.Text[len(current.Text)-1:], ".!?")
+			if cue.Start-current.End >= paragraphPause || (len(current.Text) >= paragraphMaxChars && endsSentence) {
+				res = append(res, *current)
+				current = nil
+			}
+		}
+		if current == nil {
+			current = &TranscriptParagraph{Start: cue.Start, End: cue.End, Text: cue.Text}
+			continue
+		}
+		current.End = cue.End
+		current.Text += " " + cue.Text
+	}
+	if current != nil {
+		res = append(res, *current)
+	}
+	return res
+}
+
+// Search returns the indices of the cues containing all words of q, ignoring case
+func (t Transcript) Search(q string) []int {
+	words := strings.Fields(strings.ToLower(q))
+	res := []int{}
+	if len(words) == 0 {
+		return res
+	}
+	for i, cue := range t.Cues {
+		text := strings.ToLower(cue.Text)
+		found := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Keywords returns the most frequent words of the transcript with the cues they're mentioned in, most frequent first.
+// Mentions shortly after one another only count once as jump target.
+func (t Transcript) Keywords() []TranscriptKeyword {
+	counts := make(map[string]int)
+	starts := make(map[string][]int64)
+	for _, cue := range t.Cues {
+		for _, word := range words(cue.Text) {
+			counts[word]++
+			s := starts[word]
+			if len(s) == 0 || cue.Start-s[len(s)-1] >= keywordJumpGap {
+				starts[word] = append(s, cue.Start)
+			}
+		}
+	}
+	keywords := make([]string, 0, len(counts))
+	for word, count := range counts {
+		if count >= keywordMinCount {
+			keywords = append(keywords, word)
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if counts[keywords[i]] != counts[keywords[j]] {
+			return counts[keywords[i]] > counts[keywords[j]]
+		}
+		return keywords[i] < keywords[j]
+	})
+	if len(keywords) > keywordMaxCount {
+		keywords = keywords[:keywordMaxCount]
+	}
+	res := []TranscriptKeyword{}
+	for rank, keyword := range keywords {
+		s := starts[keyword]
+		if len(s) > keywordMaxJumps {
+			s = s[:keywordMaxJumps]
+		}
+		for _, start := range s {
+			res = append(res, TranscriptKeyword{Keyword: keyword, Start: start, Rank: rank})
+		}
+	}
+	return res
+}
+
+// words returns the lower case words of text that may be keywords
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	res := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.Trim(f, "-")
+		if len([]rune(f)) < keywordMinLength || len(f) > 64 || stopwords[f] || strings.IndexFunc(f, unicode.IsLetter) < 0 {
+			continue
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// stopwords are frequent english and german words that aren't keywords of a lecture
+var stopwords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		about above after again against also because been before being below between both could does doing down
+		during each from further have having here into just like more most much need only other over really right same
+		should some such than that their them then there these they thing things this those through under until very
+		want what when where which while will with would your yeah okay going know think actually maybe something
+		alle allem allen aller alles andere anderen auch auf aus bei beim bereits bevor bist bitte damit dann
+		darauf darum dass dein deine dem den denen denn der des deshalb dies diese diesem diesen dieser dieses doch
+		dort durch eben eine einem einen einer eines einfach etwa etwas euch für gibt haben hier hatte hätte ihnen ihre
+		immer jetzt kann kein keine können könnte machen mach macht mehr mein meine mich mit muss müssen nach nicht nichts noch
+		nur oder ohne schon sehr sein seine sich sie sind soll sollen sondern über unter viel vielleicht voll vom
+		von vor wann war waren warum was weil weiter welche wenn werden wieder wird wir wirklich wo wollen worden wurde
+		würde zum zur zwischen genau gut halt mal ganz eigentlich
+	`) {
+		m[w] = true
+	}
+	return m
+}()
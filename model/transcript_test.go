@@ -0,0 +1,83 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+const transcriptSRT = `1
+00:00:01,000 --> 00:00:03,000
+Dynamic programming splits problems.
+
+2
+00:00:03,500 --> 00:00:05,000
+Each problem is solved once.
+
+3
+00:00:10,000 --> 00:00:12,000
+Programming with tables
+
+4
+00:00:45,000 --> 00:00:47,000
+More dynamic programming
+`
+
+func TestParseTranscript(t *testing.T) {
+	transcript, err := ParseTranscript(transcriptSRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript.Cues) != 4 || transcript.Cues[1].Start != 3500 || transcript.Cues[1].Text != "Each problem is solved once." {
+		t.Errorf("unexpected cues %v", transcript.Cues)
+	}
+	expected := []TranscriptParagraph{
+		{Start: 1000, End: 5000, Text: "Dynamic programming splits problems. Each problem is solved once."},
+		{Start: 10000, End: 12000, Text: "Programming with tables"},
+		{Start: 45000, End: 47000, Text: "More dynamic programming"},
+	}
+	if !reflect.DeepEqual(transcript.Paragraphs, expected) {
+		t.Errorf("expected paragraphs %v, got %v", expected, transcript.Paragraphs)
+	}
+
+	vtt, err := ParseTranscript("WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\nworld\n")
+	if err != nil || len(vtt.Cues) != 1 || vtt.Cues[0].Text != "Hello world" {
+		t.Errorf("unexpected webvtt transcript %v, %v", vtt, err)
+	}
+}
+
+func TestTranscriptSearch(t *testing.T) {
+	transcript, _ := ParseTranscript(transcriptSRT)
+	if hits := transcript.Search("DYNAMIC programming"); !reflect.DeepEqual(hits, []int{0, 3}) {
+		t.Errorf("expected hits 0 and 3, got %v", hits)
+	}
+	if hits := transcript.Search(" "); len(hits) != 0 {
+		t.Errorf("expected no hits for empty query, got %v", hits)
+	}
+}
+
+func TestTranscriptKeywords(t *testing.T) {
+	transcript, _ := ParseTranscript(transcriptSRT)
+	expected := []TranscriptKeyword{
+		{Keyword: "programming", Start: 1000},
+		{Keyword: "programming", Start: 45000},
+	}
+	if keywords := transcript.Keywords(); !reflect.DeepEqual(keywords, expected) {
+		t.Errorf("expected keywords %v, got %v", expected, keywords)
+	}
+}
+
+func TestTranscriptKeywordsRank(t *testing.T) {
+	transcript := Transcript{Cues: []TranscriptCue{
+		{Start: 0, Text: "alpha gamma"},
+		{Start: 60000, Text: "alpha gamma"},
+		{Start: 120000, Text: "alpha gamma"},
+		{Start: 180000, Text: "gamma"},
+	}}
+	keywords := transcript.Keywords()
+	if len(keywords) != 7 {
+		t.Fatalf("expected 7 jumps, got %v", keywords)
+	}
+	if keywords[0].Keyword != "gamma" || keywords[0].Rank != 0 || keywords[4].Keyword != "alpha" || keywords[4].Rank != 1 {
+		t.Errorf("expected the most frequent keyword first, got %v", keywords)
+	}
+}
@@ -45,6 +45,8 @@ type User struct {
 	Courses             []Course       `gorm:"many2many:course_users" json:"-"` // courses a lecturer invited this user to
 	AdministeredCourses []Course       `gorm:"many2many:course_admins"`         // courses this user is an admin of
 	PinnedCourses       []Course       `gorm:"many2many:pinned_courses"`
+	CourseRoles         []CourseRole   `gorm:"foreignKey:UserID" json:"-"` // roles in courses besides admin
+	GroupCourses        []Course       `gorm:"-" json:"-"`                 // courses the user is enrolled in through an access group
 
 	Settings  []UserSetting `gorm:"foreignkey:UserID"`
 	Bookmarks []Bookmark    `gorm:"foreignkey:UserID" json:"-"`
@@ -262,11 +264,41 @@ func (u *User) IsAdminOfCourse(course Course) bool {
 	return u.Role == AdminType || course.UserID == u.ID
 }
 
+// GetCourseRole returns the role of the user in the course, false if they have none
+func (u *User) GetCourseRole(course Course) (CourseRoleType, bool) {
+	if u == nil {
+		return "", false
+	}
+	if course.UserID == u.ID {
+		return CourseRoleOwner, true
+	}
+	if u.IsAdminOfCourse(course) {
+		return CourseRoleAdmin, true
+	}
+	for _, r := range u.CourseRoles {
+		if r.CourseID == course.ID {
+			return r.Role, true
+		}
+	}
+	return "", false
+}
+
+// HasCoursePermission returns whether the role of the user in the course grants the permission p
+func (u *User) HasCoursePermission(course Course, p CoursePermission) bool {
+	role, ok := u.GetCourseRole(course)
+	return ok && role.Allows(p)
+}
+
+// CanDownloadFrom returns whether the user may download recordings of the course even if downloads are disabled
+func (u *User) CanDownloadFrom(course Course) bool {
+	return u.HasCoursePermission(course, CoursePermissionDownload)
+}
+
 func (u *User) IsEligibleToWatchCourse(course Course) bool {
 	if course.Visibility == "loggedin" || course.Visibility == "public" {
 		return true
 	}
-	for _, invCourse := range u.Courses {
+	for _, invCourse := range u.EnrolledCourses() {
 		if invCourse.ID == course.ID {
 			return true
 		}
@@ -274,9 +306,17 @@ func (u *User) IsEligibleToWatchCourse(course Course) bool {
 	return u.IsAdminOfCourse(course)
 }
 
+// EnrolledCourses returns the courses the user is invited to directly or through an access group
+func (u *User) EnrolledCourses() []Course {
+	if len(u.GroupCourses) == 0 {
+		return u.Courses
+	}
+	return append(append([]Course{}, u.Courses...), u.GroupCourses...)
+}
+
 func (u *User) CoursesForSemester(year int, term string, context context.Context) []Course {
 	cMap := make(map[uint]Course)
-	for _, c := range u.Courses {
+	for _, c := range u.EnrolledCourses() {
 		if c.Year == year && c.TeachingTerm == term {
 			cMap[c.ID] = c
 		}
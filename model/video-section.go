@@ -15,3 +15,17 @@ type VideoSection struct {
 	StreamID uint `gorm:"not null" json:"streamID"`
 	FileID   uint `gorm:"not null" json:"fileID"`
 }
+
+// SectionSuggestion is a section detected automatically at a slide change. Lecturers review suggestions,
+// accepted ones become VideoSections.
+type SectionSuggestion struct {
+	gorm.Model
+
+	Description  string `gorm:"not null" json:"description"`
+	StartHours   uint   `gorm:"not null" json:"startHours"`
+	StartMinutes uint   `gorm:"not null" json:"startMinutes"`
+	StartSeconds uint   `gorm:"not null" json:"startSeconds"`
+
+	StreamID uint `gorm:"not null;index" json:"streamID"`
+	FileID   uint `gorm:"not null" json:"fileID"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ViewSession is a period in which a viewer watched a stream, recorded when the viewer leaves.
+type ViewSession struct {
+	gorm.Model
+
+	StreamID uint      `gorm:"not null;index"`
+	UserID   *uint     // nil for viewers that aren't logged in
+	JoinedAt time.Time `gorm:"not null"`
+	LeftAt   time.Time `gorm:"not null"`
+	Live     bool      `gorm:"not null;default:false"`
+}
+
+// LiveRetention returns how many viewers were watching at each minute [0, minutes] after start.
+// Overlapping sessions of the same user (e.g. multiple tabs) are counted once.
+func LiveRetention(sessions []ViewSession, start time.Time, minutes int) []int {
+	if minutes < 0 {
+		return nil
+	}
+	res := make([]int, minutes+1)
+	seen := make([]map[uint]bool, minutes+1)
+	for _, s := range sessions {
+		from := int(s.JoinedAt.Sub(start).Minutes())
+		if s.JoinedAt.After(start.Add(time.Duration(from) * time.Minute)) {
+			from++ // joined after the full minute, don't count it
+		}
+		to := int(s.LeftAt.Sub(start).Minutes())
+		for m := max(from, 0); m <= min(to, minutes); m++ {
+			if s.UserID != nil {
+				if seen[m] == nil {
+					seen[m] = map[uint]bool{}
+				}
+				if seen[m][*s.UserID] {
+					continue
+				}
+				seen[m][*s.UserID] = true
+			}
+			res[m]++
+		}
+	}
+	return res
+}
+
+// VodRetention returns how many viewers reached each minute [0, minutes] of a recording based on their progress (0-1).
+func VodRetention(progresses []float64, minutes int) []int {
+	if minutes < 0 {
+		return nil
+	}
+	res := make([]int, minutes+1)
+	for _, p := range progresses {
+		reached := min(int(p*float64(minutes)), minutes)
+		for m := 0; m <= reached; m++ {
+			res[m]++
+		}
+	}
+	return res
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLiveRetention(t *testing.T) {
+	start := time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC)
+	user := uint(1)
+	sessions := []ViewSession{
+		{JoinedAt: start.Add(-time.Minute), LeftAt: start.Add(time.Minute * 3)},
+		{JoinedAt: start.Add(time.Second * 30), LeftAt: start.Add(time.Minute * 5), UserID: &user},
+		// second tab of the same user
+		{JoinedAt: start.Add(time.Minute * 2), LeftAt: start.Add(time.Minute * 4), UserID: &user},
+	}
+	got := LiveRetention(sessions, start, 4)
+	if want := []int{1, 2, 2, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LiveRetention() = %v, want %v", got, want)
+	}
+}
+
+func TestVodRetention(t *testing.T) {
+	got := VodRetention([]float64{0, 0.5, 1, 1.2}, 4)
+	if want := []int{4, 3, 3, 2, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VodRetention() = %v, want %v", got, want)
+	}
+}
@@ -16,8 +16,21 @@ type Worker struct {
 	Uptime string
 
 	Version string
+
+	Draining     bool // Draining is set by admins to take the worker out of rotation, it gets no new jobs
+	ShuttingDown bool // ShuttingDown is reported by the worker while it finishes its jobs before exiting
 }
 
 func (w *Worker) IsAlive() bool {
 	return w.LastSeen.After(time.Now().Add(time.Minute * -6))
 }
+
+// IsDraining returns true if the worker shouldn't get new jobs
+func (w *Worker) IsDraining() bool {
+	return w.Draining || w.ShuttingDown
+}
+
+// SafeToShutDown returns true if a draining worker finished all its jobs
+func (w *Worker) SafeToShutDown() bool {
+	return w.IsDraining() && w.Workload == 0 && w.Status == ""
+}
@@ -3,6 +3,7 @@ package camera
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -72,3 +73,20 @@ func (c AxisCam) GetPresets() ([]model.CameraPreset, error) {
 	}
 	return presetsForLectureHall, nil
 }
+
+// Move moves the camera relative to its current position
+func (c AxisCam) Move(pan, tilt, zoom float64) error {
+	_, err := makeAuthenticatedRequest(&c.Auth, "GET", "", fmt.Sprintf("%s/axis-cgi/com/ptz.cgi?rpan=%.2f&rtilt=%.2f&rzoom=%d&camera=1",
+		fmt.Sprintf(axisBaseURL, c.Ip), clamp(pan)*180, clamp(tilt)*90, int(clamp(zoom)*9999)))
+	return err
+}
+
+// StorePreset saves the current position as server preset presetId and names it
+func (c AxisCam) StorePreset(presetId int, name string) (int, error) {
+	_, err := makeAuthenticatedRequest(&c.Auth, "GET", "", fmt.Sprintf("%s/axis-cgi/com/ptz.cgi?setserverpresetno=%d&camera=1", fmt.Sprintf(axisBaseURL, c.Ip), presetId))
+	if err != nil {
+		return 0, err
+	}
+	_, err = makeAuthenticatedRequest(&c.Auth, "POST", fmt.Sprintf("action=update&root.PTZ.Preset.P0.Position.P%d.Name=%s", presetId, url.QueryEscape(name)), fmt.Sprintf("%s/axis-cgi/param.cgi", fmt.Sprintf(axisBaseURL, c.Ip)))
+	return presetId, err
+}
@@ -2,6 +2,7 @@ package camera
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,6 +22,26 @@ type Cam interface {
 	TakeSnapshot(outDir string) (filename string, err error)
 	// GetPresets fetches all available presets
 	GetPresets() ([]model.CameraPreset, error)
+	// Move moves the camera relative to its current position.
+	// pan, tilt and zoom are fractions of the cameras range in [-1, 1], 0 leaves the axis untouched.
+	Move(pan, tilt, zoom float64) error
+	// StorePreset saves the current position of the camera as preset presetId and returns the id the camera
+	// stored it as, which differs from presetId if the camera chooses the ids of new presets itself.
+	StorePreset(presetId int, name string) (int, error)
+}
+
+// ErrUnsupported is returned by cameras that can't perform an operation.
+var ErrUnsupported = errors.New("operation not supported by camera")
+
+// clamp limits v to [-1, 1]
+func clamp(v float64) float64 {
+	if v < -1 {
+		return -1
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
 }
 
 // makeAuthenticatedRequest Sends a request to the camera.
@@ -0,0 +1,271 @@
+package camera
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+/**
+*
+* Compatible cameras:
+* - ONVIF Profile S cameras with PTZ service
+*
+**/
+
+const onvifDeviceServiceURL = "http://%s/onvif/device_service"
+
+// OnvifCam represents cameras that implement the ONVIF PTZ and media services
+type OnvifCam struct {
+	Ip   string
+	Auth string // username and password for the WS-UsernameToken (e.g. "user:password")
+
+	ptzURL       string
+	mediaURL     string
+	profileToken string
+}
+
+// NewOnvifCam Acts as a constructor for ONVIF cameras.
+// ip: the ip address (and optionally port) of the camera
+// auth: username and password of the camera (e.g. "user:password")
+func NewOnvifCam(ip string, auth string) *OnvifCam {
+	return &OnvifCam{Ip: ip, Auth: auth}
+}
+
+// SetPreset tells the camera to use a preset specified by presetId
+func (c *OnvifCam) SetPreset(presetId int) error {
+	presets, err := c.presets()
+	if err != nil {
+		return err
+	}
+	for _, p := range presets {
+		if onvifPresetID(p.Token) == presetId {
+			return c.call(c.ptzURL, fmt.Sprintf(`<GotoPreset xmlns="http://www.onvif.org/ver20/ptz/wsdl"><ProfileToken>%s</ProfileToken><PresetToken>%s</PresetToken></GotoPreset>`,
+				xmlEscape(c.profileToken), xmlEscape(p.Token)), nil)
+		}
+	}
+	return fmt.Errorf("camera %s has no preset %d", c.Ip, presetId)
+}
+
+// TakeSnapshot fetches the snapshot uri of the media profile and saves the image returned by it
+func (c *OnvifCam) TakeSnapshot(outDir string) (filename string, err error) {
+	if err = c.connect(); err != nil {
+		return "", err
+	}
+	var res struct {
+		URI string `xml:"Body>GetSnapshotUriResponse>MediaUri>Uri"`
+	}
+	err = c.call(c.mediaURL, fmt.Sprintf(`<GetSnapshotUri xmlns="http://www.onvif.org/ver10/media/wsdl"><ProfileToken>%s</ProfileToken></GetSnapshotUri>`,
+		xmlEscape(c.profileToken)), &res)
+	if err != nil {
+		return "", err
+	}
+	resp, err := makeAuthenticatedRequest(c.auth(), "GET", "", res.URI)
+	if err != nil {
+		return "", err
+	}
+	filename = uuid.NewV4().String() + ".jpg"
+	err = saveResponseBuffer(outDir, filename, resp)
+	if err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// GetPresets fetches all presets stored on the camera. Their ids are derived from the presets' tokens.
+func (c *OnvifCam) GetPresets() ([]model.CameraPreset, error) {
+	presets, err := c.presets()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.CameraPreset, 0, len(presets))
+	for _, p := range presets {
+		res = append(res, model.CameraPreset{Name: p.Name, PresetID: onvifPresetID(p.Token)})
+	}
+	return res, nil
+}
+
+type onvifPreset struct {
+	Token string `xml:"token,attr"`
+	Name  string `xml:"Name"`
+}
+
+// presets fetches the presets of the media profile with their tokens
+func (c *OnvifCam) presets() ([]onvifPreset, error) {
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	var res struct {
+		Presets []onvifPreset `xml:"Body>GetPresetsResponse>Preset"`
+	}
+	err := c.call(c.ptzURL, fmt.Sprintf(`<GetPresets xmlns="http://www.onvif.org/ver20/ptz/wsdl"><ProfileToken>%s</ProfileToken></GetPresets>`,
+		xmlEscape(c.profileToken)), &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Presets, nil
+}
+
+// onvifPresetID maps the opaque token of a preset (e.g. "Preset_1") to a preset id. Numeric tokens are used as they
+// are, others are hashed so their ids don't change when other presets are added or removed.
+func onvifPresetID(token string) int {
+	if id, err := strconv.Atoi(token); err == nil && id >= 0 && id < 1<<30 {
+		return id
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(token))
+	return int(h.Sum32()>>2) | 1<<30
+}
+
+// Move moves the camera relative to its current position in the generic translation space
+func (c *OnvifCam) Move(pan, tilt, zoom float64) error {
+	if err := c.connect(); err != nil {
+		return err
+	}
+	return c.call(c.ptzURL, fmt.Sprintf(`<RelativeMove xmlns="http://www.onvif.org/ver20/ptz/wsdl"><ProfileToken>%s</ProfileToken><Translation>`+
+		`<PanTilt xmlns="http://www.onvif.org/ver10/schema" x="%.4f" y="%.4f"/><Zoom xmlns="http://www.onvif.org/ver10/schema" x="%.4f"/>`+
+		`</Translation></RelativeMove>`, xmlEscape(c.profileToken), clamp(pan), clamp(tilt), clamp(zoom)), nil)
+}
+
+// StorePreset saves the current position as preset presetId if it exists, otherwise as a new preset with the token
+// the camera chooses
+func (c *OnvifCam) StorePreset(presetId int, name string) (int, error) {
+	presets, err := c.presets()
+	if err != nil {
+		return 0, err
+	}
+	var token string
+	for _, p := range presets {
+		if onvifPresetID(p.Token) == presetId {
+			token = `<PresetToken>` + xmlEscape(p.Token) + `</PresetToken>`
+		}
+	}
+	var res struct {
+		Token string `xml:"Body>SetPresetResponse>PresetToken"`
+	}
+	err = c.call(c.ptzURL, fmt.Sprintf(`<SetPreset xmlns="http://www.onvif.org/ver20/ptz/wsdl"><ProfileToken>%s</ProfileToken><PresetName>%s</PresetName>%s</SetPreset>`,
+		xmlEscape(c.profileToken), xmlEscape(name), token), &res)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		return presetId, nil
+	}
+	if res.Token == "" {
+		return 0, fmt.Errorf("camera %s didn't return the token of the new preset", c.Ip)
+	}
+	return onvifPresetID(res.Token), nil
+}
+
+// connect looks up the service addresses and the first media profile of the camera if not done yet.
+func (c *OnvifCam) connect() error {
+	if c.profileToken != "" {
+		return nil
+	}
+	var capabilities struct {
+		Media string `xml:"Body>GetCapabilitiesResponse>Capabilities>Media>XAddr"`
+		PTZ   string `xml:"Body>GetCapabilitiesResponse>Capabilities>PTZ>XAddr"`
+	}
+	err := c.call(fmt.Sprintf(onvifDeviceServiceURL, c.Ip), `<GetCapabilities xmlns="http://www.onvif.org/ver10/device/wsdl"><Category>All</Category></GetCapabilities>`, &capabilities)
+	if err != nil {
+		return fmt.Errorf("get capabilities: %w", err)
+	}
+	if capabilities.PTZ == "" || capabilities.Media == "" {
+		return fmt.Errorf("camera %s doesn't provide ptz and media services", c.Ip)
+	}
+	c.ptzURL, c.mediaURL = capabilities.PTZ, capabilities.Media
+
+	var profiles struct {
+		Profiles []struct {
+			Token string `xml:"token,attr"`
+		} `xml:"Body>GetProfilesResponse>Profiles"`
+	}
+	err = c.call(c.mediaURL, `<GetProfiles xmlns="http://www.onvif.org/ver10/media/wsdl"/>`, &profiles)
+	if err != nil {
+		return fmt.Errorf("get profiles: %w", err)
+	}
+	if len(profiles.Profiles) == 0 {
+		return fmt.Errorf("camera %s has no media profiles", c.Ip)
+	}
+	c.profileToken = profiles.Profiles[0].Token
+	return nil
+}
+
+// call sends body wrapped in a soap envelope to url and decodes the response into res if not nil.
+func (c *OnvifCam) call(url string, body string, res interface{}) error {
+	envelope := `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Header>` + c.securityHeader() + `</s:Header>` +
+		`<s:Body>` + body + `</s:Body></s:Envelope>`
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(envelope))
+	if err != nil {
+		return fmt.Errorf("create http request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bts, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var fault struct {
+			Reason string `xml:"Body>Fault>Reason>Text"`
+		}
+		_ = xml.Unmarshal(bts, &fault)
+		return fmt.Errorf("onvif request failed with status %d: %s", resp.StatusCode, fault.Reason)
+	}
+	if res == nil {
+		return nil
+	}
+	return xml.NewDecoder(bytes.NewReader(bts)).Decode(res)
+}
+
+// securityHeader builds a WS-Security UsernameToken header with password digest.
+// Returns an empty string if no credentials are configured.
+func (c *OnvifCam) securityHeader() string {
+	userPassword := strings.SplitN(c.Auth, ":", 2)
+	if len(userPassword) != 2 {
+		return ""
+	}
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	created := time.Now().UTC().Format(time.RFC3339)
+	h := sha1.New()
+	h.Write(nonce)
+	h.Write([]byte(created))
+	h.Write([]byte(userPassword[1]))
+	return `<Security xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd" s:mustUnderstand="1"><UsernameToken>` +
+		`<Username>` + xmlEscape(userPassword[0]) + `</Username>` +
+		`<Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">` + base64.StdEncoding.EncodeToString(h.Sum(nil)) + `</Password>` +
+		`<Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">` + base64.StdEncoding.EncodeToString(nonce) + `</Nonce>` +
+		`<Created xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">` + created + `</Created>` +
+		`</UsernameToken></Security>`
+}
+
+func (c *OnvifCam) auth() *string {
+	if c.Auth == "" {
+		return nil
+	}
+	return &c.Auth
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package camera
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeOnvifCam answers the soap requests of OnvifCam with presets identified by tokens and records the requests
+func fakeOnvifCam(t *testing.T, tokens ...string) (cam *OnvifCam, requests *[]string) {
+	requests = &[]string{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body := string(b)
+		*requests = append(*requests, body)
+		var res string
+		switch {
+		case strings.Contains(body, "<GetCapabilities"):
+			res = `<GetCapabilitiesResponse><Capabilities><Media><XAddr>` + srv.URL + `/media</XAddr></Media>` +
+				`<PTZ><XAddr>` + srv.URL + `/ptz</XAddr></PTZ></Capabilities></GetCapabilitiesResponse>`
+		case strings.Contains(body, "<GetProfiles"):
+			res = `<GetProfilesResponse><Profiles token="profile_1"/></GetProfilesResponse>`
+		case strings.Contains(body, "<GetPresets"):
+			res = `<GetPresetsResponse>`
+			for _, token := range tokens {
+				res += `<Preset token="` + token + `"><Name>` + token + `</Name></Preset>`
+			}
+			res += `</GetPresetsResponse>`
+		case strings.Contains(body, "<SetPreset"):
+			token := "Preset_new"
+			if _, requested, ok := strings.Cut(body, "<PresetToken>"); ok {
+				token, _, _ = strings.Cut(requested, "<")
+			}
+			res = `<SetPresetResponse><PresetToken>` + token + `</PresetToken></SetPresetResponse>`
+		}
+		_, _ = w.Write([]byte(`<Envelope><Body>` + res + `</Body></Envelope>`))
+	}))
+	t.Cleanup(srv.Close)
+	return NewOnvifCam(strings.TrimPrefix(srv.URL, "http://"), ""), requests
+}
+
+func TestOnvifPresets(t *testing.T) {
+	cam, requests := fakeOnvifCam(t, "2", "Preset_1")
+	presets, err := cam.GetPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presets) != 2 || presets[0].PresetID != 2 || presets[1].PresetID != onvifPresetID("Preset_1") {
+		t.Fatalf("unexpected presets %v", presets)
+	}
+
+	if err = cam.SetPreset(presets[1].PresetID); err != nil {
+		t.Fatal(err)
+	}
+	if last := (*requests)[len(*requests)-1]; !strings.Contains(last, "<PresetToken>Preset_1</PresetToken>") {
+		t.Errorf("expected the token of the preset to be sent, got %s", last)
+	}
+	if err = cam.SetPreset(3); err == nil {
+		t.Error("expected unknown presets to be rejected")
+	}
+
+	if id, err := cam.StorePreset(2, "Board"); err != nil || id != 2 {
+		t.Errorf("expected existing presets to be overwritten, got %d (%v)", id, err)
+	}
+	if last := (*requests)[len(*requests)-1]; !strings.Contains(last, "<PresetToken>2</PresetToken>") {
+		t.Errorf("expected the token of the existing preset to be sent, got %s", last)
+	}
+	if id, err := cam.StorePreset(5, "Desk"); err != nil || id != onvifPresetID("Preset_new") {
+		t.Errorf("expected the id of the token chosen by the camera, got %d (%v)", id, err)
+	}
+	if last := (*requests)[len(*requests)-1]; strings.Contains(last, "<PresetToken>") {
+		t.Errorf("expected new presets to be stored without token, got %s", last)
+	}
+}
+
+func TestOnvifPresetID(t *testing.T) {
+	if onvifPresetID("12") != 12 {
+		t.Error("expected numeric tokens to be used as ids")
+	}
+	if id := onvifPresetID("Preset_1"); id < 1<<30 || id != onvifPresetID("Preset_1") || id == onvifPresetID("Preset_2") {
+		t.Errorf("expected stable ids for tokens, got %d", id)
+	}
+}
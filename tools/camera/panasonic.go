@@ -2,6 +2,7 @@ package camera
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/TUM-Dev/gocast/model"
 	uuid "github.com/satori/go.uuid"
@@ -65,3 +66,77 @@ func (c PanasonicCam) GetPresets() ([]model.CameraPreset, error) {
 	}
 	return presets, nil
 }
+
+// absolute position limits of the HE40 series, see the "HD Integrated Camera Interface Specifications"
+const (
+	panasonicPanMin  = 0x2D09
+	panasonicPanMax  = 0xD2F5
+	panasonicTiltMin = 0x5555
+	panasonicTiltMax = 0x8E38
+	panasonicZoomMin = 0x555
+	panasonicZoomMax = 0xFFF
+)
+
+// Move queries the current position of the camera and moves it relative to it
+func (c PanasonicCam) Move(pan, tilt, zoom float64) error {
+	if pan != 0 || tilt != 0 {
+		resp, err := c.ptzCommand("APC")
+		if err != nil {
+			return err
+		}
+		var curPan, curTilt int
+		if _, err = fmt.Sscanf(resp, "aPC%04X%04X", &curPan, &curTilt); err != nil {
+			return fmt.Errorf("parse position %q: %w", resp, err)
+		}
+		curPan = moveWithin(curPan, pan, panasonicPanMin, panasonicPanMax)
+		curTilt = moveWithin(curTilt, tilt, panasonicTiltMin, panasonicTiltMax)
+		if _, err = c.ptzCommand(fmt.Sprintf("APC%04X%04X", curPan, curTilt)); err != nil {
+			return err
+		}
+	}
+	if zoom != 0 {
+		resp, err := c.ptzCommand("GZ")
+		if err != nil {
+			return err
+		}
+		var curZoom int
+		if _, err = fmt.Sscanf(resp, "gz%03X", &curZoom); err != nil {
+			return fmt.Errorf("parse zoom %q: %w", resp, err)
+		}
+		curZoom = moveWithin(curZoom, zoom, panasonicZoomMin, panasonicZoomMax)
+		if _, err = c.ptzCommand(fmt.Sprintf("AXZ%03X", curZoom)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorePreset saves the current position in the preset memory slot presetId. Panasonic cameras don't store names.
+func (c PanasonicCam) StorePreset(presetId int, _ string) (int, error) {
+	if presetId < 0 || presetId > 99 {
+		return 0, fmt.Errorf("preset %d out of range [0, 99]", presetId)
+	}
+	_, err := c.ptzCommand(fmt.Sprintf("M%02d", presetId))
+	return presetId, err
+}
+
+// ptzCommand sends cmd (without leading #) to the ptz interface of the camera and returns the response
+func (c PanasonicCam) ptzCommand(cmd string) (string, error) {
+	resp, err := makeAuthenticatedRequest(c.Auth, "GET", "", fmt.Sprintf("%s/aw_ptz?cmd=%%23%s&res=1", fmt.Sprintf(panasonicBaseUrl, c.Ip), cmd))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.String()), nil
+}
+
+// moveWithin moves cur by delta * (max-min) and keeps the result within [min, max]
+func moveWithin(cur int, delta float64, min int, max int) int {
+	cur += int(clamp(delta) * float64(max-min))
+	if cur < min {
+		return min
+	}
+	if cur > max {
+		return max
+	}
+	return cur
+}
@@ -0,0 +1,184 @@
+package camera
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+)
+
+/**
+*
+* Compatible cameras:
+* - Sony SRG/BRC series (VISCA over IP)
+* - cameras implementing the Sony VISCA over IP protocol on udp port 52381 (e.g. PTZOptics, Lumens)
+*
+**/
+
+const (
+	viscaDefaultPort = "52381"
+	viscaTimeout     = time.Second * 3
+
+	viscaPayloadCommand = 0x0100
+	viscaPayloadInquiry = 0x0110
+	viscaPayloadControl = 0x0200
+
+	// relative pan and tilt limits of the Sony SRG-300 series
+	viscaPanRange  = 0x0990
+	viscaTiltRange = 0x0510
+	viscaZoomMax   = 0x4000
+)
+
+var errViscaCommand = errors.New("visca command failed")
+
+// ViscaCam represents PTZ cameras controlled via VISCA over IP
+type ViscaCam struct {
+	Ip string // ip of the camera, optionally with port (e.g. "10.0.0.1:52381")
+}
+
+// NewViscaCam Acts as a constructor for VISCA cameras.
+// ip: the ip address of the camera, the default port 52381 is used if none is given
+func NewViscaCam(ip string) *ViscaCam {
+	return &ViscaCam{Ip: ip}
+}
+
+// SetPreset recalls the preset specified by presetId
+func (c ViscaCam) SetPreset(presetId int) error {
+	if presetId < 0 || presetId > 0xFF {
+		return fmt.Errorf("preset %d out of range", presetId)
+	}
+	_, err := c.send(viscaPayloadCommand, []byte{0x81, 0x01, 0x04, 0x3F, 0x02, byte(presetId), 0xFF})
+	return err
+}
+
+// TakeSnapshot is not supported by VISCA
+func (c ViscaCam) TakeSnapshot(string) (string, error) {
+	return "", ErrUnsupported
+}
+
+// GetPresets returns stubs for the first ten preset slots as VISCA can't list presets
+func (c ViscaCam) GetPresets() ([]model.CameraPreset, error) {
+	presets := make([]model.CameraPreset, 10)
+	for i := range presets {
+		presets[i].PresetID = i
+		presets[i].Name = fmt.Sprintf("Preset #%2d", i)
+		if i == 0 {
+			presets[i].Name = "Home"
+		}
+	}
+	return presets, nil
+}
+
+// Move moves the camera relative to its current position
+func (c ViscaCam) Move(pan, tilt, zoom float64) error {
+	if pan != 0 || tilt != 0 {
+		cmd := []byte{0x81, 0x01, 0x06, 0x03, 0x18, 0x14}
+		cmd = append(cmd, viscaNibbles(uint16(int16(clamp(pan)*viscaPanRange)))...)
+		cmd = append(cmd, viscaNibbles(uint16(int16(clamp(tilt)*viscaTiltRange)))...)
+		cmd = append(cmd, 0xFF)
+		if _, err := c.send(viscaPayloadCommand, cmd); err != nil {
+			return err
+		}
+	}
+	if zoom != 0 {
+		// VISCA has no relative zoom, read the current position and set the new one directly.
+		res, err := c.send(viscaPayloadInquiry, []byte{0x81, 0x09, 0x04, 0x47, 0xFF})
+		if err != nil {
+			return err
+		}
+		if len(res) != 7 {
+			return fmt.Errorf("unexpected zoom inquiry response: % X", res)
+		}
+		cur := int(viscaFromNibbles(res[2:6])) + int(clamp(zoom)*viscaZoomMax)
+		if cur < 0 {
+			cur = 0
+		}
+		if cur > viscaZoomMax {
+			cur = viscaZoomMax
+		}
+		cmd := append([]byte{0x81, 0x01, 0x04, 0x47}, viscaNibbles(uint16(cur))...)
+		if _, err = c.send(viscaPayloadCommand, append(cmd, 0xFF)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorePreset saves the current position in preset slot presetId. VISCA doesn't store names.
+func (c ViscaCam) StorePreset(presetId int, _ string) (int, error) {
+	if presetId < 0 || presetId > 0xFF {
+		return 0, fmt.Errorf("preset %d out of range", presetId)
+	}
+	_, err := c.send(viscaPayloadCommand, []byte{0x81, 0x01, 0x04, 0x3F, 0x01, byte(presetId), 0xFF})
+	return presetId, err
+}
+
+// send resets the sequence number of the camera, sends payload and waits for the completion message.
+// The completion message is returned.
+func (c ViscaCam) send(payloadType uint16, payload []byte) ([]byte, error) {
+	addr := c.Ip
+	if !strings.Contains(addr, ":") {
+		addr = net.JoinHostPort(addr, viscaDefaultPort)
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(viscaTimeout)); err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.Write(viscaPacket(viscaPayloadControl, 0, []byte{0x01})); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 64)
+	if _, err = conn.Read(buf); err != nil {
+		return nil, fmt.Errorf("reset sequence number: %w", err)
+	}
+
+	if _, err = conn.Write(viscaPacket(payloadType, 1, payload)); err != nil {
+		return nil, err
+	}
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		if n < 11 {
+			continue
+		}
+		msg := buf[8:n]
+		switch msg[1] & 0xF0 {
+		case 0x40: // ACK, wait for completion
+			continue
+		case 0x50:
+			return msg, nil
+		case 0x60:
+			return nil, fmt.Errorf("%w: % X", errViscaCommand, msg)
+		}
+	}
+}
+
+// viscaPacket prepends the VISCA over IP header to payload
+func viscaPacket(payloadType uint16, seq uint32, payload []byte) []byte {
+	p := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint16(p[0:2], payloadType)
+	binary.BigEndian.PutUint16(p[2:4], uint16(len(payload)))
+	binary.BigEndian.PutUint32(p[4:8], seq)
+	return append(p, payload...)
+}
+
+// viscaNibbles splits v into four bytes holding one nibble each (e.g. 0x1234 -> 01 02 03 04)
+func viscaNibbles(v uint16) []byte {
+	return []byte{byte(v>>12) & 0xF, byte(v>>8) & 0xF, byte(v>>4) & 0xF, byte(v) & 0xF}
+}
+
+// viscaFromNibbles is the inverse of viscaNibbles
+func viscaFromNibbles(b []byte) uint16 {
+	return uint16(b[0]&0xF)<<12 | uint16(b[1]&0xF)<<8 | uint16(b[2]&0xF)<<4 | uint16(b[3]&0xF)
+}
@@ -0,0 +1,68 @@
+package camera
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// fakeViscaCam acknowledges and completes every command it receives and records the payloads
+func fakeViscaCam(t *testing.T, completion []byte) (addr string, received chan []byte) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	received = make(chan []byte, 10)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			payload := append([]byte{}, buf[8:n]...)
+			if buf[0] == 0x02 { // control command
+				_, _ = conn.WriteTo(viscaPacket(0x0201, 0, []byte{0x01}), from)
+				continue
+			}
+			received <- payload
+			_, _ = conn.WriteTo(viscaPacket(0x0111, 1, []byte{0x90, 0x41, 0xFF}), from)
+			_, _ = conn.WriteTo(viscaPacket(0x0111, 1, completion), from)
+		}
+	}()
+	return conn.LocalAddr().String(), received
+}
+
+func TestViscaSetPreset(t *testing.T) {
+	addr, received := fakeViscaCam(t, []byte{0x90, 0x51, 0xFF})
+	err := NewViscaCam(addr).SetPreset(3)
+	if err != nil {
+		t.Fatalf("SetPreset returned error: %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, []byte{0x81, 0x01, 0x04, 0x3F, 0x02, 0x03, 0xFF}) {
+		t.Errorf("unexpected payload: % X", got)
+	}
+}
+
+func TestViscaCommandError(t *testing.T) {
+	addr, _ := fakeViscaCam(t, []byte{0x90, 0x61, 0x41, 0xFF})
+	_, err := NewViscaCam(addr).StorePreset(1, "")
+	if err == nil {
+		t.Fatal("expected error for failed command")
+	}
+}
+
+func TestViscaNibbles(t *testing.T) {
+	if got := viscaNibbles(0x1A2B); !bytes.Equal(got, []byte{0x01, 0x0A, 0x02, 0x0B}) {
+		t.Errorf("viscaNibbles(0x1A2B) = % X", got)
+	}
+	if got := viscaFromNibbles(viscaNibbles(0xFEDC)); got != 0xFEDC {
+		t.Errorf("viscaFromNibbles(viscaNibbles(0xFEDC)) = %X", got)
+	}
+	// negative relative positions are encoded as two's complement
+	minusOne := int16(-1)
+	if got := viscaNibbles(uint16(minusOne)); !bytes.Equal(got, []byte{0x0F, 0x0F, 0x0F, 0x0F}) {
+		t.Errorf("viscaNibbles(-1) = % X", got)
+	}
+}
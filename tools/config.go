@@ -160,9 +160,16 @@ type Config struct {
 		} `yaml:"matrix"`
 	} `yaml:"alerts"`
 	VoiceService *struct {
-		Host string `yaml:"host"`
-		Port string `yaml:"port"`
+		Host  string `yaml:"host"`
+		Port  string `yaml:"port"`
+		Token string `yaml:"token"` // authenticates the voice-service and TUM-Live to each other
+		TLS   bool   `yaml:"tls"`   // connect to the voice-service with TLS
 	}
+	GRPC *struct {
+		CACert    string   `yaml:"caCert"` // CA issuing the certificates of TUM-Live and workers, created with caKey if it doesn't exist
+		CAKey     string   `yaml:"caKey"`
+		Hostnames []string `yaml:"hostnames"` // hostnames or IPs workers and the voice-service connect to TUM-Live with
+	} `yaml:"grpc"`
 	IngestBase  string  `yaml:"ingestBase"`
 	WebUrl      string  `yaml:"webUrl"`
 	WorkerToken string  `yaml:"workerToken"` // used for workers to join the worker pool
@@ -171,10 +178,18 @@ type Config struct {
 		Host   string `yaml:"host"`
 		ApiKey string `yaml:"apiKey"`
 	} `yaml:"meili"`
-	VodURLTemplate string `yaml:"vodURLTemplate"`
-	CanonicalURL   string `yaml:"canonicalURL"`
-	WikiURL        string `yaml:"wikiURL"`
-	RtmpProxyURL   string `yaml:"rtmpProxyURL"`
+	Stats          StatsConfig `yaml:"stats"`
+	VodURLTemplate string      `yaml:"vodURLTemplate"`
+	CanonicalURL   string      `yaml:"canonicalURL"`
+	WikiURL        string      `yaml:"wikiURL"`
+	RtmpProxyURL   string      `yaml:"rtmpProxyURL"`
+}
+
+// StatsConfig controls how much detail viewer statistics reveal
+type StatsConfig struct {
+	MinGroupSize  int     `yaml:"minGroupSize"`  // buckets with fewer viewers are merged or suppressed, 0 disables this
+	Noise         float64 `yaml:"noise"`         // scale of the laplace noise added to counts, 0 disables noise
+	RetentionDays int     `yaml:"retentionDays"` // raw stats older than this are aggregated and deleted, 0 keeps them forever
 }
 
 type MailConfig struct {
@@ -0,0 +1,339 @@
+// Package grpcauth issues the certificates TUM-Live and its workers authenticate each other with over gRPC
+// and provides the interceptors checking them.
+package grpcauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// TUMLiveName is the common name of TUM-Live's certificate. Workers only accept calls from it.
+	TUMLiveName = "tumlive"
+
+	// WorkerCertValidity is how long certificates of workers are valid, workers renew them before they expire
+	WorkerCertValidity = 7 * 24 * time.Hour
+
+	identityValidity = 30 * 24 * time.Hour
+	caValidity       = 10 * 365 * 24 * time.Hour
+)
+
+// CA is the certificate authority of TUM-Live and its workers
+type CA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+}
+
+// LoadOrCreateCA loads the CA from certFile and keyFile. Both are created if they don't exist yet.
+func LoadOrCreateCA(certFile, keyFile string) (*CA, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return createCA(certFile, keyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	c, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("load CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(c.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := c.PrivateKey.(crypto.Signer)
+	if !ok || !cert.IsCA {
+		return nil, errors.New("load CA: not a CA certificate")
+	}
+	return &CA{cert: cert, key: key, certPEM: certPEM}, nil
+}
+
+func createCA(certFile, keyFile string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "TUM-Live gRPC CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		return nil, err
+	}
+	return &CA{cert: cert, key: key, certPEM: certPEM}, nil
+}
+
+// CertPEM returns the PEM encoded certificate of the CA, workers and the voice-service verify TUM-Live with it
+func (ca *CA) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// Pool returns a pool containing the CA's certificate
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// SignCSR issues a certificate for the key of the PEM encoded certificate signing request. The subject
+// requested is ignored, the certificate is issued for name only.
+func (ca *CA) SignCSR(csrPEM []byte, name string, validity time.Duration) ([]byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("no PEM encoded certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("check csr signature: %w", err)
+	}
+	der, err := ca.issue(csr.PublicKey, name, []string{name}, validity)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// issue creates a certificate usable for both sides of a connection
+func (ca *CA) issue(pub crypto.PublicKey, name string, hosts []string, validity time.Duration) ([]byte, error) {
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour), // tolerate clock skew
+		NotAfter:     time.Now().Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	return x509.CreateCertificate(rand.Reader, template, ca.cert, pub, ca.key)
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// NeedsRenewal returns whether two thirds of the validity of cert have passed
+func NeedsRenewal(cert *x509.Certificate, now time.Time) bool {
+	validity := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotBefore.Add(validity * 2 / 3))
+}
+
+// Identity is TUM-Live's certificate, it's reissued by the CA before it expires
+type Identity struct {
+	ca    *CA
+	hosts []string
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+}
+
+// NewIdentity returns TUM-Live's identity, valid for the hostnames or IPs workers and the voice-service connect to
+func (ca *CA) NewIdentity(hosts []string) *Identity {
+	return &Identity{ca: ca, hosts: hosts}
+}
+
+// Certificate returns the current certificate of TUM-Live
+func (i *Identity) Certificate() (*tls.Certificate, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.cert != nil && !NeedsRenewal(i.cert.Leaf, time.Now()) {
+		return i.cert, nil
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := i.ca.issue(key.Public(), TUMLiveName, i.hosts, identityValidity)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	i.cert = &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+	return i.cert, nil
+}
+
+// ServerTLSConfig returns the config of servers workers or the voice-service connect to
+func (i *Identity) ServerTLSConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return i.Certificate()
+		},
+		ClientAuth: clientAuth,
+		ClientCAs:  i.ca.Pool(),
+	}
+}
+
+// ClientTLSConfig returns the config for connecting to the worker with the id workerID
+func (i *Identity) ClientTLSConfig(workerID string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: workerID,
+		RootCAs:    i.ca.Pool(),
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return i.Certificate()
+		},
+	}
+}
+
+// PeerName returns the common name of the verified client certificate of the caller
+func PeerName(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName, true
+}
+
+// workerRequest is implemented by all requests workers send, except for joining the pool
+type workerRequest interface {
+	GetWorkerID() string
+}
+
+// checkWorker verifies the caller's certificate was issued for the worker the request is from
+func checkWorker(ctx context.Context, req any) error {
+	name, ok := PeerName(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "client certificate required")
+	}
+	if r, ok := req.(workerRequest); ok && r.GetWorkerID() != name {
+		return status.Error(codes.PermissionDenied, "certificate was issued for another worker")
+	}
+	return nil
+}
+
+// WorkerUnaryInterceptor requires a worker certificate for all methods except the public ones
+func WorkerUnaryInterceptor(public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for _, method := range public {
+			if info.FullMethod == method {
+				return handler(ctx, req)
+			}
+		}
+		if err := checkWorker(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// WorkerStreamInterceptor requires a worker certificate matching every message of a stream
+func WorkerStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := PeerName(ss.Context()); !ok {
+			return status.Error(codes.Unauthenticated, "client certificate required")
+		}
+		return handler(srv, workerStream{ss})
+	}
+}
+
+type workerStream struct {
+	grpc.ServerStream
+}
+
+func (s workerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkWorker(s.Context(), m)
+}
+
+// TokenUnaryInterceptor requires callers to send token as bearer token, used for services without certificates
+func TokenUnaryInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, auth := range md.Get("authorization") {
+			sent, found := strings.CutPrefix(auth, "Bearer ")
+			if found && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1 {
+				return handler(ctx, req)
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+}
+
+// TokenCredentials sends Token as bearer token with every call
+type TokenCredentials struct {
+	Token  string
+	Secure bool // only send the token over TLS
+}
+
+func (t TokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.Token}, nil
+}
+
+func (t TokenCredentials) RequireTransportSecurity() bool {
+	return t.Secure
+}
@@ -0,0 +1,128 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newCSR(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: TUMLiveName}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	created, err := LoadOrCreateCA(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := LoadOrCreateCA(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ca.CertPEM()) != string(created.CertPEM()) {
+		t.Fatal("expected the created CA to be loaded")
+	}
+
+	certPEM, err := ca.SignCSR(newCSR(t), "worker-1", WorkerCertValidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Subject.CommonName != "worker-1" {
+		t.Errorf("expected requested name to be ignored, got %s", cert.Subject.CommonName)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:   "worker-1",
+		Roots:     created.Pool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		t.Errorf("expected certificate to be valid for worker-1: %v", err)
+	}
+
+	if _, err := ca.SignCSR([]byte("not a csr"), "worker-1", WorkerCertValidity); err == nil {
+		t.Error("expected invalid csr to be rejected")
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := LoadOrCreateCA(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := ca.NewIdentity([]string{"live.example.com", "10.0.0.1"})
+	cert, err := identity.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := identity.Certificate(); again != cert {
+		t.Error("expected certificate to be reused while valid")
+	}
+	for _, host := range []string{"live.example.com", "10.0.0.1"} {
+		if err := cert.Leaf.VerifyHostname(host); err != nil {
+			t.Errorf("expected certificate to be valid for %s: %v", host, err)
+		}
+	}
+	if NeedsRenewal(cert.Leaf, time.Now()) || !NeedsRenewal(cert.Leaf, cert.Leaf.NotAfter.Add(-time.Hour)) {
+		t.Error("expected renewal after two thirds of the validity")
+	}
+}
+
+type heartBeat struct {
+	workerID string
+}
+
+func (h heartBeat) GetWorkerID() string {
+	return h.workerID
+}
+
+func TestCheckWorker(t *testing.T) {
+	withPeer := func(name string) context.Context {
+		state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: name}}}}}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+	tests := map[string]struct {
+		ctx      context.Context
+		req      any
+		expected codes.Code
+	}{
+		"no certificate":      {context.Background(), heartBeat{"worker-1"}, codes.Unauthenticated},
+		"other worker":        {withPeer("worker-2"), heartBeat{"worker-1"}, codes.PermissionDenied},
+		"matching worker":     {withPeer("worker-1"), heartBeat{"worker-1"}, codes.OK},
+		"request without id":  {withPeer("worker-1"), struct{}{}, codes.OK},
+		"no certificate, any": {context.Background(), struct{}{}, codes.Unauthenticated},
+	}
+	for name, test := range tests {
+		if code := status.Code(checkWorker(test.ctx, test.req)); code != test.expected {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, code)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	guestLinkSubject    = "guest-link"    // token in the url of a guest link
+	guestSessionSubject = "guest-session" // cookie of a guest who redeemed a link
+	guestCookie         = "guest"
+)
+
+// GuestClaims are the claims of guest link tokens and guest sessions
+type GuestClaims struct {
+	*jwt.RegisteredClaims
+	GuestLinkID uint
+	Email       string `json:",omitempty"` // email the guest entered when redeeming the link
+}
+
+// GuestLinkToken returns the signed token of the guest link that is put into its url
+func GuestLinkToken(link model.GuestLink) (string, error) {
+	return signGuestClaims(link, guestLinkSubject, "")
+}
+
+// ParseGuestLinkToken returns the id of the guest link of a token created by GuestLinkToken
+func ParseGuestLinkToken(token string) (uint, error) {
+	claims, err := parseGuestClaims(token, guestLinkSubject)
+	if err != nil {
+		return 0, err
+	}
+	return claims.GuestLinkID, nil
+}
+
+// StartGuestSession lets the client watch what the link grants access to until it expires
+func StartGuestSession(c *gin.Context, link model.GuestLink, email string) error {
+	token, err := signGuestClaims(link, guestSessionSubject, email)
+	if err != nil {
+		return err
+	}
+	c.SetCookie(guestCookie, token, int(time.Until(link.ExpiresAt).Seconds()), "/", "", CookieSecure, true)
+	return nil
+}
+
+func signGuestClaims(link model.GuestLink, subject string, email string) (string, error) {
+	t := jwt.New(jwt.GetSigningMethod("RS256"))
+	t.Claims = &GuestClaims{
+		RegisteredClaims: &jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(link.ExpiresAt),
+		},
+		GuestLinkID: link.ID,
+		Email:       email,
+	}
+	return t.SignedString(Cfg.GetJWTKey())
+}
+
+func parseGuestClaims(token string, subject string) (*GuestClaims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &GuestClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return Cfg.GetJWTKey().Public(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims := parsed.Claims.(*GuestClaims)
+	if !parsed.Valid || claims.RegisteredClaims == nil || claims.Subject != subject {
+		return nil, errors.New("invalid guest token")
+	}
+	return claims, nil
+}
+
+// InitGuest adds the guest link of a guest session to the context. The link only grants watching, everything else
+// still requires an account.
+func InitGuest(daoWrapper dao.DaoWrapper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		foundContext, exists := c.Get("TUMLiveContext")
+		if !exists {
+			return
+		}
+		tumLiveContext := foundContext.(TUMLiveContext)
+		cookie, err := c.Cookie(guestCookie)
+		if err != nil {
+			return
+		}
+		claims, err := parseGuestClaims(cookie, guestSessionSubject)
+		var link model.GuestLink
+		if err == nil {
+			link, err = daoWrapper.GuestLinksDao.GetGuestLinkByID(c, claims.GuestLinkID)
+		}
+		if err == nil {
+			err = link.Check(time.Now())
+		}
+		if err != nil {
+			c.SetCookie(guestCookie, "", -1, "/", "", CookieSecure, true)
+			return
+		}
+		tumLiveContext.Guest, tumLiveContext.GuestEmail = &link, claims.Email
+		c.Set("TUMLiveContext", tumLiveContext)
+	}
+}
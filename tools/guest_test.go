@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+func TestGuestLinkToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwtKey = key
+	defer func() { jwtKey = nil }()
+
+	link := model.GuestLink{Model: gorm.Model{ID: 12}, ExpiresAt: time.Now().Add(time.Hour)}
+	token, err := GuestLinkToken(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := ParseGuestLinkToken(token); err != nil || id != link.ID {
+		t.Errorf("expected link %d, got %d (%v)", link.ID, id, err)
+	}
+
+	session, err := signGuestClaims(link, guestSessionSubject, "guest@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseGuestLinkToken(session); err == nil {
+		t.Error("expected guest sessions not to be accepted as guest links")
+	}
+	if claims, err := parseGuestClaims(session, guestSessionSubject); err != nil || claims.Email != "guest@example.com" {
+		t.Errorf("unexpected session claims %v (%v)", claims, err)
+	}
+
+	link.ExpiresAt = time.Now().Add(-time.Minute)
+	expired, _ := GuestLinkToken(link)
+	if _, err = ParseGuestLinkToken(expired); err == nil {
+		t.Error("expected expired token to be rejected")
+	}
+}
+
+func TestGuestPlaylistsNotDownloadable(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwtKey = key
+	defer func() { jwtKey = nil }()
+
+	course := model.Course{Model: gorm.Model{ID: 2}, DownloadsEnabled: true}
+	allowsDownload := func(c TUMLiveContext) bool {
+		stream := model.Stream{Model: gorm.Model{ID: 3}, CourseID: 2, Recording: true, PlaylistUrl: "https://edge.example.com/vod/course/3/playlist.m3u8"}
+		c.Course, c.Stream = &course, &stream
+		if err := SetSignedPlaylists(&stream, c.User, c.MayDownloadStream()); err != nil {
+			t.Fatal(err)
+		}
+		playlist, _ := url.Parse(stream.PlaylistUrl)
+		claims := &JWTPlaylistClaims{}
+		if _, err := jwt.ParseWithClaims(playlist.Query().Get("jwt"), claims, func(*jwt.Token) (interface{}, error) {
+			return key.Public(), nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return claims.Download
+	}
+
+	if allowsDownload(TUMLiveContext{Guest: &model.GuestLink{}}) {
+		t.Error("expected guests not to be allowed to download")
+	}
+	if !allowsDownload(TUMLiveContext{}) {
+		t.Error("expected downloads of the course to be allowed")
+	}
+	if !allowsDownload(TUMLiveContext{User: &model.User{Model: gorm.Model{ID: 5}}, Guest: &model.GuestLink{}}) {
+		t.Error("expected users with a guest session to be allowed to download")
+	}
+}
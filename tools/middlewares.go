@@ -160,7 +160,7 @@ func InitCourse(wrapper dao.DaoWrapper) gin.HandlerFunc {
 			return
 		}
 		// check if course is accessible by user:
-		if course.Visibility == "public" || course.Visibility == "hidden" || (tumLiveContext.User != nil && tumLiveContext.User.IsEligibleToWatchCourse(course)) {
+		if course.Visibility == "public" || course.Visibility == "hidden" || (tumLiveContext.User != nil && tumLiveContext.User.IsEligibleToWatchCourse(course)) || tumLiveContext.Guest.AllowsCourse(course) {
 			tumLiveContext.Course = &course
 			c.Set("TUMLiveContext", tumLiveContext)
 		} else if tumLiveContext.User == nil {
@@ -217,7 +217,7 @@ func InitStream(wrapper dao.DaoWrapper) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		if course.Visibility != "public" && course.Visibility != "hidden" {
+		if course.Visibility != "public" && course.Visibility != "hidden" && !tumLiveContext.Guest.AllowsStream(stream) {
 			if tumLiveContext.User == nil {
 				c.Redirect(http.StatusFound, "/login?return="+url.QueryEscape(c.Request.RequestURI))
 				c.Abort()
@@ -277,7 +277,7 @@ func InitStreamRealtime() realtime.SubscriptionMiddleware {
 		if stream.Private && (tumLiveContext.User == nil || !tumLiveContext.User.IsAdminOfCourse(course)) {
 			return realtime.NewError(http.StatusForbidden, "forbidden to see course")
 		}
-		if course.Visibility != "public" && course.Visibility != "hidden" {
+		if course.Visibility != "public" && course.Visibility != "hidden" && !tumLiveContext.Guest.AllowsStream(stream) {
 			if tumLiveContext.User == nil {
 				return realtime.NewError(http.StatusForbidden, "course only visible for logged in users")
 			} else if tumLiveContext.User == nil || !tumLiveContext.User.IsEligibleToWatchCourse(course) {
@@ -308,22 +308,31 @@ func OwnerOfCourse(c *gin.Context) {
 // AdminOfCourse checks if the user is an admin of the course or admin.
 // If not, aborts with status Forbidden.
 func AdminOfCourse(c *gin.Context) {
-	foundContext, exists := c.Get("TUMLiveContext")
-	if !exists {
-		sentry.CaptureException(errors.New("context should exist but doesn't"))
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
-	tumLiveContext := foundContext.(TUMLiveContext)
-	if tumLiveContext.User == nil {
-		c.Redirect(http.StatusFound, "/login?return="+url.QueryEscape(c.Request.RequestURI))
-		c.Abort()
-		return
-	}
-	if tumLiveContext.User.IsAdminOfCourse(*tumLiveContext.Course) {
-		return
+	manageCourse(c)
+}
+
+var manageCourse = HasCoursePermission(model.CoursePermissionManage)
+
+// HasCoursePermission aborts requests of users whose role in the course doesn't grant the permission p
+func HasCoursePermission(p model.CoursePermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		foundContext, exists := c.Get("TUMLiveContext")
+		if !exists {
+			sentry.CaptureException(errors.New("context should exist but doesn't"))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		tumLiveContext := foundContext.(TUMLiveContext)
+		if tumLiveContext.User == nil {
+			c.Redirect(http.StatusFound, "/login?return="+url.QueryEscape(c.Request.RequestURI))
+			c.Abort()
+			return
+		}
+		if tumLiveContext.User.HasCoursePermission(*tumLiveContext.Course, p) {
+			return
+		}
+		c.AbortWithStatus(http.StatusForbidden)
 	}
-	c.AbortWithStatus(http.StatusForbidden) // user is not admin of course
 }
 
 func AtLeastLecturer(c *gin.Context) {
@@ -382,6 +391,10 @@ type TUMLiveContext struct {
 	Course        *model.Course
 	Stream        *model.Stream
 	SamlSubjectID *string
+
+	// Guest is the guest link of a client without account that redeemed it, nil otherwise
+	Guest      *model.GuestLink
+	GuestEmail string
 }
 
 func (c *TUMLiveContext) UserIsAdmin() bool {
@@ -390,3 +403,20 @@ func (c *TUMLiveContext) UserIsAdmin() bool {
 	}
 	return c.User.IsAdminOfCourse(*c.Course)
 }
+
+// MayDownloadStream returns whether the playlists of the stream may be signed for downloads. Guest links only
+// grant watching.
+func (c *TUMLiveContext) MayDownloadStream() bool {
+	if c.Stream == nil || c.Course == nil || (c.User == nil && c.Guest != nil) {
+		return false
+	}
+	return (c.Course.DownloadsEnabled || c.UserHasCoursePermission(model.CoursePermissionDownload)) && c.Stream.IsDownloadable()
+}
+
+// UserHasCoursePermission returns whether the user's role in the course grants the permission p
+func (c *TUMLiveContext) UserHasCoursePermission(p model.CoursePermission) bool {
+	if c.User == nil || c.Course == nil {
+		return false
+	}
+	return c.User.HasCoursePermission(*c.Course, p)
+}
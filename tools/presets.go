@@ -19,6 +19,8 @@ type PresetUtility interface {
 	UsePreset(model.CameraPreset)
 	TakeSnapshot(model.CameraPreset)
 	ProvideCamera(model.CameraType, string) (camera.Cam, error)
+	MoveCamera(lectureHall model.LectureHall, pan, tilt, zoom float64) error
+	StorePreset(lectureHall model.LectureHall, presetID int, name string) (model.CameraPreset, error)
 }
 
 type presetUtility struct {
@@ -35,6 +37,10 @@ func (p presetUtility) ProvideCamera(ctype model.CameraType, ip string) (camera.
 		return camera.NewAxisCam(ip, Cfg.Auths.CamAuth), nil
 	case model.Panasonic:
 		return camera.NewPanasonicCam(ip, nil), nil
+	case model.ONVIF:
+		return camera.NewOnvifCam(ip, Cfg.Auths.CamAuth), nil
+	case model.VISCA:
+		return camera.NewViscaCam(ip), nil
 	}
 	return nil, errors.New("invalid camera type")
 }
@@ -114,3 +120,35 @@ func (p presetUtility) TakeSnapshot(preset model.CameraPreset) {
 		return
 	}
 }
+
+// MoveCamera moves the camera of the lecture hall relative to its current position
+func (p presetUtility) MoveCamera(lectureHall model.LectureHall, pan, tilt, zoom float64) error {
+	cam, err := p.ProvideCamera(lectureHall.CameraType, lectureHall.CameraIP)
+	if err != nil {
+		return err
+	}
+	return cam.Move(pan, tilt, zoom)
+}
+
+// StorePreset saves the current position of the lecture halls camera as a new preset on the camera and in the database.
+func (p presetUtility) StorePreset(lectureHall model.LectureHall, presetID int, name string) (model.CameraPreset, error) {
+	cam, err := p.ProvideCamera(lectureHall.CameraType, lectureHall.CameraIP)
+	if err != nil {
+		return model.CameraPreset{}, err
+	}
+	presetID, err = cam.StorePreset(presetID, name)
+	if err != nil {
+		return model.CameraPreset{}, err
+	}
+	preset := model.CameraPreset{
+		Name:          name,
+		PresetID:      presetID,
+		LectureHallID: lectureHall.ID,
+	}
+	for _, existing := range lectureHall.CameraPresets {
+		if existing.PresetID == presetID {
+			preset.IsDefault = existing.IsDefault
+		}
+	}
+	return preset, p.LectureHallDao.SavePreset(preset)
+}
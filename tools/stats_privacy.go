@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	"github.com/TUM-Dev/gocast/dao"
+)
+
+// AnonymizeStats applies Cfg.Stats to a series of stats before it is handed out:
+// consecutive buckets with fewer than MinGroupSize viewers are merged until they reach it, the remainder is merged
+// into the previous bucket. If the whole series is below MinGroupSize, nil is returned.
+// Afterward, laplace noise is added if configured. The noise is seeded by key and the bucket label, so repeated
+// requests can't average it out.
+func AnonymizeStats(key string, stats []dao.Stat) []dao.Stat {
+	return anonymizeStats(Cfg.Stats, key, stats)
+}
+
+// AnonymizeCount applies Cfg.Stats to a single count. ok is false if the count has to be suppressed.
+func AnonymizeCount(key string, count int) (res int, ok bool) {
+	stats := anonymizeStats(Cfg.Stats, key, []dao.Stat{{X: key, Y: count}})
+	if stats == nil {
+		return 0, false
+	}
+	return stats[0].Y, true
+}
+
+func anonymizeStats(cfg StatsConfig, key string, stats []dao.Stat) []dao.Stat {
+	type bucket struct {
+		first, last string
+		y           int
+	}
+	var buckets []bucket
+	if k := cfg.MinGroupSize; k > 1 {
+		var pending *bucket
+		for _, stat := range stats {
+			if pending == nil && (stat.Y == 0 || stat.Y >= k) {
+				buckets = append(buckets, bucket{stat.X, stat.X, stat.Y})
+				continue
+			}
+			if pending == nil {
+				pending = &bucket{first: stat.X}
+			}
+			pending.last = stat.X
+			pending.y += stat.Y
+			if pending.y >= k {
+				buckets = append(buckets, *pending)
+				pending = nil
+			}
+		}
+		if pending != nil {
+			if len(buckets) == 0 {
+				return nil
+			}
+			buckets[len(buckets)-1].last = pending.last
+			buckets[len(buckets)-1].y += pending.y
+		}
+	} else {
+		for _, stat := range stats {
+			buckets = append(buckets, bucket{stat.X, stat.X, stat.Y})
+		}
+	}
+
+	res := make([]dao.Stat, len(buckets))
+	for i, b := range buckets {
+		res[i] = dao.Stat{X: b.first, Y: b.y}
+		if b.first != b.last {
+			res[i].X = b.first + " - " + b.last
+		}
+		if cfg.Noise > 0 {
+			h := fnv.New64a()
+			_, _ = h.Write([]byte(key + "\x00" + res[i].X))
+			noise := laplace(rand.New(rand.NewSource(int64(h.Sum64()))), cfg.Noise)
+			res[i].Y = max(0, res[i].Y+int(math.Round(noise)))
+		}
+	}
+	return res
+}
+
+// laplace draws a sample from the laplace distribution centered at 0 with the given scale
+func laplace(r *rand.Rand, scale float64) float64 {
+	u := r.Float64() - 0.5
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
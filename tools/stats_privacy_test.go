@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TUM-Dev/gocast/dao"
+)
+
+func TestAnonymizeStats(t *testing.T) {
+	stats := []dao.Stat{{X: "Mon", Y: 12}, {X: "Tue", Y: 2}, {X: "Wed", Y: 0}, {X: "Thu", Y: 4}, {X: "Fri", Y: 7}, {X: "Sat", Y: 1}}
+
+	tests := []struct {
+		name  string
+		cfg   StatsConfig
+		stats []dao.Stat
+		want  []dao.Stat
+	}{
+		{"disabled", StatsConfig{}, stats, stats},
+		{
+			"merges small buckets",
+			StatsConfig{MinGroupSize: 5},
+			stats,
+			[]dao.Stat{{X: "Mon", Y: 12}, {X: "Tue - Thu", Y: 6}, {X: "Fri - Sat", Y: 8}},
+		},
+		{"keeps empty buckets", StatsConfig{MinGroupSize: 5}, []dao.Stat{{X: "a", Y: 0}, {X: "b", Y: 5}}, []dao.Stat{{X: "a", Y: 0}, {X: "b", Y: 5}}},
+		{"suppresses small series", StatsConfig{MinGroupSize: 5}, []dao.Stat{{X: "a", Y: 1}, {X: "b", Y: 3}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := anonymizeStats(tt.cfg, "key", tt.stats); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("anonymizeStats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnonymizeStatsNoise(t *testing.T) {
+	cfg := StatsConfig{Noise: 2}
+	stats := []dao.Stat{{X: "a", Y: 100}, {X: "b", Y: 100}}
+	first := anonymizeStats(cfg, "key", stats)
+	if !reflect.DeepEqual(first, anonymizeStats(cfg, "key", stats)) {
+		t.Errorf("noise must be stable for the same key")
+	}
+	for _, s := range first {
+		if s.Y < 80 || s.Y > 120 {
+			t.Errorf("unexpected noise: %v", s)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,6 +17,55 @@ type JWTPlaylistClaims struct {
 	Download bool
 	StreamID string
 	CourseID string
+	Chapters []DownloadChapter `json:",omitempty"` // Chapters are embedded in downloads by the edge
+}
+
+// DownloadChapter is a chapter of a downloaded lecture, start and end are in milliseconds
+type DownloadChapter struct {
+	Title string `json:"title"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+// downloadSubject is the subject of tokens for offline downloads. They are valid longer than playlist tokens
+// so interrupted downloads can be resumed.
+const downloadSubject = "download"
+
+// DownloadToken returns a token to download playlist, one of the playlists of s, as mp4 with the chapters embedded.
+// The method assumes that the user has been pre-authorized and doesn't check for permissions.
+func DownloadToken(s model.Stream, playlist string, user *model.User, chapters []DownloadChapter, validFor time.Duration) (string, error) {
+	t := jwt.New(jwt.GetSigningMethod("RS256"))
+	t.Claims = &JWTPlaylistClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   downloadSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(validFor)),
+		},
+		UserID:   user.ID,
+		Playlist: playlist,
+		Download: true,
+		StreamID: fmt.Sprintf("%d", s.ID),
+		CourseID: fmt.Sprintf("%d", s.CourseID),
+		Chapters: chapters,
+	}
+	return t.SignedString(Cfg.GetJWTKey())
+}
+
+// ParseDownloadToken returns the claims of a token created by DownloadToken
+func ParseDownloadToken(token string) (*JWTPlaylistClaims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &JWTPlaylistClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return Cfg.GetJWTKey().Public(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims := parsed.Claims.(*JWTPlaylistClaims)
+	if !parsed.Valid || claims.Subject != downloadSubject || !claims.Download {
+		return nil, errors.New("invalid download token")
+	}
+	return claims, nil
 }
 
 // SetSignedPlaylists adds a signed jwt to all available playlist urls that indicates that the
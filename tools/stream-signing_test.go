@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"gorm.io/gorm"
+)
+
+func TestDownloadToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwtKey = key
+	defer func() { jwtKey = nil }()
+
+	stream := model.Stream{Model: gorm.Model{ID: 3}, CourseID: 2, PlaylistUrl: "https://edge.example.com/vod/course/3/playlist.m3u8"}
+	chapters := []DownloadChapter{{Title: "Intro", Start: 0, End: 60000}}
+	token, err := DownloadToken(stream, stream.PlaylistUrl, &model.User{Model: gorm.Model{ID: 5}}, chapters, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ParseDownloadToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 5 || claims.StreamID != "3" || len(claims.Chapters) != 1 || claims.Chapters[0].Title != "Intro" {
+		t.Errorf("unexpected claims %v", claims)
+	}
+
+	if err = SetSignedPlaylists(&stream, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	playlist, _ := url.Parse(stream.PlaylistUrl)
+	if _, err = ParseDownloadToken(playlist.Query().Get("jwt")); err == nil {
+		t.Error("expected playlist tokens not to be accepted as download tokens")
+	}
+}
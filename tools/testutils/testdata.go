@@ -438,7 +438,11 @@ func GetFileMock(t *testing.T) dao.FileDao {
 		Return(Attachment, nil)
 	fileMock.
 		EXPECT().
-		DeleteFile(Attachment.ID).
+		GetAttachmentVersions(Attachment.ID).
+		Return(nil, nil)
+	fileMock.
+		EXPECT().
+		DeleteAttachment(Attachment).
 		Return(nil)
 	return fileMock
 }
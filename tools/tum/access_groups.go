@@ -0,0 +1,62 @@
+package tum
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TUM-Dev/gocast/dao"
+	"github.com/TUM-Dev/gocast/model"
+)
+
+// ErrEmptyLdapGroup is returned if the LDAP group of an access group has no members. The members of the access group
+// are kept in that case, so a typo in the group or an outage doesn't lock everyone out.
+var ErrEmptyLdapGroup = errors.New("ldap group has no members")
+
+// SyncAccessGroups updates the members of all access groups with an LDAP group
+func SyncAccessGroups(daoWrapper dao.DaoWrapper) func() {
+	return func() {
+		ctx := context.Background()
+		groups, err := daoWrapper.AccessGroupsDao.GetAccessGroups(ctx)
+		if err != nil {
+			logger.Error("Could not get access groups", "err", err)
+			return
+		}
+		for _, group := range groups {
+			if group.LdapGroup == "" {
+				continue
+			}
+			added, removed, err := SyncAccessGroup(ctx, daoWrapper, group)
+			if err != nil {
+				logger.Error("Could not sync access group", "group", group.Name, "err", err)
+				continue
+			}
+			logger.Info("Synced access group", "group", group.Name, "added", len(added), "removed", len(removed))
+		}
+	}
+}
+
+// SyncAccessGroup replaces the members of the group with the members of its LDAP group and stores the result
+func SyncAccessGroup(ctx context.Context, daoWrapper dao.DaoWrapper, group model.AccessGroup) (added []string, removed []string, err error) {
+	defer func() {
+		if saveErr := daoWrapper.AccessGroupsDao.SaveSyncResult(ctx, group.ID, len(added), len(removed), err); saveErr != nil {
+			logger.Error("Could not save sync result", "group", group.Name, "err", saveErr)
+		}
+	}()
+	if group.LdapGroup == "" {
+		return nil, nil, errors.New("access group has no ldap group")
+	}
+	members, err := FindGroupMembers(group.LdapGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil, ErrEmptyLdapGroup
+	}
+	lrzIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		if lrzID, ok := model.NormalizeLrzID(member); ok {
+			lrzIDs = append(lrzIDs, lrzID)
+		}
+	}
+	return daoWrapper.AccessGroupsDao.SetMembers(ctx, group.ID, lrzIDs)
+}
@@ -141,3 +141,36 @@ func FindUserWithEmail(email string) (*model.User, error) {
 		Role:                model.LecturerType,
 	}, nil
 }
+
+// FindGroupMembers returns the LRZ IDs of the members of the LDAP group with the DN groupDN
+func FindGroupMembers(groupDN string) ([]string, error) {
+	l, err := ldap.DialURL(tools.Cfg.Ldap.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	err = l.Bind(tools.Cfg.Ldap.User, tools.Cfg.Ldap.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		"ou=users,ou=data,ou=prod,ou=iauth,dc=tum,dc=de",
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(memberOf=%s)", ldap.EscapeFilter(groupDN)),
+		[]string{"imLRZKennung"},
+		nil,
+	)
+	sr, err := l.SearchWithPaging(searchRequest, 500)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't query group members: %w", err)
+	}
+	members := make([]string, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		if lrzID := entry.GetAttributeValue("imLRZKennung"); lrzID != "" {
+			members = append(members, lrzID)
+		}
+	}
+	return members, nil
+}
@@ -96,17 +96,17 @@ func (a *App) packageFile(file, name string) {
 		logger.Error("Error on creating directories", "err", err)
 		return
 	}
+	// the arguments are passed without splitting, so paths may contain spaces
 	c := exec.Command("ffmpeg",
-		strings.Split(
-			"-i "+file+
-				" -c copy "+
-				"-f hls "+
-				"-hls_time 8 "+
-				"-hls_playlist_type vod "+
-				"-hls_flags independent_segments "+
-				"-hls_segment_type mpegts "+
-				"-hls_segment_filename "+a.config.outputDir+name+"/"+"segment%04d.ts "+
-				a.config.outputDir+name+"/"+"playlist.m3u8", " ")...)
+		"-i", file,
+		"-c", "copy",
+		"-f", "hls",
+		"-hls_time", "8",
+		"-hls_playlist_type", "vod",
+		"-hls_flags", "independent_segments",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", a.config.outputDir+name+"/"+"segment%04d.ts",
+		a.config.outputDir+name+"/"+"playlist.m3u8")
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
 	err = c.Run()
@@ -24,6 +24,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SubtitleGeneratorClient interface {
 	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Translate(ctx context.Context, in *TranslateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type subtitleGeneratorClient struct {
@@ -43,11 +44,21 @@ func (c *subtitleGeneratorClient) Generate(ctx context.Context, in *GenerateRequ
 	return out, nil
 }
 
+func (c *subtitleGeneratorClient) Translate(ctx context.Context, in *TranslateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/live.voice.v1.SubtitleGenerator/Translate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubtitleGeneratorServer is the server API for SubtitleGenerator service.
 // All implementations must embed UnimplementedSubtitleGeneratorServer
 // for forward compatibility
 type SubtitleGeneratorServer interface {
 	Generate(context.Context, *GenerateRequest) (*emptypb.Empty, error)
+	Translate(context.Context, *TranslateRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedSubtitleGeneratorServer()
 }
 
@@ -58,6 +69,9 @@ type UnimplementedSubtitleGeneratorServer struct {
 func (UnimplementedSubtitleGeneratorServer) Generate(context.Context, *GenerateRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
 }
+func (UnimplementedSubtitleGeneratorServer) Translate(context.Context, *TranslateRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Translate not implemented")
+}
 func (UnimplementedSubtitleGeneratorServer) mustEmbedUnimplementedSubtitleGeneratorServer() {}
 
 // UnsafeSubtitleGeneratorServer may be embedded to opt out of forward compatibility for this service.
@@ -89,6 +103,24 @@ func _SubtitleGenerator_Generate_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SubtitleGenerator_Translate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TranslateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubtitleGeneratorServer).Translate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/live.voice.v1.SubtitleGenerator/Translate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubtitleGeneratorServer).Translate(ctx, req.(*TranslateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SubtitleGenerator_ServiceDesc is the grpc.ServiceDesc for SubtitleGenerator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -100,6 +132,10 @@ var SubtitleGenerator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Generate",
 			Handler:    _SubtitleGenerator_Generate_Handler,
 		},
+		{
+			MethodName: "Translate",
+			Handler:    _SubtitleGenerator_Translate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "subtitles.proto",
@@ -110,6 +146,7 @@ var SubtitleGenerator_ServiceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SubtitleReceiverClient interface {
 	Receive(ctx context.Context, in *ReceiveRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ReportStatus(ctx context.Context, in *JobStatus, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type subtitleReceiverClient struct {
@@ -129,11 +166,21 @@ func (c *subtitleReceiverClient) Receive(ctx context.Context, in *ReceiveRequest
 	return out, nil
 }
 
+func (c *subtitleReceiverClient) ReportStatus(ctx context.Context, in *JobStatus, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/live.voice.v1.SubtitleReceiver/ReportStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubtitleReceiverServer is the server API for SubtitleReceiver service.
 // All implementations must embed UnimplementedSubtitleReceiverServer
 // for forward compatibility
 type SubtitleReceiverServer interface {
 	Receive(context.Context, *ReceiveRequest) (*emptypb.Empty, error)
+	ReportStatus(context.Context, *JobStatus) (*emptypb.Empty, error)
 	mustEmbedUnimplementedSubtitleReceiverServer()
 }
 
@@ -144,6 +191,9 @@ type UnimplementedSubtitleReceiverServer struct {
 func (UnimplementedSubtitleReceiverServer) Receive(context.Context, *ReceiveRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Receive not implemented")
 }
+func (UnimplementedSubtitleReceiverServer) ReportStatus(context.Context, *JobStatus) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportStatus not implemented")
+}
 func (UnimplementedSubtitleReceiverServer) mustEmbedUnimplementedSubtitleReceiverServer() {}
 
 // UnsafeSubtitleReceiverServer may be embedded to opt out of forward compatibility for this service.
@@ -175,6 +225,24 @@ func _SubtitleReceiver_Receive_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SubtitleReceiver_ReportStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubtitleReceiverServer).ReportStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/live.voice.v1.SubtitleReceiver/ReportStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubtitleReceiverServer).ReportStatus(ctx, req.(*JobStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SubtitleReceiver_ServiceDesc is the grpc.ServiceDesc for SubtitleReceiver service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -186,6 +254,10 @@ var SubtitleReceiver_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Receive",
 			Handler:    _SubtitleReceiver_Receive_Handler,
 		},
+		{
+			MethodName: "ReportStatus",
+			Handler:    _SubtitleReceiver_ReportStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "subtitles.proto",
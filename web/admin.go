@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 
 	"github.com/TUM-Dev/gocast/dao"
 	"github.com/TUM-Dev/gocast/model"
@@ -128,6 +129,8 @@ func GetPageString(s string) string {
 		return "courseImport"
 	case "/admin/audits":
 		return "audits"
+	case "/admin/access-groups":
+		return "accessGroups"
 	case "/admin/maintenance":
 		return "maintenance"
 	case "/admin/notifications":
@@ -309,6 +312,17 @@ func (r mainRoutes) UpdateCourse(c *gin.Context) {
 	enChatMod := c.PostForm("enChatMod") == "on"
 	livePrivate := c.PostForm("livePrivate") == "on"
 	vodPrivate := c.PostForm("vodPrivate") == "on"
+	livePlayback := c.DefaultPostForm("livePlayback", model.LivePlaybackHLS)
+	if !model.IsValidLivePlayback(livePlayback) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "bad live playback mode"})
+		return
+	}
+	dvrEnabled := c.PostForm("dvrEnabled") == "on"
+	dvrWindow, err := strconv.ParseUint(c.DefaultPostForm("dvrWindow", "0"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "bad dvr window"})
+		return
+	}
 	tumLiveContext.Course.Visibility = access
 	tumLiveContext.Course.VODEnabled = enVOD
 	tumLiveContext.Course.DownloadsEnabled = enDL
@@ -317,6 +331,9 @@ func (r mainRoutes) UpdateCourse(c *gin.Context) {
 	tumLiveContext.Course.ModeratedChatEnabled = enChatMod
 	tumLiveContext.Course.LivePrivate = livePrivate
 	tumLiveContext.Course.VodPrivate = vodPrivate
+	tumLiveContext.Course.LivePlayback = livePlayback
+	tumLiveContext.Course.DvrEnabled = dvrEnabled
+	tumLiveContext.Course.DvrWindow = uint(dvrWindow)
 	r.CoursesDao.UpdateCourseMetadata(context.Background(), *tumLiveContext.Course)
 	c.Redirect(http.StatusFound, fmt.Sprintf("/admin/course/%v", tumLiveContext.Course.ID))
 }
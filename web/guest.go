@@ -0,0 +1,100 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/model"
+	"github.com/TUM-Dev/gocast/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// GuestPageData is the data of the page that asks guests for their email address
+type GuestPageData struct {
+	VersionTag string
+	Branding   tools.Branding
+	CourseName string
+	Error      bool
+}
+
+// GuestLinkPage redeems a guest link and redirects to the course or lecture it grants access to.
+// Guests enter their email address first if the link requires it.
+func (r mainRoutes) GuestLinkPage(c *gin.Context) {
+	link, err := r.guestLink(c)
+	if err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, model.ErrGuestLinkNotFound) {
+			status = http.StatusNotFound
+		}
+		c.Status(status)
+		tools.RenderErrorPage(c, status, "This guest link can't be used: "+err.Error()+".")
+		return
+	}
+	course, err := r.CoursesDao.GetCourseById(c, link.CourseID)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		tools.RenderErrorPage(c, http.StatusNotFound, tools.CourseNotFoundErrMsg)
+		return
+	}
+
+	email := strings.TrimSpace(c.PostForm("email"))
+	if link.RequireEmail {
+		if _, err := mail.ParseAddress(email); err != nil || c.Request.Method != http.MethodPost {
+			_ = templateExecutor.ExecuteTemplate(c.Writer, "guest.gohtml", GuestPageData{
+				VersionTag: VersionTag,
+				Branding:   tools.BrandingCfg,
+				CourseName: course.Name,
+				Error:      c.Request.Method == http.MethodPost,
+			})
+			return
+		}
+	} else {
+		email = ""
+	}
+
+	if err = r.GuestLinksDao.UseGuestLink(c, link.ID); err != nil {
+		c.Status(http.StatusForbidden)
+		tools.RenderErrorPage(c, http.StatusForbidden, "This guest link can't be used: "+model.ErrGuestLinkUsedUp.Error()+".")
+		return
+	}
+	guest := "anonymous guest"
+	if email != "" {
+		guest = "guest " + email
+	}
+	if err = r.AuditDao.Create(&model.Audit{Message: fmt.Sprintf("%s used guest link %d of %s", guest, link.ID, course.Name), Type: model.AuditInfo}); err != nil {
+		logger.Error("Create Audit:", "err", err)
+	}
+	if err = tools.StartGuestSession(c, link, email); err != nil {
+		logger.Error("Could not start guest session", "err", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if link.StreamID != nil {
+		c.Redirect(http.StatusFound, fmt.Sprintf("/w/%s/%d", course.Slug, *link.StreamID))
+		return
+	}
+	c.Redirect(http.StatusFound, fmt.Sprintf("/course/%d/%s/%s", course.Year, course.TeachingTerm, course.Slug))
+}
+
+// guestLink returns the guest link of the token in the url if it can be used
+func (r mainRoutes) guestLink(c *gin.Context) (model.GuestLink, error) {
+	id, err := tools.ParseGuestLinkToken(c.Param("token"))
+	if err != nil {
+		return model.GuestLink{}, model.ErrGuestLinkNotFound
+	}
+	link, err := r.GuestLinksDao.GetGuestLinkByID(c, id)
+	if err != nil {
+		return model.GuestLink{}, model.ErrGuestLinkNotFound
+	}
+	if err = link.Check(time.Now()); err != nil {
+		return link, err
+	}
+	if !link.HasUsesLeft() {
+		return link, model.ErrGuestLinkUsedUp
+	}
+	return link, nil
+}
@@ -124,6 +124,7 @@ func configMainRoute(router *gin.Engine) {
 	adminGroup.GET("/admin/infopages", routes.AdminPage)
 	adminGroup.GET("/admin/notifications", routes.AdminPage)
 	adminGroup.GET("/admin/audits", routes.AdminPage)
+	adminGroup.GET("/admin/access-groups", routes.AdminPage)
 	adminGroup.GET("/admin/maintenance", routes.AdminPage)
 
 	courseAdminGroup := router.Group("/")
@@ -146,6 +147,10 @@ func configMainRoute(router *gin.Engine) {
 	router.GET("/setPassword/:key", routes.CreatePasswordPage)
 	router.POST("/setPassword/:key", routes.CreatePasswordPage)
 
+	// guest links
+	router.GET("/guest/:token", routes.GuestLinkPage)
+	router.POST("/guest/:token", routes.GuestLinkPage)
+
 	// home & course pages
 	oldStartPage(router, &routes)
 	newStartPage(router, &routes)
@@ -239,7 +244,7 @@ type CacheMetrics struct {
 }
 
 type ChatData struct {
-	IsAdminOfCourse bool // is current user admin or lecturer who created the course associated with the chat
+	IsAdminOfCourse bool // whether the current user can moderate the chat, e.g. as admin or moderator of the course
 	IndexData       IndexData
 }
 
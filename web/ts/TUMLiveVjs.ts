@@ -161,6 +161,7 @@ export const initPlayer = function (
             reloadSourceOnError: true,
             vhs: {
                 overrideNative: !videojs.browser.IS_SAFARI,
+                experimentalLLHLS: true, // use partial segments of low-latency playlists
             },
             nativeVideoTracks: false,
             nativeAudioTracks: false,
@@ -0,0 +1,82 @@
+import { Delete, postData, putData, showMessage } from "./global";
+
+export type AccessGroup = {
+    id: number;
+    name: string;
+    ldapGroup: string;
+    memberCount: number;
+    courses: number[];
+    syncedAt: string;
+    syncAdded: number;
+    syncRemoved: number;
+    syncError: string;
+};
+
+export type MemberChanges = { added: string[]; removed: string[]; skipped?: string[] };
+
+export type CourseAccessGroup = { id: number; name: string; memberCount: number; attached: boolean };
+
+export function getAccessGroups(): Promise<AccessGroup[]> {
+    return fetch("/api/accessGroups").then((res) => (res.ok ? res.json() : []));
+}
+
+export function createAccessGroup(name: string, ldapGroup: string): Promise<AccessGroup | null> {
+    return postData("/api/accessGroups", { name, ldapGroup }).then((res) => {
+        if (!res.ok) {
+            showMessage("There was an error creating the access group.");
+            return null;
+        }
+        return res.json();
+    });
+}
+
+export function updateAccessGroup(group: AccessGroup): Promise<boolean> {
+    return putData(`/api/accessGroups/${group.id}`, { name: group.name, ldapGroup: group.ldapGroup }).then(
+        (res) => res.ok,
+    );
+}
+
+export function deleteAccessGroup(id: number): Promise<boolean> {
+    if (!confirm("Delete this access group? Its members lose access to the courses it is attached to.")) {
+        return Promise.resolve(false);
+    }
+    return Delete(`/api/accessGroups/${id}`).then((res) => res.ok);
+}
+
+// uploadAccessGroupMembers replaces the members of a group with the LRZ IDs in the first column of a CSV file
+export function uploadAccessGroupMembers(id: number, file: File): Promise<MemberChanges | null> {
+    const body = new FormData();
+    body.append("file", file);
+    return fetch(`/api/accessGroups/${id}/members`, { method: "POST", body }).then((res) => {
+        if (!res.ok) {
+            showMessage("There was an error uploading the members.");
+            return null;
+        }
+        return res.json();
+    });
+}
+
+// syncAccessGroup syncs the members of a group with its LDAP group now
+export function syncAccessGroup(id: number): Promise<MemberChanges | null> {
+    return fetch(`/api/accessGroups/${id}/sync`, { method: "POST" }).then(async (res) => {
+        if (!res.ok) {
+            showMessage("There was an error syncing the group: " + (await res.text()));
+            return null;
+        }
+        return res.json();
+    });
+}
+
+export function getCourseAccessGroups(courseID: number): Promise<CourseAccessGroup[]> {
+    return fetch(`/api/course/${courseID}/accessGroups`).then((res) => (res.ok ? res.json() : []));
+}
+
+export function setCourseAccessGroup(courseID: number, groupID: number, attached: boolean): Promise<boolean> {
+    const url = `/api/course/${courseID}/accessGroups/${groupID}`;
+    return (attached ? putData(url) : Delete(url)).then((res) => {
+        if (!res.ok) {
+            showMessage("There was an error updating the access groups of the course.");
+        }
+        return res.ok;
+    });
+}
@@ -96,11 +96,14 @@ export async function createLectureHall(
     presIP: string,
     camIP: string,
     cameraIp: string,
+    cameraType: number,
     pwrCtrlIp: string,
 ) {
-    return postData("/api/createLectureHall", { name, presIP, camIP, combIP, cameraIp, pwrCtrlIp }).then((e) => {
-        return e.status === StatusCodes.OK;
-    });
+    return postData("/api/createLectureHall", { name, presIP, camIP, combIP, cameraIp, cameraType, pwrCtrlIp }).then(
+        (e) => {
+            return e.status === StatusCodes.OK;
+        },
+    );
 }
 
 export async function deleteLectureHall(lectureHallID: number) {
@@ -165,6 +168,30 @@ export async function updateUser(userID: number, role: number) {
     return success ? role : -1;
 }
 
+export type PendingDeletion = { userID: number; name: string; login: string; createdAt: string; deleteAt: string };
+
+export function getDeletionRequests(): Promise<PendingDeletion[]> {
+    return fetch("/api/users/deletionRequests").then((res) => (res.ok ? res.json() : []));
+}
+
+// cancelDeletionRequest cancels the deletion of an account on behalf of its user
+export function cancelDeletionRequest(userID: number): Promise<boolean> {
+    return fetch(`/api/users/deletionRequests/${userID}`, { method: "DELETE" }).then((res) => res.ok);
+}
+
+// executeDeletionRequest deletes an account with a pending deletion request immediately
+export function executeDeletionRequest(userID: number): Promise<boolean> {
+    if (!confirm("Delete this account and its personal data now? This can't be undone.")) {
+        return Promise.resolve(false);
+    }
+    return fetch(`/api/users/deletionRequests/${userID}/execute`, { method: "POST" }).then((res) => {
+        if (!res.ok) {
+            showMessage("There was an error deleting the user.");
+        }
+        return res.ok;
+    });
+}
+
 export async function updateText(id: number, name: string, content: string) {
     await fetch("/api/texts/" + id, {
         method: "PUT",
@@ -190,8 +217,8 @@ export async function updateText(id: number, name: string, content: string) {
         });
 }
 
-export async function requestSubtitles(streamID: number, language: string) {
-    await postData(`/api/stream/${streamID}/subtitles`, { language })
+export async function requestSubtitles(streamID: number, language: string, translations: string[] = []) {
+    await postData(`/api/stream/${streamID}/subtitles`, { language, translations })
         .then((res) => {
             if (!res.ok) {
                 throw Error(res.statusText);
@@ -21,14 +21,27 @@ export class LectureFile {
     readonly id: number;
     readonly fileType: number;
     readonly friendlyName: string;
+    readonly version: number;
+    readonly sectionID?: number;
+    readonly timestamp?: number;
 
-    constructor({ id, fileType, friendlyName }) {
+    constructor({ id, fileType, friendlyName, version = 1, sectionID = null, timestamp = null }) {
         this.id = id;
         this.fileType = fileType;
         this.friendlyName = friendlyName;
+        this.version = version;
+        this.sectionID = sectionID;
+        this.timestamp = timestamp;
     }
 }
 
+export interface AttachmentVersion {
+    id: number;
+    version: number;
+    friendlyName: string;
+    replacedAt: string;
+}
+
 export interface CreateNewLectureRequest {
     title: "";
     lectureHallId: 0;
@@ -87,6 +100,7 @@ export type VideoSection = {
     startHours: number;
     startMinutes: number;
     startSeconds: number;
+    fileID?: number;
 
     //Pseudo Fields
     key?: string;
@@ -169,6 +183,8 @@ export interface Lecture {
     isConverting: boolean;
     isLiveNow: boolean;
     isPast: boolean;
+    isPremiere: boolean;
+    isPremiereReady: boolean;
     isRecording: boolean;
     lectureHallId: number;
     lectureHallName: string;
@@ -180,6 +196,7 @@ export interface Lecture {
     streamKey: string;
     transcodingProgresses: TranscodingProgress[];
     videoSections: VideoSection[];
+    sectionSuggestions: VideoSection[];
 
     // Clientside computed fields
     hasAttachments: boolean;
@@ -223,6 +240,33 @@ export const AdminLectureList = {
         return post(`/api/stream/${courseId}/sections`, request);
     },
 
+    /**
+     * Assigns a lecture hall to lectures. If it is already booked at that time, the user is asked
+     * whether to assign it anyway.
+     * @param streamIds
+     * @param lectureHall
+     */
+    setLectureHall: async function (streamIds: number[], lectureHall: number) {
+        const send = (force: boolean) =>
+            fetch("/api/setLectureHall", {
+                method: "POST",
+                headers: { "Content-Type": "application/json" },
+                body: JSON.stringify({ streamIds, lectureHall, force }),
+            });
+        let res = await send(false);
+        if (res.status === StatusCodes.CONFLICT) {
+            const { error } = await res.json();
+            if (!confirm(`The lecture hall is already booked: ${error}\nAssign it anyway?`)) {
+                throw Error("lecture hall already booked");
+            }
+            res = await send(true);
+        }
+        if (!res.ok) {
+            throw Error(res.statusText);
+        }
+        return res;
+    },
+
     /**
      * Updates metadata of a lecture.
      * @param courseId
@@ -249,10 +293,7 @@ export const AdminLectureList = {
 
         if (request.lectureHallId !== undefined) {
             promises.push(
-                post("/api/setLectureHall", {
-                    streamIds: [lectureId],
-                    lectureHall: request.lectureHallId,
-                }),
+                AdminLectureList.setLectureHall([lectureId], request.lectureHallId),
             );
         }
 
@@ -323,6 +364,27 @@ export const AdminLectureList = {
         await del(`/api/stream/${lectureId}/sections/${sectionId}`);
     },
 
+    /**
+     * Accepts a section suggestion, turning it into a section of the lecture
+     * @param lectureId
+     * @param suggestion
+     */
+    acceptSuggestion: async (lectureId: number, suggestion: VideoSection): Promise<VideoSection> => {
+        const result = await post(`/api/stream/${lectureId}/suggestions/${suggestion.id}/accept`, {
+            description: suggestion.description,
+        });
+        return result.json();
+    },
+
+    /**
+     * Rejects a section suggestion
+     * @param lectureId
+     * @param suggestionId
+     */
+    rejectSuggestion: async (lectureId: number, suggestionId: number): Promise<void> => {
+        await del(`/api/stream/${lectureId}/suggestions/${suggestionId}`);
+    },
+
     /**
      * Updates the private state of a lecture.
      * @param lectureId
@@ -394,6 +456,45 @@ export const AdminLectureList = {
         return del(`/api/stream/${lectureId}/files/${attachmentId}`);
     },
 
+    /**
+     * Replace the file of an attachment, the old file is kept as previous version
+     * @param lectureId
+     * @param attachmentId
+     * @param file
+     */
+    replaceAttachment: async (lectureId: number, attachmentId: number, file: File) => {
+        const formData = new FormData();
+        formData.append("file", file);
+        const res = await fetch(`/api/stream/${lectureId}/files/${attachmentId}`, { method: "PUT", body: formData });
+        if (!res.ok) {
+            throw Error(res.statusText);
+        }
+        return res.json();
+    },
+
+    /**
+     * Get the previous versions of an attachment
+     * @param lectureId
+     * @param attachmentId
+     */
+    getAttachmentVersions: async (lectureId: number, attachmentId: number): Promise<AttachmentVersion[]> => {
+        return get(`/api/stream/${lectureId}/files/${attachmentId}/versions`);
+    },
+
+    /**
+     * Attach an attachment to a section or a timestamp of the lecture
+     * @param lectureId
+     * @param attachmentId
+     * @param sectionID
+     * @param timestamp in seconds
+     */
+    setAttachmentPlacement: async (lectureId: number, attachmentId: number, sectionID?: number, timestamp?: number) => {
+        return patch(`/api/stream/${lectureId}/files/${attachmentId}/placement`, {
+            videoSectionID: sectionID ?? null,
+            timestamp: timestamp ?? null,
+        });
+    },
+
     /**
      * Get transcoding progress
      * @param courseId
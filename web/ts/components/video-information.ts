@@ -31,6 +31,16 @@ export function videoInformationContext(streamId: number): AlpineComponent {
                     this.handleViewersUpdate(data);
                 } else if ("description" in data) {
                     this.handleDescriptionUpdate(data);
+                } else if ("premiere" in data) {
+                    this.handlePremiereUpdate(data);
+                } else if ("live" in data) {
+                    this.handleLiveUpdate(data);
+                } else if ("vod" in data) {
+                    // the VoD is published, players that can't switch to it seamlessly are reloaded
+                    const evt = new CustomEvent("vodpublished", { cancelable: true });
+                    if (window.dispatchEvent(evt)) {
+                        window.location.reload();
+                    }
                 }
             };
             SocketConnections.ws.subscribe(handler);
@@ -40,6 +50,21 @@ export function videoInformationContext(streamId: number): AlpineComponent {
             this.viewers = upd.viewers;
         },
 
+        handlePremiereUpdate(upd: { premiere: { start: string } }) {
+            window.dispatchEvent(new CustomEvent("premiere", { detail: upd.premiere }));
+        },
+
+        handleLiveUpdate(upd: { live: boolean }) {
+            if (upd.live) {
+                // the player only gets a source once the stream is live
+                if (!document.querySelector("video-js source")) {
+                    window.location.reload();
+                }
+            } else {
+                window.dispatchEvent(new CustomEvent("streamended"));
+            }
+        },
+
         handleDescriptionUpdate(upd: { description: { full: string } }) {
             this.less = upd.description.full.length > CUTOFFLENGTH;
             this.description = upd.description.full;
@@ -49,3 +49,78 @@ export class WatchedTracker {
         return this.streams?.findIndex((s) => !s.watched && s.recording) === -1;
     }
 }
+
+type BundleItem = {
+    filename: string;
+    url: string;
+    subtitles: { filename: string; url: string }[];
+};
+
+/**
+ * Downloads all lectures of a course for offline use, in the browser or with a download manager
+ * @param courseID
+ */
+export function downloadBundle(courseID: number) {
+    return {
+        versions: ["COMB"],
+        quality: "original",
+        subtitles: true,
+        progress: null as string | null,
+
+        query(format: string): string {
+            const params = new URLSearchParams({
+                versions: this.versions.join(","),
+                quality: this.quality,
+                subtitles: this.subtitles ? "all" : "",
+                format,
+            });
+            return `/api/download/course/${courseID}/bundle?${params.toString()}`;
+        },
+
+        aria2Url(): string {
+            return this.query("aria2");
+        },
+
+        async downloadAll() {
+            const res = await fetch(this.query("json"));
+            if (!res.ok) {
+                showMessage("Could not get the downloads of the course.");
+                return;
+            }
+            const items = (await res.json()).items as BundleItem[];
+            if (this.quality !== "original") {
+                await this.prepare(items);
+            }
+            const files = items.flatMap((item) => [item, ...item.subtitles]);
+            for (const [i, file] of files.entries()) {
+                this.progress = `${i + 1}/${files.length}`;
+                const a = document.createElement("a");
+                a.href = file.url;
+                a.download = file.filename;
+                a.click();
+                // browsers block many downloads started at once
+                await new Promise((resolve) => setTimeout(resolve, 1000));
+            }
+            this.progress = null;
+        },
+
+        /**
+         * Waits until the edge transcoded the videos, they can't be streamed while that is running
+         */
+        async prepare(items: BundleItem[]) {
+            const pending = new Set(items.map((item) => item.url));
+            while (pending.size > 0) {
+                this.progress = `Preparing ${items.length - pending.size}/${items.length}`;
+                for (const url of pending) {
+                    const res = await fetch(url, { method: "HEAD" });
+                    if (res.status === StatusCodes.OK) {
+                        pending.delete(url);
+                    }
+                }
+                if (pending.size > 0) {
+                    await new Promise((resolve) => setTimeout(resolve, 30000));
+                }
+            }
+        },
+    };
+}
@@ -5,15 +5,21 @@ class User {
     login: string;
 }
 
+// Role of a user in the course: owner, admin, moderator or viewer
+class RoleUser extends User {
+    role: string;
+}
+
 export function courseAdminManagement(): { m: CourseAdminManagement } {
     return { m: new CourseAdminManagement() };
 }
 
 export class CourseAdminManagement {
-    users: User[] = [];
+    users: RoleUser[] = [];
     searchResult: User[] = [];
     courseId: number;
     search = "";
+    newRole = "admin";
 
     userId: number;
 
@@ -21,13 +27,17 @@ export class CourseAdminManagement {
         this.courseId = courseId;
         this.userId = userId;
 
-        fetch(`/api/course/${courseId}/admins`)
-            .then((response) => response.json() as Promise<User[]>)
+        fetch(`/api/course/${courseId}/roles`)
+            .then((response) => response.json() as Promise<RoleUser[]>)
             .then((users) => {
                 this.users = users;
             });
     }
 
+    admins(): RoleUser[] {
+        return this.users.filter((u) => u.role === "admin");
+    }
+
     searchUsers() {
         if (this.search.length < 3) {
             this.searchResult = [];
@@ -40,30 +50,46 @@ export class CourseAdminManagement {
             });
     }
 
-    addAdmin(id: number) {
-        fetch(`/api/course/${this.courseId}/admins/${id}`, { method: "PUT" })
-            .then((response) => response.json() as Promise<User>)
+    addUser(id: number) {
+        const added =
+            this.newRole === "admin"
+                ? fetch(`/api/course/${this.courseId}/admins/${id}`, { method: "PUT" })
+                : fetch(`/api/course/${this.courseId}/roles/${id}`, {
+                      method: "PUT",
+                      body: JSON.stringify({ role: this.newRole }),
+                  });
+        added
+            .then((response) => {
+                if (!response.ok) {
+                    throw new Error("could not add user");
+                }
+                return response.json() as Promise<User>;
+            })
             .then((user) => {
-                this.users.push(user);
+                this.users = this.users.filter((u) => u.id !== user.id);
+                this.users.push({ ...user, role: this.newRole });
                 this.searchResult = this.searchResult.filter((u) => u.id !== user.id);
-            });
+            })
+            .catch(() => alert("Couldn't add user. Admins have to be removed before they get another role."));
     }
 
-    removeAdmin(id: number) {
-        if (id === this.userId) {
+    removeUser(user: RoleUser) {
+        if (user.id === this.userId) {
             if (!confirm("Are you sure you want to remove yourself from the course admins?")) {
                 return;
             }
         }
-        fetch(`/api/course/${this.courseId}/admins/${id}`, { method: "DELETE" })
-            .then((response) => response.json() as Promise<User>)
-            .then((user) => {
-                if (id === this.userId) {
-                    // user is no longer admin of the course, redirect them to the start page
-                    window.location.href = "/";
-                } else {
-                    this.users = this.users.filter((u) => u.id !== user.id);
-                }
-            });
+        const path = user.role === "admin" ? "admins" : "roles";
+        fetch(`/api/course/${this.courseId}/${path}/${user.id}`, { method: "DELETE" }).then((response) => {
+            if (!response.ok) {
+                return;
+            }
+            if (user.id === this.userId && user.role === "admin") {
+                // user is no longer admin of the course, redirect them to the start page
+                window.location.href = "/";
+            } else {
+                this.users = this.users.filter((u) => u.id !== user.id);
+            }
+        });
     }
 }
@@ -35,6 +35,7 @@ export class AdminLectureListProvider extends StreamableMapProvider<number, Lect
             s.hasAttachments = (s.files || []).some((f) => f.fileType === FileType.attachment);
 
             s.videoSections = (s.videoSections ?? []).sort(videoSectionSort);
+            s.sectionSuggestions = (s.sectionSuggestions ?? []).sort(videoSectionSort);
 
             s.startDate = new Date(s.start);
             s.startDateFormatted = s.startDate.toLocaleDateString("en-US", dateFormatOptions);
@@ -161,6 +162,41 @@ export class AdminLectureListProvider extends StreamableMapProvider<number, Lect
         await this.triggerUpdate(courseId);
     }
 
+    async replaceAttachment(courseId: number, lectureId: number, attachmentId: number, file: File) {
+        const res = await AdminLectureList.replaceAttachment(lectureId, attachmentId, file);
+        await this.updateAttachment(courseId, lectureId, attachmentId, {
+            version: res.version,
+            friendlyName: res.friendlyName,
+        });
+    }
+
+    async setAttachmentPlacement(
+        courseId: number,
+        lectureId: number,
+        attachmentId: number,
+        sectionID?: number,
+        timestamp?: number,
+    ) {
+        await AdminLectureList.setAttachmentPlacement(lectureId, attachmentId, sectionID, timestamp);
+        await this.updateAttachment(courseId, lectureId, attachmentId, {
+            sectionID: sectionID ?? null,
+            timestamp: timestamp ?? null,
+        });
+    }
+
+    private async updateAttachment(courseId: number, lectureId: number, attachmentId: number, update: object) {
+        this.data[courseId] = (await this.getData(courseId)).map((s) => {
+            if (s.lectureId === lectureId) {
+                return {
+                    ...s,
+                    files: s.files.map((f) => (f.id === attachmentId ? new LectureFile({ ...f, ...update }) : f)),
+                };
+            }
+            return s;
+        });
+        await this.triggerUpdate(courseId);
+    }
+
     async deleteAttachment(courseId: number, lectureId: number, attachmentId: number) {
         await AdminLectureList.deleteAttachment(courseId, lectureId, attachmentId);
 
@@ -223,6 +259,37 @@ export class AdminLectureListProvider extends StreamableMapProvider<number, Lect
         await this.triggerUpdate(courseId);
     }
 
+    async acceptSuggestion(courseId: number, lectureId: number, suggestion: VideoSection) {
+        const section = await AdminLectureList.acceptSuggestion(lectureId, suggestion);
+
+        this.data[courseId] = (await this.getData(courseId)).map((s) => {
+            if (s.lectureId === lectureId) {
+                return {
+                    ...s,
+                    videoSections: [...s.videoSections, section].sort(videoSectionSort),
+                    sectionSuggestions: s.sectionSuggestions.filter((a) => a.id !== suggestion.id),
+                };
+            }
+            return s;
+        });
+        await this.triggerUpdate(courseId);
+    }
+
+    async rejectSuggestion(courseId: number, lectureId: number, suggestionId: number) {
+        await AdminLectureList.rejectSuggestion(lectureId, suggestionId);
+
+        this.data[courseId] = (await this.getData(courseId)).map((s) => {
+            if (s.lectureId === lectureId) {
+                return {
+                    ...s,
+                    sectionSuggestions: s.sectionSuggestions.filter((a) => a.id !== suggestionId),
+                };
+            }
+            return s;
+        });
+        await this.triggerUpdate(courseId);
+    }
+
     async uploadVideo(
         courseId: number,
         lectureId: number,
@@ -30,12 +30,19 @@ export type StreamPlaylistEntry = {
     start: string;
     streamProgress: string;
     createdAt: string;
+    playback?: LivePlayback; // only set for live streams
 
     // Client generated to package data with Typescript constructors
     startDate: Date;
     progress: Progress;
 };
 
+export type LivePlayback = {
+    mode: "hls" | "llhls" | "webrtc";
+    hls: string;
+    whep?: string;
+};
+
 const StreamPlaylist = {
     get: async function (streamId: number): Promise<StreamPlaylistEntry[]> {
         const resp = await getData(`/api/stream/${streamId}/playlist`);
@@ -0,0 +1,42 @@
+import { getPlayers } from "./TUMLiveVjs";
+import { get } from "./utilities/fetch-wrappers";
+
+/**
+ * Seeks all live players the given number of seconds behind the live edge, limited to the DVR window.
+ */
+export function timeShift(seconds: number) {
+    for (const player of getPlayers()) {
+        /* eslint-disable  @typescript-eslint/no-explicit-any */
+        const liveTracker = (player as any).liveTracker;
+        const target = liveTracker.liveCurrentTime() - seconds;
+        player.currentTime(Math.max(liveTracker.seekableStart(), target));
+    }
+}
+
+/**
+ * Switches the player playing the DVR recording of a live stream to the VoD once it's published.
+ * The DVR playlist starts with the recording, so playback continues at the same position.
+ */
+export function initDvr(playerId: string, streamId: number) {
+    window.addEventListener("vodpublished", async (e) => {
+        const player = getPlayers().find((p) => p.id() === playerId);
+        if (!player) {
+            return;
+        }
+        e.preventDefault();
+        const res = await get(`/api/stream/${streamId}/vod`, {});
+        if (!res.playlist) {
+            window.location.reload();
+            return;
+        }
+        const position = player.currentTime();
+        const paused = player.paused();
+        player.src({ src: res.playlist, type: "application/x-mpegURL" });
+        player.one("loadedmetadata", () => {
+            player.currentTime(position);
+            if (!paused) {
+                player.play();
+            }
+        });
+    });
+}
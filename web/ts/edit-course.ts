@@ -3,6 +3,7 @@ import { StatusCodes } from "http-status-codes";
 import { DataStore } from "./data-store/data-store";
 import {
     AdminLectureList,
+    AttachmentVersion,
     Lecture,
     LectureVideoType,
     LectureVideoTypeCam,
@@ -108,6 +109,7 @@ export function lectureEditor(lecture: Lecture): AlpineComponent {
 
         // UI Data
         lastErrors: [] as string[],
+        attachmentVersions: {} as Record<number, AttachmentVersion[]>,
         uiEditMode: UIEditMode.none,
         isDirty: false,
         isSaving: false,
@@ -218,6 +220,33 @@ export function lectureEditor(lecture: Lecture): AlpineComponent {
             DataStore.adminLectureList.deleteAttachment(this.lectureData.courseId, this.lectureData.lectureId, id);
         },
 
+        replaceAttachment(id: number, e: Event) {
+            const input = e.target as HTMLInputElement;
+            if (input.files.length === 0) {
+                return;
+            }
+            DataStore.adminLectureList
+                .replaceAttachment(this.lectureData.courseId, this.lectureData.lectureId, id, input.files[0])
+                .catch((err) => this.lastErrors.push(err.message));
+            input.value = "";
+        },
+
+        async showAttachmentVersions(id: number) {
+            const versions = await AdminLectureList.getAttachmentVersions(this.lectureData.lectureId, id);
+            this.attachmentVersions = { ...this.attachmentVersions, [id]: versions };
+        },
+
+        setAttachmentSection(id: number, sectionID: string) {
+            DataStore.adminLectureList
+                .setAttachmentPlacement(
+                    this.lectureData.courseId,
+                    this.lectureData.lectureId,
+                    id,
+                    sectionID === "" ? undefined : parseInt(sectionID),
+                )
+                .catch((err) => this.lastErrors.push(err.message));
+        },
+
         friendlySectionTimestamp(section: VideoSection): string {
             return videoSectionFriendlyTimestamp(section);
         },
@@ -260,6 +289,32 @@ export function lectureEditor(lecture: Lecture): AlpineComponent {
             ]);
         },
 
+        acceptSuggestion(suggestion: VideoSection) {
+            DataStore.adminLectureList.acceptSuggestion(
+                this.lectureData.courseId,
+                this.lectureData.lectureId,
+                suggestion,
+            );
+        },
+
+        async acceptAllSuggestions() {
+            for (const suggestion of this.lectureData.sectionSuggestions) {
+                await DataStore.adminLectureList.acceptSuggestion(
+                    this.lectureData.courseId,
+                    this.lectureData.lectureId,
+                    suggestion,
+                );
+            }
+        },
+
+        rejectSuggestion(suggestion: VideoSection) {
+            DataStore.adminLectureList.rejectSuggestion(
+                this.lectureData.courseId,
+                this.lectureData.lectureId,
+                suggestion.id,
+            );
+        },
+
         isValidVideoSection(section: VideoSection): boolean {
             const sectionKey = this.getSectionKey(section);
             const hasValidTime = !this.lectureData.videoSections.some(
@@ -418,7 +473,7 @@ export async function saveIsChatEnabledForAllLectures(isChatEnabled: boolean) {
 }
 
 export function saveLectureHall(streamIds: number[], lectureHall: string) {
-    return postData("/api/setLectureHall", { streamIds, lectureHall: parseInt(lectureHall) });
+    return AdminLectureList.setLectureHall(streamIds, parseInt(lectureHall));
 }
 
 // Used by schedule.ts
@@ -974,7 +1029,20 @@ export function createLectureForm(args: { s: [] }) {
                 }
                 postData("/api/course/" + this.courseID + "/createLecture", payload)
                     .then(async (res) => {
+                        if (res.status === StatusCodes.CONFLICT) {
+                            const { error } = await res.json();
+                            if (!confirm(`The lecture hall is already booked: ${error}\nCreate the lecture anyway?`)) {
+                                throw new Error("lecture hall already booked");
+                            }
+                            res = await postData("/api/course/" + this.courseID + "/createLecture", {
+                                ...payload,
+                                force: true,
+                            });
+                        }
                         const { ids } = await res.json();
+                        if (this.formData.premiere) {
+                            await this.uploadPremiere(ids[0]);
+                        }
                         const url = new URL(window.location.href);
                         url.hash = `lectures:${ids.join(",")}`;
                         window.location.assign(url);
@@ -1009,6 +1077,31 @@ export function createLectureForm(args: { s: [] }) {
             window.dispatchEvent(new CustomEvent("voduploadprogress", { detail }));
         },
 
+        // uploadPremiere uploads the video of a premiere, it is transcoded before the premiere starts.
+        async uploadPremiere(streamID: number) {
+            const mediaFiles = this.getMediaFiles().filter((e) => e.type === "COMB");
+            this.dispatchMediaProgress(mediaFiles);
+            try {
+                for (const mediaUpload of mediaFiles) {
+                    await uploadFile(
+                        `/api/course/${this.courseID}/uploadVODMedia?streamID=${streamID}&videoType=${mediaUpload.type}`,
+                        mediaUpload.file,
+                        {
+                            onProgress: (progress) => {
+                                mediaUpload.progress = progress;
+                                this.dispatchMediaProgress(mediaFiles);
+                            },
+                        },
+                    );
+                }
+            } catch (e) {
+                await postData(`/api/course/${this.courseID}/deleteLectures`, {
+                    streamIDs: [streamID],
+                });
+                throw e;
+            }
+        },
+
         async uploadVod(): Promise<number> {
             const mediaFiles = this.getMediaFiles();
             this.dispatchMediaProgress(mediaFiles);
@@ -1072,13 +1165,56 @@ export function deleteCourse(courseID: string) {
     }
 }
 
-export function copyCourse(courseID: string, year: string, yearW: string, semester: string) {
+export function copyCourse(
+    courseID: string,
+    year: string,
+    yearW: string,
+    semester: string,
+    parts: string[],
+    firstLecture: string,
+) {
+    const url = `/api/course/${courseID}/copy`;
+    const send = (force: boolean) =>
+        fetch(url, { method: "POST", body: JSON.stringify({ year, yearW, semester, parts, firstLecture, force }) });
+    send(false)
+        .then(async (res) => {
+            if (res.status === StatusCodes.CONFLICT) {
+                const { error } = await res.json();
+                if (!confirm(`The lecture halls of some lectures are already booked: ${error}\nCopy the course anyway?`)) {
+                    return null;
+                }
+                res = await send(true);
+            }
+            return res;
+        })
+        .then((res) => {
+            if (res === null) {
+                return;
+            }
+            if (!res.ok) {
+                alert("Couldn't copy course.");
+            } else {
+                res.json().then((r) => window.location.replace(`/admin/course/${r.newCourse}?copied`));
+            }
+        });
+}
+
+// previewCourseCopy returns what would be copied without copying anything
+export function previewCourseCopy(
+    courseID: string,
+    year: string,
+    yearW: string,
+    semester: string,
+    parts: string[],
+    firstLecture: string,
+) {
     const url = `/api/course/${courseID}/copy`;
-    fetch(url, { method: "POST", body: JSON.stringify({ year, yearW, semester }) }).then((res) => {
+    const body = JSON.stringify({ year, yearW, semester, parts, firstLecture, dryRun: true });
+    return fetch(url, { method: "POST", body }).then((res) => {
         if (!res.ok) {
-            alert("Couldn't copy course.");
-        } else {
-            res.json().then((r) => window.location.replace(`/admin/course/${r.newCourse}?copied`));
+            alert("Couldn't preview copy.");
+            return null;
         }
+        return res.json();
     });
 }
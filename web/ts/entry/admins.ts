@@ -12,5 +12,7 @@ export * from "../worker";
 export * from "../courseAdminManagement";
 export * from "../notification-management";
 export * from "../audits";
+export * from "../access-groups";
+export * from "../guest-links";
 export * from "../maintenance";
 export * from "../change-set";
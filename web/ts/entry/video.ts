@@ -1,6 +1,8 @@
 /* This bundle contains everything regarding the watch page */
 export * from "../watch-admin";
 export * from "../TUMLiveVjs";
+export * from "../whep";
+export * from "../dvr";
 export * from "../watch";
 export * from "../splitview";
 export * from "../bookmarks";
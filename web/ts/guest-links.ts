@@ -0,0 +1,44 @@
+import { Delete, postData, showMessage } from "./global";
+
+export type GuestLink = {
+    id: number;
+    streamID: number | null;
+    note: string;
+    expiresAt: string;
+    maxUses: number;
+    uses: number;
+    requireEmail: boolean;
+    url: string;
+};
+
+export function getGuestLinks(courseID: number): Promise<GuestLink[]> {
+    return fetch(`/api/course/${courseID}/guestLinks`).then((res) => (res.ok ? res.json() : []));
+}
+
+// createGuestLink creates a link that lets people without account watch the course or the lecture streamID
+export function createGuestLink(
+    courseID: number,
+    streamID: number | null,
+    note: string,
+    expires: string,
+    maxUses: number,
+    requireEmail: boolean,
+): Promise<GuestLink | null> {
+    const expiresAt = new Date(expires).toISOString();
+    return postData(`/api/course/${courseID}/guestLinks`, { streamID, note, expiresAt, maxUses, requireEmail }).then(
+        async (res) => {
+            if (!res.ok) {
+                showMessage("There was an error creating the guest link: " + (await res.text()));
+                return null;
+            }
+            return res.json();
+        },
+    );
+}
+
+export function revokeGuestLink(courseID: number, linkID: number): Promise<boolean> {
+    if (!confirm("Revoke this guest link? Guests who used it lose access immediately.")) {
+        return Promise.resolve(false);
+    }
+    return Delete(`/api/course/${courseID}/guestLinks/${linkID}`).then((res) => res.ok);
+}
@@ -32,3 +32,11 @@ export function setDefaultPreset(lectureHallID: number, presetID: number): Promi
         return res.ok;
     });
 }
+
+export function moveCamera(lectureHallID: number, pan: number, tilt: number, zoom: number): Promise<boolean> {
+    return postData(`/api/lectureHall/${lectureHallID}/move`, { pan, tilt, zoom }).then((res) => res.ok);
+}
+
+export function storePreset(lectureHallID: number, presetID: number, name: string): Promise<boolean> {
+    return postData(`/api/lectureHall/${lectureHallID}/presets`, { presetID, name }).then((res) => res.ok);
+}
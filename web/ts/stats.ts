@@ -1,7 +1,7 @@
 import { StatusCodes } from "http-status-codes";
 import Chart from "chart.js/auto";
 
-const statsToExport = ["week", "hour", "activity-live", "activity-vod", "allDays", "quickStats"];
+const statsToExport = ["week", "hour", "activity-live", "activity-vod", "allDays", "quickStats", "engagement"];
 
 export function getStatsDownloadLink(format: string) {
     return `/api/course/${
@@ -46,8 +46,8 @@ export function initStatsPage() {
             `/api/course/${(document.getElementById("courseID") as HTMLInputElement).value}/stats?interval=${endpoint}`,
         ).then((res) => {
             if (res.status === StatusCodes.OK) {
-                res.text().then((value) => {
-                    document.getElementById(endpoint).innerHTML = `<span>${JSON.parse(value)["res"]}</span>`;
+                res.json().then((count) => {
+                    document.getElementById(endpoint).innerHTML = `<span>${formatCount(count)}</span>`;
                 });
             }
         });
@@ -63,14 +63,19 @@ export function initLectureStatsPage(lectureID: string) {
             }/stats?interval=${endpoint}&lecture=${lectureID}`,
         ).then((res) => {
             if (res.status === StatusCodes.OK) {
-                res.text().then((value) => {
-                    document.getElementById(endpoint).innerHTML = `<span>${JSON.parse(value)["res"]}</span>`;
+                res.json().then((count) => {
+                    document.getElementById(endpoint).innerHTML = `<span>${formatCount(count)}</span>`;
                 });
             }
         });
     });
 }
 
+// formatCount shows counts below the minimum group size as a range
+function formatCount(count: { res: number; anonymized: boolean; minGroupSize?: number }): string {
+    return count.anonymized ? `< ${count.minGroupSize}` : `${count.res}`;
+}
+
 export async function getAsync(url = "") {
     return await fetch(url, {
         method: "GET",
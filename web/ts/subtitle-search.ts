@@ -1,11 +1,21 @@
-export function subtitleSearch(streamID: number) {
+export function subtitleSearch(streamID: number, language: string) {
+    const transcriptUrl = `/api/stream/${streamID}/transcript/${language}`;
     return {
         hits: [],
+        keywords: [],
+        exportUrl: `${transcriptUrl}/export`,
         open: false,
         lastEventTimestamp: 0,
+        init: function () {
+            fetch(`${transcriptUrl}/keywords`).then((res) => {
+                if (res.ok) {
+                    res.json().then((data) => (this.keywords = data));
+                }
+            });
+        },
         search: function (query: string) {
             if (query.length > 2) {
-                fetch(`/api/search/stream/${streamID}/subtitles?q=${query}`).then((res) => {
+                fetch(`${transcriptUrl}/search?q=${encodeURIComponent(query)}`).then((res) => {
                     if (res.ok) {
                         res.json().then((data) => {
                             this.hits = data.hits;
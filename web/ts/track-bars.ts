@@ -1,24 +1,23 @@
 import { VideoJsPlayer } from "video.js";
 
-const LANGUAGES = [
-    { id: "en", label: "English" },
-    { id: "de", label: "Deutsch" },
-];
+const languageNames = new Intl.DisplayNames(["en"], { type: "language" });
 
 export async function loadAndSetTrackbars(player: VideoJsPlayer, streamID: number) {
-    for (const language of LANGUAGES) {
-        await fetch(`/api/stream/${streamID}/subtitles/${language.id}`).then((res) => {
-            if (res.ok) {
-                window.dispatchEvent(new CustomEvent("togglesearch", { detail: { streamID: streamID } }));
-                player.addRemoteTextTrack(
-                    {
-                        src: `/api/stream/${streamID}/subtitles/${language.id}`,
-                        kind: "captions",
-                        label: language.label,
-                    },
-                    false,
-                );
-            }
-        });
+    const languages: string[] = await fetch(`/api/stream/${streamID}/subtitles`)
+        .then((res) => (res.ok ? res.json() : []))
+        .catch(() => []);
+    if (languages.length > 0) {
+        window.dispatchEvent(new CustomEvent("togglesearch", { detail: { streamID: streamID, language: languages[0] } }));
+    }
+    for (const language of languages) {
+        player.addRemoteTextTrack(
+            {
+                src: `/api/stream/${streamID}/subtitles/${language}`,
+                kind: "captions",
+                srclang: language,
+                label: languageNames.of(language) ?? language,
+            },
+            false,
+        );
     }
 }
@@ -42,3 +42,40 @@ export function checkInputSpeed(value: number, currentSpeeds: number[]) {
     const defaultSpeeds = [0.25, 0.5, 0.75, 1, 1.5, 1.5, 1.75, 2, 2.5, 3, 3.5];
     return !defaultSpeeds.includes(value) && !currentSpeeds.includes(value) && currentSpeeds.length < 3;
 }
+
+// createCalendarToken returns the url of a new personal calendar feed, the previous url stops working.
+export function createCalendarToken(): Promise<string> {
+    return fetch("/api/users/calendar/token", { method: "POST" }).then((response) => {
+        if (response.status !== StatusCodes.OK) {
+            throw new Error("could not create calendar token");
+        }
+        return response.json().then((res) => res.url);
+    });
+}
+
+export function revokeCalendarToken(): Promise<boolean> {
+    return fetch("/api/users/calendar/token", { method: "DELETE" }).then((response) => response.ok);
+}
+
+export type DeletionRequest = { deleteAt: string };
+
+// getDeletionRequest returns the pending deletion of the user's account or null
+export function getDeletionRequest(): Promise<DeletionRequest | null> {
+    return fetch("/api/users/deletion").then((response) => (response.ok ? response.json() : null));
+}
+
+// requestDeletion schedules the deletion of the user's account, it can be canceled until deleteAt
+export function requestDeletion(): Promise<DeletionRequest> {
+    return fetch("/api/users/deletion", { method: "POST" }).then((response) =>
+        response.json().then((res) => {
+            if (!response.ok) {
+                throw new Error(res.message ?? "could not request deletion");
+            }
+            return res;
+        }),
+    );
+}
+
+export function cancelDeletion(): Promise<boolean> {
+    return fetch("/api/users/deletion", { method: "DELETE" }).then((response) => response.ok);
+}
@@ -0,0 +1,82 @@
+import { getPlayers } from "./TUMLiveVjs";
+
+/**
+ * Plays a live stream from a WHEP endpoint via WebRTC in the player with the given id.
+ * Falls back to the HLS playlist if the connection can't be established or fails later on.
+ */
+export async function initWhep(playerId: string, whepUrl: string, fallbackUrl: string) {
+    const player = getPlayers().find((p) => p.id() === playerId);
+    if (!player) {
+        return;
+    }
+    let fellBack = false;
+    const fallback = (reason: unknown) => {
+        if (fellBack) {
+            return;
+        }
+        fellBack = true;
+        console.warn("WebRTC playback failed, falling back to HLS", reason);
+        player.src({ src: fallbackUrl, type: "application/x-mpegURL" });
+    };
+    try {
+        const stream = await whepConnect(whepUrl, fallback);
+        player.ready(() => {
+            const video = player.tech(true).el() as HTMLVideoElement;
+            video.srcObject = stream;
+            player.play();
+        });
+    } catch (e) {
+        fallback(e);
+    }
+}
+
+/**
+ * Negotiates a receive-only peer connection with a WHEP endpoint.
+ * onFailure is called if the connection fails after it was established.
+ */
+async function whepConnect(url: string, onFailure: (reason: unknown) => void): Promise<MediaStream> {
+    const pc = new RTCPeerConnection();
+    pc.addTransceiver("video", { direction: "recvonly" });
+    pc.addTransceiver("audio", { direction: "recvonly" });
+
+    const stream = new MediaStream();
+    pc.ontrack = (e) => stream.addTrack(e.track);
+    pc.onconnectionstatechange = () => {
+        if (pc.connectionState === "failed") {
+            pc.close();
+            onFailure("connection failed");
+        }
+    };
+
+    await pc.setLocalDescription(await pc.createOffer());
+    await iceGatheringComplete(pc);
+
+    const res = await fetch(url, {
+        method: "POST",
+        headers: { "Content-Type": "application/sdp" },
+        body: pc.localDescription.sdp,
+    });
+    if (res.status !== 201) {
+        pc.close();
+        throw new Error(`WHEP endpoint returned ${res.status}`);
+    }
+    await pc.setRemoteDescription({ type: "answer", sdp: await res.text() });
+    return stream;
+}
+
+function iceGatheringComplete(pc: RTCPeerConnection): Promise<void> {
+    return new Promise((resolve) => {
+        if (pc.iceGatheringState === "complete") {
+            resolve();
+            return;
+        }
+        // don't wait forever for slow STUN servers, the candidates gathered so far suffice
+        const timeout = setTimeout(resolve, 2000);
+        pc.addEventListener("icegatheringstatechange", () => {
+            if (pc.iceGatheringState === "complete") {
+                clearTimeout(timeout);
+                resolve();
+            }
+        });
+    });
+}
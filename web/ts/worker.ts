@@ -1,6 +1,13 @@
+import { putData } from "./global";
+
 /** Make DELETE call to /api/workers/:id with given worker-id */
 export async function deleteWorker(id: string) {
     return await fetch("/api/workers/" + id, {
         method: "DELETE",
     });
 }
+
+/** Make PUT call to /api/workers/:id/drain to take a worker out of rotation or put it back */
+export async function setWorkerDraining(id: string, draining: boolean) {
+    return await putData("/api/workers/" + id + "/drain", { draining });
+}
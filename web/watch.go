@@ -34,12 +34,7 @@ func (r mainRoutes) WatchPage(c *gin.Context) {
 	}
 	tumLiveContext := foundContext.(tools.TUMLiveContext)
 	data.IndexData = NewIndexData()
-	if (tumLiveContext.Course.DownloadsEnabled || tumLiveContext.User.IsAdminOfCourse(*tumLiveContext.Course)) && tumLiveContext.Stream.IsDownloadable() {
-		err = tools.SetSignedPlaylists(tumLiveContext.Stream, tumLiveContext.User, true)
-	} else {
-		err = tools.SetSignedPlaylists(tumLiveContext.Stream, tumLiveContext.User, false)
-	}
-	if err != nil {
+	if err = tools.SetSignedPlaylists(tumLiveContext.Stream, tumLiveContext.User, tumLiveContext.MayDownloadStream()); err != nil {
 		logger.Warn("Can't sign playlists", "err", err)
 	}
 	data.IndexData.TUMLiveContext = tumLiveContext
@@ -47,7 +42,7 @@ func (r mainRoutes) WatchPage(c *gin.Context) {
 	data.AlertsEnabled = tools.Cfg.Alerts != nil
 
 	data.ChatData.IndexData.TUMLiveContext = foundContext.(tools.TUMLiveContext)
-	data.ChatData.IsAdminOfCourse = tumLiveContext.UserIsAdmin()
+	data.ChatData.IsAdminOfCourse = tumLiveContext.UserHasCoursePermission(model.CoursePermissionModerateChat)
 
 	if data.IsAdminOfCourse && tumLiveContext.Stream.LectureHallID != 0 {
 		lectureHall, err := r.LectureHallsDao.GetLectureHallByID(tumLiveContext.Stream.LectureHallID)
@@ -122,6 +117,10 @@ func (r mainRoutes) WatchPage(c *gin.Context) {
 		data.DVR = ""
 	}
 
+	if data.Version == "" || data.Version == "COMB" {
+		data.LivePlayback = api.GetLivePlayback(r.DaoWrapper, *tumLiveContext.Stream, *tumLiveContext.Course)
+	}
+
 	data.CutOffLength = api.CutOffLength
 	if strings.HasPrefix(data.Version, "unit-") {
 		data.Description = data.Unit.GetDescriptionHTML()
@@ -156,6 +155,16 @@ type WatchPageData struct {
 	DVR             string // ?dvr if dvr is enabled, empty string otherwise
 	LectureHallName string
 	ChatData        ChatData
+	LivePlayback    *api.LivePlayback // playback urls of the combined live stream, nil if not live
+}
+
+// DvrUrl returns the DVR playlist of the combined live player or "" if viewers can't rewind.
+// Low-latency modes play their own sources, so the DVR recording is only used with standard HLS.
+func (d WatchPageData) DvrUrl() string {
+	if d.LivePlayback == nil || d.LivePlayback.Mode != model.LivePlaybackHLS {
+		return ""
+	}
+	return d.LivePlayback.Dvr
 }
 
 // Prepare populates the data for the watch page.
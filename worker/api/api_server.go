@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"time"
 
 	"github.com/joschahenningsen/thumbgen"
@@ -14,6 +13,8 @@ import (
 	"github.com/u2takey/go-utils/uuid"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
+	"github.com/TUM-Dev/gocast/worker/grpcauth"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	"github.com/TUM-Dev/gocast/worker/worker"
 	log "github.com/sirupsen/logrus"
@@ -45,7 +46,15 @@ func (s server) RequestStream(ctx context.Context, request *pb.StreamRequest) (*
 		log.Info("Rejected request to stream")
 		return &pb.Status{Ok: false}, errors.New("unauthenticated: wrong worker id")
 	}
-	go worker.HandleStreamRequest(request)
+	done, err := worker.StartJob()
+	if err != nil {
+		log.Info("Rejected request to stream while draining")
+		return &pb.Status{Ok: false}, err
+	}
+	go func() {
+		defer done()
+		worker.HandleStreamRequest(request)
+	}()
 	return &pb.Status{Ok: true}, nil
 }
 
@@ -54,7 +63,15 @@ func (s server) RequestPremiere(ctx context.Context, request *pb.PremiereRequest
 		log.Info("Rejected request for premiere")
 		return &pb.Status{Ok: false}, errors.New("unauthenticated: wrong worker id")
 	}
-	go worker.HandlePremiere(request)
+	done, err := worker.StartJob()
+	if err != nil {
+		log.Info("Rejected request for premiere while draining")
+		return &pb.Status{Ok: false}, err
+	}
+	go func() {
+		defer done()
+		worker.HandlePremiere(request)
+	}()
 	return &pb.Status{Ok: true}, nil
 }
 
@@ -82,16 +99,16 @@ func (s server) GenerateLivePreview(ctx context.Context, request *pb.LivePreview
 		log.Info("Rejected request to generate live thumbnails")
 		return nil, errors.New("unauthenticated: wrong worker id")
 	}
-	cmd := exec.Command("ffmpeg",
-		"-sseof", "-3",
-		"-i", request.HLSUrl,
-		"-vframes", "1",
-		"-update", "1",
-		"-vf", "scale=720:-1",
-		"-q:v", "1",
-		"-c:v", "mjpeg",
-		"-f", "mjpeg",
-		"pipe:1")
+	cmd := ffmpeg.New().
+		Input(request.HLSUrl, "-sseof", "-3").
+		Output("pipe:1",
+			"-vframes", "1",
+			"-update", "1",
+			"-vf", "scale=720:-1",
+			"-q:v", "1",
+			"-c:v", "mjpeg",
+			"-f", "mjpeg").
+		Cmd()
 	liveThumb, err := cmd.Output()
 	return &pb.LivePreviewResponse{LiveThumb: liveThumb}, err
 }
@@ -115,18 +132,21 @@ func (s server) GenerateSectionImages(ctx context.Context, request *pb.GenerateS
 		timestampStr := fmt.Sprintf("%0d:%0d:%0d", section.Hours, section.Minutes, section.Seconds)
 		path := fmt.Sprintf("%s/%s.jpg", folder, uuid.NewUUID())
 
-		cmd := exec.Command("ffmpeg", "-y",
-			"-ss", timestampStr,
-			"-i", request.PlaylistURL,
-			"-vf", "scale=156:-1",
-			"-frames:v", "1",
-			"-q:v", "2",
-
-			path)
+		cmd := ffmpeg.New().
+			Global("-y").
+			Input(request.PlaylistURL, "-ss", timestampStr).
+			Output(path,
+				"-vf", "scale=156:-1",
+				"-frames:v", "1",
+				"-q:v", "2").
+			Cmd()
 		_, err = cmd.CombinedOutput()
 		if err != nil {
 			return &pb.GenerateSectionImageResponse{}, err
 		}
+		if err = worker.StoreFile(path); err != nil {
+			return &pb.GenerateSectionImageResponse{}, err
+		}
 
 		paths[i] = path
 	}
@@ -139,6 +159,10 @@ func (s server) DeleteSectionImage(ctx context.Context, request *pb.DeleteSectio
 	if err != nil {
 		return &pb.Status{Ok: false}, err
 	}
+	err = worker.Storage.Delete(ctx, worker.StorageKey(request.Path))
+	if err != nil {
+		return &pb.Status{Ok: false}, err
+	}
 	return &pb.Status{Ok: true}, err
 }
 
@@ -157,13 +181,13 @@ func InitApi(addr string) {
 	if err != nil {
 		log.WithError(err).Fatal("failed to listen")
 	}
-	grpcServer := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{
+	grpcServer := grpc.NewServer(append(grpcauth.ServerOptions(), grpc.KeepaliveParams(keepalive.ServerParameters{
 		MaxConnectionIdle:     time.Minute,
 		MaxConnectionAge:      time.Minute,
 		MaxConnectionAgeGrace: time.Second * 5,
 		Time:                  time.Minute * 10,
 		Timeout:               time.Second * 20,
-	}))
+	}))...)
 	pb.RegisterToWorkerServer(grpcServer, &server{})
 
 	reflection.Register(grpcServer)
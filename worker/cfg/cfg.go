@@ -25,6 +25,23 @@ var (
 	Token          string // setup token. Used to connect initially and to get a "WorkerID"
 	PersistDir     string // PersistDir is the directory, tum-live-worker will use to store persistent data
 	LogLevel       = log.InfoLevel
+
+	StorageBackend string // "local" (default) keeps artifacts in StorageDir, "s3" uploads them to the S3 bucket
+	S3Endpoint     string // e.g. https://s3.example.com
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+
+	FfmpegEncoder  string // "software" (default), "vaapi" or "nvenc"
+	FfmpegProfiles string // path to a JSON file overriding encoder profiles, see ffmpeg.Configure
+
+	DvrDir         string // DVR playlists of live streams are written to and served from here
+	DvrURLTemplate string // e.g. https://edge.tum.live/worker1/dvr/%s/playlist.m3u8, DVR is disabled if unset
+
+	OcrLanguages string // tesseract languages slide titles are recognized in, e.g. "deu+eng"
+
+	MainCACert string // CA certificate of TUM-Live, connections use mutual TLS if set
 )
 
 // SetConfig sets the values of the parameter config and stops the execution
@@ -45,8 +62,33 @@ func SetConfig() {
 	LrzPhone = os.Getenv("LrzPhone")
 	LrzSubDir = os.Getenv("LrzSubDir")
 	LrzUploadUrl = os.Getenv("LrzUploadUrl")
-	MainBase = os.Getenv("MainBase")             // eg. live.mm.rbg.tum.de
+	MainBase = os.Getenv("MainBase") // eg. live.mm.rbg.tum.de
+	MainCACert = os.Getenv("MainCACert")
+
+	StorageBackend = os.Getenv("StorageBackend")
+	if StorageBackend == "" {
+		StorageBackend = "local"
+	}
+	S3Endpoint = os.Getenv("S3Endpoint")
+	S3Region = os.Getenv("S3Region")
+	S3Bucket = os.Getenv("S3Bucket")
+	S3AccessKey = os.Getenv("S3AccessKey")
+	S3SecretKey = os.Getenv("S3SecretKey")
+	if StorageBackend == "s3" && (S3Endpoint == "" || S3Bucket == "") {
+		log.Fatal("StorageBackend s3 requires S3Endpoint and S3Bucket to be set")
+	}
+	FfmpegEncoder = os.Getenv("FfmpegEncoder")
+	FfmpegProfiles = os.Getenv("FfmpegProfiles")
 	VodURLTemplate = os.Getenv("VodURLTemplate") // eg. https://stream.lrz.de/vod/_definst_/mp4:tum/RBG/%s.mp4/playlist.m3u8
+	DvrDir = os.Getenv("DvrDir")
+	if DvrDir == "" {
+		DvrDir = "/dvr"
+	}
+	DvrURLTemplate = os.Getenv("DvrURLTemplate")
+	OcrLanguages = os.Getenv("OcrLanguages")
+	if OcrLanguages == "" {
+		OcrLanguages = "eng"
+	}
 
 	// logging
 	LogDir = os.Getenv("LogDir")
@@ -13,6 +13,7 @@ import (
 
 	"github.com/TUM-Dev/gocast/worker/api"
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/grpcauth"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	"github.com/TUM-Dev/gocast/worker/rest"
 	"github.com/TUM-Dev/gocast/worker/worker"
@@ -20,7 +21,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // OsSignal contains the current os signal received.
@@ -42,9 +42,12 @@ func prepare() {
 func main() {
 	cfg.SetConfig()
 	prepare()
+	if err := grpcauth.Init(); err != nil {
+		log.Fatalf("Could not load MainCACert: %v", err)
+	}
 
 	log.Infof("Trying to connect worker %s to %s:50052", cfg.WorkerID, cfg.MainBase)
-	conn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithConnectParams(grpc.ConnectParams{
+	conn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpcauth.DialOption(), grpc.WithConnectParams(grpc.ConnectParams{
 		Backoff: backoff.Config{
 			BaseDelay:  1 * time.Second,
 			Multiplier: 1.6,
@@ -61,16 +64,31 @@ func main() {
 	client := pb.NewFromWorkerClient(conn)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
-	resp, err := client.JoinWorkers(ctx, &pb.JoinWorkersRequest{
+	req := &pb.JoinWorkersRequest{
 		Token:    cfg.Token,
 		Hostname: cfg.Hostname,
-	})
+	}
+	var certReq *grpcauth.Request
+	if grpcauth.Enabled() {
+		certReq, err = grpcauth.NewRequest()
+		if err != nil {
+			log.Fatalf("Could not create certificate request: %v", err)
+		}
+		req.Csr = certReq.CSR
+	}
+	resp, err := client.JoinWorkers(ctx, req)
 	if err != nil {
 		log.Warnf("Could not join main tumlive: %v\n", err)
 		return
 	}
 	cfg.WorkerID = resp.WorkerId
 	log.Infof("Joined main tumlive with worker id: %s\n", cfg.WorkerID)
+	if certReq != nil {
+		if err := certReq.Accept(resp.Certificate, resp.CaCertificate); err != nil {
+			log.Fatalf("Could not use certificate issued by main tumlive: %v", err)
+		}
+		go grpcauth.RenewPeriodically(worker.RenewCertificate)
+	}
 	worker.VersionTag = VersionTag
 	defer profile.Start(profile.MemProfile).Stop()
 	go func() {
@@ -84,14 +102,27 @@ func main() {
 	// setup apis
 	go api.InitApi(":50051")
 	go rest.InitApi(":8060")
+	go rest.ServeDvr(":8085")
 	worker.Setup()
 	OsSignal = make(chan os.Signal, 1)
 	awaitSignal()
 }
 
 // awaitSignal Keeps the application running until a signal is received.
+// The worker then drains: it finishes running jobs before exiting. A second signal exits immediately.
 func awaitSignal() {
 	signal.Notify(OsSignal, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
 	s := <-OsSignal
-	fmt.Printf("Exiting, received OsSignal: %s\n", s.String())
+	fmt.Printf("Draining, received OsSignal: %s\n", s.String())
+	drained := make(chan struct{})
+	go func() {
+		worker.Drain()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		fmt.Println("Exiting, all jobs finished")
+	case s = <-OsSignal:
+		fmt.Printf("Exiting without draining, received OsSignal: %s\n", s.String())
+	}
 }
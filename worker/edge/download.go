@@ -1,14 +1,81 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
+// downloadDir keeps the mp4s generated for downloads. Unlike the cache directory it isn't emptied on startup.
+var downloadDir = "/tmp/edge-downloads"
+
+// downloadTTL is how long generated downloads are kept after they were last requested
+var downloadTTL = time.Hour * 24
+
+// downloadDirMaxSize is the size in bytes downloadDir is kept below, the least recently requested downloads are
+// removed first
+var downloadDirMaxSize int64 = 100 << 30
+
+// downloadConversions limits how many mp4s are generated at the same time
+var downloadConversions = make(chan struct{}, 2)
+
+var (
+	downloadsLock     = sync.Mutex{}
+	downloads         = make(map[string]*downloadInfo) // generated downloads
+	downloadsInflight = make(map[string]bool)          // downloads that are being generated
+)
+
+type downloadInfo struct {
+	lastAccess time.Time
+	size       int64
+}
+
+var (
+	errDownloadInflight = errors.New("download is being generated already")
+	errDownloadsBusy    = errors.New("too many downloads are being generated")
+)
+
+// downloadQualities are the heights downloads are scaled to, 0 keeps the original
+var downloadQualities = map[string]int{"original": 0, "720p": 720, "480p": 480}
+
+// DownloadChapter is a chapter embedded in downloads, start and end are in milliseconds
+type DownloadChapter struct {
+	Title string `json:"title"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+// runFFmpeg runs ffmpeg with its output written to stdout, it is replaced in tests
+var runFFmpeg = func(args []string, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// downloadHandler sends a vod as mp4. The mp4 is generated once per playlist, quality and chapters and kept on disk,
+// so downloads can be resumed with range requests. Until it is ready, the original quality is streamed while it is
+// written to disk, other qualities and range requests are answered with 503 and a Retry-After header. HEAD requests
+// start generating the mp4 without waiting for it.
 func downloadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Access-Control-Allow-Origin", allowedOrigin)
 	var jwtClaims *JWTPlaylistClaims
 	if jwtPubKey != nil {
 		// validate token; every page access requires a valid jwt.
@@ -18,23 +85,300 @@ func downloadHandler(w http.ResponseWriter, r *http.Request) {
 			jwtClaims = claims
 		}
 	}
+	quality := r.URL.Query().Get("quality")
+	if quality == "" {
+		quality = "original"
+	}
+	height, ok := downloadQualities[quality]
+	if !ok {
+		http.Error(w, "Bad Request. Unknown quality.", http.StatusBadRequest)
+		return
+	}
+	var chapters []DownloadChapter
 	if jwtClaims != nil {
-		vodsDownloaded.WithLabelValues(jwtClaims.StreamID, jwtClaims.CourseID).Inc()
+		chapters = jwtClaims.Chapters
 	}
-	w.Header().Add("Content-Type", "video/mp4")
-	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", jwtClaims.GetFileName()))
-	c := exec.Command("ffmpeg",
-		"-i", "http://0.0.0.0"+port+strings.ReplaceAll(r.URL.String(), "download=1", ""),
-		"-c", "copy", "-bsf:a", "aac_adtstoasc", "-movflags", "frag_keyframe", "-f", "mp4", "-")
-	c.Stdout = w
-	err := c.Start()
+
+	input := *r.URL
+	query := input.Query()
+	query.Del("download")
+	query.Del("quality")
+	input.RawQuery = query.Encode()
+	inputURL := "http://0.0.0.0" + port + input.String()
+	file := filepath.Join(downloadDir, downloadKey(r.URL.Path, quality, chapters)+".mp4")
+	generateInBackground := func() {
+		go func() {
+			if err := generateDownload(file, inputURL, height, chapters, nil); err != nil && !errors.Is(err, errDownloadInflight) {
+				log.Println("Could not generate download: ", err)
+			}
+		}()
+	}
+
+	f, err := openDownload(file)
 	if err != nil {
-		log.Println(err.Error())
+		if r.Method == http.MethodHead {
+			generateInBackground()
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if height != 0 || r.Header.Get("Range") != "" {
+			generateInBackground()
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "Service Unavailable. The download is being prepared, please retry later.", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	if jwtClaims != nil && r.Method != http.MethodHead && r.Header.Get("Range") == "" {
+		vodsDownloaded.WithLabelValues(jwtClaims.StreamID, jwtClaims.CourseID).Inc()
+	}
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", jwtClaims.GetFileName()))
+	if f != nil {
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			http.Error(w, "Internal server error. Could not open download.", http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, "", info.ModTime(), f)
 		return
 	}
-	err = c.Wait()
+
+	// stream the original quality while it is written to disk. If that's not possible right now, it is streamed
+	// without keeping it, the output is the same so later range requests can resume from the cached mp4.
+	err = generateDownload(file, inputURL, 0, chapters, w)
+	if errors.Is(err, errDownloadInflight) || errors.Is(err, errDownloadsBusy) {
+		err = streamDownload(w, inputURL, chapters)
+	}
+	if err != nil {
+		log.Println("Could not stream download: ", err)
+	}
+}
+
+// openDownload opens the mp4 at file if it was generated already and marks it as accessed
+func openDownload(file string) (*os.File, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	downloadsLock.Lock()
+	if d, ok := downloads[file]; ok {
+		d.lastAccess = time.Now()
+	}
+	downloadsLock.Unlock()
+	return f, nil
+}
+
+// downloadKey identifies the mp4 of a playlist in a quality and with chapters
+func downloadKey(playlist string, quality string, chapters []DownloadChapter) string {
+	c, _ := json.Marshal(chapters)
+	sum := sha256.Sum256([]byte(playlist + "\n" + quality + "\n" + string(c)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// generateDownload converts the playlist at input to an mp4 at file unless that exists or is being generated already
+// (errDownloadInflight). Conversions wait for one of the downloadConversions; if client is set, the mp4 is streamed
+// to it while it is written and errDownloadsBusy is returned instead of waiting.
+func generateDownload(file string, input string, height int, chapters []DownloadChapter, client io.Writer) error {
+	downloadsLock.Lock()
+	if downloadsInflight[file] {
+		downloadsLock.Unlock()
+		return errDownloadInflight
+	}
+	if _, err := os.Stat(file); err == nil {
+		if d, ok := downloads[file]; ok {
+			d.lastAccess = time.Now()
+		}
+		downloadsLock.Unlock()
+		return nil
+	}
+	downloadsInflight[file] = true
+	downloadsLock.Unlock()
+	defer func() {
+		downloadsLock.Lock()
+		delete(downloadsInflight, file)
+		downloadsLock.Unlock()
+	}()
+
+	if client == nil {
+		downloadConversions <- struct{}{}
+	} else {
+		select {
+		case downloadConversions <- struct{}{}:
+		default:
+			return errDownloadsBusy
+		}
+	}
+	defer func() { <-downloadConversions }()
+
+	if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+		return err
+	}
+	// write to a temporary file first so incomplete downloads are never served
+	tmp := filepath.Join(downloadDir, ".download-"+filepath.Base(file))
+	defer os.Remove(tmp)
+	metadata, err := writeChapterMetadata(tmp+".txt", chapters)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(metadata)
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	var stdout io.Writer = out
+	if client != nil {
+		stdout = io.MultiWriter(out, &clientWriter{w: client})
+	}
+	err = runFFmpeg(downloadArgs(input, metadata, height), stdout)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return err
+	}
+	downloadsLock.Lock()
+	downloads[file] = &downloadInfo{lastAccess: time.Now(), size: info.Size()}
+	evictDownloads(file)
+	downloadsLock.Unlock()
+	return nil
+}
+
+// streamDownload sends the original quality of the playlist at input to w without keeping it
+func streamDownload(w io.Writer, input string, chapters []DownloadChapter) error {
+	tmp, err := os.CreateTemp("", "chapters-*.txt")
+	if err != nil {
+		return err
+	}
+	_ = tmp.Close()
+	defer os.Remove(tmp.Name())
+	metadata, err := writeChapterMetadata(tmp.Name(), chapters)
+	if err != nil {
+		return err
+	}
+	return runFFmpeg(downloadArgs(input, metadata, 0), w)
+}
+
+// clientWriter writes to a client until it disconnects without failing, so the mp4 is still written to disk
+type clientWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *clientWriter) Write(p []byte) (int, error) {
+	if c.err == nil {
+		_, c.err = c.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// writeChapterMetadata writes the chapters to file for ffmpeg and returns its name, or "" if there are no chapters
+func writeChapterMetadata(file string, chapters []DownloadChapter) (string, error) {
+	if len(chapters) == 0 {
+		return "", nil
+	}
+	return file, os.WriteFile(file, []byte(chapterMetadata(chapters)), 0o644)
+}
+
+// downloadArgs returns the ffmpeg arguments to convert a playlist to a fragmented mp4 written to stdout. Fragmented
+// mp4s don't need to be rewritten at the end, so the same bytes can be streamed and kept on disk.
+func downloadArgs(input string, metadata string, height int) []string {
+	args := []string{"-y", "-i", input}
+	if metadata != "" {
+		args = append(args, "-f", "ffmetadata", "-i", metadata, "-map_chapters", "1")
+	}
+	if height == 0 {
+		args = append(args, "-c", "copy")
+	} else {
+		args = append(args, "-vf", fmt.Sprintf("scale=-2:'min(%d,ih)'", height),
+			"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-c:a", "copy")
+	}
+	return append(args, "-bsf:a", "aac_adtstoasc", "-movflags", "frag_keyframe+empty_moov+default_base_moof", "-f", "mp4", "pipe:1")
+}
+
+// chapterMetadata returns the chapters in ffmpeg's metadata format
+func chapterMetadata(chapters []DownloadChapter) string {
+	escape := strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", " ")
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, c := range chapters {
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n", c.Start, c.End, escape.Replace(c.Title))
+	}
+	return b.String()
+}
+
+// loadDownloads registers the downloads generated before a restart, so they are cleaned up eventually
+func loadDownloads() {
+	entries, err := os.ReadDir(downloadDir)
 	if err != nil {
-		log.Println(err.Error())
 		return
 	}
+	downloadsLock.Lock()
+	defer downloadsLock.Unlock()
+	for _, entry := range entries {
+		file := filepath.Join(downloadDir, entry.Name())
+		if strings.HasPrefix(entry.Name(), ".download-") {
+			_ = os.Remove(file)
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			downloads[file] = &downloadInfo{lastAccess: info.ModTime(), size: info.Size()}
+		}
+	}
+}
+
+// cleanupDownloads removes the downloads that weren't requested for downloadTTL
+func cleanupDownloads() {
+	downloadsLock.Lock()
+	defer downloadsLock.Unlock()
+	removed := 0
+	for file, d := range downloads {
+		if time.Since(d.lastAccess) > downloadTTL {
+			removed++
+			removeDownload(file)
+		}
+	}
+	removed += evictDownloads("")
+	log.Println("Removed ", removed, " downloads")
+}
+
+// evictDownloads removes the least recently requested downloads except keep until they don't exceed
+// downloadDirMaxSize and returns how many it removed. downloadsLock must be held.
+func evictDownloads(keep string) int {
+	var size int64
+	files := make([]string, 0, len(downloads))
+	for file, d := range downloads {
+		size += d.size
+		files = append(files, file)
+	}
+	slices.SortFunc(files, func(a, b string) int { return downloads[a].lastAccess.Compare(downloads[b].lastAccess) })
+	removed := 0
+	for _, file := range files {
+		if size <= downloadDirMaxSize {
+			break
+		}
+		if file == keep {
+			continue
+		}
+		size -= downloads[file].size
+		removeDownload(file)
+		removed++
+	}
+	return removed
+}
+
+// removeDownload deletes a download from disk, downloadsLock must be held
+func removeDownload(file string) {
+	if err := os.Remove(file); err != nil {
+		log.Println("Could not remove download: ", err)
+	}
+	delete(downloads, file)
 }
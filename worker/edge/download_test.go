@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChapterMetadata(t *testing.T) {
+	metadata := chapterMetadata([]DownloadChapter{{Title: "Intro", Start: 0, End: 60000}, {Title: "a=b; #1", Start: 60000, End: 90000}})
+	expected := ";FFMETADATA1\n" +
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=60000\ntitle=Intro\n" +
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=60000\nEND=90000\ntitle=a\\=b\\; \\#1\n"
+	if metadata != expected {
+		t.Errorf("expected %q, got %q", expected, metadata)
+	}
+}
+
+func TestDownloadArgs(t *testing.T) {
+	args := downloadArgs("in.m3u8", "", 0)
+	if !slices.Contains(args, "copy") || slices.Contains(args, "-map_chapters") || args[len(args)-1] != "pipe:1" {
+		t.Errorf("unexpected args %v", args)
+	}
+	args = downloadArgs("in.m3u8", "chapters.txt", 720)
+	if !slices.Contains(args, "chapters.txt") || !slices.Contains(args, "libx264") || !slices.Contains(args, "scale=-2:'min(720,ih)'") {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestDownloadKey(t *testing.T) {
+	chapters := []DownloadChapter{{Title: "Intro", End: 1000}}
+	if downloadKey("/vod/a/playlist.m3u8", "original", nil) == downloadKey("/vod/a/playlist.m3u8", "720p", nil) {
+		t.Error("expected qualities to have different keys")
+	}
+	if downloadKey("/vod/a/playlist.m3u8", "original", nil) == downloadKey("/vod/a/playlist.m3u8", "original", chapters) {
+		t.Error("expected chapters to have different keys")
+	}
+}
+
+// fakeFFmpeg writes content to the output instead of converting and counts its runs. The conversion waits until
+// release is closed.
+func fakeFFmpeg(t *testing.T, content string) (runs *atomic.Int32, release chan struct{}) {
+	runs, release = &atomic.Int32{}, make(chan struct{})
+	oldRun, oldDir, oldConversions := runFFmpeg, downloadDir, downloadConversions
+	downloadDir = t.TempDir()
+	downloadConversions = make(chan struct{}, 1)
+	runFFmpeg = func(args []string, stdout io.Writer) error {
+		runs.Add(1)
+		<-release
+		_, err := stdout.Write([]byte(content))
+		return err
+	}
+	t.Cleanup(func() { runFFmpeg, downloadDir, downloadConversions = oldRun, oldDir, oldConversions })
+	return runs, release
+}
+
+func TestGenerateDownloadConcurrent(t *testing.T) {
+	runs, release := fakeFFmpeg(t, "mp4")
+	file := filepath.Join(downloadDir, "a.mp4")
+
+	done := make(chan error)
+	go func() { done <- generateDownload(file, "in.m3u8", 0, nil, nil) }()
+	for runs.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := generateDownload(file, "in.m3u8", 0, nil, nil); !errors.Is(err, errDownloadInflight) {
+		t.Errorf("expected the running conversion to be reused, got %v", err)
+	}
+	if err := generateDownload(filepath.Join(downloadDir, "b.mp4"), "in.m3u8", 0, nil, io.Discard); !errors.Is(err, errDownloadsBusy) {
+		t.Errorf("expected the conversions to be limited, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := generateDownload(file, "in.m3u8", 0, nil, nil); err != nil || runs.Load() != 1 {
+		t.Fatalf("expected one conversion, got %d (%v)", runs.Load(), err)
+	}
+	if content, err := os.ReadFile(file); err != nil || string(content) != "mp4" {
+		t.Errorf("unexpected download %q (%v)", content, err)
+	}
+}
+
+func TestEvictDownloads(t *testing.T) {
+	oldDownloads, oldMaxSize := downloads, downloadDirMaxSize
+	defer func() { downloads, downloadDirMaxSize = oldDownloads, oldMaxSize }()
+	dir := t.TempDir()
+	downloads = make(map[string]*downloadInfo)
+	downloadDirMaxSize = 10
+	for i, name := range []string{"old", "new", "kept"} {
+		file := filepath.Join(dir, name)
+		_ = os.WriteFile(file, nil, 0o644)
+		downloads[file] = &downloadInfo{lastAccess: time.Now().Add(time.Duration(i) * time.Minute), size: 6}
+	}
+
+	downloadsLock.Lock()
+	removed := evictDownloads(filepath.Join(dir, "old"))
+	downloadsLock.Unlock()
+	if removed != 2 || len(downloads) != 1 || downloads[filepath.Join(dir, "old")] == nil {
+		t.Errorf("expected the least recently requested downloads but keep to be removed, removed %d", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+		t.Errorf("expected removed downloads to be deleted, got %v", err)
+	}
+}
+
+// waitForDownload waits until file was generated in the background
+func waitForDownload(t *testing.T, file string) {
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		downloadsLock.Lock()
+		_, err := os.Stat(file)
+		done := err == nil && !downloadsInflight[file]
+		downloadsLock.Unlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the download to be generated in the background")
+		}
+	}
+}
+
+func TestDownloadHandler(t *testing.T) {
+	_, release := fakeFFmpeg(t, "0123456789")
+	close(release)
+	oldKey := jwtPubKey
+	jwtPubKey = nil
+	defer func() { jwtPubKey = oldKey }()
+
+	r := httptest.NewRequest(http.MethodGet, "/vod/course/1/playlist.m3u8?download=1&quality=720p", nil)
+	w := httptest.NewRecorder()
+	downloadHandler(w, r)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected transcoded downloads to be prepared first, got %d", w.Code)
+	}
+	waitForDownload(t, filepath.Join(downloadDir, downloadKey("/vod/course/1/playlist.m3u8", "720p", nil)+".mp4"))
+
+	r = httptest.NewRequest(http.MethodGet, "/vod/course/1/playlist.m3u8?download=1", nil)
+	w = httptest.NewRecorder()
+	downloadHandler(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" {
+		t.Fatalf("expected the original quality to be streamed, got %d %q", w.Code, w.Body.String())
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/vod/course/1/playlist.m3u8?download=1", nil)
+	r.Header.Set("Range", "bytes=4-")
+	w = httptest.NewRecorder()
+	downloadHandler(w, r)
+	if w.Code != http.StatusPartialContent || w.Body.String() != "456789" {
+		t.Errorf("expected the streamed download to be resumed, got %d %q", w.Code, w.Body.String())
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/vod/course/1/playlist.m3u8?download=1&quality=4k", nil)
+	w = httptest.NewRecorder()
+	downloadHandler(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected unknown quality to be rejected, got %d", w.Code)
+	}
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -18,6 +19,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -34,7 +36,7 @@ var (
 	inflightLock = sync.Mutex{}
 	inflight     = make(map[string]*sync.Mutex)
 
-	allowedRe = regexp.MustCompile(`^/[a-zA-Z0-9]+/([a-zA-Z0-9_]+/)*[a-zA-Z0-9_]+\.(ts|m3u8)$`) // e.g. /vm123/live/stream/1234.ts
+	allowedRe = regexp.MustCompile(`^/[a-zA-Z0-9]+/([a-zA-Z0-9_]+/)*[a-zA-Z0-9_]+\.(ts|m3u8|mp4|m4s)$`) // e.g. /vm123/live/stream/1234.ts or LL-HLS parts like /vm123/ll/stream/seg1_part2.mp4
 	// allowedRe = regexp.MustCompile("^.*$") // e.g. /vm123/live/strean/1234.ts
 )
 
@@ -88,6 +90,27 @@ func main() {
 		mainInstance = mainInstanceEnv
 	}
 	adminToken = os.Getenv("ADMIN_TOKEN")
+	if downloadDirEnv := os.Getenv("DOWNLOAD_DIR"); downloadDirEnv != "" {
+		downloadDir = downloadDirEnv
+	}
+	if maxSizeEnv := os.Getenv("DOWNLOAD_DIR_MAX_SIZE_GB"); maxSizeEnv != "" {
+		maxSize, err := strconv.ParseInt(maxSizeEnv, 10, 64)
+		if err != nil || maxSize <= 0 {
+			log.Fatalf("invalid DOWNLOAD_DIR_MAX_SIZE_GB %q", maxSizeEnv)
+		}
+		downloadDirMaxSize = maxSize << 30
+	}
+	if conversionsEnv := os.Getenv("DOWNLOAD_CONVERSIONS"); conversionsEnv != "" {
+		conversions, err := strconv.Atoi(conversionsEnv)
+		if err != nil || conversions <= 0 {
+			log.Fatalf("invalid DOWNLOAD_CONVERSIONS %q", conversionsEnv)
+		}
+		downloadConversions = make(chan struct{}, conversions)
+	}
+	if s3Bucket := os.Getenv("VOD_S3_BUCKET"); s3Bucket != "" {
+		vodFS = newS3FS(os.Getenv("VOD_S3_ENDPOINT"), os.Getenv("VOD_S3_REGION"), s3Bucket,
+			os.Getenv("VOD_S3_ACCESS_KEY"), os.Getenv("VOD_S3_SECRET_KEY"))
+	}
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
 		http.ListenAndServe(":2112", nil)
@@ -97,11 +120,16 @@ func main() {
 
 var vodFileServer http.Handler
 
+// vodFS serves vods from VOD_DIR or an S3 bucket if VOD_S3_BUCKET is set
+var vodFS http.FileSystem
+
 func ServeEdge(port string) {
 	prepare()
+	loadDownloads()
 	go func() {
 		for {
 			cleanup()
+			cleanupDownloads()
 			time.Sleep(time.Minute * 5)
 		}
 	}()
@@ -113,7 +141,10 @@ func ServeEdge(port string) {
 	}()
 
 	mux := http.NewServeMux()
-	vodFileServer = http.FileServer(http.Dir(vodPath))
+	if vodFS == nil {
+		vodFS = http.Dir(vodPath)
+	}
+	vodFileServer = http.FileServer(vodFS)
 	mux.HandleFunc("/vod/", vodHandler)
 	mux.HandleFunc("/", edgeHandler)
 
@@ -132,6 +163,7 @@ type JWTPlaylistClaims struct {
 	Download bool
 	StreamID string
 	CourseID string
+	Chapters []DownloadChapter `json:",omitempty"`
 }
 
 func (c *JWTPlaylistClaims) GetFileName() string {
@@ -229,7 +261,7 @@ func vodHandler(w http.ResponseWriter, r *http.Request) {
 				r.URL.Path = upath
 			}
 			r.URL.Path = strings.TrimPrefix(r.URL.Path, "/vod")
-			f, err := os.Open(path.Join(vodPath, path.Clean(r.URL.Path)))
+			f, err := vodFS.Open(path.Clean(r.URL.Path))
 			if err != nil {
 				err404Playlists.WithLabelValues(claims.StreamID, claims.Playlist).Inc()
 				w.WriteHeader(http.StatusNotFound)
@@ -237,9 +269,10 @@ func vodHandler(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			fileContents, err := io.ReadAll(f)
+			f.Close()
 			if err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write([]byte("Internal server error. Can't read file: " + f.Name()))
+				_, _ = w.Write([]byte("Internal server error. Can't read file: " + r.URL.Path))
 				return
 			}
 			lines := strings.Split(string(fileContents), "\n")
@@ -329,6 +362,11 @@ func edgeHandler(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 	err := fetchFile(urlParts[1], urlParts[2])
+	var statusErr originStatusError
+	if errors.As(err, &statusErr) {
+		writer.WriteHeader(statusErr.status)
+		return
+	}
 	if err != nil {
 		log.Printf("Could not fetch file: %v", err)
 		writer.WriteHeader(http.StatusBadGateway)
@@ -338,8 +376,19 @@ func edgeHandler(writer http.ResponseWriter, request *http.Request) {
 	http.ServeFile(writer, request, cacheDir+"/"+urlParts[2])
 }
 
+// originStatusError is returned by fetchFile if the origin doesn't respond with 200 OK, e.g. for a partial segment
+// that isn't available (yet). The response is not cached and the status is passed on to the client.
+type originStatusError struct {
+	status int
+}
+
+func (e originStatusError) Error() string {
+	return fmt.Sprintf("origin responded with %d", e.status)
+}
+
 // fetchFile fetches a file from the origin tumlive and persists it in the cache directory.
 // if the file is already in the cache, it is not fetched again.
+// Concurrent requests for the same file, e.g. blocking requests for LL-HLS preload hints, share one origin request.
 func fetchFile(host, file string) error {
 	diskDir := cacheDir + "/" + file
 	// check if file is already in cache
@@ -351,14 +400,21 @@ func fetchFile(host, file string) error {
 	}
 
 	inflightLock.Lock()
-	if _, ok = inflight[file]; !ok {
-		inflight[file] = &sync.Mutex{}
+	curLock, ok := inflight[file]
+	if !ok {
+		curLock = &sync.Mutex{}
+		inflight[file] = curLock
 	}
-	curLock := inflight[file]
-	curLock.Lock()
 	inflightLock.Unlock()
+	curLock.Lock()
 	defer curLock.Unlock()
-	defer delete(inflight, file)
+	defer func() {
+		inflightLock.Lock()
+		if inflight[file] == curLock {
+			delete(inflight, file)
+		}
+		inflightLock.Unlock()
+	}()
 
 	// check if file is already in cache after acquiring lock:
 	_, err := os.Stat(diskDir)
@@ -383,12 +439,23 @@ func fetchFile(host, file string) error {
 		return err
 	}
 	defer fileResp.Body.Close()
-	f, err := os.Create(diskDir)
+	if fileResp.StatusCode != http.StatusOK {
+		return originStatusError{status: fileResp.StatusCode}
+	}
+	// write to a temporary file first so incomplete files are never served
+	f, err := os.CreateTemp(d, ".fetch-*")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer os.Remove(f.Name())
 	_, err = f.ReadFrom(fileResp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Rename(f.Name(), diskDir)
 	if err != nil {
 		return err
 	}
@@ -398,7 +465,7 @@ func fetchFile(host, file string) error {
 	return nil
 }
 
-const cacheDir = "/tmp/edge"
+var cacheDir = "/tmp/edge"
 
 func cleanup() {
 	// find files older than one hour:
@@ -442,6 +509,11 @@ func prepare() {
 	if err != nil {
 		log.Println("Error setting mimetype for m3u8:", err)
 	}
+	// fMP4 segments of LL-HLS streams:
+	err = mime.AddExtensionType(".m4s", "video/iso.segment")
+	if err != nil {
+		log.Println("Error setting mimetype for m4s:", err)
+	}
 	retries := 0
 	backoff := time.Second
 	for retries < 5 { // allow for 5 retries with backoff to reach main instance
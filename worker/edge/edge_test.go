@@ -3,8 +3,14 @@ package main
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -167,3 +173,86 @@ func prepareJWT(exp time.Duration, playlist string) (string, error) {
 	}
 	return t.SignedString(key)
 }
+
+func TestAllowedRe(t *testing.T) {
+	allowed := []string{
+		"/vm123/live/stream/1234.ts",
+		"/vm123/live/stream/playlist.m3u8",
+		"/vm123/ll/stream/init.mp4",
+		"/vm123/ll/stream/seg1_part2.mp4",
+		"/vm123/ll/stream/seg1.m4s",
+	}
+	for _, p := range allowed {
+		if !allowedRe.MatchString(p) {
+			t.Errorf("%s should be allowed", p)
+		}
+	}
+	denied := []string{"/vm123/../etc/passwd", "/vm123/live/stream/1234.mp3", "/1234.ts", "/vm123/live/stream.ts/"}
+	for _, p := range denied {
+		if allowedRe.MatchString(p) {
+			t.Errorf("%s should be denied", p)
+		}
+	}
+}
+
+// originServer starts a test server serving as origin for fetchFile and returns its host.
+func originServer(t *testing.T, handler http.HandlerFunc) string {
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	u, _ := url.Parse(s.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	originProto, originPort, cacheDir = "http://", port, t.TempDir()
+	return host
+}
+
+func TestFetchFileNotCachedOnError(t *testing.T) {
+	var available atomic.Bool
+	host := originServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("part"))
+	})
+
+	var statusErr originStatusError
+	err := fetchFile(host, "ll/stream/seg1_part1.mp4")
+	if !errors.As(err, &statusErr) || statusErr.status != http.StatusNotFound {
+		t.Fatalf("expected 404 from origin, got %v", err)
+	}
+	if _, err := os.Stat(cacheDir + "/ll/stream/seg1_part1.mp4"); !os.IsNotExist(err) {
+		t.Fatal("unavailable part must not be cached")
+	}
+
+	available.Store(true)
+	if err := fetchFile(host, "ll/stream/seg1_part1.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(cacheDir + "/ll/stream/seg1_part1.mp4")
+	if err != nil || string(content) != "part" {
+		t.Fatalf("expected part to be cached, got %q, %v", content, err)
+	}
+}
+
+func TestFetchFileConcurrent(t *testing.T) {
+	var requests atomic.Int32
+	host := originServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("part"))
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fetchFile(host, "ll/stream/seg2_part1.mp4"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if requests.Load() != 1 {
+		t.Fatalf("expected one request to origin, got %d", requests.Load())
+	}
+}
@@ -18,3 +18,8 @@ require (
 	golang.org/x/sys v0.11.0 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
 )
+
+require github.com/TUM-Dev/gocast/worker/edge/sigv4 v0.0.0
+
+// sigv4 is shared with the edge, whose image only contains ./edge
+replace github.com/TUM-Dev/gocast/worker/edge/sigv4 => ./sigv4
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/edge/sigv4"
+)
+
+// s3FS is a read only http.FileSystem that serves vods from an S3-compatible bucket.
+// Objects are read with ranged GET requests so seeking (e.g. for range requests of players) doesn't download whole files.
+type s3FS struct {
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func newS3FS(endpoint, region, bucket, accessKey, secretKey string) *s3FS {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3FS{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		// no overall timeout: it would also limit reading bodies, which are streamed to slow clients
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: time.Minute,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConnsPerHost:   16,
+		}},
+	}
+}
+
+// Open implements http.FileSystem. Directories can't be listed and are reported as not existing.
+func (s *s3FS) Open(name string) (http.File, error) {
+	key := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if key == "" {
+		return nil, os.ErrNotExist
+	}
+	resp, err := s.do(http.MethodHead, key, "")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content length for %s: %w", key, err)
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &s3File{fs: s, key: key, info: s3FileInfo{name: path.Base(key), size: size, modTime: modTime}}, nil
+}
+
+func (s *s3FS) do(method, key, rangeHeader string) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.endpoint+"/"+sigv4.URIEncode(s.bucket)+"/"+sigv4.EscapePath(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	sigv4.Sign(req, sigv4.EmptyPayloadHash, s.region, s.accessKey, s.secretKey, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, os.ErrNotExist
+	case resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: unexpected status %s", method, key, resp.Status)
+	}
+	return resp, nil
+}
+
+type s3File struct {
+	fs     *s3FS
+	key    string
+	info   s3FileInfo
+	offset int64
+	body   io.ReadCloser // open GET request starting at offset, nil if none
+}
+
+func (f *s3File) Read(p []byte) (int, error) {
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		resp, err := f.fs.do(http.MethodGet, f.key, fmt.Sprintf("bytes=%d-", f.offset))
+		if err != nil {
+			return 0, err
+		}
+		f.body = resp.Body
+	}
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	if errors.Is(err, io.EOF) && f.offset < f.info.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before start of file")
+	}
+	if offset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *s3File) Close() error {
+	if f.body != nil {
+		return f.body.Close()
+	}
+	return nil
+}
+
+func (f *s3File) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (f *s3File) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+type s3FileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i s3FileInfo) Name() string       { return i.name }
+func (i s3FileInfo) Size() int64        { return i.size }
+func (i s3FileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i s3FileInfo) ModTime() time.Time { return i.modTime }
+func (i s3FileInfo) IsDir() bool        { return false }
+func (i s3FileInfo) Sys() any           { return nil }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestS3FS(t *testing.T) {
+	content := "#EXTM3U\n#EXT-X-VERSION:3\nsegment0.ts\n"
+	bucket := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/vod/course/playlist.m3u8" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "playlist.m3u8", time.Now(), strings.NewReader(content))
+	}))
+	defer bucket.Close()
+
+	fs := newS3FS(bucket.URL, "", "vod", "access", "secret")
+	if _, err := fs.Open("/course/missing.m3u8"); err == nil {
+		t.Fatal("expected error for missing object")
+	}
+	f, err := fs.Open("/course/playlist.m3u8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if info, _ := f.Stat(); info.Size() != int64(len(content)) {
+		t.Fatalf("expected size %d, got %d", len(content), info.Size())
+	}
+	if _, err = f.Seek(8, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != content[8:] {
+		t.Fatalf("expected %q after seek, got %q", content[8:], rest)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/course/playlist.m3u8", nil)
+	req.Header.Set("Range", "bytes=0-6")
+	http.FileServer(fs).ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "#EXTM3U" {
+		t.Fatalf("unexpected range response %d: %q", rec.Code, rec.Body.String())
+	}
+}
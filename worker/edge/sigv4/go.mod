@@ -0,0 +1,3 @@
+module github.com/TUM-Dev/gocast/worker/edge/sigv4
+
+go 1.21
@@ -0,0 +1,108 @@
+// Package sigv4 signs requests to S3-compatible storage with AWS signature version 4.
+// It is a module without dependencies inside the edge, so both the edge and the worker image can build it.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmptyPayloadHash is the SHA256 of an empty body
+const EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Sign signs req with AWS signature version 4, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func Sign(req *http.Request, payloadHash, region, accessKey, secretKey string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-amz-") || k == "content-md5" || k == "content-type" || k == "range" {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + SHA256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// CanonicalQuery encodes query sorted by key with spaces as %20 as required by signature version 4
+func CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, URIEncode(k)+"="+URIEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// EscapePath encodes every segment of an object key
+func EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = URIEncode(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// URIEncode encodes everything but unreserved characters (A-Z, a-z, 0-9, '-', '.', '_' and '~')
+func URIEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// SHA256Hex returns the hex encoded SHA256 of data, as used for payload hashes
+func SHA256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
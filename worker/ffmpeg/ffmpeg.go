@@ -0,0 +1,77 @@
+// Package ffmpeg builds ffmpeg invocations from typed options and named encoder profiles.
+// Commands are executed directly, without a shell.
+package ffmpeg
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// Binary is the ffmpeg executable
+var Binary = "ffmpeg"
+
+// Command is an ffmpeg invocation with its inputs and outputs
+type Command struct {
+	niceness int // niceness > 0 runs ffmpeg with nice
+	global   []string
+	inputs   []file
+	outputs  []file
+}
+
+// file is an input or output with the options that apply to it
+type file struct {
+	options []string
+	url     string
+}
+
+// New creates an empty command
+func New() *Command {
+	return &Command{}
+}
+
+// Nice runs ffmpeg with the given niceness
+func (c *Command) Nice(niceness int) *Command {
+	c.niceness = niceness
+	return c
+}
+
+// Global adds options that apply to the whole invocation, e.g. -hide_banner or -y
+func (c *Command) Global(options ...string) *Command {
+	c.global = append(c.global, options...)
+	return c
+}
+
+// Input adds an input with options that are placed before its -i
+func (c *Command) Input(url string, options ...string) *Command {
+	c.inputs = append(c.inputs, file{options: options, url: url})
+	return c
+}
+
+// Output adds an output with options that are placed before its url. Use "-" or "pipe:1" to write to stdout.
+func (c *Command) Output(url string, options ...string) *Command {
+	c.outputs = append(c.outputs, file{options: options, url: url})
+	return c
+}
+
+// Args returns the arguments passed to ffmpeg
+func (c *Command) Args() []string {
+	var args []string
+	args = append(args, c.global...)
+	for _, in := range c.inputs {
+		args = append(args, in.options...)
+		args = append(args, "-i", in.url)
+	}
+	for _, out := range c.outputs {
+		args = append(args, out.options...)
+		args = append(args, out.url)
+	}
+	return args
+}
+
+// Cmd returns the command, ready to be run
+func (c *Command) Cmd() *exec.Cmd {
+	if c.niceness > 0 {
+		return exec.Command("nice", append([]string{"-n", strconv.Itoa(c.niceness), Binary}, c.Args()...)...)
+	}
+	return exec.Command(Binary, c.Args()...)
+}
@@ -0,0 +1,121 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	cmd := New().
+		Global("-y").
+		Input("in.ts", "-ss", "10").
+		Output("-", "-c", "copy", "-f", "mpegts").
+		Output("out.mp4", "-c:v", "libx264")
+	expected := []string{"-y", "-ss", "10", "-i", "in.ts", "-c", "copy", "-f", "mpegts", "-", "-c:v", "libx264", "out.mp4"}
+	if args := cmd.Args(); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+	if c := cmd.Cmd(); !reflect.DeepEqual(c.Args, append([]string{"ffmpeg"}, expected...)) {
+		t.Fatalf("unexpected command without niceness: %v", c.Args)
+	}
+	if c := cmd.Nice(10).Cmd(); !reflect.DeepEqual(c.Args[:4], []string{"nice", "-n", "10", "ffmpeg"}) {
+		t.Fatalf("unexpected command with niceness: %v", c.Args)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	defer func() { _ = Configure(EncoderSoftware, "") }()
+	tests := []struct {
+		name     string
+		encoder  Encoder
+		profile  string
+		expected []string
+	}{
+		{
+			name:    "vod pres",
+			profile: ProfileVodPres,
+			expected: []string{"-c:v", "libx264", "-tune", "stillimage", "-level", "4.0", "-crf", "20",
+				"-vsync", "2", "-movflags", "+faststart", "-c:a", "aac", "-b:a", "128k"},
+		},
+		{
+			name:     "audio",
+			profile:  ProfileAudio,
+			expected: []string{"-vn", "-c:a", "aac"},
+		},
+		{
+			name:    "live",
+			profile: ProfileLive,
+			expected: []string{"-c:v", "libx264", "-preset", "veryfast", "-tune", "zerolatency", "-maxrate", "2500k",
+				"-bufsize", "3000k", "-r", "30", "-g", "60", "-x264-params", "keyint=60:scenecut=0",
+				"-c:a", "aac", "-b:a", "128k", "-ar", "44100"},
+		},
+		{
+			name:    "live nvenc",
+			encoder: EncoderNVENC,
+			profile: ProfileLive,
+			expected: []string{"-c:v", "h264_nvenc", "-preset", "p2", "-tune", "ll", "-maxrate", "2500k",
+				"-bufsize", "3000k", "-r", "30", "-g", "60", "-c:a", "aac", "-b:a", "128k", "-ar", "44100"},
+		},
+		{
+			name:    "vod comb vaapi",
+			encoder: EncoderVAAPI,
+			profile: ProfileVodComb,
+			expected: []string{"-vf", "format=nv12,hwupload", "-c:v", "h264_vaapi", "-level", "4.0", "-qp", "24",
+				"-vsync", "2", "-movflags", "+faststart", "-c:a", "aac", "-b:a", "128k"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Configure(test.encoder, ""); err != nil {
+				t.Fatal(err)
+			}
+			if args := Get(test.profile).OutputArgs(); !reflect.DeepEqual(args, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, args)
+			}
+		})
+	}
+	if err := Configure(EncoderVAAPI, ""); err != nil {
+		t.Fatal(err)
+	}
+	if in := Get(ProfileVodCam).InputOptions; !reflect.DeepEqual(in, []string{"-vaapi_device", VAAPIDevice}) {
+		t.Fatalf("vaapi profiles must select the device, got %v", in)
+	}
+	if err := Configure("quicksync", ""); err == nil {
+		t.Fatal("expected error for unknown encoder")
+	}
+}
+
+func TestConfigureOverrides(t *testing.T) {
+	defer func() { _ = Configure(EncoderSoftware, "") }()
+	file := filepath.Join(t.TempDir(), "profiles.json")
+	err := os.WriteFile(file, []byte(`{"vod-cam": {"crf": 28, "preset": "slow"}, "custom": {"videoCodec": "libx265"}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Configure(EncoderSoftware, file); err != nil {
+		t.Fatal(err)
+	}
+	cam := Get(ProfileVodCam)
+	if cam.CRF != 28 || cam.Preset != "slow" || cam.Niceness != 10 || cam.Level != "4.0" {
+		t.Fatalf("override wasn't merged with the default profile: %+v", cam)
+	}
+	if Get(ProfileVodPres).CRF != 20 {
+		t.Fatal("profiles without overrides must keep their defaults")
+	}
+	if !strings.Contains(strings.Join(Get("custom").OutputArgs(), " "), "-c:v libx265") {
+		t.Fatal("new profiles can be added by overrides")
+	}
+
+	if err = os.WriteFile(file, []byte(`{"vod-cam": {"crf": "high"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err = Configure(EncoderSoftware, file); err == nil {
+		t.Fatal("expected error for invalid override")
+	}
+	if Get(ProfileVodCam).CRF != 28 {
+		t.Fatal("invalid overrides must not replace the configured profiles")
+	}
+}
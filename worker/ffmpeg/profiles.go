@@ -0,0 +1,262 @@
+package ffmpeg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in profiles
+const (
+	ProfileLive     = "live"     // ProfileLive encodes the live stream pushed to the ingest server
+	ProfileVodCam   = "vod-cam"  // ProfileVodCam transcodes camera recordings (and recordings of unknown sources)
+	ProfileVodPres  = "vod-pres" // ProfileVodPres transcodes presentation recordings
+	ProfileVodComb  = "vod-comb" // ProfileVodComb transcodes combined recordings
+	ProfilePremiere = "premiere" // ProfilePremiere streams a video file as a premiere
+	ProfileAudio    = "audio"    // ProfileAudio extracts the audio track of a VoD
+)
+
+// Profile is a named set of encoder settings. Empty values are omitted from the command.
+type Profile struct {
+	Niceness     int      `json:"niceness"`     // Niceness > 0 runs ffmpeg with nice
+	InputOptions []string `json:"inputOptions"` // InputOptions are placed before -i, e.g. for hardware decoding
+
+	NoVideo      bool     `json:"noVideo"`
+	VideoCodec   string   `json:"videoCodec"`
+	Preset       string   `json:"preset"`
+	Tune         string   `json:"tune"`
+	H264Profile  string   `json:"h264Profile"`
+	Level        string   `json:"level"`
+	CRF          int      `json:"crf"` // CRF is the constant quality, mapped to -cq for nvenc and -qp for vaapi
+	VideoBitrate string   `json:"videoBitrate"`
+	MaxRate      string   `json:"maxRate"`
+	BufSize      string   `json:"bufSize"`
+	FrameRate    int      `json:"frameRate"`
+	GOP          int      `json:"gop"`
+	PixelFormat  string   `json:"pixelFormat"`
+	VideoFilter  string   `json:"videoFilter"`
+	VideoOptions []string `json:"videoOptions"` // VideoOptions are additional output options, e.g. -movflags +faststart
+
+	AudioCodec    string `json:"audioCodec"`
+	AudioBitrate  string `json:"audioBitrate"`
+	AudioRate     int    `json:"audioRate"`
+	AudioChannels int    `json:"audioChannels"`
+	AudioFilter   string `json:"audioFilter"`
+}
+
+// OutputArgs returns the output options encoding with the profile
+func (p Profile) OutputArgs() []string {
+	var args []string
+	opt := func(name, value string) {
+		if value != "" {
+			args = append(args, name, value)
+		}
+	}
+	num := func(name string, value int) {
+		if value != 0 {
+			args = append(args, name, strconv.Itoa(value))
+		}
+	}
+	if p.NoVideo {
+		args = append(args, "-vn")
+	} else {
+		opt("-pix_fmt", p.PixelFormat)
+		opt("-vf", p.VideoFilter)
+		opt("-c:v", p.VideoCodec)
+		opt("-preset", p.Preset)
+		opt("-tune", p.Tune)
+		opt("-profile:v", p.H264Profile)
+		opt("-level", p.Level)
+		switch p.VideoCodec {
+		case "h264_nvenc":
+			num("-cq", p.CRF)
+		case "h264_vaapi":
+			num("-qp", p.CRF)
+		default:
+			num("-crf", p.CRF)
+		}
+		opt("-b:v", p.VideoBitrate)
+		opt("-maxrate", p.MaxRate)
+		opt("-bufsize", p.BufSize)
+		num("-r", p.FrameRate)
+		num("-g", p.GOP)
+		args = append(args, p.VideoOptions...)
+	}
+	opt("-c:a", p.AudioCodec)
+	opt("-b:a", p.AudioBitrate)
+	num("-ar", p.AudioRate)
+	num("-ac", p.AudioChannels)
+	opt("-af", p.AudioFilter)
+	return args
+}
+
+// Encoder is the implementation used for h264 encoding
+type Encoder string
+
+const (
+	EncoderSoftware Encoder = "software" // EncoderSoftware uses libx264
+	EncoderVAAPI    Encoder = "vaapi"    // EncoderVAAPI uses intel/amd gpus via VAAPI
+	EncoderNVENC    Encoder = "nvenc"    // EncoderNVENC uses nvidia gpus
+)
+
+// VAAPIDevice is the render device used by EncoderVAAPI
+var VAAPIDevice = "/dev/dri/renderD128"
+
+// forEncoder adapts a libx264 profile to a hardware encoder. Options only libx264 understands are dropped.
+func (p Profile) forEncoder(e Encoder) Profile {
+	if p.NoVideo || p.VideoCodec != "libx264" || e == EncoderSoftware || e == "" {
+		return p
+	}
+	var videoOptions []string
+	for i := 0; i < len(p.VideoOptions); i++ {
+		if p.VideoOptions[i] == "-x264-params" {
+			i++ // skip value
+			continue
+		}
+		videoOptions = append(videoOptions, p.VideoOptions[i])
+	}
+	p.VideoOptions = videoOptions
+	switch e {
+	case EncoderVAAPI:
+		p.InputOptions = append([]string{"-vaapi_device", VAAPIDevice}, p.InputOptions...)
+		p.VideoFilter = strings.Trim(p.VideoFilter+",format=nv12,hwupload", ",")
+		p.VideoCodec = "h264_vaapi"
+		p.PixelFormat = "" // frames are uploaded to the gpu by the filter
+		p.Preset = ""
+		p.Tune = ""
+	case EncoderNVENC:
+		p.VideoCodec = "h264_nvenc"
+		if p.Preset == "veryfast" || p.Preset == "ultrafast" || p.Preset == "superfast" {
+			p.Preset = "p2"
+		} else {
+			p.Preset = "p4"
+		}
+		if p.Tune == "zerolatency" {
+			p.Tune = "ll"
+		} else {
+			p.Tune = ""
+		}
+	}
+	return p
+}
+
+// DefaultProfiles returns the built-in profiles
+func DefaultProfiles() map[string]Profile {
+	vod := Profile{
+		VideoCodec:   "libx264",
+		Level:        "4.0",
+		VideoOptions: []string{"-vsync", "2", "-movflags", "+faststart"},
+		AudioCodec:   "aac",
+		AudioBitrate: "128k",
+	}
+	cam, pres, comb := vod, vod, vod
+	// compress camera image slightly more
+	cam.Niceness, cam.CRF = 10, 26
+	pres.Niceness, pres.CRF, pres.Tune = 9, 20, "stillimage"
+	comb.Niceness, comb.CRF = 8, 24
+	return map[string]Profile{
+		ProfileLive: {
+			VideoCodec:   "libx264",
+			Preset:       "veryfast",
+			Tune:         "zerolatency",
+			MaxRate:      "2500k",
+			BufSize:      "3000k",
+			GOP:          60,
+			FrameRate:    30,
+			VideoOptions: []string{"-x264-params", "keyint=60:scenecut=0"},
+			AudioCodec:   "aac",
+			AudioRate:    44100,
+			AudioBitrate: "128k",
+		},
+		ProfileVodCam:  cam,
+		ProfileVodPres: pres,
+		ProfileVodComb: comb,
+		ProfilePremiere: {
+			PixelFormat:   "yuv420p",
+			VideoCodec:    "libx264",
+			Preset:        "veryfast",
+			Tune:          "film",
+			H264Profile:   "baseline",
+			VideoBitrate:  "2500k",
+			MaxRate:       "3000k",
+			BufSize:       "3000k",
+			FrameRate:     30,
+			GOP:           60,
+			VideoOptions:  []string{"-vsync", "1", "-threads", "0", "-sc_threshold", "0"},
+			AudioCodec:    "aac",
+			AudioBitrate:  "128k",
+			AudioChannels: 2,
+			AudioRate:     48000,
+			AudioFilter:   "aresample=async=1:min_hard_comp=0.100000:first_pts=0",
+		},
+		ProfileAudio: {
+			NoVideo:    true,
+			AudioCodec: "aac",
+		},
+	}
+}
+
+var (
+	mutex    sync.RWMutex
+	profiles = DefaultProfiles()
+	encoder  = EncoderSoftware
+)
+
+// Get returns the profile with the given name adapted to the configured encoder
+func Get(name string) Profile {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	p, ok := profiles[name]
+	if !ok {
+		p = profiles[ProfileVodCam]
+	}
+	return p.forEncoder(encoder)
+}
+
+// VodProfile returns the name of the profile used to transcode recordings of a source type (CAM, PRES or COMB)
+func VodProfile(sourceType string) string {
+	switch sourceType {
+	case "PRES":
+		return ProfileVodPres
+	case "COMB":
+		return ProfileVodComb
+	default:
+		return ProfileVodCam
+	}
+}
+
+// Configure sets the encoder and overrides profiles with the settings in overridesFile (if not empty).
+// The file is a JSON object mapping profile names to settings; settings missing in the file keep their defaults.
+func Configure(e Encoder, overridesFile string) error {
+	switch e {
+	case "", EncoderSoftware, EncoderVAAPI, EncoderNVENC:
+	default:
+		return fmt.Errorf("unknown encoder %q", e)
+	}
+	configured := DefaultProfiles()
+	if overridesFile != "" {
+		data, err := os.ReadFile(overridesFile)
+		if err != nil {
+			return fmt.Errorf("read profile overrides: %w", err)
+		}
+		var overrides map[string]json.RawMessage
+		if err = json.Unmarshal(data, &overrides); err != nil {
+			return fmt.Errorf("parse profile overrides: %w", err)
+		}
+		for name, override := range overrides {
+			p := configured[name]
+			if err = json.Unmarshal(override, &p); err != nil {
+				return fmt.Errorf("parse profile %s: %w", name, err)
+			}
+			configured[name] = p
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	profiles = configured
+	encoder = e
+	return nil
+}
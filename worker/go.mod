@@ -48,3 +48,8 @@ require (
 	golang.org/x/image v0.18.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240108191215-35c7eff3a6b1 // indirect
 )
+
+require github.com/TUM-Dev/gocast/worker/edge/sigv4 v0.0.0
+
+// sigv4 is shared with the edge, whose image only contains ./edge
+replace github.com/TUM-Dev/gocast/worker/edge/sigv4 => ./edge/sigv4
@@ -0,0 +1,218 @@
+// Package grpcauth holds the certificate TUM-Live issued to this worker and authenticates gRPC connections with it.
+// Connections are unencrypted if MainCACert isn't set.
+package grpcauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/cfg"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// tumLiveName is the common name of TUM-Live's certificate, only TUM-Live may call the worker
+const tumLiveName = "tumlive"
+
+var (
+	mu       sync.RWMutex
+	rootCAs  *x509.CertPool // verifies TUM-Live when connecting to it
+	clientCA *x509.CertPool // verifies TUM-Live when it connects to the worker
+	cert     *tls.Certificate
+)
+
+// Enabled returns whether connections are authenticated with certificates
+func Enabled() bool {
+	return cfg.MainCACert != ""
+}
+
+// Init loads the CA certificate of TUM-Live
+func Init() error {
+	if !Enabled() {
+		log.Warn("MainCACert is not set, connections to TUM-Live are neither encrypted nor authenticated")
+		return nil
+	}
+	ca, err := os.ReadFile(cfg.MainCACert)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return errors.New("no certificate found in MainCACert")
+	}
+	mu.Lock()
+	rootCAs, clientCA = pool, pool
+	mu.Unlock()
+	return nil
+}
+
+// Request is a request for a certificate, its key is used once TUM-Live issued the certificate
+type Request struct {
+	key *ecdsa.PrivateKey
+	CSR []byte // PEM encoded certificate signing request
+}
+
+// NewRequest creates a key and a request for its certificate
+func NewRequest() (*Request, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cfg.Hostname}}, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{key: key, CSR: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})}, nil
+}
+
+// Accept makes the certificate issued for the request the worker's certificate
+func (r *Request) Accept(certPEM, caPEM []byte) error {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return errors.New("no certificate issued")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if pub, ok := leaf.PublicKey.(*ecdsa.PublicKey); !ok || !pub.Equal(r.key.Public()) {
+		return errors.New("certificate was issued for another key")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return errors.New("no CA certificate")
+	}
+	mu.Lock()
+	cert = &tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: r.key, Leaf: leaf}
+	clientCA = pool
+	mu.Unlock()
+	log.WithField("expires", leaf.NotAfter).Info("Certificate issued")
+	return nil
+}
+
+// needsRenewal returns whether two thirds of the validity of the worker's certificate have passed
+func needsRenewal(now time.Time) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if cert == nil {
+		return true
+	}
+	validity := cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore)
+	return now.After(cert.Leaf.NotBefore.Add(validity * 2 / 3))
+}
+
+// RenewPeriodically requests a new certificate with renew before the current one expires
+func RenewPeriodically(renew func(csr []byte) (certPEM []byte, caPEM []byte, err error)) {
+	for range time.Tick(time.Hour) {
+		if !needsRenewal(time.Now()) {
+			continue
+		}
+		r, err := NewRequest()
+		if err != nil {
+			log.WithError(err).Error("Can't create certificate request")
+			continue
+		}
+		c, ca, err := renew(r.CSR)
+		if err == nil {
+			err = r.Accept(c, ca)
+		}
+		if err != nil {
+			log.WithError(err).Error("Can't renew certificate")
+		}
+	}
+}
+
+func getCertificate() (*tls.Certificate, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if cert == nil {
+		return &tls.Certificate{}, nil // no certificate yet, only joining the worker pool is allowed
+	}
+	return cert, nil
+}
+
+// DialOption returns the credentials for connecting to TUM-Live
+func DialOption() grpc.DialOption {
+	if !Enabled() {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    rootCAs,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return getCertificate()
+		},
+	}))
+}
+
+// ServerOptions returns the options of the server TUM-Live connects to, it only accepts calls from TUM-Live
+func ServerOptions() []grpc.ServerOption {
+	if !Enabled() {
+		return nil
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			mu.RLock()
+			defer mu.RUnlock()
+			if cert == nil {
+				return nil, errors.New("no certificate issued yet")
+			}
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    clientCA,
+			}, nil
+		},
+	}
+	return []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(config)),
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := checkTUMLive(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := checkTUMLive(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// checkTUMLive verifies the caller is TUM-Live and not e.g. another worker with a certificate of the same CA
+func checkTUMLive(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no peer")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return status.Error(codes.Unauthenticated, "client certificate required")
+	}
+	if name := info.State.VerifiedChains[0][0].Subject.CommonName; name != tumLiveName {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("calls from %s are not allowed", name))
+	}
+	return nil
+}
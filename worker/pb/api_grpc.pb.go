@@ -456,6 +456,8 @@ const (
 	FromWorker_SendSelfStreamRequest_FullMethodName     = "/api.FromWorker/SendSelfStreamRequest"
 	FromWorker_GetStreamInfoForUpload_FullMethodName    = "/api.FromWorker/GetStreamInfoForUpload"
 	FromWorker_NotifyTranscodingFailure_FullMethodName  = "/api.FromWorker/NotifyTranscodingFailure"
+	FromWorker_NotifySlideSuggestions_FullMethodName    = "/api.FromWorker/NotifySlideSuggestions"
+	FromWorker_RenewCertificate_FullMethodName          = "/api.FromWorker/RenewCertificate"
 )
 
 // FromWorkerClient is the client API for FromWorker service.
@@ -475,6 +477,8 @@ type FromWorkerClient interface {
 	SendSelfStreamRequest(ctx context.Context, in *SelfStreamRequest, opts ...grpc.CallOption) (*SelfStreamResponse, error)
 	GetStreamInfoForUpload(ctx context.Context, in *GetStreamInfoForUploadRequest, opts ...grpc.CallOption) (*GetStreamInfoForUploadResponse, error)
 	NotifyTranscodingFailure(ctx context.Context, in *NotifyTranscodingFailureRequest, opts ...grpc.CallOption) (*NotifyTranscodingFailureResponse, error)
+	NotifySlideSuggestions(ctx context.Context, in *SlideSuggestions, opts ...grpc.CallOption) (*Status, error)
+	RenewCertificate(ctx context.Context, in *RenewCertificateRequest, opts ...grpc.CallOption) (*RenewCertificateResponse, error)
 }
 
 type fromWorkerClient struct {
@@ -618,6 +622,24 @@ func (c *fromWorkerClient) NotifyTranscodingFailure(ctx context.Context, in *Not
 	return out, nil
 }
 
+func (c *fromWorkerClient) NotifySlideSuggestions(ctx context.Context, in *SlideSuggestions, opts ...grpc.CallOption) (*Status, error) {
+	out := new(Status)
+	err := c.cc.Invoke(ctx, FromWorker_NotifySlideSuggestions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fromWorkerClient) RenewCertificate(ctx context.Context, in *RenewCertificateRequest, opts ...grpc.CallOption) (*RenewCertificateResponse, error) {
+	out := new(RenewCertificateResponse)
+	err := c.cc.Invoke(ctx, FromWorker_RenewCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FromWorkerServer is the server API for FromWorker service.
 // All implementations must embed UnimplementedFromWorkerServer
 // for forward compatibility
@@ -635,6 +657,8 @@ type FromWorkerServer interface {
 	SendSelfStreamRequest(context.Context, *SelfStreamRequest) (*SelfStreamResponse, error)
 	GetStreamInfoForUpload(context.Context, *GetStreamInfoForUploadRequest) (*GetStreamInfoForUploadResponse, error)
 	NotifyTranscodingFailure(context.Context, *NotifyTranscodingFailureRequest) (*NotifyTranscodingFailureResponse, error)
+	NotifySlideSuggestions(context.Context, *SlideSuggestions) (*Status, error)
+	RenewCertificate(context.Context, *RenewCertificateRequest) (*RenewCertificateResponse, error)
 	mustEmbedUnimplementedFromWorkerServer()
 }
 
@@ -678,6 +702,12 @@ func (UnimplementedFromWorkerServer) GetStreamInfoForUpload(context.Context, *Ge
 func (UnimplementedFromWorkerServer) NotifyTranscodingFailure(context.Context, *NotifyTranscodingFailureRequest) (*NotifyTranscodingFailureResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyTranscodingFailure not implemented")
 }
+func (UnimplementedFromWorkerServer) NotifySlideSuggestions(context.Context, *SlideSuggestions) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NotifySlideSuggestions not implemented")
+}
+func (UnimplementedFromWorkerServer) RenewCertificate(context.Context, *RenewCertificateRequest) (*RenewCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewCertificate not implemented")
+}
 func (UnimplementedFromWorkerServer) mustEmbedUnimplementedFromWorkerServer() {}
 
 // UnsafeFromWorkerServer may be embedded to opt out of forward compatibility for this service.
@@ -915,6 +945,42 @@ func _FromWorker_NotifyTranscodingFailure_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FromWorker_NotifySlideSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SlideSuggestions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FromWorkerServer).NotifySlideSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FromWorker_NotifySlideSuggestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FromWorkerServer).NotifySlideSuggestions(ctx, req.(*SlideSuggestions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FromWorker_RenewCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FromWorkerServer).RenewCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FromWorker_RenewCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FromWorkerServer).RenewCertificate(ctx, req.(*RenewCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FromWorker_ServiceDesc is the grpc.ServiceDesc for FromWorker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -966,6 +1032,14 @@ var FromWorker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NotifyTranscodingFailure",
 			Handler:    _FromWorker_NotifyTranscodingFailure_Handler,
 		},
+		{
+			MethodName: "NotifySlideSuggestions",
+			Handler:    _FromWorker_NotifySlideSuggestions_Handler,
+		},
+		{
+			MethodName: "RenewCertificate",
+			Handler:    _FromWorker_RenewCertificate_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3,10 +3,14 @@ package rest
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"sync"
 
+	"github.com/TUM-Dev/gocast/worker/cfg"
 	"github.com/TUM-Dev/gocast/worker/worker"
 	log "github.com/sirupsen/logrus"
 )
@@ -28,8 +32,43 @@ func InitApi(addr string) {
 	log.Fatal(http.ListenAndServe(addr, nil))
 }
 
-// mustGetStreamInfo gets the stream key and slug from mediamtx requests and aborts with bad request if something is wrong
+// ServeDvr serves the DVR playlists of live streams under /dvr/, usually to edge nodes
+func ServeDvr(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/dvr/", dvrHandler(cfg.DvrDir))
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+func dvrHandler(dir string) http.Handler {
+	return http.StripPrefix("/dvr/", http.FileServer(filesOnly{http.Dir(dir)}))
+}
+
+// filesOnly is a http.FileSystem that reports directories as not existing, so they aren't listed
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+// mustGetStreamInfo gets the stream key and slug from mediamtx requests and aborts with bad request if something is wrong.
+// RTMP publishers send both in the query (secret=<key>/<slug>). SRT and WHIP publishers publish to the path <slug> and send
+// the key as query parameter (?secret=<key>), as password (SRT stream id publish:<slug>:<user>:<key>, WHIP basic auth)
+// or as bearer token (WHIP).
 func mustGetStreamInfo(req OnStartReq) (streamKey string, slug string, err error) {
+	if req.Protocol == "srt" || req.Protocol == "webrtc" {
+		return getPathStreamInfo(req)
+	}
 	pts := strings.Split(req.Query, "/")
 	if len(pts) != 2 {
 		return "", "", errors.New("stream key in wrong format")
@@ -44,3 +83,29 @@ func mustGetStreamInfo(req OnStartReq) (streamKey string, slug string, err error
 	}
 	return key, slug, nil
 }
+
+func getPathStreamInfo(req OnStartReq) (streamKey string, slug string, err error) {
+	slug = strings.Trim(req.Path, "/")
+	if slug == "" || strings.Contains(slug, "/") {
+		return "", "", errors.New("no slug provided")
+	}
+	query, err := url.ParseQuery(req.Query)
+	if err != nil {
+		return "", "", fmt.Errorf("parse query: %w", err)
+	}
+	for _, key := range []string{query.Get("secret"), req.Password, req.Token} {
+		if key != "" {
+			return key, slug, nil
+		}
+	}
+	return "", "", errors.New("no stream key provided")
+}
+
+// selfStreamSource returns the url the published self stream of slug is read from.
+// SRT and WebRTC publishers may use codecs RTMP can't carry (e.g. opus), so they are read via the local RTSP server.
+func selfStreamSource(protocol, slug string) string {
+	if protocol == "srt" || protocol == "webrtc" {
+		return "rtsp://localhost:8554/" + slug
+	}
+	return "rtmp://localhost/" + slug
+}
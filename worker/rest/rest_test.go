@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMustGetStreamInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     OnStartReq
+		key     string
+		slug    string
+		wantErr bool
+	}{
+		{"rtmp", OnStartReq{Protocol: "rtmp", Path: "ABC-123", Query: "secret=abc/ABC-123"}, "abc", "ABC-123", false},
+		{"rtmp without slug", OnStartReq{Protocol: "rtmp", Path: "ABC-123", Query: "secret=abc"}, "", "", true},
+		{"srt with password", OnStartReq{Protocol: "srt", Path: "ABC-123", User: "lecturer", Password: "abc"}, "abc", "ABC-123", false},
+		{"srt with query", OnStartReq{Protocol: "srt", Path: "ABC-123", Query: "secret=abc"}, "abc", "ABC-123", false},
+		{"whip with bearer token", OnStartReq{Protocol: "webrtc", Path: "ABC-123", Token: "abc"}, "abc", "ABC-123", false},
+		{"whip without key", OnStartReq{Protocol: "webrtc", Path: "ABC-123"}, "", "", true},
+		{"srt without slug", OnStartReq{Protocol: "srt", Password: "abc"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, slug, err := mustGetStreamInfo(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mustGetStreamInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if key != tt.key || slug != tt.slug {
+				t.Fatalf("mustGetStreamInfo() = %q, %q, want %q, %q", key, slug, tt.key, tt.slug)
+			}
+		})
+	}
+}
+
+func TestSelfStreamSource(t *testing.T) {
+	if src := selfStreamSource("rtmp", "ABC-123"); src != "rtmp://localhost/ABC-123" {
+		t.Fatalf("unexpected source of rtmp self stream: %s", src)
+	}
+	if src := selfStreamSource("webrtc", "ABC-123"); src != "rtsp://localhost:8554/ABC-123" {
+		t.Fatalf("unexpected source of webrtc self stream: %s", src)
+	}
+}
+
+func TestDvrHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "stream"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "stream", "playlist.m3u8"), []byte("#EXTM3U\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]int{
+		"/dvr/stream/playlist.m3u8": http.StatusOK,
+		"/dvr/":                     http.StatusNotFound,
+		"/dvr/stream/":              http.StatusNotFound,
+		"/dvr/stream":               http.StatusNotFound,
+	}
+	for path, want := range tests {
+		rec := httptest.NewRecorder()
+		dvrHandler(dir).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -46,6 +46,18 @@ func (s *safeStreams) onPublish(w http.ResponseWriter, r *http.Request) {
 		// all good, client is reading
 		return
 	}
+	done, err := worker.StartJob()
+	if err != nil {
+		log.Info("Rejected self stream while draining")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	handedOver := false
+	defer func() {
+		if !handedOver {
+			done()
+		}
+	}()
 	streamKey, slug, err := mustGetStreamInfo(req)
 	if err != nil {
 		log.WithFields(log.Fields{"request": r.Form}).WithError(err).Warn("onPublish: bad request")
@@ -74,6 +86,7 @@ func (s *safeStreams) onPublish(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Authentication failed for SendSelfStreamRequest", http.StatusForbidden)
 		return
 	}
+	handedOver = true
 	go func() {
 		s.mutex.Lock()
 		if streamCtx, ok := s.streams[streamKey]; ok {
@@ -84,13 +97,14 @@ func (s *safeStreams) onPublish(w http.ResponseWriter, r *http.Request) {
 
 		// todo is this right?
 		// register stream in local map
-		streamContext := worker.HandleSelfStream(resp, slug)
+		streamContext := worker.HandleSelfStream(resp, selfStreamSource(req.Protocol, slug))
 
 		s.mutex.Lock()
 		s.streams[streamKey] = streamContext // todo this is only added after the stream has ended
 		s.mutex.Unlock()
 
 		go func() {
+			defer done()
 			worker.HandleStreamEnd(streamContext, false)
 			worker.NotifyStreamDone(streamContext)
 			worker.HandleSelfStreamRecordEnd(streamContext)
@@ -102,6 +116,7 @@ type OnStartReq struct {
 	Ip       string `json:"ip"`
 	User     string `json:"user"`
 	Password string `json:"password"`
+	Token    string `json:"token"` // bearer token of WHIP publishers
 	Path     string `json:"path"`
 	Protocol string `json:"protocol"`
 	Id       string `json:"id"`
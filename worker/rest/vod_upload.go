@@ -12,6 +12,18 @@ import (
 // handleUpload handles VOD upload requests proxied by TUM-Live.
 func handleUpload(w http.ResponseWriter, r *http.Request) {
 	log.Info(r.URL.String())
+	done, err := worker.StartJob()
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	handedOver := false
+	defer func() {
+		if !handedOver {
+			done()
+		}
+	}()
 	_, f, err := r.FormFile("file")
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -45,6 +57,10 @@ func handleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	go worker.HandleUploadRestReq(streamUploadInfo, out.Name())
+	handedOver = true
+	go func() {
+		defer done()
+		worker.HandleUploadRestReq(streamUploadInfo, out.Name())
+	}()
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores objects in a directory, e.g. the shared mass storage.
+type Local struct {
+	Root string
+}
+
+// NewLocal returns a storage that keeps all objects in root.
+func NewLocal(root string) *Local {
+	return &Local{Root: root}
+}
+
+// Put copies the file at localPath to key. Files that are already in place aren't copied.
+func (l *Local) Put(_ context.Context, key string, localPath string) error {
+	dest, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(localPath) == dest {
+		return nil
+	}
+	if err = os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	// write to a temporary file first, so readers never see partial files
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = io.Copy(tmp, src); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// Get opens the file stored at key.
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// Stat returns the size and modification time of the file stored at key.
+func (l *Local) Stat(_ context.Context, key string) (ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete removes the file stored at key.
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the location of key in the file system. Keys must not leave the root.
+func (l *Local) path(key string) (string, error) {
+	p := filepath.Join(l.Root, filepath.FromSlash(key))
+	root := filepath.Clean(l.Root)
+	if p != root && !strings.HasPrefix(p, root+string(filepath.Separator)) {
+		return "", errors.New("invalid key: " + key)
+	}
+	return p, nil
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocal(t.TempDir())
+	src := filepath.Join(t.TempDir(), "thumb.jpg")
+	if err := os.WriteFile(src, []byte("jpg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Put(ctx, "2024/S/eidi/thumb.jpg", src); err != nil {
+		t.Fatal(err)
+	}
+	r, err := l.Get(ctx, "2024/S/eidi/thumb.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(content) != "jpg" {
+		t.Errorf("Get() = %q", content)
+	}
+	// files that are already in place are kept
+	if err = l.Put(ctx, "2024/S/eidi/thumb.jpg", filepath.Join(l.Root, "2024/S/eidi/thumb.jpg")); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := l.Stat(ctx, "2024/S/eidi/thumb.jpg"); err != nil || info.Size != 3 {
+		t.Errorf("Stat() = %v, %v", info, err)
+	}
+	if err = l.Put(ctx, "../outside.jpg", src); err == nil {
+		t.Errorf("Put() outside of root succeeded")
+	}
+	if err = l.Delete(ctx, "2024/S/eidi/thumb.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = l.Stat(ctx, "2024/S/eidi/thumb.jpg"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Stat() after Delete() = %v, want ErrNotExist", err)
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LRZ publishes VoDs using the upload form of the LRZ video portal.
+type LRZ struct {
+	UploadURL string
+	User      string
+	Mail      string
+	Phone     string
+	SubDir    string
+}
+
+// Put uploads the file at localPath. The key is ignored, the LRZ names files after their original name.
+func (l LRZ) Put(ctx context.Context, _ string, localPath string) error {
+	client := &http.Client{
+		// 15 minutes timeout, some large files can take a while.
+		Timeout: time.Minute * 15,
+	}
+	r, w := io.Pipe()
+	writer := multipart.NewWriter(w)
+
+	go func() {
+		err := writeFile(writer, "filename", localPath)
+		if err != nil {
+			_ = w.CloseWithError(fmt.Errorf("create form file: %w", err))
+			return
+		}
+
+		fields := map[string]string{
+			"benutzer":    l.User,
+			"mailadresse": l.Mail,
+			"telefon":     l.Phone,
+			"unidir":      "tum",
+			"subdir":      l.SubDir,
+			"info":        "",
+		}
+
+		for name, value := range fields {
+			err = writeField(writer, name, value)
+			if err != nil {
+				_ = w.CloseWithError(fmt.Errorf("create form field: %w", err))
+				return
+			}
+		}
+		_ = w.CloseWithError(writer.Close())
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.UploadURL, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	all, err := io.ReadAll(rsp.Body)
+	if err == nil {
+		log.WithField("fileUploaded", localPath).Debug(string(all))
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upload failed with response code %d", rsp.StatusCode)
+	}
+	return nil
+}
+
+func writeField(writer *multipart.Writer, name string, value string) error {
+	formFieldWriter, err := writer.CreateFormField(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(formFieldWriter, strings.NewReader(value))
+	return err
+}
+
+func writeFile(writer *multipart.Writer, fieldname string, file string) error {
+	formFileWriter, err := writer.CreateFormFile(fieldname, file)
+	if err != nil {
+		return err
+	}
+	fileReader, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer fileReader.Close()
+	_, err = io.Copy(formFileWriter, fileReader)
+	return err
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestLRZUpload(t *testing.T) {
+	var filesize uint = 1024 // mB
+	filename, err := createDummyFile(filesize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	lrz := LRZ{
+		User:   "TestUserName",
+		Mail:   "mail@mail.de",
+		Phone:  "0123456789",
+		SubDir: "testDir",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		err := request.ParseMultipartForm(32 << 20)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+		if is := request.Form["benutzer"][0]; is != lrz.User {
+			t.Errorf("benutzer:  %v != %v", is, lrz.User)
+		}
+		if is := request.Form["mailadresse"][0]; is != lrz.Mail {
+			t.Errorf("mailadresse:  %v != %v", is, lrz.Mail)
+		}
+		if is := request.Form["telefon"][0]; is != lrz.Phone {
+			t.Errorf("telefon:  %v != %v", is, lrz.Phone)
+		}
+		if is := request.Form["unidir"][0]; is != "tum" {
+			t.Errorf("unidir:  %v != tum", is)
+		}
+		if is := request.Form["subdir"][0]; is != lrz.SubDir {
+			t.Errorf("subdir:  %v != %v", is, lrz.SubDir)
+		}
+
+		_, h, err := request.FormFile("filename")
+		if err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if uint(h.Size) != filesize*1<<20 {
+			t.Errorf("incorrect size: %v", h.Size)
+		}
+		w.WriteHeader(200)
+	}))
+	defer srv.Close()
+	lrz.UploadURL = srv.URL
+
+	if err = lrz.Put(context.Background(), "", filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createDummyFile(filesize uint) (string, error) {
+	file, err := os.CreateTemp("", "recording")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data := make([]byte, 1<<20)
+	for i := uint(0); i < filesize; i++ {
+		if _, err = file.Write(data); err != nil {
+			return "", err
+		}
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/edge/sigv4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPartSize is the size of the parts of multipart uploads. S3 requires at least 5 MiB.
+	DefaultPartSize = 64 << 20
+	minPartSize     = 5 << 20
+)
+
+// S3 stores objects in a bucket of an S3 compatible object storage (e.g. MinIO or Ceph).
+// Objects are addressed path-style: <Endpoint>/<Bucket>/<key>.
+type S3 struct {
+	Endpoint  string // e.g. https://s3.example.com
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PartSize  int64 // files larger than this are uploaded in parts of this size
+
+	Client *http.Client
+}
+
+// NewS3 returns an S3 storage with the default part size.
+func NewS3(endpoint, region, bucket, accessKey, secretKey string) *S3 {
+	if region == "" {
+		region = "us-east-1" // default region of most S3 compatible storages
+	}
+	return &S3{
+		Endpoint:  strings.TrimSuffix(endpoint, "/"),
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		PartSize:  DefaultPartSize,
+		Client:    &http.Client{Timeout: time.Minute * 15},
+	}
+}
+
+// Put uploads the file at localPath. Every request carries the MD5 and SHA256 checksum of its payload,
+// so the storage rejects corrupted uploads.
+func (s *S3) Put(ctx context.Context, key string, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() <= s.partSize() {
+		_, err = s.upload(ctx, http.MethodPut, key, nil, io.NewSectionReader(f, 0, info.Size()))
+		return err
+	}
+	return s.putMultipart(ctx, key, f, info.Size())
+}
+
+func (s *S3) putMultipart(ctx context.Context, key string, f io.ReaderAt, size int64) error {
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, sigv4.EmptyPayloadHash, nil)
+	if err != nil {
+		return fmt.Errorf("create multipart upload: %w", err)
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = decodeXML(resp, &initiated)
+	if err != nil {
+		return fmt.Errorf("create multipart upload: %w", err)
+	}
+
+	type part struct {
+		PartNumber int
+		ETag       string
+	}
+	var parts []part
+	for offset, n := int64(0), 1; offset < size; offset, n = offset+s.partSize(), n+1 {
+		section := io.NewSectionReader(f, offset, min(s.partSize(), size-offset))
+		query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {initiated.UploadID}}
+		etag, err := s.upload(ctx, http.MethodPut, key, query, section)
+		if err != nil {
+			s.abortMultipart(key, initiated.UploadID)
+			return fmt.Errorf("upload part %d: %w", n, err)
+		}
+		parts = append(parts, part{PartNumber: n, ETag: etag})
+	}
+
+	complete, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	resp, err = s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {initiated.UploadID}}, bytes.NewReader(complete), sigv4.SHA256Hex(complete), nil)
+	if err == nil {
+		// errors of CompleteMultipartUpload may be reported with status 200
+		var result struct {
+			XMLName xml.Name
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		err = decodeXML(resp, &result)
+		if err == nil && result.XMLName.Local == "Error" {
+			err = fmt.Errorf("%s: %s", result.Code, result.Message)
+		}
+	}
+	if err != nil {
+		s.abortMultipart(key, initiated.UploadID)
+		return fmt.Errorf("complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s *S3) abortMultipart(key string, uploadID string) {
+	resp, err := s.do(context.Background(), http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, sigv4.EmptyPayloadHash, nil)
+	if err != nil {
+		log.WithError(err).WithField("key", key).Warn("Could not abort multipart upload")
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// upload sends the content of r with its checksums and returns the ETag of the stored object or part.
+func (s *S3) upload(ctx context.Context, method, key string, query url.Values, r *io.SectionReader) (string, error) {
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	header := http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(md5Hash.Sum(nil))}}
+	resp, err := s.do(ctx, method, key, query, r, hex.EncodeToString(sha256Hash.Sum(nil)), header)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+// Get downloads the object at key.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, sigv4.EmptyPayloadHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Stat returns the size and modification time of the object at key.
+func (s *S3) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, sigv4.EmptyPayloadHash, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	_ = resp.Body.Close()
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return ObjectInfo{Key: key, Size: resp.ContentLength, ModTime: modTime}, nil
+}
+
+// Delete removes the object at key.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, sigv4.EmptyPayloadHash, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a signed request for key and returns the response if its status is 2xx.
+// Responses with status 404 are reported as ErrNotExist.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body io.Reader, payloadHash string, header http.Header) (*http.Response, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/" + s.Bucket + "/" + strings.TrimPrefix(key, "/")
+	u.RawPath = "/" + s.Bucket + "/" + sigv4.EscapePath(strings.TrimPrefix(key, "/"))
+	u.RawQuery = sigv4.CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := body.(*io.SectionReader); ok {
+		req.ContentLength = r.Size()
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	sigv4.Sign(req, payloadHash, s.Region, s.AccessKey, s.SecretKey, time.Now())
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s: %w", method, key, ErrNotExist)
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("%s %s: unexpected status %d: %s", method, key, resp.StatusCode, msg)
+}
+
+func (s *S3) partSize() int64 {
+	if s.PartSize < minPartSize {
+		return DefaultPartSize
+	}
+	return s.PartSize
+}
+
+func (s *S3) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+func decodeXML(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TUM-Dev/gocast/worker/edge/sigv4"
+)
+
+// fakeS3 is an in memory S3 server that verifies the checksums of uploads.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if sha := r.Header.Get("X-Amz-Content-Sha256"); sha != sigv4.SHA256Hex(body) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if contentMD5 := r.Header.Get("Content-Md5"); contentMD5 != "" {
+		sum := md5.Sum(body)
+		if contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	key := r.URL.Path
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := strconv.Itoa(len(f.uploads))
+		f.uploads[id] = map[int][]byte{}
+		_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		f.uploads[q.Get("uploadId")][n] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		_ = xml.Unmarshal(body, &complete)
+		parts := f.uploads[q.Get("uploadId")]
+		if len(complete.Parts) != len(parts) || !sort.SliceIsSorted(complete.Parts, func(i, j int) bool {
+			return complete.Parts[i].PartNumber < complete.Parts[j].PartNumber
+		}) {
+			_, _ = w.Write([]byte("<Error><Code>InvalidPart</Code></Error>"))
+			return
+		}
+		var object []byte
+		for _, p := range complete.Parts {
+			object = append(object, parts[p.PartNumber]...)
+		}
+		f.objects[key] = object
+		delete(f.uploads, q.Get("uploadId"))
+		_, _ = w.Write([]byte("<CompleteMultipartUploadResult/>"))
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		delete(f.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		object, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object)))
+		_, _ = w.Write(object)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	s3 := NewS3(srv.URL, "", "vod", "access", "secret")
+	s3.PartSize = minPartSize
+
+	ctx := context.Background()
+	for _, size := range []int{1024, minPartSize*2 + 17} {
+		content := bytes.Repeat([]byte{'x'}, size)
+		file := filepath.Join(t.TempDir(), "recording.mp4")
+		if err := os.WriteFile(file, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := s3.Put(ctx, "2024/S/eidi/video.mp4", file); err != nil {
+			t.Fatalf("Put(%d bytes): %v", size, err)
+		}
+		if got := fake.objects["/vod/2024/S/eidi/video.mp4"]; !bytes.Equal(got, content) {
+			t.Fatalf("stored %d bytes, want %d", len(got), size)
+		}
+		info, err := s3.Stat(ctx, "2024/S/eidi/video.mp4")
+		if err != nil || info.Size != int64(size) {
+			t.Fatalf("Stat() = %v, %v", info, err)
+		}
+	}
+	if len(fake.uploads) != 0 {
+		t.Errorf("multipart uploads weren't completed")
+	}
+
+	r, err := s3.Get(ctx, "2024/S/eidi/video.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+	if err = s3.Delete(ctx, "2024/S/eidi/video.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s3.Get(ctx, "2024/S/eidi/video.mp4"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Get() after Delete() = %v, want ErrNotExist", err)
+	}
+}
@@ -0,0 +1,37 @@
+// Package storage provides the backends workers store their artifacts (recordings, transcodes, thumbnails and
+// section images) in, as well as additional sinks VoDs can be published to.
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// ErrNotExist is returned if an object doesn't exist in a storage.
+var ErrNotExist = fs.ErrNotExist
+
+// Sink is a destination files can be published to.
+type Sink interface {
+	// Put uploads the local file at localPath to key.
+	Put(ctx context.Context, key string, localPath string) error
+}
+
+// Storage is a Sink that can also read and delete the objects it stores.
+type Storage interface {
+	Sink
+	// Get returns a reader for the object at key. The reader must be closed.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat returns information about the object at key.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes the object at key. Deleting an object that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ObjectInfo describes an object in a storage.
+type ObjectInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrDraining is returned for new jobs while the worker is draining
+var ErrDraining = errors.New("worker is draining")
+
+// jobTracker counts the streams and pipelines that have to finish before the worker can shut down
+type jobTracker struct {
+	mutex    sync.Mutex
+	idle     *sync.Cond
+	running  int
+	draining bool
+}
+
+var jobs = newJobTracker()
+
+func newJobTracker() *jobTracker {
+	t := &jobTracker{}
+	t.idle = sync.NewCond(&t.mutex)
+	return t
+}
+
+// start registers a new job. It returns false if the worker is draining and the job must be rejected.
+func (t *jobTracker) start() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.draining {
+		return false
+	}
+	t.running++
+	return true
+}
+
+// forceStart registers a job that was accepted before, e.g. a resumed pipeline, regardless of the drain state.
+func (t *jobTracker) forceStart() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.running++
+}
+
+func (t *jobTracker) done() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.running--
+	if t.running == 0 {
+		t.idle.Broadcast()
+	}
+}
+
+func (t *jobTracker) isDraining() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.draining
+}
+
+// drain rejects all new jobs and blocks until the running ones finished
+func (t *jobTracker) drain() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.draining = true
+	for t.running > 0 {
+		t.idle.Wait()
+	}
+}
+
+// StartJob registers a job that keeps the worker from shutting down. It returns ErrDraining if the worker doesn't accept new jobs.
+// The returned function must be called once the job finished.
+func StartJob() (func(), error) {
+	if !jobs.start() {
+		return nil, ErrDraining
+	}
+	return jobs.done, nil
+}
+
+// Draining returns true if the worker doesn't accept new jobs
+func Draining() bool {
+	return jobs.isDraining()
+}
+
+// Drain stops accepting new jobs and blocks until all running streams and pipelines finished.
+// The drain state is reported to TUM-Live with every heartbeat, the last one tells it's safe to shut down.
+func Drain() {
+	log.Info("Draining worker, waiting for running jobs to finish")
+	go S.SendHeartbeat()
+	jobs.drain()
+	S.SendHeartbeat()
+	log.Info("All jobs finished, safe to shut down")
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobTrackerDrain(t *testing.T) {
+	tracker := newJobTracker()
+	if !tracker.start() {
+		t.Fatal("job rejected before draining")
+	}
+	drained := make(chan struct{})
+	go func() {
+		tracker.drain()
+		close(drained)
+	}()
+	for !tracker.isDraining() {
+		time.Sleep(time.Millisecond)
+	}
+	if tracker.start() {
+		t.Fatal("job accepted while draining")
+	}
+	select {
+	case <-drained:
+		t.Fatal("drain returned while a job was running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	tracker.done()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain didn't return after the last job finished")
+	}
+}
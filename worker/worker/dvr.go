@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+
+	"github.com/TUM-Dev/gocast/worker/cfg"
+)
+
+// dvrSegmentLength is the target duration of DVR segments in seconds
+const dvrSegmentLength = 6
+
+// dvrEnabled returns whether a DVR playlist is recorded for the stream. Only the combined view is recorded
+// and workers without a DvrURLTemplate don't record DVR playlists at all.
+func (s StreamContext) dvrEnabled() bool {
+	return s.dvr && s.streamVersion == "COMB" && cfg.DvrURLTemplate != ""
+}
+
+// getDvrDir returns the directory the DVR playlist and its segments are written to.
+// example: /dvr/eidi_2021_09_23_10_00COMB
+func (s StreamContext) getDvrDir() string {
+	return filepath.Join(cfg.DvrDir, s.getStreamNameVoD())
+}
+
+// getDvrUrl returns the url viewers can play the DVR playlist from or "" if DVR is disabled
+func (s StreamContext) getDvrUrl() string {
+	if !s.dvrEnabled() {
+		return ""
+	}
+	return fmt.Sprintf(cfg.DvrURLTemplate, s.getStreamNameVoD())
+}
+
+// dvrOutputArgs returns the options of the DVR output of the live command. The playlist is continued with a
+// discontinuity if ffmpeg restarts. Without a window, the whole stream is kept as event playlist.
+func dvrOutputArgs(streamCtx *StreamContext) []string {
+	args := []string{"-map", "0", "-c", "copy", "-f", "hls", "-hls_time", strconv.Itoa(dvrSegmentLength)}
+	flags := "append_list+discont_start+program_date_time"
+	listSize := 0
+	if streamCtx.dvrWindow == 0 {
+		args = append(args, "-hls_playlist_type", "event")
+	} else {
+		listSize = int(math.Ceil(float64(streamCtx.dvrWindow) / dvrSegmentLength))
+		flags += "+delete_segments"
+	}
+	return append(args,
+		"-hls_list_size", strconv.Itoa(listSize),
+		"-hls_flags", flags,
+		"-hls_segment_filename", filepath.Join(streamCtx.getDvrDir(), "seg%05d.ts"))
+}
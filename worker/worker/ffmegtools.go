@@ -45,3 +45,12 @@ func probe(file string) (string, error) {
 		"-show_format", "-show_streams", file).CombinedOutput()
 	return string(out), err
 }
+
+// getStartTime returns the timestamp of the first frame of file in seconds
+func getStartTime(file string) (float64, error) {
+	probe, err := probe(file)
+	if err != nil {
+		return 0, err
+	}
+	return gjson.Get(probe, "format.start_time").Float(), nil
+}
@@ -6,10 +6,10 @@ import (
 	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/grpcauth"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 func closeConnection(conn *grpc.ClientConn) {
@@ -86,6 +86,7 @@ func notifyStreamStart(streamCtx *StreamContext) {
 		StreamID:   streamCtx.streamId,
 		HlsUrl:     fmt.Sprintf(streamCtx.outUrl, streamCtx.streamName), // could look like: fmt.Sprintf("https://live.stream.lrz.de/livetum/smil:%s_all.smil/playlist.m3u8?dvr", streamCtx.streamName)
 		SourceType: streamCtx.streamVersion,
+		DvrUrl:     streamCtx.getDvrUrl(),
 	})
 	if err != nil || !resp.Ok {
 		log.WithError(err).Error("Could not notify stream started")
@@ -110,7 +111,7 @@ func NotifyStreamDone(streamCtx *StreamContext) {
 	}
 }
 
-func notifyTranscodingDone(streamCtx *StreamContext) {
+func notifyTranscodingDone(streamCtx *StreamContext, v verification) {
 	client, conn, err := GetClient()
 	if err != nil {
 		log.WithError(err).Error("Unable to dial tumlive")
@@ -120,11 +121,13 @@ func notifyTranscodingDone(streamCtx *StreamContext) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 	resp, err := client.NotifyTranscodingFinished(ctx, &pb.TranscodingFinished{
-		WorkerID:   cfg.WorkerID,
-		StreamID:   streamCtx.streamId,
-		FilePath:   streamCtx.getTranscodingFileName(),
-		Duration:   streamCtx.duration,
-		SourceType: streamCtx.streamVersion,
+		WorkerID:     cfg.WorkerID,
+		StreamID:     streamCtx.streamId,
+		FilePath:     streamCtx.getTranscodingFileName(),
+		Duration:     streamCtx.duration,
+		SourceType:   streamCtx.streamVersion,
+		Verified:     true,
+		Verification: v.String(),
 	})
 	if err != nil || !resp.Ok {
 		log.WithError(err).Error("Could not notify stream finished")
@@ -176,9 +179,25 @@ func notifyThumbnailDone(streamCtx *StreamContext) {
 }
 
 func GetClient() (pb.FromWorkerClient, *grpc.ClientConn, error) {
-	conn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpcauth.DialOption())
 	if err != nil {
 		return nil, nil, err
 	}
 	return pb.NewFromWorkerClient(conn), conn, nil
 }
+
+// RenewCertificate requests a new certificate of the worker from TUM-Live
+func RenewCertificate(csr []byte) ([]byte, []byte, error) {
+	client, conn, err := GetClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeConnection(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resp, err := client.RenewCertificate(ctx, &pb.RenewCertificateRequest{WorkerID: cfg.WorkerID, Csr: csr})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Certificate, resp.CaCertificate, nil
+}
@@ -17,22 +17,47 @@ type Deletable struct { // Deletable is a file that can safely be deleted
 	Time time.Time // Time is the time the file was marked for deletion
 }
 
+// Pipeline is the post-processing state of a recording
+type Pipeline struct {
+	StreamID      uint32
+	CourseSlug    string
+	TeachingTerm  string
+	TeachingYear  uint32
+	StartTime     time.Time
+	EndTime       time.Time
+	StreamVersion string
+	PublishVoD    bool
+	IsSelfStream  bool
+	RecordingPath string // RecordingPath is the file the recording was saved to
+	Duration      uint32 // Duration of the transcoded recording in seconds, known once transcoded
+	Steps         []Step // Steps are the steps that didn't succeed yet in the order they are run
+	Attempts      int    // Attempts counts how often the pipeline was resumed after a restart
+}
+
 type Persistable struct { // Persistable is a struct for all persistable objects
-	Deletable []Deletable // Deletable are all files that can safely be deleted
+	Deletable []Deletable         // Deletable are all files that can safely be deleted
+	Pipelines map[string]Pipeline // Pipelines are all unfinished pipelines by stream name
 	mutex     *sync.Mutex
 }
 
 const persistFileName = "/persist.gob"
 
-// writeOut writes out the persistable object to disk
+// writeOut writes out the persistable object to disk. The file is replaced atomically so a crash can't corrupt it.
 func (p *Persistable) writeOut() error {
-	f, err := os.OpenFile(cfg.PersistDir+persistFileName, os.O_RDWR|os.O_CREATE, 0o666)
+	f, err := os.CreateTemp(cfg.PersistDir, "persist-*.gob")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer os.Remove(f.Name())
 	err = gob.NewEncoder(f).Encode(p)
-	return err
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), cfg.PersistDir+persistFileName)
 }
 
 // NewPersistable reads in the persistable object from disk and returns it
@@ -75,3 +100,45 @@ func (p *Persistable) SetDeletables(d []Deletable) error {
 	p.Deletable = d
 	return p.writeOut()
 }
+
+// SetPipeline persists the state of the pipeline of a stream
+func (p *Persistable) SetPipeline(name string, pipeline Pipeline) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.Pipelines == nil {
+		p.Pipelines = make(map[string]Pipeline)
+	}
+	p.Pipelines[name] = pipeline
+	return p.writeOut()
+}
+
+// RemovePipeline removes the pipeline of a stream once it finished
+func (p *Persistable) RemovePipeline(name string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.Pipelines, name)
+	return p.writeOut()
+}
+
+// GetPipelines returns a copy of all unfinished pipelines
+func (p *Persistable) GetPipelines() map[string]Pipeline {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	pipelines := make(map[string]Pipeline, len(p.Pipelines))
+	for name, pipeline := range p.Pipelines {
+		pipelines[name] = pipeline
+	}
+	return pipelines
+}
+
+// isNeeded returns true if file is still needed by an unfinished pipeline and must not be deleted
+func (p *Persistable) isNeeded(file string) bool {
+	for _, pipeline := range p.GetPipelines() {
+		for _, f := range pipeline.files() {
+			if f == file {
+				return true
+			}
+		}
+	}
+	return false
+}
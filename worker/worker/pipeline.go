@@ -0,0 +1,272 @@
+package worker
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Step is a post-processing step of a recording
+type Step string
+
+const (
+	StepTranscode  Step = "transcode"
+	StepConvert    Step = "convert" // StepConvert transcodes uploads only if they aren't streamable already
+	StepAudio      Step = "audio"
+	StepThumbnails Step = "thumbnails"
+	StepStore      Step = "store"
+	StepUpload     Step = "upload"
+	StepSilence    Step = "silence"
+	StepSlides     Step = "slides" // StepSlides suggests sections at the slide changes of presentation recordings
+)
+
+// maxPipelineAttempts is how often a pipeline is resumed after restarts before the worker gives up on it
+const maxPipelineAttempts = 3
+
+// errPipelineCanceled is returned by steps of self streams that restarted while post-processing
+var errPipelineCanceled = errors.New("pipeline canceled")
+
+// newPipeline creates a pipeline running steps for the recording of streamCtx
+func newPipeline(streamCtx *StreamContext, steps ...Step) Pipeline {
+	return Pipeline{
+		StreamID:      streamCtx.streamId,
+		CourseSlug:    streamCtx.courseSlug,
+		TeachingTerm:  streamCtx.teachingTerm,
+		TeachingYear:  streamCtx.teachingYear,
+		StartTime:     streamCtx.startTime,
+		EndTime:       streamCtx.endTime,
+		StreamVersion: streamCtx.streamVersion,
+		PublishVoD:    streamCtx.publishVoD,
+		IsSelfStream:  streamCtx.isSelfStream,
+		RecordingPath: streamCtx.getRecordingFileName(),
+		Duration:      streamCtx.duration,
+		Steps:         steps,
+	}
+}
+
+// streamContext restores the context of a stream from its pipeline
+func (p Pipeline) streamContext() *StreamContext {
+	recordingPath := p.RecordingPath
+	return &StreamContext{
+		streamId:      p.StreamID,
+		courseSlug:    p.CourseSlug,
+		teachingTerm:  p.TeachingTerm,
+		teachingYear:  p.TeachingYear,
+		startTime:     p.StartTime,
+		endTime:       p.EndTime,
+		streamVersion: p.StreamVersion,
+		publishVoD:    p.PublishVoD,
+		isSelfStream:  p.IsSelfStream,
+		recordingPath: &recordingPath,
+		duration:      p.Duration,
+	}
+}
+
+// files returns the files the pipeline reads from
+func (p Pipeline) files() []string {
+	ctx := p.streamContext()
+	return []string{ctx.getRecordingFileName(), ctx.getRecordingTrashName(), ctx.getTranscodingFileName(), ctx.getAudioTranscodingFileName()}
+}
+
+// runPipeline runs the remaining steps of a pipeline. The pipeline is persisted after every step,
+// so a restarted worker resumes where it left off. If a step fails, the pipeline stays persisted and
+// the recording is kept; it is only marked for deletion once all steps succeeded.
+func runPipeline(streamCtx *StreamContext, pipeline Pipeline) {
+	name := streamCtx.getStreamName()
+	logger := log.WithField("stream", name)
+	if err := persisted.SetPipeline(name, pipeline); err != nil {
+		logger.WithError(err).Error("Can't persist pipeline")
+	}
+	for len(pipeline.Steps) > 0 {
+		err := runStep(streamCtx, pipeline.Steps[0])
+		if errors.Is(err, errPipelineCanceled) {
+			// the restarted self stream continues with this pipeline once it ends
+			return
+		}
+		if err != nil {
+			logger.WithField("step", pipeline.Steps[0]).WithError(err).Error("Pipeline step failed, keeping recording")
+			return
+		}
+		pipeline.Steps = pipeline.Steps[1:]
+		pipeline.Duration = streamCtx.duration
+		if err := persisted.SetPipeline(name, pipeline); err != nil {
+			logger.WithError(err).Error("Can't persist pipeline")
+		}
+	}
+	if err := persisted.RemovePipeline(name); err != nil {
+		logger.WithError(err).Error("Can't remove finished pipeline")
+	}
+	if _, err := os.Stat(streamCtx.getRecordingFileName()); err != nil {
+		return // e.g. uploads that were moved to the storage directory
+	}
+	if err := markForDeletion(streamCtx); err != nil {
+		logger.WithError(err).Error("Error marking for deletion")
+	}
+}
+
+// runStep runs a single step of a pipeline. Errors of steps that don't produce the VoD itself are logged but not returned.
+func runStep(streamCtx *StreamContext, step Step) error {
+	switch step {
+	case StepTranscode:
+		S.startTranscoding(streamCtx.getStreamName())
+		err := transcode(streamCtx)
+		S.endTranscoding(streamCtx.getStreamName())
+		if streamCtx.canceled {
+			return errPipelineCanceled
+		}
+		if err != nil {
+			streamCtx.TranscodingSuccessful = false
+			NotifyTranscodingFailure(*streamCtx, err)
+			return err
+		}
+		// the recording is only deleted after the pipeline finished, failing here keeps it
+		v, err := verifyTranscoding(streamCtx)
+		if err != nil {
+			streamCtx.TranscodingSuccessful = false
+			NotifyTranscodingFailure(*streamCtx, err)
+			return err
+		}
+		streamCtx.TranscodingSuccessful = true
+		notifyTranscodingDone(streamCtx, v)
+	case StepConvert:
+		return convertUpload(streamCtx)
+	case StepAudio:
+		if err := transcodeAudio(streamCtx); err != nil {
+			log.WithError(err).Error("Error transcoding audio")
+		}
+	case StepThumbnails:
+		createThumbnails(streamCtx)
+	case StepStore:
+		return storeArtifacts(streamCtx)
+	case StepUpload:
+		if err := upload(streamCtx); err != nil {
+			return err
+		}
+		notifyUploadDone(streamCtx)
+	case StepSilence:
+		S.startSilenceDetection(streamCtx)
+		defer S.endSilenceDetection(streamCtx)
+		sd := NewSilenceDetector(streamCtx.getTranscodingFileName())
+		if err := sd.ParseSilence(); err != nil {
+			log.WithField("File", streamCtx.getTranscodingFileName()).WithError(err).Error("Detecting silence failed.")
+			return nil
+		}
+		notifySilenceResults(sd.Silences, streamCtx.streamId)
+	case StepSlides:
+		S.startSlideDetection(streamCtx)
+		defer S.endSlideDetection(streamCtx)
+		slides, err := detectSlides(streamCtx)
+		if err != nil {
+			log.WithField("File", streamCtx.getTranscodingFileName()).WithError(err).Error("Detecting slides failed.")
+			return nil
+		}
+		notifySlideSuggestions(slides, streamCtx.streamId)
+	default:
+		log.WithField("step", step).Warn("Skipping unknown pipeline step")
+	}
+	return nil
+}
+
+// createThumbnails creates the thumbnail sprite and the large thumbnail of a transcoded stream
+func createThumbnails(streamCtx *StreamContext) {
+	S.startThumbnailGeneration(streamCtx)
+	defer S.endThumbnailGeneration(streamCtx)
+	err := createThumbnailSprite(streamCtx, streamCtx.getTranscodingFileName())
+	thumbSuccessful := true
+	if err != nil {
+		log.WithField("File", streamCtx.getThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail sprite failed.")
+		thumbSuccessful = false
+	}
+	err = createVideoThumbnail(streamCtx, streamCtx.getTranscodingFileName())
+	if err != nil {
+		log.WithField("File", streamCtx.getLargeThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail failed.")
+		thumbSuccessful = false
+	}
+	if thumbSuccessful {
+		notifyThumbnailDone(streamCtx)
+	}
+}
+
+// convertUpload transcodes an uploaded video if its container, codec or level isn't suitable for streaming.
+// Otherwise the upload is moved to the storage directory as is.
+func convertUpload(c *StreamContext) error {
+	localFile := c.getRecordingFileName()
+	if _, err := os.Stat(localFile); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(c.getTranscodingFileName()); err == nil {
+			return nil // moved before the worker restarted
+		}
+	}
+	needsConversion := false
+
+	if container, err := getContainer(localFile); err != nil {
+		log.WithError(err).Error("Error getting container")
+		needsConversion = true
+	} else if !strings.Contains(container, "mp4") {
+		needsConversion = true
+		log.Debugf("Wrong container: %s, converting", container)
+	}
+
+	if codec, err := getCodec(localFile); err != nil {
+		log.WithError(err).Warn("Error getting codec")
+		needsConversion = true
+	} else if codec != "h264" {
+		needsConversion = true
+		log.Debugf("wrong codec: %s, converting", codec)
+	}
+
+	level, err := getLevel(localFile)
+	if err != nil {
+		log.WithError(err).Warn("Error getting level")
+		needsConversion = true
+	}
+	if levelInt, err := strconv.Atoi(level); err != nil {
+		log.WithError(err).Warnf("Error converting level(%s) to int", level)
+		needsConversion = true
+	} else {
+		if levelInt > 42 {
+			needsConversion = true
+			log.Debugf("Level too high: %d, converting", levelInt)
+		}
+	}
+
+	if needsConversion {
+		log.WithField("stream", c.streamId).Debug("Converting video from upload request")
+		return runStep(c, StepTranscode)
+	}
+	log.WithField("stream", c.streamId).Debug("Not converting video from upload request")
+	// create required directories
+	log.WithField("transcodingFileName", c.getTranscodingFileName()).Debug("Creating output directory")
+	if err = prepare(c.getTranscodingFileName()); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"in": c.getRecordingFileName(), "out": c.getTranscodingFileName()}).Debug("Copying file")
+	if err = moveFile(c.getRecordingFileName(), c.getTranscodingFileName()); err != nil {
+		return err
+	}
+	log.WithField("stream", c.streamId).Debug("Successfully moved upload to target dir")
+	return nil
+}
+
+// resumePipelines continues all pipelines that were interrupted by a restart of the worker, one after another.
+func resumePipelines() {
+	jobs.forceStart()
+	defer jobs.done()
+	for name, pipeline := range persisted.GetPipelines() {
+		logger := log.WithFields(log.Fields{"stream": name, "steps": pipeline.Steps})
+		pipeline.Attempts++
+		if pipeline.Attempts > maxPipelineAttempts {
+			logger.Error("Giving up on pipeline after too many attempts, keeping recording")
+			if err := persisted.RemovePipeline(name); err != nil {
+				logger.WithError(err).Error("Can't remove pipeline")
+			}
+			continue
+		}
+		logger.Info("Resuming pipeline")
+		S.startResumedPipeline(name)
+		runPipeline(pipeline.streamContext(), pipeline)
+		S.endResumedPipeline(name)
+	}
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/storage"
+)
+
+type testSink struct {
+	err  error
+	puts []string
+}
+
+func (s *testSink) Put(_ context.Context, key string, _ string) error {
+	s.puts = append(s.puts, key)
+	return s.err
+}
+
+// TestPipelineResume tests that failed pipelines are persisted with the recording kept and finish after a restart
+func TestPipelineResume(t *testing.T) {
+	persistDir, storageDir := cfg.PersistDir, cfg.StorageDir
+	defer func() { cfg.PersistDir, cfg.StorageDir, sinks = persistDir, storageDir, nil }()
+	cfg.PersistDir = t.TempDir()
+	cfg.StorageDir = t.TempDir()
+	var err error
+	persisted, err = NewPersistable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording := filepath.Join(t.TempDir(), "eidi.ts")
+	if err = os.WriteFile(recording, []byte("recording"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &StreamContext{
+		streamId:      1,
+		courseSlug:    "eidi",
+		teachingTerm:  "W",
+		teachingYear:  2021,
+		startTime:     time.Date(2021, 9, 23, 8, 0, 0, 0, time.Local),
+		streamVersion: "COMB",
+		publishVoD:    true,
+		recordingPath: &recording,
+		duration:      60,
+	}
+
+	failing := &testSink{err: errors.New("lrz unavailable")}
+	sinks = []storage.Sink{failing}
+	runPipeline(ctx, newPipeline(ctx, StepUpload))
+	if len(failing.puts) != 1 {
+		t.Fatalf("expected one upload attempt, got %d", len(failing.puts))
+	}
+	if _, err = os.Stat(recording); err != nil {
+		t.Fatalf("recording of failed pipeline was removed: %v", err)
+	}
+
+	// simulate restart
+	persisted, err = NewPersistable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipelines := persisted.GetPipelines()
+	pipeline, ok := pipelines[ctx.getStreamName()]
+	if !ok || len(pipeline.Steps) != 1 || pipeline.Steps[0] != StepUpload || pipeline.Duration != 60 {
+		t.Fatalf("unexpected persisted pipelines: %+v", pipelines)
+	}
+	if !persisted.isNeeded(recording) {
+		t.Fatal("recording of unfinished pipeline must not be deleted")
+	}
+
+	working := &testSink{}
+	sinks = []storage.Sink{working}
+	runPipeline(pipeline.streamContext(), pipeline)
+	if len(working.puts) != 1 || filepath.Base(working.puts[0]) != ctx.getStreamName()+".mp4" {
+		t.Fatalf("unexpected uploads: %v", working.puts)
+	}
+	if len(persisted.GetPipelines()) != 0 {
+		t.Fatal("finished pipeline was not removed")
+	}
+	if _, err = os.Stat(ctx.getRecordingTrashName()); err != nil {
+		t.Fatalf("recording of finished pipeline not marked for deletion: %v", err)
+	}
+	if len(persisted.Deletable) != 1 || persisted.Deletable[0].File != ctx.getRecordingTrashName() {
+		t.Fatalf("unexpected deletables: %+v", persisted.Deletable)
+	}
+}
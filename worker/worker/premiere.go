@@ -3,22 +3,30 @@ package worker
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"strconv"
+	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	log "github.com/sirupsen/logrus"
 )
 
+// premiereCommand returns the command streaming the file of a premiere in real time.
+// Premieres that start after their scheduled start skip ahead, so viewers joining late see the same position as everyone else.
+func premiereCommand(ctx *StreamContext, now time.Time) *ffmpeg.Command {
+	// we're a little paranoid about our input as we can't control it, see ffmpeg.ProfilePremiere
+	profile := ffmpeg.Get(ffmpeg.ProfilePremiere)
+	inputOptions := []string{"-re"}
+	if offset := int(now.Sub(ctx.startTime).Seconds()); offset > 0 {
+		inputOptions = append(inputOptions, "-ss", strconv.Itoa(offset))
+	}
+	return ffmpeg.New().
+		Input(ctx.sourceUrl, append(inputOptions, profile.InputOptions...)...).
+		Output(fmt.Sprintf("%s%s", ctx.ingestServer, ctx.streamName), append(profile.OutputArgs(), "-f", "flv")...)
+}
+
 func streamPremiere(ctx *StreamContext) {
-	// we're a little paranoid about our input as we can't control it:
-	cmd := exec.Command(
-		"ffmpeg", "-re", "-i", ctx.sourceUrl,
-		"-pix_fmt", "yuv420p", "-vsync", "1", "-threads", "0", "-vcodec", "libx264",
-		"-r", "30", "-g", "60", "-sc_threshold", "0",
-		"-b:v", "2500k", "-bufsize", "3000k", "-maxrate", "3000k",
-		"-preset", "veryfast", "-profile:v", "baseline", "-tune", "film",
-		"-acodec", "aac", "-b:a", "128k", "-ac", "2", "-ar", "48000", "-af", "aresample=async=1:min_hard_comp=0.100000:first_pts=0",
-		"-f", "flv", fmt.Sprintf("%s%s", ctx.ingestServer, ctx.streamName))
+	cmd := premiereCommand(ctx, time.Now()).Cmd()
 	log.WithField("cmd", cmd.String()).Info("Starting premiere")
 	ffmpegErr, errFfmpegErrFile := os.OpenFile(fmt.Sprintf("%s/ffmpeg_%s.log", cfg.LogDir, ctx.getStreamName()), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
 	if errFfmpegErrFile == nil {
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPremiereCommand(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	ctx := &StreamContext{sourceUrl: "/srv/vod.mp4", ingestServer: "rtmp://ingest.tum.live/live/", streamName: "abc", startTime: start}
+
+	args := premiereCommand(ctx, start.Add(-time.Second)).Args()
+	if !reflect.DeepEqual(args[:3], []string{"-re", "-i", "/srv/vod.mp4"}) {
+		t.Fatalf("premieres on time must start at the beginning, got %v", args)
+	}
+	if args[len(args)-1] != "rtmp://ingest.tum.live/live/abc" {
+		t.Fatalf("expected output to ingest server, got %v", args)
+	}
+
+	args = premiereCommand(ctx, start.Add(90*time.Second)).Args()
+	if !reflect.DeepEqual(args[:5], []string{"-re", "-ss", "90", "-i", "/srv/vod.mp4"}) {
+		t.Fatalf("late premieres must skip ahead, got %v", args)
+	}
+}
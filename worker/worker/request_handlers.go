@@ -8,7 +8,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -35,10 +34,15 @@ func (s *safeStreams) addContext(streamID uint32, streamCtx *StreamContext) {
 }
 
 func HandlePremiere(request *pb.PremiereRequest) {
+	startTime := time.Now()
+	if request.Start != nil {
+		startTime = request.Start.AsTime()
+	}
 	streamCtx := &StreamContext{
 		streamId:      request.StreamID,
 		sourceUrl:     request.FilePath,
-		startTime:     time.Now(),
+		startTime:     startTime,
+		streamName:    request.StreamName,
 		streamVersion: "",
 		courseSlug:    "PREMIERE",
 		ingestServer:  request.IngestServer,
@@ -53,7 +57,8 @@ func HandlePremiere(request *pb.PremiereRequest) {
 	NotifyStreamDone(streamCtx)
 }
 
-func HandleSelfStream(request *pb.SelfStreamResponse, slug string) *StreamContext {
+// HandleSelfStream records and streams a self stream read from sourceUrl, e.g. rtmp://localhost/<slug>
+func HandleSelfStream(request *pb.SelfStreamResponse, sourceUrl string) *StreamContext {
 	streamCtx := &StreamContext{
 		streamId:      request.GetStreamID(),
 		courseSlug:    request.GetCourseSlug(),
@@ -65,69 +70,23 @@ func HandleSelfStream(request *pb.SelfStreamResponse, slug string) *StreamContex
 		streamVersion: "COMB",
 		isSelfStream:  true,
 		ingestServer:  request.IngestServer,
-		sourceUrl:     "rtmp://localhost/" + slug,
+		sourceUrl:     sourceUrl,
 		streamName:    request.StreamName,
 		outUrl:        request.OutUrl,
+		dvr:           request.GetDvr(),
+		dvrWindow:     request.GetDvrWindow(),
 	}
 	stream(streamCtx)
 	return streamCtx
 }
 
 func HandleSelfStreamRecordEnd(ctx *StreamContext) {
-	S.startTranscoding(ctx.getStreamName())
-	err := transcode(ctx)
-	if err != nil {
-		ctx.TranscodingSuccessful = false
-		NotifyTranscodingFailure(*ctx, err)
-		log.Errorf("Error while transcoding: %v", err)
-	} else {
-		ctx.TranscodingSuccessful = true
-	}
-	S.endTranscoding(ctx.getStreamName())
-	if ctx.canceled {
-		// self stream restarted while transcoding
-		return
-	}
-	notifyTranscodingDone(ctx)
+	steps := []Step{StepTranscode}
 	if ctx.publishVoD {
-		upload(ctx)
-		notifyUploadDone(ctx)
-	}
-
-	S.startThumbnailGeneration(ctx)
-	defer S.endThumbnailGeneration(ctx)
-	err = createThumbnailSprite(ctx, ctx.getTranscodingFileName())
-	thumbSuccessful := true
-	if err != nil {
-		log.WithField("File", ctx.getThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail sprite failed.")
-		thumbSuccessful = false
-	}
-	err = createVideoThumbnail(ctx, ctx.getTranscodingFileName())
-	if err != nil {
-		log.WithField("File", ctx.getLargeThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail failed.")
-		thumbSuccessful = false
-	}
-	if thumbSuccessful {
-		notifyThumbnailDone(ctx)
-	}
-
-	S.startSilenceDetection(ctx)
-	defer S.endSilenceDetection(ctx)
-
-	sd := NewSilenceDetector(ctx.getTranscodingFileName())
-	err = sd.ParseSilence()
-	if err != nil {
-		log.WithField("File", ctx.getTranscodingFileName()).WithError(err).Error("Detecting silence failed.")
-		return
-	}
-	notifySilenceResults(sd.Silences, ctx.streamId)
-
-	if ctx.TranscodingSuccessful {
-		err := markForDeletion(ctx)
-		if err != nil {
-			log.WithField("stream", ctx.streamId).WithError(err).Error("Error marking for deletion")
-		}
+		steps = append(steps, StepUpload)
 	}
+	steps = append(steps, StepThumbnails, StepStore, StepSilence)
+	runPipeline(ctx, newPipeline(ctx, steps...))
 }
 
 // HandleThumbnailRequest creates a thumbnail on demand.
@@ -160,6 +119,7 @@ func HandleThumbnailRequest(request *pb.GenerateThumbnailRequest) {
 	if thumbSuccessful {
 		notifyThumbnailDone(streamCtx)
 	}
+	_ = store(streamCtx, streamCtx.getThumbnailSpriteFileName(), streamCtx.getLargeThumbnailSpriteFileName())
 }
 
 // HandleStreamEndRequest ends all streams for a given streamID contained in request
@@ -233,6 +193,8 @@ func HandleStreamRequest(request *pb.StreamRequest) {
 		ingestServer:  request.GetIngestServer(),
 		isSelfStream:  false,
 		outUrl:        request.GetOutUrl(),
+		dvr:           request.GetDvr(),
+		dvrWindow:     request.GetDvrWindow(),
 	}
 
 	// Register worker for stream
@@ -245,64 +207,21 @@ func HandleStreamRequest(request *pb.StreamRequest) {
 		log.Info("Skipping VoD creation")
 		return
 	}
-	S.startTranscoding(streamCtx.getStreamName())
-	err := transcode(streamCtx)
-	if err != nil {
-		streamCtx.TranscodingSuccessful = false
-		NotifyTranscodingFailure(*streamCtx, err)
-		log.Errorf("Error while transcoding: %v", err)
-	} else {
-		streamCtx.TranscodingSuccessful = true
-	}
-	S.endTranscoding(streamCtx.getStreamName())
-	notifyTranscodingDone(streamCtx)
-
+	steps := []Step{StepTranscode}
 	if streamCtx.streamVersion == "COMB" {
-		err = transcodeAudio(streamCtx)
-		if err != nil {
-			log.WithError(err).Error("Error transcoding audio")
-		}
-	}
-
-	S.startThumbnailGeneration(streamCtx)
-	defer S.endThumbnailGeneration(streamCtx)
-	err = createThumbnailSprite(streamCtx, streamCtx.getTranscodingFileName())
-	thumbSuccessful := true
-	if err != nil {
-		log.WithField("File", streamCtx.getThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail sprite failed")
-		thumbSuccessful = false
+		steps = append(steps, StepAudio)
 	}
-	err = createVideoThumbnail(streamCtx, streamCtx.getTranscodingFileName())
-	if err != nil {
-		log.WithField("File", streamCtx.getLargeThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail failed.")
-		thumbSuccessful = false
+	steps = append(steps, StepThumbnails, StepStore)
+	if streamCtx.publishVoD {
+		steps = append(steps, StepUpload)
 	}
-	if thumbSuccessful {
-		notifyThumbnailDone(streamCtx)
-	}
-
-	if request.PublishVoD {
-		upload(streamCtx)
-		notifyUploadDone(streamCtx)
-	}
-
 	if streamCtx.streamVersion == "COMB" {
-		S.startSilenceDetection(streamCtx)
-		defer S.endSilenceDetection(streamCtx)
-		sd := NewSilenceDetector(streamCtx.getTranscodingFileName())
-		err := sd.ParseSilence()
-		if err != nil {
-			log.WithField("File", streamCtx.getTranscodingFileName()).WithError(err).Error("Detecting silence failed.")
-			return
-		}
-		notifySilenceResults(sd.Silences, streamCtx.streamId)
+		steps = append(steps, StepSilence)
 	}
-	if streamCtx.TranscodingSuccessful {
-		err := markForDeletion(streamCtx)
-		if err != nil {
-			log.WithField("stream", streamCtx.streamId).WithError(err).Error("Error marking for deletion")
-		}
+	if streamCtx.streamVersion == "PRES" {
+		steps = append(steps, StepSlides)
 	}
+	runPipeline(streamCtx, newPipeline(streamCtx, steps...))
 }
 
 func GetStreamInfoForUploadReq(uploadKey string) (*pb.GetStreamInfoForUploadResponse, error) {
@@ -331,99 +250,11 @@ func HandleUploadRestReq(streamInfo *pb.GetStreamInfoForUploadResponse, localFil
 	}
 	log.WithFields(log.Fields{"stream": c.streamId, "course": c.courseSlug, "file": localFile}).Debug("Handling upload request")
 
-	needsConversion := false
-
-	if container, err := getContainer(localFile); err != nil {
-		log.WithError(err).Error("Error getting container")
-		needsConversion = true
-	} else if !strings.Contains(container, "mp4") {
-		needsConversion = true
-		log.Debugf("Wrong container: %s, converting", container)
-	}
-
-	if codec, err := getCodec(localFile); err != nil {
-		log.WithError(err).Warn("Error getting codec")
-		needsConversion = true
-	} else if codec != "h264" {
-		needsConversion = true
-		log.Debugf("wrong codec: %s, converting", codec)
-	}
-
-	level, err := getLevel(localFile)
-	if err != nil {
-		log.WithError(err).Warn("Error getting level")
-		needsConversion = true
+	steps := []Step{StepConvert, StepAudio, StepThumbnails, StepStore, StepSilence, StepUpload}
+	if c.streamVersion == "PRES" {
+		steps = append(steps, StepSlides)
 	}
-	if levelInt, err := strconv.Atoi(level); err != nil {
-		log.WithError(err).Warnf("Error converting level(%s) to int", level)
-		needsConversion = true
-	} else {
-		if levelInt > 42 {
-			needsConversion = true
-			log.Debugf("Level too high: %d, converting", levelInt)
-		}
-	}
-
-	if needsConversion {
-		log.WithField("stream", c.streamId).Debug("Converting video from upload request")
-		S.startTranscoding(c.getStreamName())
-		err := transcode(&c)
-		if err != nil {
-			NotifyTranscodingFailure(c, err)
-			log.WithError(err).Error("Error transcoding")
-		}
-		notifyTranscodingDone(&c)
-		S.endTranscoding(c.getStreamName())
-
-	} else {
-		log.WithField("stream", c.streamId).Debug("Not converting video from upload request")
-		// create required directories
-		log.WithField("transcodingFileName", c.getTranscodingFileName()).Debug("Creating output directory")
-		if err = prepare(c.getTranscodingFileName()); err != nil {
-			log.Error(err)
-		}
-		log.WithFields(log.Fields{"in": c.getRecordingTrashName(), "out": c.getTranscodingFileName()}).Debug("Copying file")
-		if err = moveFile(c.getRecordingFileName(), c.getTranscodingFileName()); err != nil {
-			log.WithError(err).Error("Can't move upload to transcoding dir")
-		} else {
-			log.WithField("stream", c.streamId).Debug("Successfully moved upload to target dir")
-		}
-	}
-
-	err = transcodeAudio(&c)
-	if err != nil {
-		log.WithError(err).Error("Error transcoding audio")
-	}
-
-	S.startThumbnailGeneration(&c)
-	defer S.endThumbnailGeneration(&c)
-	err = createThumbnailSprite(&c, c.getTranscodingFileName())
-	thumbSuccessful := true
-	if err != nil {
-		log.WithField("File", c.getThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail sprite failed.")
-		thumbSuccessful = false
-	}
-	err = createVideoThumbnail(&c, c.getTranscodingFileName())
-	if err != nil {
-		log.WithField("File", c.getLargeThumbnailSpriteFileName()).WithError(err).Error("Creating thumbnail failed.")
-		thumbSuccessful = false
-	}
-	if thumbSuccessful {
-		notifyThumbnailDone(&c)
-	}
-
-	S.startSilenceDetection(&c)
-	defer S.endSilenceDetection(&c)
-	sd := NewSilenceDetector(c.getTranscodingFileName())
-	if err = sd.ParseSilence(); err != nil {
-		log.WithField("File", c.getTranscodingFileName()).WithError(err).Error("Detecting silence failed.")
-	} else {
-		notifySilenceResults(sd.Silences, c.streamId)
-	}
-
-	upload(&c)
-	notifyUploadDone(&c)
-	_ = os.Remove(c.getRecordingFileName())
+	runPipeline(&c, newPipeline(&c, steps...))
 }
 
 // moveFile moves a file from sourcePath to destPath.
@@ -473,6 +304,8 @@ type StreamContext struct {
 	stopped      bool   // whether the stream has been stopped
 	outUrl       string // url the stream will be available at
 	discardVoD   bool   // whether the VoD should be discarded
+	dvr          bool   // whether a DVR playlist is recorded while live
+	dvrWindow    uint32 // seconds the DVR playlist spans, 0 for the whole stream
 
 	// calculated after stream:
 	duration      uint32 // duration of the stream in seconds
@@ -1,10 +1,10 @@
 package worker
 
 import (
-	"os/exec"
 	"strconv"
 	"strings"
 
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -24,7 +24,12 @@ func NewSilenceDetector(input string) *SilenceDetect {
 
 func (s *SilenceDetect) ParseSilence() error {
 	log.WithField("File", s.Input).Info("Start detecting silence")
-	cmd := exec.Command("nice", "ffmpeg", "-nostats", "-i", s.Input, "-af", "silencedetect=n=-15dB:d=30", "-f", "null", "-")
+	cmd := ffmpeg.New().
+		Nice(10).
+		Global("-nostats").
+		Input(s.Input).
+		Output("-", "-af", "silencedetect=n=-15dB:d=30", "-f", "null").
+		Cmd()
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return err
@@ -0,0 +1,181 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
+	"github.com/TUM-Dev/gocast/worker/pb"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// slideChangeThreshold is the scene score from which a frame counts as a new slide
+	slideChangeThreshold = 0.3
+	// minSlideDuration is the minimum number of seconds between two suggested slides. Changes closer to the
+	// previous slide, e.g. animations or slides the lecturer skipped through, are merged into it.
+	minSlideDuration = 30
+	// maxSlideTitleLength is the maximum number of characters of a recognized slide title
+	maxSlideTitleLength = 80
+)
+
+// slide is a section suggested at a slide change of a presentation recording
+type slide struct {
+	Second    uint32
+	Title     string
+	ImagePath string
+}
+
+var showinfoPtsTime = regexp.MustCompile(`Parsed_showinfo.*pts_time:\s*(\d+(?:\.\d+)?)`)
+
+// slideDetectionCommand returns the command logging the scene changes of file. Frames are sampled once a
+// second and downscaled as slides don't change faster and the slides' details don't matter for detection.
+func slideDetectionCommand(file string) *ffmpeg.Command {
+	return ffmpeg.New().
+		Nice(10).
+		Global("-hide_banner", "-nostats").
+		Input(file).
+		Output("-", "-an", "-vf", fmt.Sprintf("fps=1,scale=320:-2,select='gt(scene,%.2f)',showinfo", slideChangeThreshold), "-f", "null")
+}
+
+// parseSlideChanges returns the seconds of the slides found in the output of slideDetectionCommand.
+// The first slide always starts at 0.
+func parseSlideChanges(output string) []uint32 {
+	changes := []uint32{0}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		m := showinfoPtsTime.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		t, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		if uint32(t) >= changes[len(changes)-1]+minSlideDuration {
+			changes = append(changes, uint32(t))
+		}
+	}
+	return changes
+}
+
+// parseSlideTitle returns the first line of text recognized on a slide, shortened to maxSlideTitleLength
+func parseSlideTitle(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if len([]rune(line)) < 3 {
+			continue // noise, e.g. bullet points or logos recognized as characters
+		}
+		if r := []rune(line); len(r) > maxSlideTitleLength {
+			line = strings.TrimSpace(string(r[:maxSlideTitleLength]))
+		}
+		return line
+	}
+	return ""
+}
+
+// getSlideFileName returns the file the image of the slide starting at second is written to
+func (s StreamContext) getSlideFileName(second uint32) string {
+	return filepath.Join(filepath.Dir(s.getTranscodingFileName()), fmt.Sprintf("%s-slide-%d.jpg", s.getStreamName(), second))
+}
+
+// detectSlides suggests sections of a presentation recording at its slide changes. The image of every slide
+// is stored, titles are recognized if tesseract is installed.
+func detectSlides(streamCtx *StreamContext) ([]slide, error) {
+	in := streamCtx.getTranscodingFileName()
+	log.WithField("file", in).Info("Start detecting slides")
+	out, err := slideDetectionCommand(in).Cmd().CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("detect slide changes: %w: %s", err, out)
+	}
+	_, ocrErr := exec.LookPath("tesseract")
+	var slides []slide
+	for _, second := range parseSlideChanges(string(out)) {
+		s := slide{Second: second, ImagePath: streamCtx.getSlideFileName(second)}
+		if err := extractSlideImage(in, second, s.ImagePath); err != nil {
+			return nil, err
+		}
+		if err := StoreFile(s.ImagePath); err != nil {
+			return nil, err
+		}
+		if ocrErr == nil {
+			s.Title, err = recognizeSlideTitle(in, second)
+			if err != nil {
+				log.WithField("file", in).WithField("second", second).WithError(err).Warn("Can't recognize slide title")
+			}
+		}
+		slides = append(slides, s)
+	}
+	log.WithField("file", in).WithField("slides", len(slides)).Info("Slides detected")
+	return slides, nil
+}
+
+// extractSlideImage writes the frame at second to out, scaled like the images of manually created sections
+func extractSlideImage(in string, second uint32, out string) error {
+	cmd := ffmpeg.New().
+		Global("-y", "-v", "error").
+		Input(in, "-ss", strconv.Itoa(int(second))).
+		Output(out, "-vf", "scale=156:-1", "-frames:v", "1", "-q:v", "2").
+		Cmd()
+	if o, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("extract slide image: %w: %s", err, o)
+	}
+	return nil
+}
+
+// recognizeSlideTitle runs OCR on the upper quarter of the frame at second, where slides usually have their title
+func recognizeSlideTitle(in string, second uint32) (string, error) {
+	f, err := os.CreateTemp("", "slide-*.png")
+	if err != nil {
+		return "", err
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+	cmd := ffmpeg.New().
+		Global("-y", "-v", "error").
+		Input(in, "-ss", strconv.Itoa(int(second))).
+		Output(f.Name(), "-vf", "crop=iw:ih/4:0:0", "-frames:v", "1").
+		Cmd()
+	if o, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("extract slide title: %w: %s", err, o)
+	}
+	text, err := exec.Command("tesseract", f.Name(), "stdout", "-l", cfg.OcrLanguages).Output()
+	if err != nil {
+		return "", fmt.Errorf("run tesseract: %w", err)
+	}
+	return parseSlideTitle(string(text)), nil
+}
+
+// notifySlideSuggestions sends the detected slides to TUM-Live, lecturers review them before they become sections
+func notifySlideSuggestions(slides []slide, streamID uint32) {
+	client, conn, err := GetClient()
+	if err != nil {
+		log.WithError(err).Error("Unable to dial tumlive")
+		return
+	}
+	defer closeConnection(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	suggestions := make([]*pb.SlideSuggestion, len(slides))
+	for i, s := range slides {
+		suggestions[i] = &pb.SlideSuggestion{Second: s.Second, Title: s.Title, ImagePath: s.ImagePath}
+	}
+	_, err = client.NotifySlideSuggestions(ctx, &pb.SlideSuggestions{
+		WorkerID: cfg.WorkerID,
+		StreamID: streamID,
+		Slides:   suggestions,
+	})
+	if err != nil {
+		log.WithError(err).Error("Could not send slide suggestions")
+	}
+}
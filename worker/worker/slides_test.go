@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSlideChanges(t *testing.T) {
+	output := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'in.mp4':
+[Parsed_showinfo_3 @ 0x55d0c8a0] n:   0 pts:     12 pts_time:12      duration:      1 fmt:yuv420p
+[Parsed_showinfo_3 @ 0x55d0c8a0] n:   1 pts:     20 pts_time:20      duration:      1 fmt:yuv420p
+[Parsed_showinfo_3 @ 0x55d0c8a0] n:   2 pts:     95 pts_time:95.5    duration:      1 fmt:yuv420p
+[Parsed_showinfo_3 @ 0x55d0c8a0] n:   3 pts:    110 pts_time:110     duration:      1 fmt:yuv420p
+[Parsed_showinfo_3 @ 0x55d0c8a0] n:   4 pts:    300 pts_time:300     duration:      1 fmt:yuv420p
+frame=    5 fps=0.0 q=-0.0 Lsize=N/A time=00:05:00.00 bitrate=N/A speed= 500x`
+	got := parseSlideChanges(output)
+	expected := []uint32{0, 95, 300}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if got := parseSlideChanges(""); !reflect.DeepEqual(got, []uint32{0}) {
+		t.Fatalf("expected first slide only, got %v", got)
+	}
+}
+
+func TestParseSlideTitle(t *testing.T) {
+	tests := []struct {
+		text, expected string
+	}{
+		{"\n  •\nDynamic   Programming\nOptimal substructure\n", "Dynamic Programming"},
+		{"", ""},
+		{"A Very Long Slide Title That Goes On And On Because Someone Wrote A Whole Sentence There", "A Very Long Slide Title That Goes On And On Because Someone Wrote A Whole Senten"},
+	}
+	for _, test := range tests {
+		if got := parseSlideTitle(test.text); got != test.expected {
+			t.Errorf("parseSlideTitle(%q) = %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
@@ -8,11 +8,11 @@ import (
 	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/grpcauth"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	"github.com/TUM-Dev/gocast/worker/worker/vmstat"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
@@ -26,6 +26,7 @@ const (
 	costTranscoding         = 2
 	costTranscodingAudio    = 1
 	costSilenceDetection    = 1
+	costSlideDetection      = 1
 	costThumbnailGeneration = 1
 )
 
@@ -46,6 +47,14 @@ func (s *Status) startSilenceDetection(streamCtx *StreamContext) {
 	statusLock.Unlock()
 }
 
+func (s *Status) startSlideDetection(streamCtx *StreamContext) {
+	defer s.SendHeartbeat()
+	statusLock.Lock()
+	s.workload += costSlideDetection
+	s.Jobs = append(s.Jobs, fmt.Sprintf("detecting slides in %s", streamCtx.getStreamName()))
+	statusLock.Unlock()
+}
+
 func (s *Status) startStream(streamCtx *StreamContext) {
 	defer s.SendHeartbeat()
 	statusLock.Lock()
@@ -131,6 +140,19 @@ func (s *Status) endSilenceDetection(streamCtx *StreamContext) {
 	statusLock.Unlock()
 }
 
+func (s *Status) endSlideDetection(streamCtx *StreamContext) {
+	defer s.SendHeartbeat()
+	statusLock.Lock()
+	s.workload -= costSlideDetection
+	for i := range s.Jobs {
+		if s.Jobs[i] == fmt.Sprintf("detecting slides in %s", streamCtx.getStreamName()) {
+			s.Jobs = append(s.Jobs[:i], s.Jobs[i+1:]...)
+			break
+		}
+	}
+	statusLock.Unlock()
+}
+
 func (s *Status) endTranscodingAudio(name string) {
 	defer s.SendHeartbeat()
 	statusLock.Lock()
@@ -144,9 +166,28 @@ func (s *Status) endTranscodingAudio(name string) {
 	statusLock.Unlock()
 }
 
+// startResumedPipeline reports a pipeline resumed after a restart as a job. The steps add their own workload.
+func (s *Status) startResumedPipeline(name string) {
+	defer s.SendHeartbeat()
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	s.Jobs = append(s.Jobs, fmt.Sprintf("resumed pipeline of %s", name))
+}
+
+func (s *Status) endResumedPipeline(name string) {
+	defer s.SendHeartbeat()
+	statusLock.Lock()
+	for i := range s.Jobs {
+		if s.Jobs[i] == fmt.Sprintf("resumed pipeline of %s", name) {
+			s.Jobs = append(s.Jobs[:i], s.Jobs[i+1:]...)
+			break
+		}
+	}
+	statusLock.Unlock()
+}
+
 func (s *Status) SendHeartbeat() {
-	// WithInsecure: workerId used for authentication, all servers are inside their own VLAN to further improve security
-	clientConn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	clientConn, err := grpc.Dial(fmt.Sprintf("%s:50052", cfg.MainBase), grpcauth.DialOption())
 	if err != nil {
 		log.WithError(err).Error("unable to dial for heartbeat")
 		return
@@ -171,6 +212,7 @@ func (s *Status) SendHeartbeat() {
 		Memory:   s.Stat.GetMemStr(),
 		Disk:     s.Stat.GetDiskStr(),
 		Uptime:   strings.ReplaceAll(time.Since(s.StartTime).Round(time.Minute).String(), "0s", ""),
+		Draining: jobs.isDraining(),
 	})
 	if err != nil {
 		log.WithError(err).Error("Sending Heartbeat failed")
@@ -3,12 +3,13 @@ package worker
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -18,30 +19,32 @@ func stream(streamCtx *StreamContext) {
 	streamUntil := streamCtx.endTime.Add(time.Minute * 10)
 	log.WithFields(log.Fields{"source": streamCtx.sourceUrl, "end": streamUntil, "fileName": streamCtx.getRecordingFileName()}).
 		Info("streaming lecture hall")
+	if streamCtx.dvrEnabled() {
+		if err := os.MkdirAll(streamCtx.getDvrDir(), 0o755); err != nil {
+			log.WithError(err).Error("Can't create DVR directory, disabling DVR")
+			streamCtx.dvr = false
+		} else {
+			// viewers switch to the VoD once it's published, the DVR recording is only kept as a fallback
+			defer func() {
+				if err := persisted.AddDeletable(streamCtx.getDvrDir()); err != nil {
+					log.WithError(err).Error("Can't mark DVR recording for deletion")
+				}
+			}()
+		}
+	}
 	S.startStream(streamCtx)
 	defer S.endStream(streamCtx)
 	// in case ffmpeg dies retry until stream should be done.
 	lastErr := time.Now().Add(time.Minute * -1)
 	errCount := 0
 	for time.Now().Before(streamUntil) && !streamCtx.stopped {
-		var cmd *exec.Cmd
-
-		// todo: too much duplication
-		if strings.Contains(streamCtx.sourceUrl, "rtsp") {
-			cmd = exec.Command(
-				"sh", "-c",
-				`ffmpeg -hide_banner -nostats -rtsp_transport tcp -t `+fmt.Sprintf("%.0f", time.Until(streamUntil).Seconds())+ // timeout ffmpeg when stream is finished
-					" -i "+fmt.Sprintf(streamCtx.sourceUrl)+
-					` -map 0 -c copy -f mpegts - -c:v libx264 -preset veryfast -tune zerolatency -maxrate 2500k -bufsize 3000k -g 60 -r 30 -x264-params keyint=60:scenecut=0 -c:a aac -ar 44100 -b:a 128k `+
-					`-f flv `+fmt.Sprintf("%s/%s", streamCtx.ingestServer, streamCtx.streamName)+" >> "+streamCtx.getRecordingFileName())
-		} else {
-			cmd = exec.Command(
-				"sh", "-c",
-				`ffmpeg -hide_banner -nostats -rw_timeout 5000000 -t `+fmt.Sprintf("%.0f", time.Until(streamUntil).Seconds())+ // timeout ffmpeg when stream is finished
-					" -i "+fmt.Sprintf(streamCtx.sourceUrl)+
-					` -map 0 -c copy -f mpegts - -c:v libx264 -preset veryfast -tune zerolatency -maxrate 2500k -bufsize 3000k -g 60 -r 30 -x264-params keyint=60:scenecut=0 -c:a aac -ar 44100 -b:a 128k `+
-					`-f flv `+fmt.Sprintf("%s/%s", streamCtx.ingestServer, streamCtx.streamName)+" >> "+streamCtx.getRecordingFileName())
+		cmd := liveCommand(streamCtx, time.Until(streamUntil)).Cmd()
+		recording, err := os.OpenFile(streamCtx.getRecordingFileName(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			errorWithBackoff(&lastErr, "Can't open recording file", err)
+			continue
 		}
+		cmd.Stdout = recording
 		// persist stream command in context, so it can be killed later
 		streamCtx.streamCmd = cmd
 		log.WithField("cmd", cmd.String()).Info("Starting stream")
@@ -53,7 +56,8 @@ func stream(streamCtx *StreamContext) {
 		}
 		// Create a new pgid for the new process, so we don't kill the parent process when ending the stream
 		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-		err := cmd.Run()
+		err = cmd.Run()
+		_ = recording.Close()
 		if err != nil && !streamCtx.stopped {
 			errCount++
 			if errCount > 20 && strings.Contains(streamCtx.sourceUrl, "localhost") {
@@ -74,6 +78,25 @@ func stream(streamCtx *StreamContext) {
 	streamCtx.streamCmd = nil
 }
 
+// liveCommand returns the ffmpeg command that pushes the source to the ingest server and writes the unmodified
+// source to stdout for the recording and to the DVR playlist if enabled. ffmpeg times out after duration.
+func liveCommand(streamCtx *StreamContext, duration time.Duration) *ffmpeg.Command {
+	inputOptions := []string{"-rw_timeout", "5000000"}
+	if strings.Contains(streamCtx.sourceUrl, "rtsp") {
+		inputOptions = []string{"-rtsp_transport", "tcp"}
+	}
+	profile := ffmpeg.Get(ffmpeg.ProfileLive)
+	inputOptions = append(inputOptions, "-t", fmt.Sprintf("%.0f", duration.Seconds()))
+	cmd := ffmpeg.New().
+		Global("-hide_banner", "-nostats").
+		Input(streamCtx.sourceUrl, append(inputOptions, profile.InputOptions...)...).
+		Output("-", "-map", "0", "-c", "copy", "-f", "mpegts")
+	if streamCtx.dvrEnabled() {
+		cmd.Output(filepath.Join(streamCtx.getDvrDir(), "playlist.m3u8"), dvrOutputArgs(streamCtx)...)
+	}
+	return cmd.Output(fmt.Sprintf("%s/%s", streamCtx.ingestServer, streamCtx.streamName), append(profile.OutputArgs(), "-f", "flv")...)
+}
+
 // errorWithBackoff updates lastError and sleeps for a second if the last error was within this second
 func errorWithBackoff(lastError *time.Time, msg string, err error) {
 	log.WithFields(log.Fields{"lastErr": lastError}).WithError(err).Error(msg)
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TUM-Dev/gocast/worker/cfg"
+)
+
+func TestLiveCommand(t *testing.T) {
+	ctx := &StreamContext{sourceUrl: "rtsp://10.0.0.4", ingestServer: "rtmp://ingest.tum.live/live", streamName: "abc"}
+	args := liveCommand(ctx, time.Hour).Args()
+	expected := []string{"-hide_banner", "-nostats", "-rtsp_transport", "tcp", "-t", "3600", "-i", "rtsp://10.0.0.4",
+		"-map", "0", "-c", "copy", "-f", "mpegts", "-"}
+	if !reflect.DeepEqual(args[:len(expected)], expected) {
+		t.Fatalf("expected recording output %v, got %v", expected, args)
+	}
+	if args[len(args)-3] != "-f" || args[len(args)-2] != "flv" || args[len(args)-1] != "rtmp://ingest.tum.live/live/abc" {
+		t.Fatalf("expected flv output to ingest server, got %v", args)
+	}
+
+	ctx.sourceUrl = "rtmp://localhost/selfstream"
+	if args = liveCommand(ctx, time.Minute).Args(); !reflect.DeepEqual(args[2:8], []string{"-rw_timeout", "5000000", "-t", "60", "-i", "rtmp://localhost/selfstream"}) {
+		t.Fatalf("unexpected input of self stream: %v", args)
+	}
+}
+
+func TestLiveCommandDvr(t *testing.T) {
+	cfg.DvrDir, cfg.DvrURLTemplate = "/dvr", "https://edge.tum.live/worker1/dvr/%s/playlist.m3u8"
+	defer func() { cfg.DvrURLTemplate = "" }()
+	ctx := &StreamContext{sourceUrl: "rtsp://10.0.0.4", ingestServer: "rtmp://ingest.tum.live/live", streamName: "abc",
+		courseSlug: "eidi", startTime: time.Date(2021, 9, 23, 10, 0, 0, 0, time.Local), streamVersion: "COMB", dvr: true}
+	if ctx.getDvrUrl() != "https://edge.tum.live/worker1/dvr/eidi_2021_09_23_10_00COMB/playlist.m3u8" {
+		t.Fatalf("unexpected DVR url %s", ctx.getDvrUrl())
+	}
+	args := strings.Join(liveCommand(ctx, time.Hour).Args(), " ")
+	expected := "-hls_playlist_type event -hls_list_size 0 -hls_flags append_list+discont_start+program_date_time " +
+		"-hls_segment_filename /dvr/eidi_2021_09_23_10_00COMB/seg%05d.ts /dvr/eidi_2021_09_23_10_00COMB/playlist.m3u8"
+	if !strings.Contains(args, expected) {
+		t.Fatalf("expected DVR output of the whole stream, got %s", args)
+	}
+
+	ctx.dvrWindow = 1800
+	args = strings.Join(liveCommand(ctx, time.Hour).Args(), " ")
+	if !strings.Contains(args, "-hls_list_size 300 -hls_flags append_list+discont_start+program_date_time+delete_segments") {
+		t.Fatalf("expected DVR window of 300 segments, got %s", args)
+	}
+
+	ctx.streamVersion = "PRES"
+	if strings.Contains(strings.Join(liveCommand(ctx, time.Hour).Args(), " "), "hls") || ctx.getDvrUrl() != "" {
+		t.Fatal("only the combined view should be recorded for DVR")
+	}
+}
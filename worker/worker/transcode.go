@@ -6,32 +6,29 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	log "github.com/sirupsen/logrus"
 )
 
-func buildCommand(niceness int, infile string, outfile string, tune string, crf int, self bool) *exec.Cmd {
-	c := []string{
-		"-n", fmt.Sprintf("%d", niceness),
-		"ffmpeg", "-nostats", "-loglevel", "error", "-y",
-		"-progress", "-",
-		"-i", infile,
-		"-vsync", "2", "-c:v", "libx264", "-level", "4.0", "-movflags", "+faststart",
-	}
-	if tune != "" {
-		c = append(c, "-tune", tune)
-	}
+// transcodeCommand returns the command transcoding a recording with the profile of its source type.
+// Progress is written to stdout, self streams are probed longer as their sources vary more.
+func transcodeCommand(in, out, sourceType string, self bool) *ffmpeg.Command {
+	profile := ffmpeg.Get(ffmpeg.VodProfile(sourceType))
+	inputOptions := profile.InputOptions
 	if self {
-		c = append(c, "-probesize 25M -analyzeduration 50M")
+		inputOptions = append([]string{"-probesize", "25M", "-analyzeduration", "50M"}, inputOptions...)
 	}
-	c = append(c, "-c:a", "aac", "-b:a", "128k", "-crf", fmt.Sprintf("%d", crf), outfile)
-	return exec.Command("nice", c...)
+	return ffmpeg.New().
+		Nice(profile.Niceness).
+		Global("-nostats", "-loglevel", "error", "-y", "-progress", "-").
+		Input(in, inputOptions...).
+		Output(out, profile.OutputArgs()...)
 }
 
 func transcode(streamCtx *StreamContext) error {
@@ -60,7 +57,6 @@ func transcode(streamCtx *StreamContext) error {
 	if err != nil {
 		return err
 	}
-	var cmd *exec.Cmd
 	// create command fitting its content with appropriate niceness:
 	in := streamCtx.getRecordingFileName()
 	inputTime, err := getDuration(in)
@@ -69,18 +65,7 @@ func transcode(streamCtx *StreamContext) error {
 	}
 
 	out := streamCtx.getTranscodingFileName()
-	switch streamCtx.streamVersion {
-	case "CAM":
-		// compress camera image slightly more
-		cmd = buildCommand(10, in, out, "", 26, streamCtx.isSelfStream)
-	case "PRES":
-		cmd = buildCommand(9, in, out, "stillimage", 20, streamCtx.isSelfStream)
-	case "COMB":
-		cmd = buildCommand(8, in, out, "", 24, streamCtx.isSelfStream)
-	default:
-		// unknown source, use higher compression and less priority
-		cmd = buildCommand(10, in, out, "", 26, streamCtx.isSelfStream)
-	}
+	cmd := transcodeCommand(in, out, streamCtx.streamVersion, streamCtx.isSelfStream).Cmd()
 	log.WithFields(log.Fields{"input": in, "output": out, "command": cmd.String()}).Info("Transcoding")
 	streamCtx.transcodingCmd = cmd
 	stderr, err := cmd.StderrPipe()
@@ -206,12 +191,13 @@ func transcodeAudio(ctx *StreamContext) error {
 
 	input := ctx.getTranscodingFileName()
 	output := ctx.getAudioTranscodingFileName()
-	cmd := exec.Command("ffmpeg",
-		"-y",
-		"-v", "quiet",
-		"-i", input,
-		"-c:a", "aac",
-		"-vn", output)
+	profile := ffmpeg.Get(ffmpeg.ProfileAudio)
+	cmd := ffmpeg.New().
+		Nice(profile.Niceness).
+		Global("-y", "-v", "quiet").
+		Input(input, profile.InputOptions...).
+		Output(output, profile.OutputArgs()...).
+		Cmd()
 	log.WithFields(log.Fields{"input": input, "output": output, "command": cmd.String()}).Info("Transcoding audio")
 
 	out, err := cmd.CombinedOutput()
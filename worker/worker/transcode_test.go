@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranscodeCommand(t *testing.T) {
+	cmd := transcodeCommand("in.ts", "out.mp4", "PRES", false).Cmd()
+	expected := []string{"nice", "-n", "9", "ffmpeg", "-nostats", "-loglevel", "error", "-y", "-progress", "-", "-i", "in.ts",
+		"-c:v", "libx264", "-tune", "stillimage", "-level", "4.0", "-crf", "20", "-vsync", "2", "-movflags", "+faststart",
+		"-c:a", "aac", "-b:a", "128k", "out.mp4"}
+	if !reflect.DeepEqual(cmd.Args, expected) {
+		t.Fatalf("expected %v, got %v", expected, cmd.Args)
+	}
+
+	args := transcodeCommand("in.ts", "out.mp4", "COMB", true).Args()
+	if !reflect.DeepEqual(args[6:12], []string{"-probesize", "25M", "-analyzeduration", "50M", "-i", "in.ts"}) {
+		t.Fatalf("self streams must be probed longer, got %v", args)
+	}
+}
@@ -1,92 +1,100 @@
 package worker
 
 import (
-	"io"
-	"mime/multipart"
-	"net/http"
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/storage"
 	log "github.com/sirupsen/logrus"
 )
 
-func upload(streamCtx *StreamContext) {
-	log.WithField("stream", streamCtx.getStreamName()).Info("Uploading stream")
-	err := post(streamCtx.getTranscodingFileName())
-	if err != nil {
-		log.WithField("stream", streamCtx.getStreamName()).WithError(err).Error("Error uploading stream")
-	}
-	log.WithField("stream", streamCtx.getStreamName()).Info("Uploaded stream")
-}
+var (
+	// Storage keeps the artifacts (transcodes, thumbnails, section images) of this worker
+	Storage storage.Storage = storage.NewLocal("/mass")
+	// sinks are additional destinations VoDs are published to
+	sinks []storage.Sink
+)
 
-func post(file string) error {
-	client := &http.Client{
-		// 5 minutes timeout, some large files can take a while.
-		Timeout: time.Minute * 15,
+// setupStorage configures Storage and the sinks based on cfg
+func setupStorage() {
+	switch cfg.StorageBackend {
+	case "s3":
+		Storage = storage.NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		Storage = storage.NewLocal(cfg.StorageDir)
 	}
-	r, w := io.Pipe()
-	writer := multipart.NewWriter(w)
-
-	go func() {
-		defer w.Close()
-		defer writer.Close()
-		err := writeFile(writer, "filename", file)
-		if err != nil {
-			log.Error("Cannot create form file: ", err)
-			return
-		}
-
-		fields := map[string]string{
-			"benutzer":    cfg.LrzUser,
-			"mailadresse": cfg.LrzMail,
-			"telefon":     cfg.LrzPhone,
-			"unidir":      "tum",
-			"subdir":      cfg.LrzSubDir,
-			"info":        "",
-		}
-
-		for name, value := range fields {
-			err = writeField(writer, name, value)
-			if err != nil {
-				log.Error("Cannot create form field: ", err)
-				return
-			}
-		}
-	}()
-	rsp, err := client.Post(cfg.LrzUploadUrl, writer.FormDataContentType(), r)
-	if err == nil && rsp.StatusCode != http.StatusOK {
-		log.Error("Request failed with response code: ", rsp.StatusCode)
+	if cfg.LrzUploadUrl != "" {
+		sinks = append(sinks, storage.LRZ{
+			UploadURL: cfg.LrzUploadUrl,
+			User:      cfg.LrzUser,
+			Mail:      cfg.LrzMail,
+			Phone:     cfg.LrzPhone,
+			SubDir:    cfg.LrzSubDir,
+		})
 	}
-	if err == nil && rsp != nil {
-		all, err := io.ReadAll(rsp.Body)
-		if err == nil {
-			log.WithField("fileUploaded", file).Debug(string(all))
-		}
+}
+
+// StorageKey returns the key of a file in cfg.StorageDir, e.g. /mass/2021/S/eidi/thumb.jpg -> 2021/S/eidi/thumb.jpg
+func StorageKey(file string) string {
+	rel, err := filepath.Rel(cfg.StorageDir, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(file)
 	}
-	return err
+	return filepath.ToSlash(rel)
 }
 
-func writeField(writer *multipart.Writer, name string, value string) error {
-	formFieldWriter, err := writer.CreateFormField(name)
+// StoreFile puts a file written to cfg.StorageDir into Storage. If Storage is remote, the local copy is deleted a day later.
+func StoreFile(file string) error {
+	err := Storage.Put(context.Background(), StorageKey(file), file)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(formFieldWriter, strings.NewReader(value))
-	return err
+	if cfg.StorageBackend != "local" {
+		return persisted.AddDeletable(file)
+	}
+	return nil
 }
 
-func writeFile(writer *multipart.Writer, fieldname string, file string) error {
-	formFileWriter, err := writer.CreateFormFile(fieldname, file)
-	if err != nil {
-		return err
+// storeArtifacts puts all artifacts of a stream into Storage. Artifacts that weren't created (e.g. audio of PRES streams) are skipped.
+func storeArtifacts(streamCtx *StreamContext) error {
+	return store(streamCtx,
+		streamCtx.getTranscodingFileName(),
+		streamCtx.getAudioTranscodingFileName(),
+		streamCtx.getThumbnailSpriteFileName(),
+		streamCtx.getLargeThumbnailSpriteFileName())
+}
+
+func store(streamCtx *StreamContext, files ...string) error {
+	var errs []error
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		if err := StoreFile(file); err != nil {
+			log.WithField("stream", streamCtx.getStreamName()).WithField("file", file).WithError(err).Error("Error storing file")
+			errs = append(errs, err)
+		}
 	}
-	fileReader, err := os.Open(file)
-	if err != nil {
-		return err
+	return errors.Join(errs...)
+}
+
+// upload publishes the VoD of a stream to all configured sinks
+func upload(streamCtx *StreamContext) error {
+	log.WithField("stream", streamCtx.getStreamName()).Info("Uploading stream")
+	file := streamCtx.getTranscodingFileName()
+	var errs []error
+	for _, sink := range sinks {
+		err := sink.Put(context.Background(), StorageKey(file), file)
+		if err != nil {
+			log.WithField("stream", streamCtx.getStreamName()).WithError(err).Error("Error uploading stream")
+			errs = append(errs, err)
+			continue
+		}
+		log.WithField("stream", streamCtx.getStreamName()).Info("Uploaded stream")
 	}
-	defer fileReader.Close()
-	_, err = io.Copy(formFileWriter, fileReader)
-	return err
+	return errors.Join(errs...)
 }
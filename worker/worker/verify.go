@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	verifyTolerance         = 5.0  // verifyTolerance is the absolute difference in seconds allowed between input and output
+	verifyRelativeTolerance = 0.01 // verifyRelativeTolerance is the difference allowed relative to the input duration
+	verifySegmentLength     = 10   // verifySegmentLength is the number of seconds decoded at the start and end of the output
+)
+
+// verification is the result of checking a transcoded recording against its source
+type verification struct {
+	InputDuration  float64
+	OutputDuration float64
+}
+
+func (v verification) String() string {
+	return fmt.Sprintf("duration %.1fs of %.1fs, first and last %ds decodable", v.OutputDuration, v.InputDuration, verifySegmentLength)
+}
+
+// checkDurations returns an error if the output is considerably shorter or longer than the input.
+// offset is the difference between the start times of input and output, e.g. if the first frames
+// of a recording are cut off while transcoding.
+func checkDurations(in, out, offset float64) error {
+	if out <= 0 {
+		return errors.New("output has no duration")
+	}
+	tolerance := math.Max(verifyTolerance, in*verifyRelativeTolerance) + math.Abs(offset)
+	if diff := in - out; math.Abs(diff) > tolerance {
+		return fmt.Errorf("output duration %.1fs differs from input duration %.1fs by more than %.1fs", out, in, tolerance)
+	}
+	return nil
+}
+
+// decodeCommand returns the command decoding the first (fromEnd = false) or last seconds of file.
+// Decoding errors abort ffmpeg and are written to stderr.
+func decodeCommand(file string, seconds int, fromEnd bool) *ffmpeg.Command {
+	inputOptions := []string{"-t", strconv.Itoa(seconds)}
+	if fromEnd {
+		inputOptions = []string{"-sseof", "-" + strconv.Itoa(seconds)}
+	}
+	return ffmpeg.New().
+		Global("-nostdin", "-v", "error", "-xerror").
+		Input(file, inputOptions...).
+		Output("-", "-f", "null")
+}
+
+// checkDecodable decodes the first and last seconds of file
+func checkDecodable(file string) error {
+	for _, fromEnd := range []bool{false, true} {
+		out, err := decodeCommand(file, verifySegmentLength, fromEnd).Cmd().CombinedOutput()
+		part := "first"
+		if fromEnd {
+			part = "last"
+		}
+		if err != nil || len(strings.TrimSpace(string(out))) > 0 {
+			return fmt.Errorf("decode %s %ds of %s: %v %s", part, verifySegmentLength, file, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// verifyTranscoding checks that the transcoded recording of streamCtx is complete and decodable.
+// ffmpeg exiting successfully isn't enough, truncated inputs and full disks produced short VoDs before.
+func verifyTranscoding(streamCtx *StreamContext) (verification, error) {
+	in, out := streamCtx.getRecordingFileName(), streamCtx.getTranscodingFileName()
+	var v verification
+	var err error
+	if v.InputDuration, err = getDuration(in); err != nil {
+		return v, fmt.Errorf("verification failed: probe input: %w", err)
+	}
+	if v.OutputDuration, err = getDuration(out); err != nil {
+		return v, fmt.Errorf("verification failed: probe output: %w", err)
+	}
+	inStart, err := getStartTime(in)
+	if err != nil {
+		return v, fmt.Errorf("verification failed: probe input: %w", err)
+	}
+	outStart, err := getStartTime(out)
+	if err != nil {
+		return v, fmt.Errorf("verification failed: probe output: %w", err)
+	}
+	if err = checkDurations(v.InputDuration, v.OutputDuration, inStart-outStart); err != nil {
+		return v, fmt.Errorf("verification failed: %w", err)
+	}
+	if err = checkDecodable(out); err != nil {
+		return v, fmt.Errorf("verification failed: %w", err)
+	}
+	log.WithFields(log.Fields{"stream": streamCtx.getStreamName(), "result": v}).Info("Transcoding verified")
+	return v, nil
+}
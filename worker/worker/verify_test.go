@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		in, out float64
+		offset  float64
+		wantErr bool
+	}{
+		{"equal", 3600, 3600, 0, false},
+		{"within relative tolerance", 3600, 3570, 0, false},
+		{"within absolute tolerance", 60, 56, 0, false},
+		{"truncated", 5400, 2700, 0, true},
+		{"slightly too short", 60, 50, 0, true},
+		{"offset allows shorter output", 60, 50, 8, false},
+		{"longer than input", 600, 700, 0, true},
+		{"empty output", 600, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDurations(tt.in, tt.out, tt.offset); (err != nil) != tt.wantErr {
+				t.Fatalf("checkDurations(%v, %v, %v) = %v, wantErr %v", tt.in, tt.out, tt.offset, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeCommand(t *testing.T) {
+	expected := []string{"-nostdin", "-v", "error", "-xerror", "-t", "10", "-i", "out.mp4", "-f", "null", "-"}
+	if args := decodeCommand("out.mp4", 10, false).Args(); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+	expected = []string{"-nostdin", "-v", "error", "-xerror", "-sseof", "-10", "-i", "out.mp4", "-f", "null", "-"}
+	if args := decodeCommand("out.mp4", 10, true).Args(); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"strings"
 
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	"github.com/TUM-Dev/gocast/worker/pb"
 	uuid "github.com/iris-contrib/go.uuid"
 	log "github.com/sirupsen/logrus"
@@ -29,13 +29,13 @@ func GetWaveform(request *pb.WaveformRequest) ([]byte, error) {
 		return nil, err
 	}
 	tempFile := "/tmp/" + v4.String() + ".png"
-	c := []string{
-		"ffmpeg", "-i", request.File,
-		"-filter_complex", fmt.Sprintf("aformat=channel_layouts=mono,showwavespic=s=%dx%d:filter=average:colors=white|white:scale=lin", waveFormWitdth, waveFormHeight),
-		"-frames:v", "1",
-		tempFile,
-	}
-	cmd := exec.Command("nice", c...)
+	cmd := ffmpeg.New().
+		Nice(10).
+		Input(request.File).
+		Output(tempFile,
+			"-filter_complex", fmt.Sprintf("aformat=channel_layouts=mono,showwavespic=s=%dx%d:filter=average:colors=white|white:scale=lin", waveFormWitdth, waveFormHeight),
+			"-frames:v", "1").
+		Cmd()
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		log.WithField("combinedOutput", string(output)).Error("Could not get waveform with ffmpeg")
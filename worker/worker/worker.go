@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/TUM-Dev/gocast/worker/cfg"
+	"github.com/TUM-Dev/gocast/worker/ffmpeg"
 	"github.com/TUM-Dev/gocast/worker/worker/vmstat"
 	"github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
@@ -26,6 +27,11 @@ func Setup() {
 	if err != nil {
 		log.WithError(err).Fatal("Failed to create persistable")
 	}
+	setupStorage()
+	err = ffmpeg.Configure(ffmpeg.Encoder(cfg.FfmpegEncoder), cfg.FfmpegProfiles)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to configure ffmpeg profiles")
+	}
 
 	c := cron.New()
 	_, _ = c.AddFunc("* * * * *", S.SendHeartbeat)
@@ -39,8 +45,11 @@ func Setup() {
 		log.Debugf("deleting %d old files", len(persisted.Deletable))
 		var notDeleted []Deletable
 		for i, deletable := range persisted.Deletable {
-			if time.Since(deletable.Time) >= time.Hour*24 {
-				err := os.Remove(deletable.File)
+			if persisted.isNeeded(deletable.File) {
+				log.Debugf("keeping %s (needed by unfinished pipeline)", deletable.File)
+				notDeleted = append(notDeleted, persisted.Deletable[i])
+			} else if time.Since(deletable.Time) >= time.Hour*24 {
+				err := os.RemoveAll(deletable.File) // DVR recordings are directories
 				if err != nil {
 					log.WithError(err).Error("Failed to delete old recording")
 				}
@@ -55,4 +64,6 @@ func Setup() {
 		}
 	})
 	c.Start()
+
+	go resumePipelines()
 }